@@ -0,0 +1,197 @@
+package build
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/distribution/reference"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker/buildkit"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// The Docker engine can't export a build cache on its own. Instead, we ask
+// Buildkit to embed the cache metadata in the image, then store the image
+// wherever the user wants the cache.
+const inlineCacheBuildArg = "BUILDKIT_INLINE_CACHE"
+
+// Local caches are stored as a `docker save` archive in the cache directory.
+const localCacheFile = "tilt-cache.tar"
+
+// The tag of the image we save to a local cache.
+const localCacheTag = "tilt-cache"
+
+// Resolves cache_from specs to images that the Docker engine can import.
+//
+// Local caches are loaded into the image store. If a local cache doesn't
+// exist yet (e.g., because nothing has exported it), we skip it.
+func (d *DockerBuilder) importCaches(ctx context.Context, cacheFrom []string) ([]string, error) {
+	var result []string
+	for _, v := range cacheFrom {
+		spec, err := buildkit.ParseCacheFrom(v)
+		if err != nil {
+			// Leave anything we don't understand for the Docker engine to interpret.
+			result = append(result, v)
+			continue
+		}
+
+		switch spec.Type {
+		case buildkit.CacheTypeRegistry:
+			result = append(result, spec.Ref)
+		case buildkit.CacheTypeLocal:
+			ref, err := d.loadLocalCache(ctx, spec.Dir)
+			if err != nil {
+				return nil, errors.Wrapf(err, "importing cache from %s", spec.Dir)
+			}
+			if ref != "" {
+				result = append(result, ref)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (d *DockerBuilder) loadLocalCache(ctx context.Context, dir string) (string, error) {
+	f, err := os.Open(filepath.Join(dir, localCacheFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			logger.Get(ctx).Infof("No build cache found in %s", dir)
+			return "", nil
+		}
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	resp, err := d.dCli.ImageLoad(ctx, f, dockerclient.ImageLoadWithQuiet(true))
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Close()
+	}()
+	return loadedImageRef(resp)
+}
+
+// Reads the image ref from the output of `docker load`.
+func loadedImageRef(r io.Reader) (string, error) {
+	const prefix = "Loaded image: "
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		var msg struct {
+			Stream string `json:"stream"`
+			Error  string `json:"error"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &msg); err != nil {
+			continue
+		}
+		if msg.Error != "" {
+			return "", errors.New(msg.Error)
+		}
+		if strings.HasPrefix(msg.Stream, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(msg.Stream, prefix)), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("no image found in cache")
+}
+
+// Exports the cache of the image we just built to each cache_to destination.
+func (d *DockerBuilder) exportCaches(ctx context.Context, spec v1alpha1.DockerImageSpec, dig digest.Digest) error {
+	for _, v := range spec.CacheTo {
+		cacheSpec, err := buildkit.ParseCacheTo(v)
+		if err != nil {
+			return errors.Wrapf(err, "cache_to %q", v)
+		}
+
+		switch cacheSpec.Type {
+		case buildkit.CacheTypeInline:
+			// The cache is already part of the image.
+		case buildkit.CacheTypeRegistry:
+			logger.Get(ctx).Infof("Exporting build cache to %s", cacheSpec.Ref)
+			err = d.exportRegistryCache(ctx, cacheSpec.Ref, dig)
+		case buildkit.CacheTypeLocal:
+			logger.Get(ctx).Infof("Exporting build cache to %s", cacheSpec.Dir)
+			err = d.exportLocalCache(ctx, spec.Ref, cacheSpec.Dir, dig)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "exporting cache to %s", cacheSpec)
+		}
+	}
+	return nil
+}
+
+func (d *DockerBuilder) exportRegistryCache(ctx context.Context, ref string, dig digest.Digest) error {
+	named, err := container.ParseNamed(ref)
+	if err != nil {
+		return err
+	}
+	tagged, ok := reference.TagNameOnly(named).(reference.NamedTagged)
+	if !ok {
+		return fmt.Errorf("can't push to %s: must be a tag, not a digest", ref)
+	}
+
+	_, err = d.dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: dig.String(), Target: tagged.String()})
+	if err != nil {
+		return err
+	}
+	return d.PushImage(ctx, tagged)
+}
+
+func (d *DockerBuilder) exportLocalCache(ctx context.Context, imageRef string, dir string, dig digest.Digest) error {
+	// Save the image under a tag, so that we know what to call it
+	// when we load it again.
+	name := localCacheTag
+	if imageRef != "" {
+		named, err := container.ParseNamed(imageRef)
+		if err != nil {
+			return err
+		}
+		name = reference.FamiliarName(named)
+	}
+	ref := fmt.Sprintf("%s:%s", name, localCacheTag)
+
+	_, err := d.dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: dig.String(), Target: ref})
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file first, so that we never leave a partial cache behind.
+	tmp, err := os.CreateTemp(dir, localCacheFile+".*")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	err = d.SaveImage(ctx, ref, tmp)
+	if err != nil {
+		return err
+	}
+	err = tmp.Close()
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(dir, localCacheFile))
+}
@@ -0,0 +1,111 @@
+package build
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+var cacheTestDigest = digest.Digest("sha256:cc5f4c463f81c55183d8d737ba2f0d30b3e6f3670dbe2da68f0aac168e93fbb1")
+
+func TestExportCacheInline(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+
+	spec := v1alpha1.DockerImageSpec{Ref: "gcr.io/foo/bar", CacheTo: []string{"type=inline"}}
+	err := f.b.exportCaches(f.ctx, spec, cacheTestDigest)
+	require.NoError(t, err)
+	assert.Equal(t, 0, f.fakeDocker.TagCount)
+	assert.Equal(t, 0, f.fakeDocker.PushCount)
+}
+
+func TestExportCacheRegistry(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+
+	spec := v1alpha1.DockerImageSpec{Ref: "gcr.io/foo/bar", CacheTo: []string{"type=registry,ref=gcr.io/foo/bar:cache"}}
+	err := f.b.exportCaches(f.ctx, spec, cacheTestDigest)
+	require.NoError(t, err)
+	assert.Equal(t, cacheTestDigest.String(), f.fakeDocker.TagSource)
+	assert.Equal(t, "gcr.io/foo/bar:cache", f.fakeDocker.TagTarget)
+	assert.Equal(t, 1, f.fakeDocker.PushCount)
+	assert.Equal(t, "gcr.io/foo/bar:cache", f.fakeDocker.PushImage)
+}
+
+func TestExportCacheLocal(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.fakeDocker.SaveOutput = "image-archive"
+
+	dir := f.JoinPath("cache")
+	spec := v1alpha1.DockerImageSpec{Ref: "gcr.io/foo/bar", CacheTo: []string{"type=local,dest=" + dir}}
+	err := f.b.exportCaches(f.ctx, spec, cacheTestDigest)
+	require.NoError(t, err)
+	assert.Equal(t, "gcr.io/foo/bar:tilt-cache", f.fakeDocker.TagTarget)
+	assert.Equal(t, []string{"gcr.io/foo/bar:tilt-cache"}, f.fakeDocker.SavedImageIDs)
+	contents, err := os.ReadFile(filepath.Join(dir, localCacheFile))
+	require.NoError(t, err)
+	assert.Equal(t, "image-archive", string(contents))
+}
+
+func TestExportCacheWithoutBuildkit(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	out := &bytes.Buffer{}
+	ctx := logger.WithLogger(f.ctx, logger.NewTestLogger(out))
+
+	// The fake client reports the legacy builder.
+	spec := v1alpha1.DockerImageSpec{
+		DockerfileContents: dockerfile.Dockerfile("FROM alpine").String(),
+		Context:            f.Path(),
+		CacheTo:            []string{"type=registry,ref=gcr.io/foo/bar:cache"},
+	}
+	_, _, err := f.b.BuildImage(ctx, f.ps, f.getNameFromTest(), spec, nil, nil, model.EmptyMatcher)
+	require.NoError(t, err)
+	assert.Nil(t, f.fakeDocker.BuildOptions.BuildArgs[inlineCacheBuildArg])
+	assert.Contains(t, out.String(), "cache_to requires BuildKit")
+}
+
+func TestImportCacheRegistry(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+
+	result, err := f.b.importCaches(f.ctx, []string{"gcr.io/foo/bar:cache", "type=registry,ref=gcr.io/foo/baz:cache"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gcr.io/foo/bar:cache", "gcr.io/foo/baz:cache"}, result)
+}
+
+func TestImportCacheLocal(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.WriteFile(filepath.Join("cache", localCacheFile), "image-archive")
+	f.fakeDocker.LoadOutput = `{"stream":"Loaded image: gcr.io/foo/bar:tilt-cache\n"}`
+
+	result, err := f.b.importCaches(f.ctx, []string{"type=local,src=" + f.JoinPath("cache")})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"gcr.io/foo/bar:tilt-cache"}, result)
+	assert.Equal(t, "image-archive", f.fakeDocker.LoadInput)
+}
+
+func TestImportCacheLocalMissing(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+
+	result, err := f.b.importCaches(f.ctx, []string{"type=local,src=" + f.JoinPath("cache")})
+	require.NoError(t, err)
+	assert.Empty(t, result)
+	assert.Equal(t, 0, f.fakeDocker.LoadCount)
+}
+
+func TestImportCacheLocalError(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.WriteFile(filepath.Join("cache", localCacheFile), "image-archive")
+	f.fakeDocker.LoadOutput = `{"error":"archive/tar: invalid tar header"}`
+
+	_, err := f.b.importCaches(f.ctx, []string{"type=local,src=" + f.JoinPath("cache")})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid tar header")
+}
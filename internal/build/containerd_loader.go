@@ -0,0 +1,141 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/distribution/reference"
+	"github.com/moby/moby/client"
+	"github.com/pkg/errors"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Labels that local cluster tools put on their node containers.
+const (
+	kindClusterLabel     = "io.x-k8s.kind.cluster"
+	k3dClusterLabel      = "k3d.cluster"
+	k3dRoleLabel         = "k3d.role"
+	minikubeClusterLabel = "name.minikube.sigs.k8s.io"
+)
+
+var containerdImportCmd = model.Cmd{Argv: []string{
+	"ctr", "--namespace=k8s.io", "images", "import", "--all-platforms", "--digests", "-",
+}}
+
+// Loads images into local clusters whose nodes run as containers
+// on the same daemon that we build on (kind, k3d, and minikube with the docker driver).
+//
+// Streams the image from `docker save` into `ctr images import` on each node,
+// so that the cluster doesn't need a registry.
+type containerdLoader struct {
+	dCli docker.Client
+}
+
+func newContainerdLoader(dCli docker.Client) *containerdLoader {
+	return &containerdLoader{dCli: dCli}
+}
+
+// Whether we know how to find the nodes of this cluster.
+func supportsContainerdLoad(cluster *v1alpha1.Cluster) bool {
+	_, ok := nodeLabelFilter(k8sConnStatus(cluster))
+	return ok
+}
+
+func nodeLabelFilter(conn *v1alpha1.KubernetesClusterConnectionStatus) (string, bool) {
+	switch clusterid.Product(conn.Product) {
+	case clusterid.ProductKIND:
+		return fmt.Sprintf("%s=%s", kindClusterLabel, strings.TrimPrefix(conn.Cluster, "kind-")), true
+	case clusterid.ProductK3D:
+		return fmt.Sprintf("%s=%s", k3dClusterLabel, strings.TrimPrefix(conn.Cluster, "k3d-")), true
+	case clusterid.ProductMinikube:
+		return fmt.Sprintf("%s=%s", minikubeClusterLabel, conn.Cluster), true
+	}
+	return "", false
+}
+
+// Finds the containers that run the Kubernetes nodes of the cluster.
+//
+// Returns an empty list if the nodes aren't containers on this daemon
+// (e.g., minikube with a VM driver).
+func (l *containerdLoader) Nodes(ctx context.Context, cluster *v1alpha1.Cluster) ([]container.ID, error) {
+	conn := k8sConnStatus(cluster)
+	filter, ok := nodeLabelFilter(conn)
+	if !ok {
+		return nil, nil
+	}
+
+	result, err := l.dCli.ContainerList(ctx, client.ContainerListOptions{
+		Filters: make(client.Filters).Add("label", filter),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding cluster nodes")
+	}
+
+	var nodes []container.ID
+	for _, c := range result.Items {
+		// k3d also runs load balancers and registries with the cluster label.
+		if clusterid.Product(conn.Product) == clusterid.ProductK3D {
+			role := c.Labels[k3dRoleLabel]
+			if role != "server" && role != "agent" {
+				continue
+			}
+		}
+		nodes = append(nodes, container.ID(c.ID))
+	}
+	return nodes, nil
+}
+
+// Imports the image into the containerd image store of each node.
+func (l *containerdLoader) Load(ctx context.Context, nodes []container.ID, ref reference.NamedTagged) error {
+	resp, err := l.dCli.ImageSave(ctx, []string{ref.String()})
+	if err != nil {
+		return errors.Wrap(err, "exporting image")
+	}
+	defer func() {
+		_ = resp.Close()
+	}()
+
+	g, ctx := errgroup.WithContext(ctx)
+	writers := make([]io.Writer, 0, len(nodes))
+	pipes := make([]*io.PipeWriter, 0, len(nodes))
+	for _, node := range nodes {
+		pr, pw := io.Pipe()
+		writers = append(writers, pw)
+		pipes = append(pipes, pw)
+
+		g.Go(func() error {
+			out := &bytes.Buffer{}
+			err := l.dCli.ExecInContainer(ctx, node, containerdImportCmd, pr, out)
+
+			// Unblock the writer if the import exited early.
+			_ = pr.CloseWithError(fmt.Errorf("import on node %s exited", node.ShortStr()))
+			if err != nil {
+				return fmt.Errorf("importing image on node %s: %v\n%s", node.ShortStr(), err, out.String())
+			}
+			return nil
+		})
+	}
+
+	_, copyErr := io.Copy(io.MultiWriter(writers...), resp)
+	for _, pw := range pipes {
+		_ = pw.CloseWithError(copyErr)
+	}
+
+	err = g.Wait()
+	if err != nil {
+		return err
+	}
+	if copyErr != nil {
+		return errors.Wrap(copyErr, "exporting image")
+	}
+	return nil
+}
@@ -147,6 +147,29 @@ func (d *DockerBuilder) ImageExists(ctx context.Context, ref reference.NamedTagg
 	return true, nil
 }
 
+// Writes the image as a tarball, in the format of `docker save`.
+func (d *DockerBuilder) SaveImage(ctx context.Context, ref string, w io.Writer) error {
+	resp, err := d.dCli.ImageSave(ctx, []string{ref})
+	if err != nil {
+		return errors.Wrap(err, "SaveImage")
+	}
+	defer func() {
+		_ = resp.Close()
+	}()
+
+	_, err = io.Copy(w, resp)
+	if err != nil {
+		return errors.Wrap(err, "SaveImage")
+	}
+	return nil
+}
+
+// Whether we're building with podman's Docker-compatible API.
+func (d *DockerBuilder) IsPodman(ctx context.Context) bool {
+	v, err := d.dCli.ServerVersion(ctx)
+	return err == nil && docker.IsPodman(v)
+}
+
 func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs container.RefSet,
 	spec v1alpha1.DockerImageSpec,
 	cluster *v1alpha1.Cluster,
@@ -165,8 +188,31 @@ func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs
 	logger.Get(ctx).Infof("Building Dockerfile%s:\n%s\n", platformSuffix, indent(spec.DockerfileContents, "  "))
 
 	ps.StartBuildStep(ctx, "Building image")
-	allowBuildkit := true
 	ctx = ps.AttachLogger(ctx)
+	digest, stages, err := d.buildToDigestWithRetry(ctx, spec, filter)
+	if err != nil {
+		return container.TaggedRefs{}, stages, err
+	}
+
+	tagged, err := d.TagRefs(ctx, refs, digest)
+	if err != nil {
+		return container.TaggedRefs{}, stages, errors.Wrap(err, "docker tag")
+	}
+
+	if len(spec.CacheTo) > 0 {
+		ps.StartBuildStep(ctx, "Exporting build cache")
+		err = d.exportCaches(ctx, spec, digest)
+		if err != nil {
+			return container.TaggedRefs{}, stages, err
+		}
+	}
+
+	return tagged, stages, nil
+}
+
+// Builds the image, retrying without Buildkit if Buildkit's cache is corrupted.
+func (d *DockerBuilder) buildToDigestWithRetry(ctx context.Context, spec v1alpha1.DockerImageSpec, filter model.PathMatcher) (digest.Digest, []v1alpha1.DockerImageStageStatus, error) {
+	allowBuildkit := true
 	digest, stages, err := d.buildToDigest(ctx, spec, filter, allowBuildkit)
 	if err != nil {
 		isMysteriousCorruption := strings.Contains(err.Error(), "failed precondition") &&
@@ -190,18 +236,8 @@ func (d *DockerBuilder) BuildImage(ctx context.Context, ps *PipelineState, refs
 			logger.Get(ctx).Infof("Detected Buildkit corruption. Rebuilding without Buildkit")
 			digest, stages, err = d.buildToDigest(ctx, spec, filter, allowBuildkit)
 		}
-
-		if err != nil {
-			return container.TaggedRefs{}, stages, err
-		}
-	}
-
-	tagged, err := d.TagRefs(ctx, refs, digest)
-	if err != nil {
-		return container.TaggedRefs{}, stages, errors.Wrap(err, "docker tag")
 	}
-
-	return tagged, stages, nil
+	return digest, stages, err
 }
 
 // A helper function that builds the paths to the given docker image,
@@ -271,6 +307,24 @@ func (d *DockerBuilder) buildToDigest(ctx context.Context, spec v1alpha1.DockerI
 	}
 
 	options := Options(contextReader, spec)
+	options.CacheFrom, err = d.importCaches(ctx, spec.CacheFrom)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(spec.CacheTo) > 0 {
+		if useFSSync {
+			enabled := "1"
+			if options.BuildArgs == nil {
+				options.BuildArgs = make(map[string]*string)
+			}
+			options.BuildArgs[inlineCacheBuildArg] = &enabled
+		} else {
+			// The legacy builder (and podman) can't write cache metadata into the
+			// image, so the exported cache won't speed up later builds.
+			logger.Get(ctx).Warnf("cache_to requires BuildKit, but this image was built without it. " +
+				"The exported image won't contain any build cache.")
+		}
+	}
 	if useFSSync {
 		dockerfileDir, err := writeTempDockerfileSyncdir(spec.DockerfileContents)
 		if err != nil {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"os"
 
 	"github.com/distribution/reference"
 	"k8s.io/apimachinery/pkg/types"
@@ -21,6 +22,7 @@ type ImageBuilder struct {
 	db    *DockerBuilder
 	custb *CustomBuilder
 	kl    KINDLoader
+	cl    *containerdLoader
 }
 
 func NewImageBuilder(db *DockerBuilder, custb *CustomBuilder, kl KINDLoader) *ImageBuilder {
@@ -28,6 +30,7 @@ func NewImageBuilder(db *DockerBuilder, custb *CustomBuilder, kl KINDLoader) *Im
 		db:    db,
 		custb: custb,
 		kl:    kl,
+		cl:    newContainerdLoader(db.dCli),
 	}
 }
 
@@ -55,6 +58,13 @@ func (ib *ImageBuilder) Build(ctx context.Context,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	ps *PipelineState) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error) {
+	if bd, ok := iTarget.BuildDetails.(model.DockerBuild); ok {
+		spec := InjectClusterPlatform(bd.DockerImageSpec, cluster)
+		if IsMultiPlatform(spec) {
+			return ib.buildMultiPlatform(ctx, iTarget, spec, cluster, imageMaps, ps)
+		}
+	}
+
 	refs, stages, err := ib.buildOnly(ctx, iTarget, customBuildCmd, cluster, imageMaps, ps)
 	if err != nil {
 		return refs, stages, err
@@ -111,6 +121,59 @@ func (ib *ImageBuilder) buildOnly(ctx context.Context,
 		"DockerBuild nor CustomBuild)", refs.ConfigurationRef)
 }
 
+// Build and push an image for each platform, then push a manifest list.
+//
+// The manifest list only exists in the registry, so we can't use
+// any of the other ways of getting an image into a cluster.
+func (ib *ImageBuilder) buildMultiPlatform(ctx context.Context,
+	iTarget model.ImageTarget,
+	spec v1alpha1.DockerImageSpec,
+	cluster *v1alpha1.Cluster,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	ps *PipelineState,
+) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error) {
+	refs, err := iTarget.Refs(cluster)
+	if err != nil {
+		return container.TaggedRefs{}, nil, err
+	}
+
+	userFacingRefName := container.FamiliarString(refs.ConfigurationRef)
+	err = ib.checkCanPushMultiPlatform(refs, iTarget, cluster)
+	if err != nil {
+		return container.TaggedRefs{}, nil, fmt.Errorf("Building %s for platforms %s: %v", userFacingRefName, spec.Platform, err)
+	}
+
+	ps.StartPipelineStep(ctx, "Building Dockerfile: [%s]", userFacingRefName)
+	defer ps.EndPipelineStep(ctx)
+
+	filter := ignore.CreateBuildContextFilter(spec.ContextIgnores)
+	return ib.db.BuildMultiPlatformImage(ctx, ps, refs, spec, imageMaps, filter)
+}
+
+func (ib *ImageBuilder) checkCanPushMultiPlatform(refs container.RefSet, iTarget model.ImageTarget, cluster *v1alpha1.Cluster) error {
+	isDC := cluster != nil &&
+		cluster.Spec.Connection != nil &&
+		cluster.Spec.Connection.Docker != nil
+	if isDC {
+		return errors.New("multi-platform images must be pushed to a registry, but Docker Compose doesn't push images")
+	}
+
+	if iTarget.ClusterNeeds() != v1alpha1.ClusterImageNeedsPush {
+		return errors.New("multi-platform images must be pushed to a registry, but this image isn't deployed")
+	}
+
+	if ib.db.WillBuildToKubeContext(k8s.KubeContext(k8sConnStatus(cluster).Context)) {
+		return errors.New("multi-platform images must be pushed to a registry, but this cluster uses images from the local container runtime")
+	}
+
+	isKIND := k8sConnStatus(cluster).Product == string(clusterid.ProductKIND)
+	hasRegistry := cluster != nil && cluster.Status.Registry != nil && cluster.Status.Registry.Host != ""
+	if isKIND && !hasRegistry && refs.LocalRef().String() == refs.ClusterRef().String() {
+		return errors.New("multi-platform images must be pushed to a registry, but this KIND cluster has no registry")
+	}
+	return nil
+}
+
 // Push the image if the cluster requires it.
 func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps *PipelineState, iTarget model.ImageTarget, cluster *v1alpha1.Cluster) *v1alpha1.DockerImageStageStatus {
 	// Skip the push phase entirely if we're on Docker Compose.
@@ -150,9 +213,32 @@ func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps
 
 	startTime := apis.NowMicro()
 	var err error
+	if ib.shouldLoadIntoCluster(refs, cluster) {
+		nodes, err := ib.cl.Nodes(ctx, cluster)
+		if err != nil {
+			ps.Printf(ctx, "Skipping containerd import: %v", err)
+		} else if len(nodes) > 0 {
+			ps.Printf(ctx, "Importing image into %d cluster node(s) with containerd", len(nodes))
+			err := ib.cl.Load(ps.AttachLogger(ctx), nodes, refs.LocalRef)
+			if err == nil {
+				endTime := apis.NowMicro()
+				return &v1alpha1.DockerImageStageStatus{
+					Name:       "containerd import",
+					StartedAt:  &startTime,
+					FinishedAt: &endTime,
+				}
+			}
+
+			// The nodes may not have `ctr`, or may not be able to read the image.
+			// The slower paths still work, so fall back to them.
+			ps.Printf(ctx, "Containerd import failed, falling back: %v", err)
+			startTime = apis.NowMicro()
+		}
+	}
+
 	if ib.shouldUseKINDLoad(refs, cluster) {
 		ps.Printf(ctx, "Loading image to KIND")
-		err := ib.kl.LoadToKIND(ps.AttachLogger(ctx), cluster, refs.LocalRef)
+		err := ib.loadToKIND(ps.AttachLogger(ctx), cluster, refs.LocalRef)
 		endTime := apis.NowMicro()
 		stage := &v1alpha1.DockerImageStageStatus{
 			Name:       "kind load",
@@ -180,25 +266,55 @@ func (ib *ImageBuilder) push(ctx context.Context, refs container.TaggedRefs, ps
 	return stage
 }
 
-func (ib *ImageBuilder) shouldUseKINDLoad(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
-	isKIND := k8sConnStatus(cluster).Product == string(clusterid.ProductKIND)
-	if !isKIND {
-		return false
+func (ib *ImageBuilder) loadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	if !ib.db.IsPodman(ctx) {
+		return ib.kl.LoadToKIND(ctx, cluster, ref)
 	}
 
-	// if we're using KIND and the image has a separate ref by which it's referred to
-	// in the cluster, that implies that we have a local registry in place, and should
-	// push to that instead of using KIND load.
-	if refs.LocalRef.String() != refs.ClusterRef.String() {
-		return false
+	// `kind load docker-image` reads from the docker CLI, which can't see
+	// images built with podman. So we export the image ourselves.
+	f, err := os.CreateTemp("", "tilt-kind-load-*.tar")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = f.Close()
+		_ = os.Remove(f.Name())
+	}()
+
+	err = ib.db.SaveImage(ctx, ref.String(), f)
+	if err != nil {
+		return err
 	}
+	err = f.Close()
+	if err != nil {
+		return err
+	}
+	return ib.kl.LoadArchiveToKIND(ctx, cluster, f.Name())
+}
 
-	hasRegistry := cluster.Status.Registry != nil && cluster.Status.Registry.Host != ""
-	if hasRegistry {
-		return false
+func (ib *ImageBuilder) shouldUseKINDLoad(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
+	isKIND := k8sConnStatus(cluster).Product == string(clusterid.ProductKIND)
+	return isKIND && !hasSeparateClusterRefOrRegistry(refs, cluster)
+}
+
+// Local clusters without a registry can import images directly into their nodes.
+//
+// If we can't find the nodes, or the import fails, we fall back to
+// `kind load` on KIND and to a push everywhere else.
+func (ib *ImageBuilder) shouldLoadIntoCluster(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
+	return supportsContainerdLoad(cluster) && !hasSeparateClusterRefOrRegistry(refs, cluster)
+}
+
+// If the image has a separate ref by which it's referred to in the cluster,
+// that implies that we have a local registry in place, and should
+// push to that instead of loading the image into the cluster.
+func hasSeparateClusterRefOrRegistry(refs container.TaggedRefs, cluster *v1alpha1.Cluster) bool {
+	if refs.LocalRef.String() != refs.ClusterRef.String() {
+		return true
 	}
 
-	return true
+	return cluster.Status.Registry != nil && cluster.Status.Registry.Host != ""
 }
 
 func k8sConnStatus(cluster *v1alpha1.Cluster) *v1alpha1.KubernetesClusterConnectionStatus {
@@ -0,0 +1,89 @@
+package build
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/distribution/reference"
+	typescontainer "github.com/moby/moby/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestPushFallsBackToKINDLoadWhenContainerdImportFails(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	kl := &fakeKINDLoader{}
+	ib := NewImageBuilder(f.b, nil, kl)
+
+	f.fakeDocker.LabeledContainers = []typescontainer.Summary{
+		{ID: "kind-control-plane", Labels: map[string]string{"io.x-k8s.kind.cluster": "kind"}},
+	}
+	f.fakeDocker.SetExecError(fmt.Errorf("ctr: not found"))
+
+	refs := pushTestRefs()
+	stage := ib.push(f.ctx, refs, f.ps, pushTestImageTarget(), pushTestCluster(clusterid.ProductKIND, "kind-kind"))
+	require.NotNil(t, stage)
+	assert.Equal(t, "kind load", stage.Name)
+	assert.Empty(t, stage.Error)
+	assert.Equal(t, []string{refs.LocalRef.String()}, kl.loaded)
+	assert.Equal(t, 0, f.fakeDocker.PushCount)
+}
+
+func TestPushFallsBackToPushWhenContainerdImportFails(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	ib := NewImageBuilder(f.b, nil, &fakeKINDLoader{})
+
+	f.fakeDocker.LabeledContainers = []typescontainer.Summary{
+		{ID: "minikube", Labels: map[string]string{"name.minikube.sigs.k8s.io": "minikube"}},
+	}
+	f.fakeDocker.SetExecError(fmt.Errorf("ctr: not found"))
+
+	stage := ib.push(f.ctx, pushTestRefs(), f.ps, pushTestImageTarget(), pushTestCluster(clusterid.ProductMinikube, "minikube"))
+	require.NotNil(t, stage)
+	assert.Equal(t, "docker push", stage.Name)
+	assert.Empty(t, stage.Error)
+	assert.Equal(t, 1, f.fakeDocker.PushCount)
+}
+
+func pushTestRefs() container.TaggedRefs {
+	ref := container.MustParseNamedTagged("sancho:tilt-123")
+	return container.TaggedRefs{LocalRef: ref, ClusterRef: ref}
+}
+
+func pushTestImageTarget() model.ImageTarget {
+	return model.MustNewImageTarget(container.MustParseSelector("sancho")).
+		WithDockerImage(v1alpha1.DockerImageSpec{ClusterNeeds: v1alpha1.ClusterImageNeedsPush})
+}
+
+func pushTestCluster(product clusterid.Product, name string) *v1alpha1.Cluster {
+	return &v1alpha1.Cluster{
+		Status: v1alpha1.ClusterStatus{
+			Connection: &v1alpha1.ClusterConnectionStatus{
+				Kubernetes: &v1alpha1.KubernetesClusterConnectionStatus{
+					Product: string(product),
+					Cluster: name,
+				},
+			},
+		},
+	}
+}
+
+type fakeKINDLoader struct {
+	loaded []string
+}
+
+func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	kl.loaded = append(kl.loaded, ref.String())
+	return nil
+}
+
+func (kl *fakeKINDLoader) LoadArchiveToKIND(ctx context.Context, cluster *v1alpha1.Cluster, path string) error {
+	kl.loaded = append(kl.loaded, path)
+	return nil
+}
@@ -2,6 +2,7 @@ package build
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/types"
@@ -26,17 +27,48 @@ var validBuildkitArchSet = map[string]bool{
 }
 
 // Create a new ImageTarget with the platform OS/Arch from the target cluster.
+//
+// If the platform is "cluster-all", expands it to every architecture in the cluster.
 func InjectClusterPlatform(spec v1alpha1.DockerImageSpec, cluster *v1alpha1.Cluster) v1alpha1.DockerImageSpec {
+	if spec.Platform == v1alpha1.PlatformClusterAll {
+		spec.Platform = ""
+		if cluster == nil {
+			return spec
+		}
+
+		arches := cluster.Status.Arches
+		if len(arches) == 0 {
+			arches = []string{cluster.Status.Arch}
+		}
+
+		var platforms []string
+		for _, arch := range arches {
+			p, ok := archToPlatform(arch)
+			if ok {
+				platforms = append(platforms, p)
+			}
+		}
+		spec.Platform = strings.Join(platforms, ",")
+		return spec
+	}
+
 	if spec.Platform != "" || cluster == nil {
 		return spec
 	}
 
+	p, ok := archToPlatform(cluster.Status.Arch)
+	if ok {
+		spec.Platform = p
+	}
+	return spec
+}
+
+func archToPlatform(targetArch string) (string, bool) {
 	// Eventually, it might make sense to read the supported platforms
 	// off the buildkit server and negotiate the right one, but for
 	// now we hard-code a whitelist.
-	targetArch := cluster.Status.Arch
 	if !validBuildkitArchSet[targetArch] {
-		return spec
+		return "", false
 	}
 
 	if targetArch == "arm" {
@@ -47,8 +79,24 @@ func InjectClusterPlatform(spec v1alpha1.DockerImageSpec, cluster *v1alpha1.Clus
 
 	// Currently Tilt only supports linux containers.
 	// We don't even build windows-compatible docker contexts.
-	spec.Platform = fmt.Sprintf("linux/%s", targetArch)
-	return spec
+	return fmt.Sprintf("linux/%s", targetArch), true
+}
+
+// Splits a comma-separated platform list.
+func SplitPlatforms(platform string) []string {
+	var result []string
+	for _, p := range strings.Split(platform, ",") {
+		p = strings.TrimSpace(p)
+		if p != "" {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+// Whether the (already-injected) spec needs a multi-arch manifest list.
+func IsMultiPlatform(spec v1alpha1.DockerImageSpec) bool {
+	return len(SplitPlatforms(spec.Platform)) > 1
 }
 
 // Create a new ImageTarget with the Dockerfiles rewritten with the injected images.
@@ -0,0 +1,144 @@
+package build
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/platforms"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// The tag we give an image built from inputs with the given hash.
+//
+// If we find an image with this tag later, we can use it
+// instead of building the image again.
+func InputHashTag(inputHash string) string {
+	if len(inputHash) > 16 {
+		inputHash = inputHash[:16]
+	}
+	return fmt.Sprintf("%sinputs-%s", ImageTagPrefix, inputHash)
+}
+
+// Looks for an image that we built from inputs with the given hash, first in
+// the local image store, then in the registry.
+//
+// If we find the image locally, we push it to the cluster like we would
+// a newly built image.
+//
+// We only do this for docker_build() images. Custom builds don't have a reliable
+// way to check if an image exists, and multi-platform images only exist in
+// the registry as manifest lists.
+func (ib *ImageBuilder) FindImageByInputHash(ctx context.Context,
+	iTarget model.ImageTarget,
+	cluster *v1alpha1.Cluster,
+	inputHash string,
+	ps *PipelineState) (container.TaggedRefs, bool, error) {
+	bd, ok := iTarget.BuildDetails.(model.DockerBuild)
+	if !ok || inputHash == "" {
+		return container.TaggedRefs{}, false, nil
+	}
+	spec := InjectClusterPlatform(bd.DockerImageSpec, cluster)
+	if IsMultiPlatform(spec) {
+		return container.TaggedRefs{}, false, nil
+	}
+
+	refs, err := ib.inputHashRefs(iTarget, cluster, inputHash)
+	if err != nil {
+		return container.TaggedRefs{}, false, err
+	}
+
+	exists, err := ib.db.ImageExists(ctx, refs.LocalRef)
+	if err != nil {
+		logger.Get(ctx).Debugf("%v", err)
+	}
+	if exists {
+		pushStage := ib.push(ctx, refs, ps, iTarget, cluster)
+		if pushStage != nil && pushStage.Error != "" {
+			return container.TaggedRefs{}, false, errors.New(pushStage.Error)
+		}
+		return refs, true, nil
+	}
+
+	if !ib.pushesToRegistry(refs, iTarget, cluster) {
+		return container.TaggedRefs{}, false, nil
+	}
+
+	platform := platforms.DefaultSpec()
+	if spec.Platform != "" {
+		platform, err = platforms.Parse(spec.Platform)
+		if err != nil {
+			return container.TaggedRefs{}, false, errors.Wrapf(err, "invalid platform %q", spec.Platform)
+		}
+	}
+
+	// Any error here most likely means the image isn't in the registry.
+	_, err = ib.db.dCli.ManifestDescriptor(ctx, refs.LocalRef, platform)
+	if err != nil {
+		logger.Get(ctx).Debugf("Image %s not found in registry: %v", container.FamiliarString(refs.LocalRef), err)
+		return container.TaggedRefs{}, false, nil
+	}
+	return refs, true, nil
+}
+
+// Tags a newly built image with the hash of its inputs, so that
+// FindImageByInputHash can find it later.
+//
+// If the cluster pulls images from a registry, we push the new tag as well.
+// The layers are already there, so this is cheap.
+func (ib *ImageBuilder) TagInputHash(ctx context.Context,
+	iTarget model.ImageTarget,
+	cluster *v1alpha1.Cluster,
+	built container.TaggedRefs,
+	inputHash string) error {
+	if !iTarget.IsDockerBuild() || inputHash == "" {
+		return nil
+	}
+
+	refs, err := ib.inputHashRefs(iTarget, cluster, inputHash)
+	if err != nil {
+		return err
+	}
+
+	_, err = ib.db.dCli.ImageTag(ctx, dockerclient.ImageTagOptions{
+		Source: built.LocalRef.String(),
+		Target: refs.LocalRef.String(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "docker tag")
+	}
+
+	if !ib.pushesToRegistry(refs, iTarget, cluster) {
+		return nil
+	}
+	return ib.db.PushImage(ctx, refs.LocalRef)
+}
+
+func (ib *ImageBuilder) inputHashRefs(iTarget model.ImageTarget, cluster *v1alpha1.Cluster, inputHash string) (container.TaggedRefs, error) {
+	refs, err := iTarget.Refs(cluster)
+	if err != nil {
+		return container.TaggedRefs{}, err
+	}
+	return refs.AddTagSuffix(InputHashTag(inputHash))
+}
+
+// Whether push() would push the image to a registry with the Docker client,
+// rather than loading it into the cluster some other way.
+func (ib *ImageBuilder) pushesToRegistry(refs container.TaggedRefs, iTarget model.ImageTarget, cluster *v1alpha1.Cluster) bool {
+	isDC := cluster != nil &&
+		cluster.Spec.Connection != nil &&
+		cluster.Spec.Connection.Docker != nil
+	if isDC || iTarget.ClusterNeeds() != v1alpha1.ClusterImageNeedsPush {
+		return false
+	}
+	if ib.db.WillBuildToKubeContext(k8s.KubeContext(k8sConnStatus(cluster).Context)) {
+		return false
+	}
+	return !ib.shouldLoadIntoCluster(refs, cluster) && !ib.shouldUseKINDLoad(refs, cluster)
+}
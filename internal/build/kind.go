@@ -14,12 +14,35 @@ import (
 
 type KINDLoader interface {
 	LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error
+
+	// Loads an image tarball (e.g., from `docker save`) into KIND.
+	//
+	// Used when the image isn't in a Docker daemon that the kind CLI can read from
+	// (e.g., when building with podman).
+	LoadArchiveToKIND(ctx context.Context, cluster *v1alpha1.Cluster, path string) error
 }
 
 type cmdKINDLoader struct {
 }
 
 func (kl *cmdKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
+	return kl.run(ctx, "docker-image", ref.String(), kindClusterName(cluster))
+}
+
+func (kl *cmdKINDLoader) LoadArchiveToKIND(ctx context.Context, cluster *v1alpha1.Cluster, path string) error {
+	return kl.run(ctx, "image-archive", path, kindClusterName(cluster))
+}
+
+func (kl *cmdKINDLoader) run(ctx context.Context, subcommand, arg, kindName string) error {
+	cmd := exec.CommandContext(ctx, "kind", "load", subcommand, arg, "--name", kindName)
+	w := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.InfoLvl))
+	cmd.Stdout = w
+	cmd.Stderr = w
+
+	return cmd.Run()
+}
+
+func kindClusterName(cluster *v1alpha1.Cluster) string {
 	// In Kind5, --name specifies the name of the cluster in the kubeconfig.
 	// In Kind6, the -name parameter is prefixed with 'kind-' before being written to/read from the kubeconfig
 	k8sConn := k8sConnStatus(cluster)
@@ -27,13 +50,7 @@ func (kl *cmdKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Clust
 	if k8sConn.Product == string(clusterid.ProductKIND) {
 		kindName = strings.TrimPrefix(kindName, "kind-")
 	}
-
-	cmd := exec.CommandContext(ctx, "kind", "load", "docker-image", ref.String(), "--name", kindName)
-	w := logger.NewMutexWriter(logger.Get(ctx).Writer(logger.InfoLvl))
-	cmd.Stdout = w
-	cmd.Stderr = w
-
-	return cmd.Run()
+	return kindName
 }
 
 func NewKINDLoader() KINDLoader {
@@ -0,0 +1,152 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	dockerclient "github.com/moby/moby/client"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	ktypes "k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Builds the image once for each platform, pushes each image, then pushes a
+// manifest list that refers to all of them.
+//
+// The Docker engine can only build one platform at a time, and can't store
+// manifest lists, so the manifest list only exists in the registry.
+//
+// The returned ClusterRef is pinned to the digest of the manifest list. The
+// returned LocalRef points to the image for the first platform, so that other
+// local builds can use it.
+func (d *DockerBuilder) BuildMultiPlatformImage(ctx context.Context, ps *PipelineState, refs container.RefSet,
+	spec v1alpha1.DockerImageSpec,
+	imageMaps map[ktypes.NamespacedName]*v1alpha1.ImageMap,
+	filter model.PathMatcher) (container.TaggedRefs, []v1alpha1.DockerImageStageStatus, error) {
+	spec, err := InjectImageDependencies(spec, imageMaps)
+	if err != nil {
+		return container.TaggedRefs{}, nil, err
+	}
+
+	platformStrs := SplitPlatforms(spec.Platform)
+	logger.Get(ctx).Infof("Building Dockerfile for platforms %s:\n%s\n",
+		strings.Join(platformStrs, ", "), indent(spec.DockerfileContents, "  "))
+
+	var allStages []v1alpha1.DockerImageStageStatus
+	var digests []digest.Digest
+	var manifests []ocispec.Descriptor
+	for _, platformStr := range platformStrs {
+		platform, err := platforms.Parse(platformStr)
+		if err != nil {
+			return container.TaggedRefs{}, allStages, errors.Wrapf(err, "invalid platform %q", platformStr)
+		}
+
+		platformSpec := spec
+		platformSpec.Platform = platformStr
+
+		ps.StartBuildStep(ctx, "Building image for %s", platformStr)
+		dig, stages, err := d.buildToDigestWithRetry(ps.AttachLogger(ctx), platformSpec, filter)
+		allStages = append(allStages, stages...)
+		if err != nil {
+			return container.TaggedRefs{}, allStages, err
+		}
+
+		tagged, err := d.TagRefs(ctx, refs, dig)
+		if err != nil {
+			return container.TaggedRefs{}, allStages, errors.Wrap(err, "docker tag")
+		}
+
+		ps.StartBuildStep(ctx, "Pushing image for %s", platformStr)
+		err = d.PushImage(ps.AttachLogger(ctx), tagged.LocalRef)
+		if err != nil {
+			return container.TaggedRefs{}, allStages, err
+		}
+
+		desc, err := d.dCli.ManifestDescriptor(ctx, tagged.LocalRef, platform)
+		if err != nil {
+			return container.TaggedRefs{}, allStages, err
+		}
+
+		digests = append(digests, dig)
+		manifests = append(manifests, desc)
+	}
+
+	ps.StartBuildStep(ctx, "Pushing manifest list")
+	ctx = ps.AttachLogger(ctx)
+	indexDesc, indexData, err := newManifestList(manifests)
+	if err != nil {
+		return container.TaggedRefs{}, allStages, err
+	}
+
+	tag, err := digestAsTag(indexDesc.Digest)
+	if err != nil {
+		return container.TaggedRefs{}, allStages, errors.Wrap(err, "manifest list tag")
+	}
+	tagged, err := refs.AddTagSuffix(tag)
+	if err != nil {
+		return container.TaggedRefs{}, allStages, errors.Wrap(err, "manifest list tag")
+	}
+
+	err = d.dCli.ManifestPush(ctx, tagged.LocalRef, indexDesc, indexData)
+	if err != nil {
+		return container.TaggedRefs{}, allStages, err
+	}
+	logger.Get(ctx).Infof("Pushed manifest list %s", indexDesc.Digest)
+
+	_, err = d.dCli.ImageTag(ctx, dockerclient.ImageTagOptions{Source: digests[0].String(), Target: tagged.LocalRef.String()})
+	if err != nil {
+		return container.TaggedRefs{}, allStages, errors.Wrap(err, "docker tag")
+	}
+
+	pinned, err := reference.WithDigest(tagged.ClusterRef, indexDesc.Digest)
+	if err != nil {
+		return container.TaggedRefs{}, allStages, errors.Wrap(err, "manifest list ref")
+	}
+	clusterRef, ok := pinned.(reference.NamedTagged)
+	if !ok {
+		return container.TaggedRefs{}, allStages, fmt.Errorf("manifest list ref %s has no tag", pinned)
+	}
+
+	if len(spec.CacheTo) > 0 {
+		// The cache of each platform would overwrite the others, so we only
+		// export the cache of the first platform.
+		ps.StartBuildStep(ctx, "Exporting build cache")
+		err = d.exportCaches(ctx, spec, digests[0])
+		if err != nil {
+			return container.TaggedRefs{}, allStages, err
+		}
+	}
+
+	return container.TaggedRefs{LocalRef: tagged.LocalRef, ClusterRef: clusterRef}, allStages, nil
+}
+
+// Creates an OCI image index from the manifests of each platform.
+func newManifestList(manifests []ocispec.Descriptor) (ocispec.Descriptor, []byte, error) {
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: manifests,
+	}
+	data, err := json.Marshal(index)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, errors.Wrap(err, "manifest list")
+	}
+
+	desc := ocispec.Descriptor{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Digest:    digest.FromBytes(data),
+		Size:      int64(len(data)),
+	}
+	return desc, data, nil
+}
@@ -0,0 +1,86 @@
+package build
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestInjectClusterPlatform(t *testing.T) {
+	cluster := &v1alpha1.Cluster{
+		Status: v1alpha1.ClusterStatus{Arch: "arm64", Arches: []string{"amd64", "arm", "arm64", "sparc"}},
+	}
+
+	type tc struct {
+		platform string
+		cluster  *v1alpha1.Cluster
+		expected string
+	}
+	tcs := []tc{
+		{"", cluster, "linux/arm64"},
+		{"", nil, ""},
+		{"linux/amd64", cluster, "linux/amd64"},
+		{"cluster-all", cluster, "linux/amd64,linux/arm/v7,linux/arm64"},
+		{"cluster-all", &v1alpha1.Cluster{Status: v1alpha1.ClusterStatus{Arch: "amd64"}}, "linux/amd64"},
+		{"cluster-all", nil, ""},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.platform, func(t *testing.T) {
+			spec := InjectClusterPlatform(v1alpha1.DockerImageSpec{Platform: tc.platform}, tc.cluster)
+			assert.Equal(t, tc.expected, spec.Platform)
+		})
+	}
+}
+
+func TestBuildMultiPlatformImage(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+	f.WriteFile("Dockerfile", "FROM alpine")
+
+	spec := v1alpha1.DockerImageSpec{
+		DockerfileContents: "FROM alpine",
+		Context:            f.Path(),
+		Platform:           "linux/amd64,linux/arm64",
+	}
+	refs, _, err := f.b.BuildMultiPlatformImage(f.ctx, f.ps, f.getNameFromTest(), spec, nil, model.EmptyMatcher)
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, f.fakeDocker.BuildCount)
+	assert.Equal(t, 2, f.fakeDocker.PushCount)
+	require.Len(t, f.fakeDocker.PushedManifests, 1)
+
+	data := f.fakeDocker.PushedManifests[refs.LocalRef.String()]
+	var index ocispec.Index
+	require.NoError(t, json.Unmarshal(data, &index))
+	assert.Equal(t, ocispec.MediaTypeImageIndex, index.MediaType)
+	require.Len(t, index.Manifests, 2)
+	assert.Equal(t, "amd64", index.Manifests[0].Platform.Architecture)
+	assert.Equal(t, "arm64", index.Manifests[1].Platform.Architecture)
+
+	// The cluster ref is pinned to the manifest list.
+	canonical, ok := refs.ClusterRef.(reference.Canonical)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(refs.ClusterRef.Tag(), "tilt-"))
+	assert.Equal(t, "sha256:", canonical.Digest().String()[:len("sha256:")])
+	assert.True(t, digestMatchesRef(refs.LocalRef, canonical.Digest()))
+}
+
+func TestBuildMultiPlatformImageInvalidPlatform(t *testing.T) {
+	f := newFakeDockerBuildFixture(t)
+
+	spec := v1alpha1.DockerImageSpec{
+		DockerfileContents: "FROM alpine",
+		Context:            f.Path(),
+		Platform:           "linux/amd64,bogus/os/arch/extra",
+	}
+	_, _, err := f.b.BuildMultiPlatformImage(f.ctx, f.ps, f.getNameFromTest(), spec, nil, model.EmptyMatcher)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid platform")
+}
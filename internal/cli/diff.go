@@ -0,0 +1,221 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/controllers/apis/imagemap"
+	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
+	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store/kubernetesapplys"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type diffCmd struct {
+	streams genericclioptions.IOStreams
+	confirm bool
+}
+
+var _ tiltCmd = &diffCmd{}
+
+func newDiffCmd(streams genericclioptions.IOStreams) *diffCmd {
+	return &diffCmd{streams: streams}
+}
+
+func (c *diffCmd) name() model.TiltSubcommand { return "diff" }
+
+func (c *diffCmd) register() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff [RESOURCE...]",
+		Short: "Show how the Kubernetes objects of resources differ from the cluster",
+		Long: `Show how applying the Kubernetes objects of resources would change the cluster.
+
+Runs a server-side dry-run apply of the objects that Tilt would deploy,
+with the current images, and prints the diff against the live objects.
+Also lists the objects from earlier applies that aren't in the resource
+anymore, which the next apply will prune.
+With no arguments, diffs all resources with Kubernetes YAML.
+
+With --confirm, confirms the pending diff of a resource that was
+configured with k8s_resource(confirm_diff_kinds=...), so that the apply
+can continue.
+
+# show the diff of the 'frontend' resource
+tilt diff frontend
+
+# apply the changes that 'frontend' is waiting on
+tilt diff --confirm frontend
+`,
+	}
+
+	addConnectServerFlags(cmd)
+	cmd.Flags().BoolVar(&c.confirm, "confirm", false, "Confirm the diff that the resources are waiting on")
+	return cmd
+}
+
+func (c *diffCmd) run(ctx context.Context, args []string) error {
+	a := analytics.Get(ctx)
+	cmdTags := engineanalytics.CmdTags(map[string]string{})
+	cmdTags["confirm"] = strconv.FormatBool(c.confirm)
+	a.Incr("cmd.diff", cmdTags.AsMap())
+	defer a.Flush(time.Second)
+
+	ctrlclient, err := newClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	if c.confirm {
+		if len(args) == 0 {
+			return errors.New("must specify at least one resource to confirm")
+		}
+		for _, name := range args {
+			err := c.confirmDiff(ctx, ctrlclient, name)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	kas, err := c.kubernetesApplies(ctx, ctrlclient, args)
+	if err != nil {
+		return err
+	}
+
+	var kCli k8s.Client
+	var ns k8s.Namespace
+	for _, ka := range kas {
+		if ka.Spec.YAML == "" {
+			if len(args) > 0 {
+				_, _ = fmt.Fprintf(c.streams.Out, "%s: skipped, deployed with a custom command\n\n", ka.Name)
+			}
+			continue
+		}
+
+		imageMaps, err := imagemap.NamesToObjects(ctx, ctrlclient, ka.Spec.ImageMaps)
+		if err != nil {
+			return err
+		}
+		if !imagesReady(ka.Spec.ImageMaps, imageMaps) {
+			_, _ = fmt.Fprintf(c.streams.Out, "%s: skipped, images not built yet\n\n", ka.Name)
+			continue
+		}
+
+		entities, err := kubernetesapply.EntitiesToDeploy(ctx, imageMaps, ka.Spec)
+		if err != nil {
+			return errors.Wrapf(err, "%s", ka.Name)
+		}
+
+		if kCli == nil {
+			kCli, err = wireK8sClient(ctx)
+			if err != nil {
+				return err
+			}
+			ns, err = wireNamespace(ctx)
+			if err != nil {
+				return err
+			}
+		}
+
+		diffs, err := kCli.Diff(ctx, entities, kubernetesapply.FieldManager)
+		if err != nil {
+			return errors.Wrapf(err, "%s", ka.Name)
+		}
+		_, _ = fmt.Fprintf(c.streams.Out, "%s:\n%s", ka.Name, k8s.FormatDiffs(diffs))
+
+		toPrune, err := kubernetesapply.PruneCandidates(ctx, kCli, ka.Spec.ApplySet, ns, entities, nil)
+		if err != nil {
+			return errors.Wrapf(err, "%s", ka.Name)
+		}
+		if len(toPrune) > 0 {
+			_, _ = fmt.Fprintf(c.streams.Out, "Objects removed from the resource, which the next apply will prune:\n")
+			for _, name := range k8s.UniqueNames(toPrune, 2) {
+				_, _ = fmt.Fprintf(c.streams.Out, "  → %s\n", name)
+			}
+		}
+		_, _ = fmt.Fprintln(c.streams.Out)
+	}
+	return nil
+}
+
+// Marks the diff that the resource is waiting on as confirmed.
+func (c *diffCmd) confirmDiff(ctx context.Context, ctrlclient client.Client, name string) error {
+	var ka v1alpha1.KubernetesApply
+	err := ctrlclient.Get(ctx, types.NamespacedName{Name: name}, &ka)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("no such resource %q", name)
+		}
+		return err
+	}
+
+	hash := ka.Status.DiffAwaitingConfirmation
+	if hash == "" {
+		return fmt.Errorf("%s: no diff awaiting confirmation", name)
+	}
+
+	_, _ = fmt.Fprintf(c.streams.Out, "%s:\n%s\n", name, ka.Status.Diff)
+
+	err = kubernetesapplys.ConfirmDiff(ctx, ctrlclient, name, hash)
+	if err != nil {
+		return err
+	}
+
+	_, _ = fmt.Fprintf(c.streams.Out, "Confirmed diff of %s\n", name)
+	return nil
+}
+
+// Returns the KubernetesApply objects with the given names, or all of them
+// if there are no names.
+func (c *diffCmd) kubernetesApplies(ctx context.Context, ctrlclient client.Client, names []string) ([]v1alpha1.KubernetesApply, error) {
+	var list v1alpha1.KubernetesApplyList
+	err := ctrlclient.List(ctx, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]v1alpha1.KubernetesApply)
+	for _, ka := range list.Items {
+		byName[ka.Name] = ka
+	}
+
+	if len(names) == 0 {
+		result := list.Items
+		sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+		return result, nil
+	}
+
+	var result []v1alpha1.KubernetesApply
+	for _, name := range names {
+		ka, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("no Kubernetes objects for resource %q", name)
+		}
+		result = append(result, ka)
+	}
+	return result, nil
+}
+
+func imagesReady(names []string, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) bool {
+	for _, name := range names {
+		im, ok := imageMaps[types.NamespacedName{Name: name}]
+		if !ok || im.Status.Image == "" {
+			return false
+		}
+	}
+	return true
+}
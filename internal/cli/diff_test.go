@@ -0,0 +1,61 @@
+package cli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+func TestDiffConfirm(t *testing.T) {
+	f := newServerFixture(t)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec:       v1alpha1.KubernetesApplySpec{YAML: "fake-yaml"},
+	}
+	require.NoError(t, f.client.Create(f.ctx, &ka))
+	ka.Status.Diff = "frontend:deployment:\n-  replicas: 1\n+  replicas: 2\n"
+	ka.Status.DiffAwaitingConfirmation = "abc123"
+	require.NoError(t, f.client.Status().Update(f.ctx, &ka))
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newDiffCmd(streams)
+	c := cmd.register()
+	require.NoError(t, c.Flags().Parse([]string{"--confirm", "frontend"}))
+	require.NoError(t, cmd.run(f.ctx, c.Flags().Args()))
+
+	assert.Contains(t, out.String(), "+  replicas: 2")
+	assert.Contains(t, out.String(), "Confirmed diff of frontend")
+
+	require.NoError(t, f.client.Get(f.ctx, types.NamespacedName{Name: "frontend"}, &ka))
+	assert.Equal(t, "abc123", ka.Annotations[v1alpha1.AnnotationConfirmedDiff])
+}
+
+func TestDiffConfirmNothingPending(t *testing.T) {
+	f := newServerFixture(t)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "frontend"},
+		Spec:       v1alpha1.KubernetesApplySpec{YAML: "fake-yaml"},
+	}
+	require.NoError(t, f.client.Create(f.ctx, &ka))
+
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	cmd := newDiffCmd(streams)
+	c := cmd.register()
+	require.NoError(t, c.Flags().Parse([]string{"--confirm", "frontend"}))
+
+	err := cmd.run(f.ctx, c.Flags().Args())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "frontend: no diff awaiting confirmation")
+
+	err = cmd.run(f.ctx, []string{"backend"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `no such resource "backend"`)
+}
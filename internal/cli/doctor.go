@@ -72,6 +72,7 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 		printField("Host", host, nil)
 
 		version, err := clusterDocker.ServerVersion(ctx)
+		printField("Runtime", docker.RuntimeFromServerVersion(version), err)
 		printField("Server Version", version.Version, err)
 		printField("API Version", version.APIVersion, err)
 
@@ -94,6 +95,7 @@ func (c *doctorCmd) run(ctx context.Context, args []string) error {
 			printField("Host", host, nil)
 
 			version, err := localDocker.ServerVersion(ctx)
+			printField("Runtime", docker.RuntimeFromServerVersion(version), err)
 			printField("Server Version", version.Version, err)
 			printField("Version", version.APIVersion, err)
 
@@ -101,6 +101,8 @@ func addLogFilterFlags(cmd *cobra.Command, prefix string) {
 			return completions, cobra.ShellCompDirectiveNoFileComp
 		},
 	)
+	cmd.Flags().StringVar(&logGrepFlag, prefix+"grep", "", `Only show logs that match this regular expression (e.g., "error|panic")`)
+	cmd.Flags().StringArrayVar(&logFieldFlag, prefix+"field", nil, `Only show structured logs with this field, as key=value (e.g., "request_id=abc"). May be repeated`)
 	cmd.Flags().StringVar(&logSourceFlag, prefix+"source", defaultLogSource, `Specify a log source. One of "all", "build", "runtime"`)
 	_ = cmd.RegisterFlagCompletionFunc(
 		prefix+"source",
@@ -126,6 +128,7 @@ func addLogOutputFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVar(&logSinceFlag, "since", "", `Only show logs since duration ago (e.g., "5m", "1h", "30s")`)
 	cmd.Flags().IntVar(&logTailFlag, "tail", -1, `Number of lines to show from the end of logs (-1 for all)`)
 	cmd.Flags().BoolVar(&logJSONFlag, "json", false, `Output logs in JSON Lines format`)
+	cmd.Flags().StringVarP(&logOutputFlag, "output", "o", "text", `Output format. One of "text", "json". With "json", each log line is printed as a JSON object, without ANSI colors`)
 }
 
 var kubeContextOverride string
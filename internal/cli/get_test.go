@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"testing"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,11 +16,14 @@ import (
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/testdata"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
 	"github.com/tilt-dev/tilt/internal/token"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -125,8 +129,10 @@ func newServerFixture(t *testing.T) *serverFixture {
 	state.Token = token.Token(token.Load())
 	st.UnlockMutableState()
 
+	fs := afero.NewMemMapFs()
+	archive := logarchive.NewArchive(xdg.NewFakeBase(f.Path(), fs), fs, "test", false)
 	hudServer, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta,
-		server.NewWebsocketList(), client)
+		server.NewWebsocketList(), client, metrics.NewMetrics(st), archive)
 	require.NoError(t, err)
 
 	cfgAccess := server.ProvideConfigAccess(dir)
@@ -0,0 +1,191 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+)
+
+// The apply and delete commands of helm_release() in the Tiltfile.
+//
+// Not intended to be run by hand, so hidden from the help.
+func newHelmReleaseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "helm-release",
+		Short:  "Manage a Helm release deployed by helm_release() in a Tiltfile",
+		Hidden: true,
+	}
+	cmd.AddCommand(newHelmReleaseApplyCmd())
+	cmd.AddCommand(newHelmReleaseDeleteCmd())
+	return cmd
+}
+
+type helmReleaseApplyCmd struct {
+	namespace string
+	imageKeys []string
+
+	run    commandRunner
+	stdout io.Writer
+}
+
+// Runs a command with the given stdin and stdout. Stderr always goes to our stderr.
+type commandRunner func(ctx context.Context, name string, args []string, stdin io.Reader, stdout io.Writer) error
+
+func newHelmReleaseApplyCmd() *cobra.Command {
+	c := &helmReleaseApplyCmd{run: runCommand, stdout: os.Stdout}
+	cmd := &cobra.Command{
+		Use:   "apply RELEASE CHART [-- HELM_FLAGS...]",
+		Short: "Install or upgrade the release, then print its objects",
+		Long: `Runs 'helm upgrade --install', then prints the objects of the release to stdout,
+as they exist in the cluster.
+
+Image #i from the TILT_IMAGE_i env var is passed to the chart under the i-th --image-key.
+An image key of the form 'repoKey,tagKey' splits the image into a repository and a tag.
+`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.apply(cmd.Context(), args)
+		},
+	}
+	cmd.Flags().StringVarP(&c.namespace, "namespace", "n", "", "Namespace of the release")
+	cmd.Flags().StringArrayVar(&c.imageKeys, "image-key", nil, "Chart value to set to the image #i")
+	return cmd
+}
+
+func (c *helmReleaseApplyCmd) apply(ctx context.Context, args []string) error {
+	release, chart, helmFlags := args[0], args[1], args[2:]
+
+	imageArgs, err := helmImageSetArgs(c.imageKeys, os.Getenv)
+	if err != nil {
+		return err
+	}
+
+	upgradeArgs := []string{"upgrade", "--install", release, chart}
+	if c.namespace != "" {
+		upgradeArgs = append(upgradeArgs, "--namespace", c.namespace, "--create-namespace")
+	}
+	upgradeArgs = append(upgradeArgs, helmFlags...)
+	upgradeArgs = append(upgradeArgs, imageArgs...)
+
+	// Tilt reads the applied objects from stdout, so all of helm's
+	// own output goes to stderr.
+	err = c.run(ctx, "helm", upgradeArgs, nil, os.Stderr)
+	if err != nil {
+		return errors.Wrap(err, "helm upgrade")
+	}
+
+	manifest := &bytes.Buffer{}
+	err = c.run(ctx, "helm", helmNamespaced([]string{"get", "manifest", release}, c.namespace), nil, manifest)
+	if err != nil {
+		return errors.Wrap(err, "helm get manifest")
+	}
+	if strings.TrimSpace(manifest.String()) == "" {
+		return nil
+	}
+
+	// The manifest is template output, without UIDs and often without
+	// namespaces. Tilt needs both to find the pods of the release, so
+	// read the live objects back from the cluster.
+	getArgs := helmNamespaced([]string{"get", "-f", "-", "-o", "yaml"}, c.namespace)
+	err = c.run(ctx, "kubectl", getArgs, manifest, c.stdout)
+	if err != nil {
+		return errors.Wrap(err, "kubectl get")
+	}
+	return nil
+}
+
+type helmReleaseDeleteCmd struct {
+	namespace string
+}
+
+func newHelmReleaseDeleteCmd() *cobra.Command {
+	c := &helmReleaseDeleteCmd{}
+	cmd := &cobra.Command{
+		Use:   "delete RELEASE",
+		Short: "Uninstall the release, if it exists",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return c.run(cmd.Context(), args)
+		},
+	}
+	cmd.Flags().StringVarP(&c.namespace, "namespace", "n", "", "Namespace of the release")
+	return cmd
+}
+
+func (c *helmReleaseDeleteCmd) run(ctx context.Context, args []string) error {
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, "helm", helmNamespaced([]string{"uninstall", args[0]}, c.namespace)...)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = io.MultiWriter(os.Stderr, stderr)
+	err := cmd.Run()
+	if err != nil && strings.Contains(stderr.String(), "not found") {
+		// Deletes must be idempotent, like `kubectl delete --ignore-not-found`.
+		return nil
+	}
+	if err != nil {
+		return errors.Wrap(err, "helm uninstall")
+	}
+	return nil
+}
+
+// Converts the image keys into --set-string flags, with the images
+// from the TILT_IMAGE_i env vars.
+func helmImageSetArgs(imageKeys []string, getenv func(string) string) ([]string, error) {
+	var result []string
+	for i, key := range imageKeys {
+		envVar := fmt.Sprintf("TILT_IMAGE_%d", i)
+		image := getenv(envVar)
+		if image == "" {
+			return nil, fmt.Errorf("image key %q: %s not set", key, envVar)
+		}
+
+		repoKey, tagKey, isPair := strings.Cut(key, ",")
+		if !isPair {
+			result = append(result, "--set-string", fmt.Sprintf("%s=%s", key, image))
+			continue
+		}
+
+		repo, tag := splitImageTag(image)
+		result = append(result,
+			"--set-string", fmt.Sprintf("%s=%s", repoKey, repo),
+			"--set-string", fmt.Sprintf("%s=%s", tagKey, tag))
+	}
+	return result, nil
+}
+
+// Splits an image ref into the repository and everything after it.
+//
+// The tag includes any digest, so that charts that render "repo:tag"
+// get the full ref.
+func splitImageTag(image string) (string, string) {
+	nameEnd := len(image)
+	if i := strings.Index(image, "@"); i >= 0 {
+		nameEnd = i
+	}
+	if i := strings.LastIndex(image[:nameEnd], ":"); i > strings.LastIndex(image[:nameEnd], "/") {
+		nameEnd = i
+	}
+	return image[:nameEnd], strings.TrimPrefix(image[nameEnd:], ":")
+}
+
+func helmNamespaced(args []string, namespace string) []string {
+	if namespace == "" {
+		return args
+	}
+	return append(args, "--namespace", namespace)
+}
+
+func runCommand(ctx context.Context, name string, args []string, stdin io.Reader, stdout io.Writer) error {
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
@@ -0,0 +1,124 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+)
+
+func TestHelmImageSetArgs(t *testing.T) {
+	env := map[string]string{
+		"TILT_IMAGE_0": "localhost:5000/frontend:tilt-1234",
+		"TILT_IMAGE_1": "gcr.io/proj/backend:tilt-5678@sha256:abcd",
+	}
+	args, err := helmImageSetArgs([]string{"frontend.image", "backend.image.repository,backend.image.tag"},
+		func(k string) string { return env[k] })
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"--set-string", "frontend.image=localhost:5000/frontend:tilt-1234",
+		"--set-string", "backend.image.repository=gcr.io/proj/backend",
+		"--set-string", "backend.image.tag=tilt-5678@sha256:abcd",
+	}, args)
+}
+
+func TestHelmImageSetArgsMissingImage(t *testing.T) {
+	_, err := helmImageSetArgs([]string{"image"}, func(k string) string { return "" })
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `image key "image": TILT_IMAGE_0 not set`)
+	}
+}
+
+func TestSplitImageTag(t *testing.T) {
+	for _, tc := range []struct {
+		image, repo, tag string
+	}{
+		{"frontend:tilt-1234", "frontend", "tilt-1234"},
+		{"localhost:5000/frontend:tilt-1234", "localhost:5000/frontend", "tilt-1234"},
+		{"localhost:5000/frontend", "localhost:5000/frontend", ""},
+		{"frontend:tilt-1234@sha256:abcd", "frontend", "tilt-1234@sha256:abcd"},
+	} {
+		t.Run(tc.image, func(t *testing.T) {
+			repo, tag := splitImageTag(tc.image)
+			assert.Equal(t, tc.repo, repo)
+			assert.Equal(t, tc.tag, tag)
+		})
+	}
+}
+
+func TestHelmReleaseApplyPrintsLiveObjects(t *testing.T) {
+	manifest := `---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: app
+`
+	live := `apiVersion: v1
+kind: List
+items:
+- apiVersion: apps/v1
+  kind: Deployment
+  metadata:
+    name: app
+    namespace: app-ns
+    uid: 7c1e5f2a-1111-2222-3333-444455556666
+`
+
+	var calls []string
+	out := &bytes.Buffer{}
+	c := &helmReleaseApplyCmd{
+		namespace: "app-ns",
+		stdout:    out,
+		run: func(ctx context.Context, name string, args []string, stdin io.Reader, stdout io.Writer) error {
+			calls = append(calls, name+" "+strings.Join(args, " "))
+			switch {
+			case name == "helm" && args[0] == "get":
+				_, _ = stdout.Write([]byte(manifest))
+			case name == "kubectl":
+				in, err := io.ReadAll(stdin)
+				require.NoError(t, err)
+				assert.Equal(t, manifest, string(in))
+				_, _ = stdout.Write([]byte(live))
+			}
+			return nil
+		},
+	}
+
+	err := c.apply(context.Background(), []string{"app", "./chart", "--wait"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"helm upgrade --install app ./chart --namespace app-ns --create-namespace --wait",
+		"helm get manifest app --namespace app-ns",
+		"kubectl get -f - -o yaml --namespace app-ns",
+	}, calls)
+
+	entities, err := k8s.ParseYAMLFromString(out.String())
+	require.NoError(t, err)
+	require.Len(t, entities, 1)
+	assert.Equal(t, "app-ns", entities[0].Meta().GetNamespace())
+	assert.Equal(t, "7c1e5f2a-1111-2222-3333-444455556666", string(entities[0].UID()))
+}
+
+func TestHelmReleaseApplyEmptyManifest(t *testing.T) {
+	out := &bytes.Buffer{}
+	c := &helmReleaseApplyCmd{
+		stdout: out,
+		run: func(ctx context.Context, name string, args []string, stdin io.Reader, stdout io.Writer) error {
+			if name == "kubectl" {
+				t.Fatalf("unexpected kubectl call: %v", args)
+			}
+			return nil
+		},
+	}
+
+	err := c.apply(context.Background(), []string{"app", "./chart"})
+	require.NoError(t, err)
+	assert.Equal(t, "", out.String())
+}
@@ -34,6 +34,20 @@ func (c *logsCmd) register() *cobra.Command {
 
 By default, looks for a running Tilt instance on localhost:10350
 (this is configurable with the --port and --host flags).
+
+Logs are filtered by the Tilt instance, so only matching logs are sent.
+With --output=json, each log line is printed as a JSON object, for
+scripts and editors to consume.
+`,
+		Example: `
+# Streams runtime errors from all resources.
+tilt logs -f --level=error --source=runtime
+
+# Prints the last 10 minutes of frontend logs that match a regular expression.
+tilt logs --since=10m --grep='timeout|refused' frontend
+
+# Streams logs as JSON, one log line per line.
+tilt logs -f --output=json
 `,
 	}
 
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -116,6 +117,51 @@ func TestProvideLogTailValidation(t *testing.T) {
 	}
 }
 
+func TestProvideLogGrepValidation(t *testing.T) {
+	oldFlag := logGrepFlag
+	defer func() { logGrepFlag = oldFlag }()
+
+	logGrepFlag = ""
+	grep, err := provideLogGrep()
+	require.NoError(t, err)
+	assert.Nil(t, grep)
+
+	logGrepFlag = "err(or)?"
+	grep, err = provideLogGrep()
+	require.NoError(t, err)
+	assert.NotNil(t, grep)
+
+	logGrepFlag = "("
+	_, err = provideLogGrep()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--grep must be a valid regular expression")
+}
+
+func TestProvideLogOutputValidation(t *testing.T) {
+	oldOutput, oldJSON := logOutputFlag, logJSONFlag
+	defer func() { logOutputFlag, logJSONFlag = oldOutput, oldJSON }()
+
+	for _, tc := range []struct {
+		output   string
+		json     bool
+		expected bool
+	}{
+		{"text", false, false},
+		{"text", true, true},
+		{"json", false, true},
+	} {
+		logOutputFlag, logJSONFlag = tc.output, tc.json
+		actual, err := provideLogJSON()
+		require.NoError(t, err)
+		assert.Equal(t, tc.expected, bool(actual), "--output=%s --json=%v", tc.output, tc.json)
+	}
+
+	logOutputFlag = "yaml"
+	_, err := provideLogJSON()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `--output must be one of "text", "json", got "yaml"`)
+}
+
 func TestLogStream(t *testing.T) {
 	f := newServerFixture(t)
 	state := f.store.LockMutableStateForTesting()
@@ -141,8 +187,65 @@ func TestLogsWebsocketTokenRejected(t *testing.T) {
 	streams, _, _, _ := genericclioptions.NewTestIOStreams()
 	cmd := newLogsCmd(streams)
 	cmd.register()
+	cmd.follow = true
 	err := cmd.run(f.ctx, nil)
 
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "fetching websocket token")
 }
+
+func TestLogsQueryTokenRejected(t *testing.T) {
+	f := newServerFixture(t)
+
+	state := f.store.LockMutableStateForTesting()
+	state.Token = "a-different-token"
+	f.store.UnlockMutableState()
+
+	streams, _, _, _ := genericclioptions.NewTestIOStreams()
+	cmd := newLogsCmd(streams)
+	cmd.register()
+	err := cmd.run(f.ctx, nil)
+
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "fetching logs")
+	require.Contains(t, err.Error(), "invalid session token")
+}
+
+func TestLogStreamResources(t *testing.T) {
+	f := newServerFixture(t)
+	state := f.store.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("fe log\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be-span", logger.InfoLvl, nil, []byte("be log\n")), nil)
+	f.store.UnlockMutableState()
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newLogsCmd(streams)
+	cmd.register()
+	require.NoError(t, cmd.run(f.ctx, []string{"be"}))
+
+	require.Equal(t, "be log\n", out.String())
+}
+
+func TestLogStreamGrepJSON(t *testing.T) {
+	f := newServerFixture(t)
+	state := f.store.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("fe started\n")), nil)
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("fe crashed\n")), nil)
+	f.store.UnlockMutableState()
+
+	streams, _, out, _ := genericclioptions.NewTestIOStreams()
+	cmd := newLogsCmd(streams)
+	cmd.register()
+
+	oldGrep, oldOutput := logGrepFlag, logOutputFlag
+	defer func() { logGrepFlag, logOutputFlag = oldGrep, oldOutput }()
+	logGrepFlag = "crash"
+	logOutputFlag = "json"
+
+	require.NoError(t, cmd.run(f.ctx, nil))
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &line))
+	assert.Equal(t, "fe crashed", line["message"])
+	assert.Equal(t, "fe", line["resource"])
+}
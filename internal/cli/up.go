@@ -17,6 +17,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/controllers"
 	engineanalytics "github.com/tilt-dev/tilt/internal/engine/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
@@ -37,9 +39,14 @@ var (
 	logSourceFlag    string   = ""
 	logResourcesFlag []string = nil
 	logLevelFlag     string   = ""
+	logFieldFlag     []string = nil
+	logGrepFlag      string   = ""
 	logSinceFlag     string   = ""
 	logTailFlag      int      = -1 // -1 means no limit
 	logJSONFlag      bool     = false
+	logOutputFlag    string   = ""
+	persistStateFlag bool     = false
+	persistLogsFlag  bool     = false
 )
 
 var userExitError = errors.New("user requested Tilt exit")
@@ -95,6 +102,10 @@ local resources--i.e. those using serve_cmd--are terminated when you exit Tilt.
 	addLogFilterFlags(cmd, "log-")
 	addLogFilterResourcesFlag(cmd)
 	cmd.Flags().Lookup("logactions").Hidden = true
+	cmd.Flags().BoolVar(&persistStateFlag, "persist-state", false,
+		"If true, Tilt will save build history and UI state to disk, and restore them the next time it starts. Images whose inputs haven't changed won't be rebuilt.")
+	cmd.Flags().BoolVar(&persistLogsFlag, "persist-logs", false,
+		"If true, Tilt will keep logs that no longer fit in memory on disk, so that 'tilt logs' can still show the full history.")
 	cmd.Flags().StringVar(&c.outputSnapshotOnExit, "output-snapshot-on-exit", "", "If specified, Tilt will dump a snapshot of its state to the specified path when it exits")
 
 	return cmd
@@ -199,6 +210,14 @@ func provideLogActions() store.LogActionsFlag {
 	return store.LogActionsFlag(logActionsFlag)
 }
 
+func providePersistState() persist.EnabledFlag {
+	return persist.EnabledFlag(persistStateFlag)
+}
+
+func provideLogArchive() logarchive.EnabledFlag {
+	return logarchive.EnabledFlag(persistLogsFlag)
+}
+
 func provideWebMode(b model.TiltBuild) (model.WebMode, error) {
 	switch webModeFlag {
 	case model.LocalWebMode,
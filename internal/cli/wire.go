@@ -8,6 +8,8 @@ package cli
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/spf13/afero"
@@ -21,7 +23,6 @@ import (
 	"github.com/google/wire"
 	"github.com/jonboulle/clockwork"
 	"github.com/mattn/go-colorable"
-	sdktrace "go.opentelemetry.io/otel/sdk/trace"
 	"k8s.io/apimachinery/pkg/version"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -43,6 +44,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -108,6 +112,10 @@ var BaseWireSet = wire.NewSet(
 	cloud.WireSet,
 	cloudurl.ProvideAddress,
 	k8srollout.NewPodMonitor,
+	k8srollout.NewAutoRollback,
+	persist.NewPersister,
+	metrics.NewMetrics,
+	logarchive.NewArchive,
 	telemetry.NewStartTracker,
 	session.NewController,
 
@@ -116,6 +124,8 @@ var BaseWireSet = wire.NewSet(
 	provideLogSource,
 	provideLogResources,
 	provideLogLevel,
+	provideLogFields,
+	provideLogGrep,
 	provideLogSince,
 	provideLogTail,
 	provideLogJSON,
@@ -124,6 +134,8 @@ var BaseWireSet = wire.NewSet(
 	wire.Value(openurl.OpenURL(openurl.BrowserOpen)),
 
 	provideLogActions,
+	providePersistState,
+	provideLogArchive,
 	store.NewStore,
 	wire.Bind(new(store.RStore), new(*store.Store)),
 	wire.Bind(new(store.Dispatcher), new(*store.Store)),
@@ -153,7 +165,8 @@ var BaseWireSet = wire.NewSet(
 	provideAssetServer,
 
 	tracer.NewSpanCollector,
-	wire.Bind(new(sdktrace.SpanExporter), new(*tracer.SpanCollector)),
+	tracer.NewOTLPExporter,
+	tracer.ProvideSpanExporter,
 	wire.Bind(new(tracer.SpanSource), new(*tracer.SpanCollector)),
 
 	dirs.UseTiltDevDir,
@@ -312,6 +325,8 @@ var LogStreamerWireSet = wire.NewSet(
 	provideLogSource,
 	provideLogResources,
 	provideLogLevel,
+	provideLogFields,
+	provideLogGrep,
 	provideLogSince,
 	provideLogTail,
 	provideLogJSON,
@@ -387,6 +402,32 @@ func provideLogLevel() hudclient.FilterLevel {
 	}
 }
 
+func provideLogFields() (hudclient.FilterFields, error) {
+	if len(logFieldFlag) == 0 {
+		return nil, nil
+	}
+	result := hudclient.FilterFields{}
+	for _, field := range logFieldFlag {
+		k, v, ok := strings.Cut(field, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("--field must be in key=value format, got %q", field)
+		}
+		result[k] = v
+	}
+	return result, nil
+}
+
+func provideLogGrep() (hudclient.FilterGrep, error) {
+	if logGrepFlag == "" {
+		return nil, nil
+	}
+	re, err := regexp.Compile(logGrepFlag)
+	if err != nil {
+		return nil, fmt.Errorf("--grep must be a valid regular expression: %v", err)
+	}
+	return hudclient.FilterGrep(re), nil
+}
+
 func provideLogSince() (hudclient.FilterSince, error) {
 	if logSinceFlag == "" {
 		return hudclient.FilterSince{}, nil
@@ -409,6 +450,13 @@ func provideLogTail() (hudclient.FilterTail, error) {
 	return hudclient.FilterTail(logTailFlag), nil
 }
 
-func provideLogJSON() hudclient.FilterJSON {
-	return hudclient.FilterJSON(logJSONFlag)
+func provideLogJSON() (hudclient.FilterJSON, error) {
+	switch logOutputFlag {
+	case "", "text":
+		return hudclient.FilterJSON(logJSONFlag), nil
+	case "json":
+		return true, nil
+	default:
+		return false, fmt.Errorf("--output must be one of \"text\", \"json\", got %q", logOutputFlag)
+	}
 }
@@ -10,6 +10,7 @@ const (
 	RuntimeDocker      Runtime = "docker"
 	RuntimeContainerd  Runtime = "containerd"
 	RuntimeCrio        Runtime = "cri-o"
+	RuntimePodman      Runtime = "podman"
 	RuntimeUnknown     Runtime = "unknown"
 	RuntimeReadFailure Runtime = "read-failure"
 )
@@ -23,6 +24,8 @@ func RuntimeFromVersionString(s string) Runtime {
 		return RuntimeContainerd
 	case RuntimeCrio:
 		return RuntimeCrio
+	case RuntimePodman:
+		return RuntimePodman
 	}
 	return RuntimeUnknown
 }
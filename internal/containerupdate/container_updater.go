@@ -11,4 +11,8 @@ import (
 type ContainerUpdater interface {
 	UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
 		archiveToCopy io.Reader, filesToDelete []string, cmds []model.Cmd, hotReload bool) error
+
+	// HashFiles returns the sha256 checksums of the given files in the container,
+	// keyed by path. Paths that don't exist or aren't regular files are omitted.
+	HashFiles(ctx context.Context, cInfo liveupdates.Container, paths []string) (map[string]string, error)
 }
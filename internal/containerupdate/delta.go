@@ -0,0 +1,343 @@
+package containerupdate
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Delta sync compares files in fixed-size blocks. If the same block in the
+// container has the same checksum, we don't send it.
+//
+// This is simpler than the rsync algorithm (which uses rolling checksums
+// to find blocks that moved), but only needs `dd` and `sha256sum` in the
+// container, rather than a dedicated helper binary.
+const deltaBlockSize = 1 << 20
+
+// Files smaller than this are always copied whole.
+const deltaMinFileSize = 2 * deltaBlockSize
+
+// DeltaSyncer is implemented by container updaters that can copy
+// large files by sending only the blocks that changed.
+type DeltaSyncer interface {
+	WithDeltaSync() ContainerUpdater
+}
+
+// Prints the sha256 checksum of each block of a file, one per line.
+// Prints nothing if the file doesn't exist.
+//
+// Also clears out any blocks left over from an earlier sync that failed.
+const blockChecksumScript = `command -v dd >/dev/null 2>&1 && command -v sha256sum >/dev/null 2>&1 || exit 127
+bs=$1; f=$2
+rm -rf "$(dirname "$f")/.$(basename "$f").tilt-delta"
+[ -f "$f" ] || exit 0
+n=$(( ($(wc -c < "$f") + bs - 1) / bs ))
+i=0
+while [ $i -lt $n ]; do
+  dd if="$f" bs=$bs skip=$i count=1 2>/dev/null | sha256sum
+  i=$((i+1))
+done`
+
+// Re-assembles each file from the blocks we sent and the
+// blocks of the old file in the container.
+//
+// Arguments are the block size, followed by a (path, size, mode, block count)
+// tuple for each file.
+const deltaApplyScript = `set -e
+bs=$1; shift
+while [ $# -gt 0 ]; do
+  f=$1; size=$2; mode=$3; n=$4; shift 4
+  d="$(dirname "$f")/.$(basename "$f").tilt-delta"
+  # If no blocks changed, we didn't send the directory.
+  mkdir -p "$d"
+  tmp="$d/new"
+  : > "$tmp"
+  i=0
+  while [ $i -lt $n ]; do
+    if [ -f "$d/$i" ]; then
+      cat "$d/$i" >> "$tmp"
+    else
+      dd if="$f" bs=$bs skip=$i count=1 2>/dev/null >> "$tmp"
+    fi
+    i=$((i+1))
+  done
+  if [ $(($(wc -c < "$tmp"))) -ne "$size" ]; then
+    rm -rf "$d"
+    echo "$f: expected $size bytes after applying delta" >&2
+    exit 1
+  fi
+  chmod "$mode" "$tmp"
+  mv -f "$tmp" "$f"
+  rm -rf "$d"
+done`
+
+// A file that we're sending as a delta.
+type deltaFile struct {
+	path   string
+	size   int64
+	mode   int64
+	blocks int
+}
+
+// The directory in the container where we put the changed blocks of a file.
+func deltaDir(p string) string {
+	return path.Join(path.Dir(p), "."+path.Base(p)+".tilt-delta")
+}
+
+// Tracks containers that don't have the tools we need for delta sync,
+// so that we don't keep asking.
+type deltaUnsupportedSet struct {
+	mu         sync.Mutex
+	containers map[string]bool
+}
+
+func newDeltaUnsupportedSet() *deltaUnsupportedSet {
+	return &deltaUnsupportedSet{containers: make(map[string]bool)}
+}
+
+func (s *deltaUnsupportedSet) contains(cInfo liveupdates.Container) bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.containers[cInfo.ContainerID.String()]
+}
+
+func (s *deltaUnsupportedSet) add(cInfo liveupdates.Container) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.containers[cInfo.ContainerID.String()] = true
+}
+
+// execFunc runs a command in the container.
+//
+// Non-zero exits should be reported as an ExecError.
+type execFunc func(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout io.Writer, stderr io.Writer) error
+
+// deltaArchive rewrites a tar archive so that large files that already exist
+// in the container are replaced by their changed blocks.
+//
+// Returns the new archive and the files that need to be re-assembled after
+// the archive is extracted. The caller must call the cleanup func when done
+// with the archive.
+func deltaArchive(ctx context.Context, exec execFunc, cInfo liveupdates.Container,
+	unsupported *deltaUnsupportedSet, archive io.Reader) (io.Reader, []deltaFile, func(), error) {
+	// Spool the archive to disk, so that we can ask the container
+	// for checksums before we start sending files.
+	spool, err := os.CreateTemp("", "tilt-delta-sync-*.tar")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("delta sync: %v", err)
+	}
+	cleanup := func() {
+		_ = spool.Close()
+		_ = os.Remove(spool.Name())
+	}
+
+	// We know the size and mode of each file from its header, so we can
+	// describe how to re-assemble it before we start sending blocks.
+	var candidates []deltaFile
+	tr := tar.NewReader(archive)
+	tw := tar.NewWriter(spool)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, fmt.Errorf("delta sync: reading archive: %v", err)
+		}
+
+		if hdr.Typeflag == tar.TypeReg && hdr.Size >= deltaMinFileSize {
+			candidates = append(candidates, deltaFile{
+				path:   path.Join("/", hdr.Name),
+				size:   hdr.Size,
+				mode:   hdr.Mode & 07777,
+				blocks: int((hdr.Size + deltaBlockSize - 1) / deltaBlockSize),
+			})
+		}
+
+		err = tw.WriteHeader(hdr)
+		if err == nil {
+			_, err = io.Copy(tw, tr)
+		}
+		if err != nil {
+			cleanup()
+			return nil, nil, nil, fmt.Errorf("delta sync: spooling archive: %v", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("delta sync: spooling archive: %v", err)
+	}
+
+	checksums := make(map[string][]string, len(candidates))
+	for _, c := range candidates {
+		if unsupported.contains(cInfo) {
+			break
+		}
+
+		p := c.path
+		sums, err := blockChecksums(ctx, exec, p)
+		if err != nil {
+			var execErr ExecError
+			if errors.As(err, &execErr) &&
+				(execErr.ExitCode == GenericExitCodeNotFound || execErr.ExitCode == GenericExitCodeCannotExec) {
+				logger.Get(ctx).Debugf("Container %s can't compute block checksums, copying whole files", cInfo.DisplayName())
+				unsupported.add(cInfo)
+				break
+			}
+			logger.Get(ctx).Debugf("Delta sync: copying whole file %s: %v", p, err)
+			continue
+		}
+		if len(sums) > 0 {
+			checksums[p] = sums
+		}
+	}
+
+	if _, err := spool.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, nil, fmt.Errorf("delta sync: %v", err)
+	}
+
+	if len(checksums) == 0 {
+		return spool, nil, cleanup, nil
+	}
+
+	// Re-write the archive in the background as the container reads it.
+	// The list of deltas is complete before we start, and the writer
+	// never modifies it, so the caller can use it even if the copy
+	// returns before the writer finishes.
+	var deltas []deltaFile
+	for _, c := range candidates {
+		if _, ok := checksums[c.path]; ok {
+			deltas = append(deltas, c)
+		}
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		err := writeDeltaArchive(ctx, pw, tar.NewReader(spool), checksums, deltas)
+		_ = pw.CloseWithError(err)
+	}()
+
+	return pr, deltas, func() {
+		_ = pr.Close()
+		<-done
+		cleanup()
+	}, nil
+}
+
+func writeDeltaArchive(ctx context.Context, w io.Writer, tr *tar.Reader, checksums map[string][]string, deltas []deltaFile) error {
+	isDeltaPath := make(map[string]bool, len(deltas))
+	for _, d := range deltas {
+		isDeltaPath[d.path] = true
+	}
+
+	tw := tar.NewWriter(w)
+	buf := make([]byte, deltaBlockSize)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		p := path.Join("/", hdr.Name)
+		if !isDeltaPath[p] {
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := io.Copy(tw, tr); err != nil {
+				return err
+			}
+			continue
+		}
+
+		sums := checksums[p]
+		dir := strings.TrimPrefix(deltaDir(p), "/")
+		blocks, sent := 0, 0
+		for ; ; blocks++ {
+			n, err := io.ReadFull(tr, buf)
+			if err == io.EOF {
+				break
+			}
+			if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+				return err
+			}
+
+			sum := sha256.Sum256(buf[:n])
+			if blocks < len(sums) && sums[blocks] == hex.EncodeToString(sum[:]) {
+				continue
+			}
+
+			sent++
+			err = tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeReg,
+				Name:     path.Join(dir, strconv.Itoa(blocks)),
+				Mode:     0600,
+				Size:     int64(n),
+				ModTime:  hdr.ModTime,
+			})
+			if err != nil {
+				return err
+			}
+			if _, err := tw.Write(buf[:n]); err != nil {
+				return err
+			}
+		}
+
+		logger.Get(ctx).Debugf("Delta sync: sending %d of %d blocks of %s", sent, blocks, p)
+	}
+	return tw.Close()
+}
+
+func blockChecksums(ctx context.Context, exec execFunc, p string) ([]string, error) {
+	cmd := model.Cmd{Argv: []string{"sh", "-c", blockChecksumScript, "sh", strconv.Itoa(deltaBlockSize), p}}
+	out := bytes.NewBuffer(nil)
+	err := exec(ctx, cmd, nil, out, io.Discard)
+	if err != nil {
+		return nil, err
+	}
+
+	var sums []string
+	for _, line := range strings.Split(out.String(), "\n") {
+		sum, _, ok := strings.Cut(strings.TrimRight(line, "\r"), "  ")
+		if !ok || len(sum) != 2*sha256.Size {
+			continue
+		}
+		sums = append(sums, sum)
+	}
+	return sums, nil
+}
+
+func deltaApplyCmd(deltas []deltaFile) model.Cmd {
+	argv := []string{"sh", "-c", deltaApplyScript, "sh", strconv.Itoa(deltaBlockSize)}
+	for _, d := range deltas {
+		argv = append(argv, d.path, strconv.FormatInt(d.size, 10),
+			strconv.FormatInt(d.mode, 8), strconv.Itoa(d.blocks))
+	}
+	return model.Cmd{Argv: argv}
+}
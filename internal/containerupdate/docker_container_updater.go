@@ -84,6 +84,14 @@ func (cu *DockerUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.
 	return nil
 }
 
+func (cu *DockerUpdater) HashFiles(ctx context.Context, cInfo liveupdates.Container, paths []string) (map[string]string, error) {
+	return hashFiles(paths, func(cmd model.Cmd) (string, error) {
+		out := bytes.NewBuffer(nil)
+		err := cu.dCli.ExecInContainer(ctx, cInfo.ContainerID, cmd, nil, out)
+		return out.String(), err
+	})
+}
+
 func (cu *DockerUpdater) rmPathsFromContainer(ctx context.Context, cID container.ID, paths []string) error {
 	if len(paths) == 0 {
 		return nil
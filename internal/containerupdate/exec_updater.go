@@ -15,12 +15,26 @@ import (
 
 type ExecUpdater struct {
 	kCli k8s.Client
+
+	// When set, large files that already exist in the container
+	// are copied by sending only the blocks that changed.
+	deltaSync bool
+
+	// Containers that can't do delta sync, shared between copies.
+	deltaUnsupported *deltaUnsupportedSet
 }
 
 var _ ContainerUpdater = &ExecUpdater{}
+var _ DeltaSyncer = &ExecUpdater{}
 
 func NewExecUpdater(kCli k8s.Client) *ExecUpdater {
-	return &ExecUpdater{kCli: kCli}
+	return &ExecUpdater{kCli: kCli, deltaUnsupported: newDeltaUnsupportedSet()}
+}
+
+func (cu *ExecUpdater) WithDeltaSync() ContainerUpdater {
+	updater := *cu
+	updater.deltaSync = true
+	return &updater
 }
 
 func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Container,
@@ -47,6 +61,17 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 		}
 	}
 
+	var deltas []deltaFile
+	if cu.deltaSync {
+		archive, files, cleanup, err := deltaArchive(ctx, cu.execFunc(cInfo), cInfo, cu.deltaUnsupported, archiveToCopy)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		archiveToCopy = archive
+		deltas = files
+	}
+
 	// copy files to container
 	buf := bytes.NewBuffer(nil)
 	tarWriter := io.MultiWriter(w, buf)
@@ -57,6 +82,17 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 		return wrapK8sTarErr(buf, err, tarCmd, "copying changed files")
 	}
 
+	// re-assemble files that we sent as deltas
+	if len(deltas) > 0 {
+		buf := bytes.NewBuffer(nil)
+		applyCmd := deltaApplyCmd(deltas)
+		err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+			applyCmd.Argv, nil, io.Discard, buf)
+		if err != nil {
+			return fmt.Errorf("applying delta sync: %w\n%s", wrapK8sGenericExecErr(err, applyCmd), buf.String())
+		}
+	}
+
 	// run commands
 	for i, c := range cmds {
 		if !c.EchoOff {
@@ -77,6 +113,26 @@ func (cu *ExecUpdater) UpdateContainer(ctx context.Context, cInfo liveupdates.Co
 	return nil
 }
 
+func (cu *ExecUpdater) HashFiles(ctx context.Context, cInfo liveupdates.Container, paths []string) (map[string]string, error) {
+	return hashFiles(paths, func(cmd model.Cmd) (string, error) {
+		out := bytes.NewBuffer(nil)
+		err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+			cmd.Argv, nil, out, io.Discard)
+		return out.String(), err
+	})
+}
+
+func (cu *ExecUpdater) execFunc(cInfo liveupdates.Container) execFunc {
+	return func(ctx context.Context, cmd model.Cmd, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+		err := cu.kCli.Exec(ctx, cInfo.PodID, cInfo.ContainerName, cInfo.Namespace,
+			cmd.Argv, stdin, stdout, stderr)
+		if err != nil {
+			return wrapK8sGenericExecErr(err, cmd)
+		}
+		return nil
+	}
+}
+
 // wrapK8sTarErr provides user-friendly diagnostics for common failures when
 // running `tar` as part of a Live Update.
 func wrapK8sTarErr(out *bytes.Buffer, err error, cmd model.Cmd, action string) error {
@@ -1,11 +1,18 @@
 package containerupdate
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"os"
+	osexec "os/exec"
+	"path/filepath"
+	"runtime"
 	"strings"
 	"testing"
 
@@ -13,6 +20,7 @@ import (
 	"k8s.io/client-go/util/exec"
 
 	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 
 	"github.com/tilt-dev/tilt/internal/k8s"
@@ -149,6 +157,213 @@ func TestUpdateContainerPermissionDenied(t *testing.T) {
 	assert.Equal(t, 1, len(f.kCli.ExecCalls))
 }
 
+func TestHashFiles(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(
+		"2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824  /app/a.txt\r\n" +
+			"sha256sum: /app/b.txt: Permission denied\n" +
+			"486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7  /app/dir with spaces/c.txt\n")}
+
+	hashes, err := f.ecu.HashFiles(f.ctx, TestContainerInfo,
+		[]string{"/app/a.txt", "/app/b.txt", "/app/dir with spaces/c.txt", "/app/missing.txt"})
+	if assert.NoError(t, err) {
+		assert.Equal(t, map[string]string{
+			"/app/a.txt":                 "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+			"/app/dir with spaces/c.txt": "486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7",
+		}, hashes)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 1) {
+		call := f.kCli.ExecCalls[0]
+		assert.Equal(t, []string{"sh", "-c", hashScript, "sh",
+			"/app/a.txt", "/app/b.txt", "/app/dir with spaces/c.txt", "/app/missing.txt"}, call.Cmd)
+	}
+}
+
+func TestHashFilesBatches(t *testing.T) {
+	f := newExecFixture(t)
+
+	var paths []string
+	for i := 0; i < hashBatchSize+1; i++ {
+		paths = append(paths, fmt.Sprintf("/app/%d.txt", i))
+	}
+
+	_, err := f.ecu.HashFiles(f.ctx, TestContainerInfo, paths)
+	assert.NoError(t, err)
+	if assert.Len(t, f.kCli.ExecCalls, 2) {
+		assert.Len(t, f.kCli.ExecCalls[0].Cmd, 4+hashBatchSize)
+		assert.Equal(t, []string{"sh", "-c", hashScript, "sh", paths[hashBatchSize]}, f.kCli.ExecCalls[1].Cmd)
+	}
+}
+
+func TestHashFilesMissingSha256sum(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 127"), Code: 127}}
+
+	_, err := f.ecu.HashFiles(f.ctx, TestContainerInfo, []string{"/app/a.txt"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "doesn't include `sh` or `sha256sum`")
+	}
+}
+
+func TestUpdateContainerDeltaSync(t *testing.T) {
+	f := newExecFixture(t)
+
+	oldBig := bytes.Repeat([]byte("a"), 3*deltaBlockSize)
+	newBig := append([]byte(nil), oldBig...)
+	newBig[deltaBlockSize+10] = 'b'
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(blockChecksumOutput(oldBig))}
+
+	archive := newTarReader(t, map[string][]byte{
+		"app/big.bin":   newBig,
+		"app/small.txt": []byte("hello"),
+	})
+	err := f.ecu.WithDeltaSync().UpdateContainer(f.ctx, TestContainerInfo, archive, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 3) {
+		assert.Equal(t, []string{"sh", "-c", blockChecksumScript, "sh", "1048576", "/app/big.bin"},
+			f.kCli.ExecCalls[0].Cmd)
+
+		assert.Equal(t, []string{"tar", "-C", "/", "-x", "-f", "-"}, f.kCli.ExecCalls[1].Cmd)
+		assert.Equal(t, map[string][]byte{
+			"app/.big.bin.tilt-delta/1": newBig[deltaBlockSize : 2*deltaBlockSize],
+			"app/small.txt":             []byte("hello"),
+		}, readTar(t, f.kCli.ExecCalls[1].Stdin))
+
+		assert.Equal(t, []string{"sh", "-c", deltaApplyScript, "sh", "1048576",
+			"/app/big.bin", "3145728", "644", "3"}, f.kCli.ExecCalls[2].Cmd)
+	}
+}
+
+func TestUpdateContainerDeltaSyncExecReturnsEarly(t *testing.T) {
+	f := newExecFixture(t)
+
+	oldBig := bytes.Repeat([]byte("a"), 3*deltaBlockSize)
+	newBig := append([]byte(nil), oldBig...)
+	newBig[deltaBlockSize+10] = 'b'
+
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(blockChecksumOutput(oldBig))}
+
+	// The exec returns as soon as it reads the first tar header,
+	// while the archive is still being written.
+	kCli := &partialTarReadClient{FakeK8sClient: f.kCli}
+	cu := NewExecUpdater(kCli).WithDeltaSync()
+	archive := newTarReader(t, map[string][]byte{"app/big.bin": newBig})
+	err := cu.UpdateContainer(f.ctx, TestContainerInfo, archive, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if assert.Len(t, f.kCli.ExecCalls, 2) {
+		assert.Equal(t, []string{"sh", "-c", deltaApplyScript, "sh", "1048576",
+			"/app/big.bin", "3145728", "644", "3"}, f.kCli.ExecCalls[1].Cmd)
+	}
+}
+
+func TestUpdateContainerDeltaSyncNewFile(t *testing.T) {
+	f := newExecFixture(t)
+
+	big := bytes.Repeat([]byte("a"), 3*deltaBlockSize)
+	archive := newTarReader(t, map[string][]byte{"app/big.bin": big})
+	err := f.ecu.WithDeltaSync().UpdateContainer(f.ctx, TestContainerInfo, archive, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The file doesn't exist in the container yet, so we copy all of it.
+	if assert.Len(t, f.kCli.ExecCalls, 2) {
+		assert.Equal(t, map[string][]byte{"app/big.bin": big}, readTar(t, f.kCli.ExecCalls[1].Stdin))
+	}
+}
+
+func TestUpdateContainerDeltaSyncUnsupported(t *testing.T) {
+	f := newExecFixture(t)
+
+	f.kCli.ExecErrors = []error{exec.CodeExitError{Err: fmt.Errorf("command terminated with exit code 127"), Code: 127}}
+
+	big := bytes.Repeat([]byte("a"), 3*deltaBlockSize)
+	cu := f.ecu.WithDeltaSync()
+	err := cu.UpdateContainer(f.ctx, TestContainerInfo, newTarReader(t, map[string][]byte{"app/big.bin": big}), nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, f.kCli.ExecCalls, 2) {
+		assert.Equal(t, map[string][]byte{"app/big.bin": big}, readTar(t, f.kCli.ExecCalls[1].Stdin))
+	}
+
+	// We remember that the container can't compute checksums and don't ask again.
+	f.kCli.ExecCalls = nil
+	err = cu.UpdateContainer(f.ctx, TestContainerInfo, newTarReader(t, map[string][]byte{"app/big.bin": big}), nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, f.kCli.ExecCalls, 1) {
+		assert.Equal(t, []string{"tar", "-C", "/", "-x", "-f", "-"}, f.kCli.ExecCalls[0].Cmd)
+	}
+}
+
+func TestUpdateContainerDeltaSyncUnchangedFile(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("delta sync scripts need a POSIX shell")
+	}
+	f := newExecFixture(t)
+
+	big := bytes.Repeat([]byte("a"), 3*deltaBlockSize)
+	f.kCli.ExecOutputs = []io.Reader{strings.NewReader(blockChecksumOutput(big))}
+
+	archive := newTarReader(t, map[string][]byte{"app/big.bin": big})
+	err := f.ecu.WithDeltaSync().UpdateContainer(f.ctx, TestContainerInfo, archive, nil, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !assert.Len(t, f.kCli.ExecCalls, 3) {
+		return
+	}
+
+	// No blocks changed, so we don't send any.
+	assert.Empty(t, readTar(t, f.kCli.ExecCalls[1].Stdin))
+
+	// Run the apply script on a copy of the file, the way the container would.
+	dir := t.TempDir()
+	p := filepath.Join(dir, "big.bin")
+	if err := os.WriteFile(p, big, 0644); err != nil {
+		t.Fatal(err)
+	}
+	argv := append([]string{}, f.kCli.ExecCalls[2].Cmd...)
+	argv[5] = p
+	out, err := osexec.Command(argv[0], argv[1:]...).CombinedOutput()
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+
+	contents, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, big, contents)
+	_, err = os.Stat(deltaDir(p))
+	assert.True(t, os.IsNotExist(err), "delta dir should be removed")
+}
+
+// A client whose tar exec stops reading stdin after the first block.
+type partialTarReadClient struct {
+	*k8s.FakeK8sClient
+}
+
+func (c *partialTarReadClient) Exec(ctx context.Context, podID k8s.PodID, cName container.Name, n k8s.Namespace, cmd []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+	if len(cmd) > 0 && cmd[0] == "tar" {
+		_, err := io.ReadFull(stdin, make([]byte, 512))
+		return err
+	}
+	return c.FakeK8sClient.Exec(ctx, podID, cName, n, cmd, stdin, stdout, stderr)
+}
+
 type execUpdaterFixture struct {
 	t    testing.TB
 	ctx  context.Context
@@ -158,9 +373,7 @@ type execUpdaterFixture struct {
 
 func newExecFixture(t testing.TB) *execUpdaterFixture {
 	fakeCli := k8s.NewFakeK8sClient(t)
-	cu := &ExecUpdater{
-		kCli: fakeCli,
-	}
+	cu := NewExecUpdater(fakeCli)
 	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
 
 	return &execUpdaterFixture{
@@ -174,3 +387,67 @@ func newExecFixture(t testing.TB) *execUpdaterFixture {
 func newReader(contents string) io.Reader {
 	return bytes.NewBuffer([]byte(contents))
 }
+
+func newTarReader(t testing.TB, files map[string][]byte) io.Reader {
+	buf := bytes.NewBuffer(nil)
+	tw := tar.NewWriter(buf)
+	for _, name := range sliceutils.DedupedAndSorted(keys(files)) {
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+		})
+		if err == nil {
+			_, err = tw.Write(files[name])
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf
+}
+
+func readTar(t testing.TB, archive []byte) map[string][]byte {
+	result := make(map[string][]byte)
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return result
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		contents, err := io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[hdr.Name] = contents
+	}
+}
+
+func keys(m map[string][]byte) []string {
+	var result []string
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// Simulates the output of blockChecksumScript for a file in the container.
+func blockChecksumOutput(contents []byte) string {
+	var out strings.Builder
+	for i := 0; i < len(contents); i += deltaBlockSize {
+		end := i + deltaBlockSize
+		if end > len(contents) {
+			end = len(contents)
+		}
+		sum := sha256.Sum256(contents[i:end])
+		out.WriteString(hex.EncodeToString(sum[:]) + "  -\n")
+	}
+	return out.String()
+}
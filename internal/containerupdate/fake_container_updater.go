@@ -1,10 +1,15 @@
 package containerupdate
 
 import (
+	"archive/tar"
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
+	"path"
+	"strings"
 
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -12,8 +17,14 @@ import (
 
 type FakeContainerUpdater struct {
 	UpdateErrs []error
+	HashErr    error
 
 	Calls []UpdateContainerCall
+
+	// Checksums of the files in the fake container filesystem, keyed by path.
+	//
+	// Updated by the archives and deletes passed to UpdateContainer.
+	Files map[string]string
 }
 
 type UpdateContainerCall struct {
@@ -43,6 +54,11 @@ func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo liveu
 		HotReload:     hotReload,
 	})
 
+	cu.deleteFiles(filesToDelete)
+	if err := cu.extractFiles(archive.Bytes()); err != nil {
+		return fmt.Errorf("FakeContainerUpdater failed to extract archive: %v", err)
+	}
+
 	// If we're supposed to throw an error on this call, throw it (and pop from
 	// the list of UpdateErrs)
 	var err error
@@ -52,3 +68,52 @@ func (cu *FakeContainerUpdater) UpdateContainer(ctx context.Context, cInfo liveu
 	}
 	return err
 }
+
+func (cu *FakeContainerUpdater) HashFiles(ctx context.Context, cInfo liveupdates.Container, paths []string) (map[string]string, error) {
+	if cu.HashErr != nil {
+		return nil, cu.HashErr
+	}
+
+	result := make(map[string]string)
+	for _, p := range paths {
+		if h, ok := cu.Files[p]; ok {
+			result[p] = h
+		}
+	}
+	return result, nil
+}
+
+func (cu *FakeContainerUpdater) deleteFiles(paths []string) {
+	for _, p := range paths {
+		for f := range cu.Files {
+			if f == p || strings.HasPrefix(f, strings.TrimSuffix(p, "/")+"/") {
+				delete(cu.Files, f)
+			}
+		}
+	}
+}
+
+func (cu *FakeContainerUpdater) extractFiles(archive []byte) error {
+	tr := tar.NewReader(bytes.NewReader(archive))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		h := sha256.New()
+		if _, err := io.Copy(h, tr); err != nil {
+			return err
+		}
+		if cu.Files == nil {
+			cu.Files = make(map[string]string)
+		}
+		cu.Files[path.Join("/", hdr.Name)] = hex.EncodeToString(h.Sum(nil))
+	}
+}
@@ -0,0 +1,86 @@
+package containerupdate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Keep each exec well under the kernel's argument length limit.
+const hashBatchSize = 500
+
+// Prints the checksum of each path that's a regular file, and skips
+// the rest, so that missing files don't fail the whole command.
+const hashScript = `command -v sha256sum >/dev/null 2>&1 || exit 127
+for f in "$@"; do
+  if [ -f "$f" ]; then sha256sum -- "$f" || exit $?; fi
+done`
+
+func hashCmd(paths []string) model.Cmd {
+	return model.Cmd{
+		Argv: append([]string{"sh", "-c", hashScript, "sh"}, paths...),
+	}
+}
+
+// hashFiles runs the hash command in batches with the given exec func,
+// and merges the results.
+func hashFiles(paths []string, exec func(cmd model.Cmd) (string, error)) (map[string]string, error) {
+	result := make(map[string]string, len(paths))
+	for start := 0; start < len(paths); start += hashBatchSize {
+		end := start + hashBatchSize
+		if end > len(paths) {
+			end = len(paths)
+		}
+
+		out, err := exec(hashCmd(paths[start:end]))
+		if err != nil {
+			return nil, wrapHashExecErr(err)
+		}
+
+		for p, h := range parseHashOutput(out) {
+			result[p] = h
+		}
+	}
+	return result, nil
+}
+
+// parseHashOutput reads lines in the `sha256sum` format: "<checksum>  <path>".
+//
+// Lines in any other format are ignored (e.g., the command echo that Docker
+// execs write to the output stream).
+func parseHashOutput(out string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimRight(line, "\r")
+		hash, p, ok := strings.Cut(line, "  ")
+		if !ok || len(hash) != 2*sha256.Size {
+			continue
+		}
+		if _, err := hex.DecodeString(hash); err != nil {
+			continue
+		}
+		result[p] = hash
+	}
+	return result
+}
+
+func wrapHashExecErr(err error) error {
+	exitCode, ok := ExtractExitCode(err)
+	if !ok && strings.Contains(err.Error(), "executable file not found") {
+		exitCode, ok = GenericExitCodeNotFound, true
+	}
+	if !ok {
+		return fmt.Errorf("hashing synced files: %w", err)
+	}
+
+	hashErr := NewExecError(model.Cmd{Argv: []string{"sha256sum"}}, exitCode)
+	if exitCode == GenericExitCodeCannotExec || exitCode == GenericExitCodeNotFound {
+		return fmt.Errorf("%v\n"+
+			"This usually means that the container image doesn't include `sh` or `sha256sum`, "+
+			"which Tilt needs to verify synced files.", hashErr)
+	}
+	return fmt.Errorf("hashing synced files: %w", hashErr)
+}
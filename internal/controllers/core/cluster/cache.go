@@ -52,6 +52,7 @@ type connection struct {
 	k8sClient    k8s.Client
 
 	arch          string
+	arches        []string
 	serverVersion string
 	registry      *v1alpha1.RegistryHosting
 	connStatus    *v1alpha1.ClusterConnectionStatus
@@ -3,6 +3,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/jonboulle/clockwork"
@@ -232,24 +233,47 @@ func (r *Reconciler) createKubernetesClient(cluster *v1alpha1.Cluster) (k8s.Clie
 // Note that it's normal that users may not have access to the kubernetes
 // arch if there are RBAC rules restricting read access on nodes.
 //
-// We only need to read SOME arch that the cluster supports.
-func (r *Reconciler) readKubernetesArch(ctx context.Context, client k8s.Client) string {
+// We only need to read SOME arch that the cluster supports. We pick the arch
+// of the most nodes (breaking ties alphabetically), so that the result doesn't
+// depend on the order that nodes are listed in. We also return the arches of
+// all nodes, for multi-arch builds.
+func (r *Reconciler) readKubernetesArch(ctx context.Context, client k8s.Client) (string, []string) {
 	nodeMetas, err := client.ListMeta(ctx, schema.GroupVersionKind{Version: "v1", Kind: "Node"}, "")
 	if err != nil || len(nodeMetas) == 0 {
-		return ArchUnknown
+		return ArchUnknown, nil
 	}
 
-	// https://github.com/kubernetes/enhancements/blob/0e4d5df19d396511fe41ed0860b0ab9b96f46a2d/keps/sig-node/793-node-os-arch-labels/README.md
-	// https://kubernetes.io/docs/reference/labels-annotations-taints/#kubernetes-io-arch
-	arch := nodeMetas[0].GetLabels()["kubernetes.io/arch"]
-	if arch == "" {
-		arch = nodeMetas[0].GetLabels()["beta.kubernetes.io/arch"]
+	archCounts := make(map[string]int)
+	for _, nodeMeta := range nodeMetas {
+		// https://github.com/kubernetes/enhancements/blob/0e4d5df19d396511fe41ed0860b0ab9b96f46a2d/keps/sig-node/793-node-os-arch-labels/README.md
+		// https://kubernetes.io/docs/reference/labels-annotations-taints/#kubernetes-io-arch
+		nodeArch := nodeMeta.GetLabels()["kubernetes.io/arch"]
+		if nodeArch == "" {
+			nodeArch = nodeMeta.GetLabels()["beta.kubernetes.io/arch"]
+		}
+		if nodeArch == "" {
+			continue
+		}
+		archCounts[nodeArch]++
 	}
 
-	if arch == "" {
-		return ArchUnknown
+	if len(archCounts) == 0 {
+		return ArchUnknown, nil
 	}
-	return arch
+
+	arches := make([]string, 0, len(archCounts))
+	for a := range archCounts {
+		arches = append(arches, a)
+	}
+	sort.Strings(arches)
+
+	arch := arches[0]
+	for _, a := range arches[1:] {
+		if archCounts[a] > archCounts[arch] {
+			arch = a
+		}
+	}
+	return arch, arches
 }
 
 // Reads the arch from a Docker cluster, or "unknown" if we can't
@@ -327,7 +351,7 @@ func (r *Reconciler) populateClusterMetadata(ctx context.Context, clusterNN type
 
 func (r *Reconciler) populateK8sMetadata(ctx context.Context, clusterNN types.NamespacedName, conn *connection) {
 	if conn.arch == "" {
-		conn.arch = r.readKubernetesArch(ctx, conn.k8sClient)
+		conn.arch, conn.arches = r.readKubernetesArch(ctx, conn.k8sClient)
 	}
 
 	if conn.registry == nil {
@@ -412,6 +436,9 @@ func (r *Reconciler) populateK8sMetadata(ctx context.Context, clusterNN types.Na
 func (r *Reconciler) populateDockerMetadata(ctx context.Context, conn *connection) {
 	if conn.arch == "" {
 		conn.arch = r.readDockerArch(ctx, conn.dockerClient)
+		if conn.arch != ArchUnknown {
+			conn.arches = []string{conn.arch}
+		}
 	}
 
 	if conn.serverVersion == "" {
@@ -469,6 +496,7 @@ func (c *connection) toStatus(statusErr string) v1alpha1.ClusterStatus {
 	return v1alpha1.ClusterStatus{
 		Error:       clusterError,
 		Arch:        c.arch,
+		Arches:      c.arches,
 		Version:     c.serverVersion,
 		ConnectedAt: connectedAt,
 		Registry:    c.registry,
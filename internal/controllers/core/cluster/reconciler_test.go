@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -234,6 +235,73 @@ func TestKubernetesArch(t *testing.T) {
 	assert.ElementsMatch(t, []analytics.CountEvent{connectEvt}, f.ma.Counts)
 }
 
+func TestKubernetesMixedArches(t *testing.T) {
+	f := newFixture(t)
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	}
+
+	nn := types.NamespacedName{Name: "default"}
+	for i, arch := range []string{"arm64", "amd64", "arm64"} {
+		f.k8sClient.Inject(k8s.K8sEntity{
+			Obj: &v1.Node{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%d", i),
+					UID:  types.UID(fmt.Sprintf("uid-%d", i)),
+					Labels: map[string]string{
+						"kubernetes.io/arch": arch,
+					},
+				},
+			},
+		})
+	}
+
+	f.Create(cluster)
+	f.MustGet(nn, cluster)
+	assert.Equal(t, "arm64", cluster.Status.Arch)
+	assert.Equal(t, []string{"amd64", "arm64"}, cluster.Status.Arches)
+}
+
+func TestKubernetesMixedArchesTie(t *testing.T) {
+	f := newFixture(t)
+	cluster := &v1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "default"},
+		Spec: v1alpha1.ClusterSpec{
+			Connection: &v1alpha1.ClusterConnection{
+				Kubernetes: &v1alpha1.KubernetesClusterConnection{},
+			},
+		},
+	}
+
+	nn := types.NamespacedName{Name: "default"}
+	for i, arch := range []string{"arm64", "amd64"} {
+		f.k8sClient.Inject(k8s.K8sEntity{
+			Obj: &v1.Node{
+				TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Node"},
+				ObjectMeta: metav1.ObjectMeta{
+					Name: fmt.Sprintf("node-%d", i),
+					UID:  types.UID(fmt.Sprintf("uid-%d", i)),
+					Labels: map[string]string{
+						"kubernetes.io/arch": arch,
+					},
+				},
+			},
+		})
+	}
+
+	f.Create(cluster)
+	f.MustGet(nn, cluster)
+	// Ties are broken alphabetically, so the result doesn't depend on node order.
+	assert.Equal(t, "amd64", cluster.Status.Arch)
+	assert.Equal(t, []string{"amd64", "arm64"}, cluster.Status.Arches)
+}
+
 func TestKubernetesConnStatus(t *testing.T) {
 	f := newFixture(t)
 	cluster := &v1alpha1.Cluster{
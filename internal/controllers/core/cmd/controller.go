@@ -7,6 +7,7 @@ import (
 	"io"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/jonboulle/clockwork"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -27,8 +28,10 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apis/trigger"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/logparser"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/timecmp"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -95,7 +98,6 @@ func (c *Controller) stop(name types.NamespacedName) {
 	}
 	proc.cancelFunc()
 	<-proc.doneCh
-	proc.probeWorker = nil
 	proc.cancelFunc = nil
 	proc.doneCh = nil
 }
@@ -204,10 +206,12 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 	startOnTriggered := timecmp.After(te.lastStartEventTime, lastStartOnEventTime)
 	execSpecChanged := !cmdExecEqual(lastSpec, cmd.Spec)
 
+	result := ctrl.Result{}
 	if !disabled {
 		// any change to the spec means we should stop the command immediately
 		if execSpecChanged {
 			c.stop(name)
+			proc.resetRestarts()
 		}
 
 		if execSpecChanged && waitsOnStartOn && !startOnTriggered {
@@ -225,6 +229,8 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 			// Otherwise, any change, new start event, or new restart event
 			// should restart the process to pick up changes.
 			_ = c.runInternal(ctx, cmd, te)
+		} else {
+			result.RequeueAfter = c.maybeRestartUnhealthy(ctx, cmd, te)
 		}
 	}
 
@@ -233,7 +239,69 @@ func (c *Controller) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Resu
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	return result, nil
+}
+
+// If a liveness or startup probe has failed, stops the process and
+// restarts it once the backoff period has passed.
+//
+// Returns how long to wait before the restart is due (if any).
+func (c *Controller) maybeRestartUnhealthy(ctx context.Context, cmd *v1alpha1.Cmd, te triggerEvents) time.Duration {
+	name := types.NamespacedName{Name: cmd.Name}
+	proc := c.ensureProc(name)
+
+	proc.statusMu.Lock()
+	reason := proc.unhealthyReason
+	restartAt := proc.restartAt
+	consecutiveRestarts := proc.consecutiveRestarts
+	proc.statusMu.Unlock()
+
+	if reason == "" && restartAt.IsZero() {
+		return 0
+	}
+
+	if restartAt.IsZero() {
+		c.stop(name)
+
+		backoff := restartBackoff(consecutiveRestarts)
+		restartAt = c.clock.Now().Add(backoff)
+		logger.Get(store.MustObjectLogHandler(ctx, c.st, cmd)).
+			Warnf("%s. Restarting in %s", reason, backoff)
+
+		proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+			proc.unhealthyReason = ""
+			proc.restartAt = restartAt
+			status.Waiting = &CmdStateWaiting{Reason: waitingOnRestartBackoffReason}
+			status.Running = nil
+			status.Terminated = nil
+			status.Ready = false
+			status.LastRestartReason = reason
+		})
+	}
+
+	if wait := restartAt.Sub(c.clock.Now()); wait > 0 {
+		return wait
+	}
+
+	proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+		proc.consecutiveRestarts++
+		status.RestartCount++
+	})
+	_ = c.runInternal(ctx, cmd, te)
+	return 0
+}
+
+// The delay before restarting a process that failed its probe,
+// doubling with each consecutive restart.
+func restartBackoff(consecutiveRestarts int) time.Duration {
+	backoff := restartBackoffInitial
+	for i := 0; i < consecutiveRestarts && backoff < restartBackoffMax; i++ {
+		backoff *= 2
+	}
+	if backoff > restartBackoffMax {
+		backoff = restartBackoffMax
+	}
+	return backoff
 }
 
 func (c *Controller) maybeUpdateObjectStatus(ctx context.Context, cmd *v1alpha1.Cmd) error {
@@ -356,36 +424,34 @@ func (c *Controller) runInternal(ctx context.Context,
 	status.Waiting = &CmdStateWaiting{}
 	status.Terminated = nil
 	status.Ready = false
+	proc.unhealthyReason = ""
+	proc.restartAt = time.Time{}
 
 	ctx = store.MustObjectLogHandler(ctx, c.st, cmd)
 	spec := cmd.Spec
 
-	if spec.ReadinessProbe != nil {
-		probeResultFunc := c.handleProbeResultFunc(ctx, name, proc)
-		probeWorker, err := probeWorkerFromSpec(
-			c.proberManager,
-			spec.ReadinessProbe,
-			probeResultFunc)
-		if err != nil {
-			logger.Get(ctx).Errorf("Invalid readiness probe: %v", err)
-			status.Terminated = &CmdStateTerminated{
-				ExitCode: 1,
-				Reason:   fmt.Sprintf("Invalid readiness probe: %v", err),
-			}
-			status.Waiting = nil
-			status.Running = nil
-			status.Ready = false
-
-			proc.doneCh = make(chan struct{})
-			close(proc.doneCh)
-			return proc.doneCh
+	probes, err := c.probeWorkersFromSpec(ctx, name, proc, spec)
+	if err != nil {
+		logger.Get(ctx).Errorf("%v", err)
+		status.Terminated = &CmdStateTerminated{
+			ExitCode: 1,
+			Reason:   err.Error(),
 		}
-		proc.probeWorker = probeWorker
+		status.Waiting = nil
+		status.Running = nil
+		status.Ready = false
+
+		proc.doneCh = make(chan struct{})
+		close(proc.doneCh)
+		return proc.doneCh
 	}
 
 	startedAt := apis.NewMicroTime(c.clock.Now())
 
-	env := append([]string{}, spec.Env...)
+	// Commands can join the trace of the build that ran them.
+	// The spec env comes later, so the user can override it.
+	env := append([]string{}, tracer.TraceparentEnv(ctx)...)
+	env = append(env, spec.Env...)
 	for _, input := range inputs {
 		env = append(env, fmt.Sprintf("%s=%s", input.spec.Name, input.stringValue()))
 	}
@@ -395,38 +461,116 @@ func (c *Controller) runInternal(ctx context.Context,
 		Dir:  spec.Dir,
 		Env:  env,
 	}
-	statusCh := c.execer.Start(ctx, cmdModel, logger.Get(ctx).Writer(logger.InfoLvl))
+	output := logparser.NewWriter(logger.Get(ctx), spec.LogParser)
+	statusCh := c.execer.Start(ctx, cmdModel, spec.Stop, output)
 	proc.doneCh = make(chan struct{})
 
-	go c.processStatuses(ctx, statusCh, proc, name, startedAt)
+	go c.processStatuses(ctx, statusCh, proc, name, startedAt, probes, output)
 
 	return proc.doneCh
 }
 
-func (c *Controller) handleProbeResultFunc(ctx context.Context, name types.NamespacedName, proc *currentProcess) probe.ResultFunc {
+// Creates workers for all the probes in the spec.
+//
+// The workers don't start until the process is running.
+func (c *Controller) probeWorkersFromSpec(ctx context.Context, name types.NamespacedName, proc *currentProcess, spec v1alpha1.CmdSpec) (probeWorkers, error) {
+	probes := probeWorkers{startupSucceeded: make(chan struct{})}
+	var err error
+	if spec.StartupProbe != nil {
+		probes.startup, err = probeWorkerFromSpec(
+			c.proberManager,
+			spec.StartupProbe,
+			c.handleHealthProbeResultFunc(ctx, name, proc, "startup", probes.onStartupSuccess(c, name, proc, spec)))
+		if err != nil {
+			return probeWorkers{}, fmt.Errorf("Invalid startup probe: %v", err)
+		}
+	}
+	if spec.LivenessProbe != nil {
+		probes.liveness, err = probeWorkerFromSpec(
+			c.proberManager,
+			spec.LivenessProbe,
+			c.handleHealthProbeResultFunc(ctx, name, proc, "liveness", nil))
+		if err != nil {
+			return probeWorkers{}, fmt.Errorf("Invalid liveness probe: %v", err)
+		}
+	}
+	if spec.ReadinessProbe != nil {
+		probes.readiness, err = probeWorkerFromSpec(
+			c.proberManager,
+			spec.ReadinessProbe,
+			c.handleProbeResultFunc(ctx, name, proc))
+		if err != nil {
+			return probeWorkers{}, fmt.Errorf("Invalid readiness probe: %v", err)
+		}
+	}
+	return probes, nil
+}
+
+// we try to balance logging important probe results without flooding the logs
+//   - ALL transitions are logged
+//   - success->{failure,warning} @ WARN
+//   - {failure,warning}->success @ INFO
+//   - subsequent non-successful results @ VERBOSE
+//   - expected healthy/steady-state is recurring success, and this is apparent
+//     from the status, so logging every invocation is superfluous
+func probeLogLevel(result prober.Result, statusChanged bool) logger.Level {
+	if statusChanged {
+		if result != prober.Success {
+			return logger.WarnLvl
+		}
+		return logger.InfoLvl
+	} else if result != prober.Success {
+		return logger.VerboseLvl
+	}
+	return logger.NoneLvl
+}
+
+// Handles results from a liveness or startup probe.
+//
+// When the probe transitions to failure, marks the process as unhealthy
+// and triggers a reconcile to restart it.
+func (c *Controller) handleHealthProbeResultFunc(ctx context.Context, name types.NamespacedName, proc *currentProcess, probeType string, onSuccess func()) probe.ResultFunc {
 	return func(result prober.Result, statusChanged bool, output string, err error) {
 		if ctx.Err() != nil {
 			return
 		}
 
-		// we try to balance logging important probe results without flooding the logs
-		//  * ALL transitions are logged
-		// 		* success->{failure,warning} @ WARN
-		// 		* {failure,warning}->success @ INFO
-		// 	* subsequent non-successful results @ VERBOSE
-		// 		* expected healthy/steady-state is recurring success, and this is apparent
-		// 		  from the "Ready" state, so logging every invocation is superfluous
-		loggerLevel := logger.NoneLvl
-		if statusChanged {
-			if result != prober.Success {
-				loggerLevel = logger.WarnLvl
-			} else {
-				loggerLevel = logger.InfoLvl
+		logProbeOutput(ctx, probeLogLevel(result, statusChanged), probeType, result, output, nil)
+
+		if !statusChanged {
+			return
+		}
+
+		if result == prober.Success || result == prober.Warning {
+			proc.statusMu.Lock()
+			proc.consecutiveRestarts = 0
+			proc.statusMu.Unlock()
+
+			if onSuccess != nil {
+				onSuccess()
 			}
-		} else if result != prober.Success {
-			loggerLevel = logger.VerboseLvl
+			return
+		}
+
+		reason := fmt.Sprintf("%s probe failed", probeType)
+		if line, _, _ := strings.Cut(strings.TrimSpace(output), "\n"); line != "" {
+			reason = fmt.Sprintf("%s: %s", reason, line)
 		}
-		logProbeOutput(ctx, loggerLevel, result, output, nil)
+
+		proc.statusMu.Lock()
+		defer proc.statusMu.Unlock()
+		proc.unhealthyReason = reason
+		c.requeuer.Add(name)
+	}
+}
+
+func (c *Controller) handleProbeResultFunc(ctx context.Context, name types.NamespacedName, proc *currentProcess) probe.ResultFunc {
+	return func(result prober.Result, statusChanged bool, output string, err error) {
+		if ctx.Err() != nil {
+			return
+		}
+
+		logProbeOutput(ctx, probeLogLevel(result, statusChanged), "readiness", result, output, nil)
 
 		if !statusChanged {
 			// the probe did not transition states, so the result is logged but not used to update status
@@ -451,7 +595,7 @@ func (c *Controller) handleProbeResultFunc(ctx context.Context, name types.Names
 	}
 }
 
-func logProbeOutput(ctx context.Context, level logger.Level, result prober.Result, output string, err error) {
+func logProbeOutput(ctx context.Context, level logger.Level, probeType string, result prober.Result, output string, err error) {
 	l := logger.Get(ctx)
 	if level == logger.NoneLvl || !l.Level().ShouldDisplay(level) {
 		return
@@ -459,12 +603,14 @@ func logProbeOutput(ctx context.Context, level logger.Level, result prober.Resul
 
 	w := l.Writer(level)
 	if err != nil {
-		_, _ = fmt.Fprintf(w, "[readiness probe error] %v\n", err)
+		_, _ = fmt.Fprintf(w, "[%s probe error] %v\n", probeType, err)
 	} else if output != "" {
 		var logMessage strings.Builder
 		s := bufio.NewScanner(strings.NewReader(output))
 		for s.Scan() {
-			logMessage.WriteString("[readiness probe: ")
+			logMessage.WriteString("[")
+			logMessage.WriteString(probeType)
+			logMessage.WriteString(" probe: ")
 			logMessage.WriteString(string(result))
 			logMessage.WriteString("] ")
 			logMessage.Write(s.Bytes())
@@ -475,13 +621,21 @@ func logProbeOutput(ctx context.Context, level logger.Level, result prober.Resul
 }
 
 const waitingOnStartOnReason = "cmd StartOn has not been triggered"
+const waitingOnRestartBackoffReason = "waiting to restart after a failed probe"
+
+const (
+	restartBackoffInitial = time.Second
+	restartBackoffMax     = time.Minute
+)
 
 func (c *Controller) processStatuses(
 	ctx context.Context,
 	statusCh chan statusAndMetadata,
 	proc *currentProcess,
 	name types.NamespacedName,
-	startedAt metav1.MicroTime) {
+	startedAt metav1.MicroTime,
+	probes probeWorkers,
+	output *logparser.Writer) {
 	defer close(proc.doneCh)
 
 	var initProbeWorker sync.Once
@@ -492,8 +646,12 @@ func (c *Controller) processStatuses(
 		}
 
 		if sm.status == Error || sm.status == Done {
+			// Log the last line, even if the process didn't end it with a newline.
+			output.Flush()
+
 			// This is a hack until CmdServer is a real object.
-			if proc.isServer && sm.exitCode == 0 {
+			// A server we stopped ourselves is allowed to exit cleanly.
+			if proc.isServer && sm.exitCode == 0 && ctx.Err() == nil {
 				logger.Get(ctx).Errorf("Server exited with exit code 0")
 			}
 
@@ -510,11 +668,9 @@ func (c *Controller) processStatuses(
 			})
 			c.requeuer.Add(name)
 		} else if sm.status == Running {
-			if proc.probeWorker != nil {
-				initProbeWorker.Do(func() {
-					go proc.probeWorker.Run(ctx)
-				})
-			}
+			initProbeWorker.Do(func() {
+				go probes.run(ctx)
+			})
 
 			proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
 				status.Waiting = nil
@@ -524,7 +680,7 @@ func (c *Controller) processStatuses(
 					StartedAt: startedAt,
 				}
 
-				if proc.probeWorker == nil {
+				if probes.readiness == nil && probes.startup == nil {
 					status.Ready = true
 				}
 			})
@@ -557,9 +713,8 @@ type currentProcess struct {
 	spec       CmdSpec
 	cancelFunc context.CancelFunc
 	// closed when the process finishes executing, intentionally or not
-	doneCh      chan struct{}
-	probeWorker *probe.Worker
-	isServer    bool
+	doneCh   chan struct{}
+	isServer bool
 
 	lastRestartOnEventTime metav1.MicroTime
 	lastStartOnEventTime   metav1.MicroTime
@@ -567,6 +722,80 @@ type currentProcess struct {
 	// We have a lock that ONLY protects the status.
 	statusMu       sync.Mutex
 	statusInternal v1alpha1.CmdStatus
+
+	// Set when a liveness or startup probe fails; protected by statusMu.
+	unhealthyReason string
+
+	// When the process has been stopped by a failing probe, the time
+	// it should be restarted; protected by statusMu.
+	restartAt time.Time
+
+	// The number of probe-triggered restarts since a probe last
+	// succeeded; protected by statusMu.
+	consecutiveRestarts int
+}
+
+// Clears the restart tracking when the process is replaced by a new spec.
+func (p *currentProcess) resetRestarts() {
+	p.mutateStatus(func(status *v1alpha1.CmdStatus) {
+		p.unhealthyReason = ""
+		p.restartAt = time.Time{}
+		p.consecutiveRestarts = 0
+		status.RestartCount = 0
+		status.LastRestartReason = ""
+	})
+}
+
+// The probe workers for a single run of a process.
+type probeWorkers struct {
+	startup   *probe.Worker
+	liveness  *probe.Worker
+	readiness *probe.Worker
+
+	// closed when the startup probe first succeeds
+	startupSucceeded chan struct{}
+}
+
+// Returns a callback that unblocks the other probes when the
+// startup probe succeeds.
+func (p probeWorkers) onStartupSuccess(c *Controller, name types.NamespacedName, proc *currentProcess, spec v1alpha1.CmdSpec) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(p.startupSucceeded)
+
+			if spec.ReadinessProbe == nil {
+				proc.mutateStatus(func(status *v1alpha1.CmdStatus) {
+					status.Ready = true
+				})
+				c.requeuer.Add(name)
+			}
+		})
+	}
+}
+
+// Runs the startup probe (if any) until it succeeds, then runs
+// the liveness and readiness probes until the context is canceled.
+func (p probeWorkers) run(ctx context.Context) {
+	if p.startup != nil {
+		startupCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		go p.startup.Run(startupCtx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-p.startupSucceeded:
+			cancel()
+		}
+	}
+
+	if p.liveness != nil {
+		go p.liveness.Run(ctx)
+	}
+	if p.readiness != nil {
+		go p.readiness.Run(ctx)
+	}
 }
 
 func (p *currentProcess) copyStatus() v1alpha1.CmdStatus {
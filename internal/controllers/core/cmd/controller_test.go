@@ -12,6 +12,7 @@ import (
 	"github.com/jonboulle/clockwork"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -19,6 +20,8 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	"github.com/tilt-dev/probe/pkg/prober"
+
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/engine/local"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -161,6 +164,116 @@ func TestServeReadinessProbeInvalidSpec(t *testing.T) {
 	assert.Equal(t, 0, f.fpm.ProbeCount())
 }
 
+func TestServeLivenessProbeRestartsProcess(t *testing.T) {
+	f := newFixture(t)
+
+	t1 := time.Unix(1, 0)
+
+	c := model.ToHostCmdInDir("sleep 60", "testdir")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil)
+	localTarget.LivenessProbe = &v1alpha1.Probe{
+		FailureThreshold: 1,
+		Handler: v1alpha1.Handler{
+			Exec: &v1alpha1.ExecAction{Command: []string{"check-health"}},
+		},
+	}
+	f.fpm.SetExecResult(prober.Failure, "connection refused")
+
+	f.resourceFromTarget("foo", localTarget, t1)
+	f.step()
+
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Waiting != nil &&
+			cmd.Status.Waiting.Reason == waitingOnRestartBackoffReason &&
+			cmd.Status.LastRestartReason == "liveness probe failed: connection refused" &&
+			cmd.Status.RestartCount == 0
+	})
+	f.assertLogMessage("foo", "liveness probe failed: connection refused. Restarting in 1s")
+
+	// Still backing off.
+	f.reconcileCmd("foo-serve-1")
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Waiting != nil && cmd.Status.RestartCount == 0
+	})
+
+	f.fpm.SetExecResult(prober.Success, "healthy")
+	f.clock.Advance(time.Second)
+	f.reconcileCmd("foo-serve-1")
+
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil &&
+			cmd.Status.Ready &&
+			cmd.Status.RestartCount == 1 &&
+			cmd.Status.LastRestartReason == "liveness probe failed: connection refused"
+	})
+	f.assertLogMessage("foo", "[liveness probe: success] healthy")
+
+	f.assertLocalResourceInfo("foo", func(lrs store.LocalRuntimeState) bool {
+		return lrs.RestartCount == 1 &&
+			lrs.LastRestartReason == "liveness probe failed: connection refused"
+	})
+}
+
+func TestServeStartupProbeFailureRestartsProcess(t *testing.T) {
+	f := newFixture(t)
+
+	t1 := time.Unix(1, 0)
+
+	c := model.ToHostCmdInDir("sleep 60", "testdir")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil)
+	localTarget.StartupProbe = &v1alpha1.Probe{
+		FailureThreshold: 1,
+		Handler: v1alpha1.Handler{
+			Exec: &v1alpha1.ExecAction{Command: []string{"check-started"}},
+		},
+	}
+	localTarget.ReadinessProbe = &v1alpha1.Probe{
+		Handler: v1alpha1.Handler{
+			TCPSocket: &v1alpha1.TCPSocketAction{Port: 8080},
+		},
+	}
+	f.fpm.SetExecResult(prober.Failure, "")
+
+	f.resourceFromTarget("foo", localTarget, t1)
+	f.step()
+
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Waiting != nil &&
+			cmd.Status.LastRestartReason == "startup probe failed"
+	})
+	assert.NotContains(t, f.Stdout(), "[readiness probe")
+}
+
+func TestServeStartupProbeGatesReadiness(t *testing.T) {
+	f := newFixture(t)
+
+	t1 := time.Unix(1, 0)
+
+	c := model.ToHostCmdInDir("sleep 60", "testdir")
+	localTarget := model.NewLocalTarget("foo", model.Cmd{}, c, nil)
+	localTarget.StartupProbe = &v1alpha1.Probe{
+		Handler: v1alpha1.Handler{
+			Exec: &v1alpha1.ExecAction{Command: []string{"check-started"}},
+		},
+	}
+
+	f.resourceFromTarget("foo", localTarget, t1)
+	f.step()
+
+	f.assertCmdMatches("foo-serve-1", func(cmd *Cmd) bool {
+		return cmd.Status.Running != nil && cmd.Status.Ready
+	})
+	f.assertLogMessage("foo", "[startup probe: success] fake probe succeeded")
+}
+
+func TestRestartBackoff(t *testing.T) {
+	assert.Equal(t, time.Second, restartBackoff(0))
+	assert.Equal(t, 2*time.Second, restartBackoff(1))
+	assert.Equal(t, 32*time.Second, restartBackoff(5))
+	assert.Equal(t, time.Minute, restartBackoff(6))
+	assert.Equal(t, time.Minute, restartBackoff(100))
+}
+
 func TestFailure(t *testing.T) {
 	f := newFixture(t)
 
@@ -799,6 +912,56 @@ func TestHiddenInput(t *testing.T) {
 	require.Equal(t, expectedEnv, actualEnv)
 }
 
+func TestForceRunTraceparent(t *testing.T) {
+	f := newFixture(t)
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(f.Context(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	cmd := &Cmd{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "testcmd",
+		},
+		Spec: v1alpha1.CmdSpec{
+			Args: []string{"myjob"},
+			Env:  []string{"foo=bar"},
+		},
+	}
+	err = f.Client.Create(f.Context(), cmd)
+	require.NoError(t, err)
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		_, err := f.c.ForceRun(ctx, cmd)
+		assert.NoError(t, err)
+	}()
+
+	var actualEnv []string
+	require.Eventually(t, func() bool {
+		f.fe.mu.Lock()
+		defer f.fe.mu.Unlock()
+		p, ok := f.fe.processes["myjob"]
+		if ok {
+			actualEnv = p.env
+		}
+		return ok
+	}, time.Second, 5*time.Millisecond)
+
+	expectedEnv := []string{"TRACEPARENT=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", "foo=bar"}
+	assert.Equal(t, expectedEnv, actualEnv)
+
+	require.NoError(t, f.fe.stop("myjob", 0))
+	<-doneCh
+}
+
 func TestChoiceInput(t *testing.T) {
 	for _, tc := range []struct {
 		name          string
@@ -1137,6 +1300,19 @@ func (f *fixture) requireCmdMatchesInAPI(name string, matcher func(cmd *Cmd) boo
 	return &cmd
 }
 
+func (f *fixture) assertLocalResourceInfo(name string, matcher func(lrs store.LocalRuntimeState) bool) {
+	f.T().Helper()
+	assert.Eventually(f.T(), func() bool {
+		st := f.st.RLockState()
+		defer f.st.RUnlockState()
+		mt, ok := st.ManifestTargets[model.ManifestName(name)]
+		if !ok {
+			return false
+		}
+		return matcher(mt.State.LocalRuntimeState())
+	}, timeout, interval)
+}
+
 func (f *fixture) assertCmdDeleted(name string) {
 	assert.Eventually(f.T(), func() bool {
 		cmd := f.st.Cmd(name)
@@ -13,6 +13,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/procutil"
@@ -23,7 +24,10 @@ var DefaultGracePeriod = 30 * time.Second
 type Execer interface {
 	// Returns a channel to pull status updates from. After the process exists
 	// (and transmits its final status), the channel is closed.
-	Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata
+	//
+	// When the context is canceled, the process is stopped as described
+	// by the stop spec (which may be nil).
+	Start(ctx context.Context, cmd model.Cmd, stop *v1alpha1.CmdStopSpec, w io.Writer) chan statusAndMetadata
 }
 
 type fakeExecProcess struct {
@@ -32,6 +36,7 @@ type fakeExecProcess struct {
 	workdir   string
 	env       []string
 	startTime time.Time
+	stop      *v1alpha1.CmdStopSpec
 }
 
 type FakeExecer struct {
@@ -46,7 +51,7 @@ func NewFakeExecer() *FakeExecer {
 	}
 }
 
-func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata {
+func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, stop *v1alpha1.CmdStopSpec, w io.Writer) chan statusAndMetadata {
 	e.mu.Lock()
 	oldProcess, ok := e.processes[cmd.String()]
 	e.mu.Unlock()
@@ -69,6 +74,7 @@ func (e *FakeExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan
 		workdir:   cmd.Dir,
 		startTime: time.Now(),
 		env:       cmd.Env,
+		stop:      stop,
 	}
 	e.mu.Unlock()
 
@@ -146,17 +152,17 @@ func NewProcessExecer(localEnv *localexec.Env) *processExecer {
 	}
 }
 
-func (e *processExecer) Start(ctx context.Context, cmd model.Cmd, w io.Writer) chan statusAndMetadata {
+func (e *processExecer) Start(ctx context.Context, cmd model.Cmd, stop *v1alpha1.CmdStopSpec, w io.Writer) chan statusAndMetadata {
 	statusCh := make(chan statusAndMetadata)
 
 	go func() {
-		e.processRun(ctx, cmd, w, statusCh)
+		e.processRun(ctx, cmd, stop, w, statusCh)
 	}()
 
 	return statusCh
 }
 
-func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writer, statusCh chan statusAndMetadata) {
+func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, stop *v1alpha1.CmdStopSpec, w io.Writer, statusCh chan statusAndMetadata) {
 	defer close(statusCh)
 
 	logger.Get(ctx).Infof("Running cmd: %s", cmd.String())
@@ -234,46 +240,149 @@ func (e *processExecer) processRun(ctx context.Context, cmd model.Cmd, w io.Writ
 		}
 		statusCh <- statusAndMetadata{status: status, pid: pid, exitCode: exitCode, reason: reason}
 	case <-ctx.Done():
-		e.killProcess(ctx, c, processExitCh)
-		statusCh <- statusAndMetadata{status: Done, pid: pid, reason: "killed", exitCode: 137}
+		exitErr, killed := e.killProcess(ctx, c, cmd, stop, w, processExitCh)
+		if killed {
+			statusCh <- statusAndMetadata{status: Done, pid: pid, reason: "killed", exitCode: 137}
+			return
+		}
+
+		// The process exited on its own after the stop signal,
+		// so report how it exited.
+		exitCode := 0
+		reason := ""
+		if ee, ok := exitErr.(*exec.ExitError); ok {
+			exitCode = ee.ExitCode()
+			if ws, ok := ee.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+				// By shell convention, a process terminated by a signal exits with 128+signal.
+				exitCode = 128 + int(ws.Signal())
+			}
+			reason = exitErr.Error()
+		} else if exitErr != nil {
+			exitCode = 1
+			reason = exitErr.Error()
+		}
+		statusCh <- statusAndMetadata{status: Done, pid: pid, reason: reason, exitCode: exitCode}
 	}
 }
 
-func (e *processExecer) killProcess(ctx context.Context, c *exec.Cmd, processExitCh chan error) {
+// Stops the process, first with the stop signal, then with SIGKILL
+// if it doesn't exit within the grace period.
+//
+// Returns how the process exited, and whether we had to kill it.
+func (e *processExecer) killProcess(ctx context.Context, c *exec.Cmd, cmd model.Cmd, stop *v1alpha1.CmdStopSpec, w io.Writer, processExitCh chan error) (error, bool) {
+	gracePeriod := e.gracePeriod
+	sig := syscall.SIGTERM
+	if stop != nil {
+		if stop.GracePeriodSeconds > 0 {
+			gracePeriod = time.Duration(stop.GracePeriodSeconds) * time.Second
+		}
+		if stop.Signal != "" {
+			s, err := procutil.ParseSignal(stop.Signal)
+			if err != nil {
+				logger.Get(ctx).Warnf("Invalid stop signal, sending SIGTERM instead: %v", err)
+			} else {
+				sig = s
+			}
+		}
+	}
+
+	deadline := time.Now().Add(gracePeriod)
+	if stop != nil && len(stop.PreStopArgs) > 0 {
+		e.runPreStop(ctx, cmd, stop.PreStopArgs, w, gracePeriod)
+
+		select {
+		case err := <-processExitCh:
+			return err, false
+		default:
+		}
+	}
+
 	logger.Get(ctx).Debugf("About to gracefully shut down process %d", c.Process.Pid)
-	err := procutil.GracefullyShutdownProcess(c.Process)
+	err := procutil.GracefullyShutdownProcessWithSignal(c.Process, sig)
 	if err != nil {
 		logger.Get(ctx).Debugf("Unable to gracefully kill process %d, sending SIGKILL to the process group: %v", c.Process.Pid, err)
 		procutil.KillProcessGroup(c)
-		return
+		return nil, true
 	}
 
-	// we wait 30 seconds to give the process enough time to finish doing any cleanup.
+	// By default, we wait 30 seconds to give the process enough time to finish doing any cleanup.
 	// this is the same timeout that Kubernetes uses
-	// TODO(dmiller): make this configurable via the Tiltfile
-	infoCh := time.After(e.gracePeriod / 20)
-	moreInfoCh := time.After(e.gracePeriod / 3)
-	finalCh := time.After(e.gracePeriod)
+	remaining := time.Until(deadline)
+	if remaining <= 0 {
+		logger.Get(ctx).Infof("Time is up! Sending %d a kill signal", c.Process.Pid)
+		procutil.KillProcessGroup(c)
+		return nil, true
+	}
+	infoCh := time.After(remaining / 20)
+	moreInfoCh := time.After(remaining / 3)
+	finalCh := time.After(remaining)
 
 	select {
 	case <-infoCh:
-		logger.Get(ctx).Infof("Waiting %s for process to exit... (pid: %d)", e.gracePeriod, c.Process.Pid)
-	case <-processExitCh:
-		return
+		logger.Get(ctx).Infof("Waiting %s for process to exit... (pid: %d)", remaining.Round(time.Second), c.Process.Pid)
+	case err := <-processExitCh:
+		return err, false
 	}
 
 	select {
 	case <-moreInfoCh:
 		logger.Get(ctx).Infof("Still waiting on exit... (pid: %d)", c.Process.Pid)
-	case <-processExitCh:
-		return
+	case err := <-processExitCh:
+		return err, false
 	}
 
 	select {
 	case <-finalCh:
 		logger.Get(ctx).Infof("Time is up! Sending %d a kill signal", c.Process.Pid)
 		procutil.KillProcessGroup(c)
-	case <-processExitCh:
+		return nil, true
+	case err := <-processExitCh:
+		return err, false
+	}
+}
+
+// Runs the pre-stop command to completion, or until the timeout,
+// whichever comes first.
+//
+// The main process context has already been canceled at this point,
+// so the pre-stop command runs on its own timer.
+func (e *processExecer) runPreStop(ctx context.Context, cmd model.Cmd, args []string, w io.Writer, timeout time.Duration) {
+	preStop := model.Cmd{Argv: args, Dir: cmd.Dir, Env: cmd.Env}
+	logger.Get(ctx).Infof("Running pre-stop cmd: %s", preStop.String())
+
+	c, err := e.localEnv.ExecCmd(preStop, logger.Get(ctx))
+	if err != nil {
+		logger.Get(ctx).Errorf("%q invalid pre-stop cmd: %v", preStop.String(), err)
 		return
 	}
+
+	c.SysProcAttr = &syscall.SysProcAttr{}
+	procutil.SetOptNewProcessGroup(c.SysProcAttr)
+	c.Stderr = w
+	c.Stdout = w
+
+	err = c.Start()
+	if err != nil {
+		logger.Get(ctx).Errorf("pre-stop cmd %s failed to start: %v", preStop.String(), err)
+		return
+	}
+
+	exitCh := make(chan error, 1)
+	go func() {
+		state, err := c.Process.Wait()
+		if err == nil && !state.Success() {
+			err = &exec.ExitError{ProcessState: state}
+		}
+		exitCh <- err
+	}()
+
+	select {
+	case err := <-exitCh:
+		if err != nil {
+			logger.Get(ctx).Warnf("pre-stop cmd %s failed: %v", preStop.String(), err)
+		}
+	case <-time.After(timeout):
+		logger.Get(ctx).Warnf("pre-stop cmd %s timed out after %s", preStop.String(), timeout)
+		procutil.KillProcessGroup(c)
+	}
 }
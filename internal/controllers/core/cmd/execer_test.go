@@ -16,6 +16,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
 	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -218,13 +219,17 @@ func (f *processExecFixture) tearDown() {
 
 func (f *processExecFixture) startMalformedCommand() {
 	c := model.Cmd{Argv: []string{"\""}, Dir: "."}
-	f.statusCh = f.execer.Start(f.ctx, c, f.testWriter)
+	f.statusCh = f.execer.Start(f.ctx, c, nil, f.testWriter)
 }
 
 func (f *processExecFixture) startWithWorkdir(cmd string, workdir string) {
+	f.startWithStop(cmd, workdir, nil)
+}
+
+func (f *processExecFixture) startWithStop(cmd string, workdir string, stop *v1alpha1.CmdStopSpec) {
 	c := model.ToHostCmd(cmd)
 	c.Dir = workdir
-	f.statusCh = f.execer.Start(f.ctx, c, f.testWriter)
+	f.statusCh = f.execer.Start(f.ctx, c, stop, f.testWriter)
 }
 
 func (f *processExecFixture) start(cmd string) {
@@ -235,7 +240,7 @@ func (f *processExecFixture) assertCmdSucceeds() {
 	f.waitForStatus(Done)
 }
 
-func (f *processExecFixture) waitForStatus(expectedStatus status) {
+func (f *processExecFixture) waitForStatus(expectedStatus status) statusAndMetadata {
 	deadlineCh := time.After(2 * time.Second)
 	for {
 		select {
@@ -244,15 +249,15 @@ func (f *processExecFixture) waitForStatus(expectedStatus status) {
 				f.t.Fatal("statusCh closed")
 			}
 			if expectedStatus == sm.status {
-				return
+				return sm
 			}
 			if sm.status == Error {
 				f.t.Error("Unexpected Error")
-				return
+				return sm
 			}
 			if sm.status == Done {
 				f.t.Error("Unexpected Done")
-				return
+				return sm
 			}
 		case <-deadlineCh:
 			f.t.Fatal("Timed out waiting for cmd sm")
@@ -13,6 +13,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 )
 
 func TestStopsBackgroundGrandchildren(t *testing.T) {
@@ -56,3 +58,76 @@ echo BACKGROUND $!
 		return err != nil && strings.Contains(err.Error(), "process already finished")
 	}, time.Second, time.Millisecond)
 }
+
+func TestShutdownWithStopSignal(t *testing.T) {
+	f := newProcessExecFixture(t)
+
+	f.startWithStop(`
+trap 'echo "caught" "INT"; exit 0' INT
+trap 'echo "caught" "TERM"; exit 0' TERM
+echo "traps" "ready"
+while true; do sleep 0.1; done
+`, ".", &v1alpha1.CmdStopSpec{Signal: "SIGINT"})
+	f.waitForStatus(Running)
+	f.assertLogContains("traps ready")
+	f.cancel()
+	sm := f.waitForStatus(Done)
+	f.assertLogContains("caught INT")
+	assert.NotContains(t, f.testWriter.String(), "caught TERM")
+	assert.Equal(t, 0, sm.exitCode)
+	assert.Equal(t, "", sm.reason)
+}
+
+func TestShutdownReportsExitCode(t *testing.T) {
+	f := newProcessExecFixture(t)
+
+	f.startWithStop(`
+trap 'echo "caught" "TERM"; exit 3' TERM
+echo "traps" "ready"
+while true; do sleep 0.1; done
+`, ".", nil)
+	f.waitForStatus(Running)
+	f.assertLogContains("traps ready")
+	f.cancel()
+	sm := f.waitForStatus(Done)
+	f.assertLogContains("caught TERM")
+	assert.Equal(t, 3, sm.exitCode)
+	assert.Equal(t, "exit status 3", sm.reason)
+}
+
+func TestShutdownRunsPreStop(t *testing.T) {
+	f := newProcessExecFixture(t)
+
+	f.startWithStop(`
+trap 'echo "caught" "TERM"; exit 0' TERM
+echo "traps" "ready"
+while true; do sleep 0.1; done
+`, ".", &v1alpha1.CmdStopSpec{PreStopArgs: []string{"sh", "-c", `echo "pre-stop" "done"`}})
+	f.waitForStatus(Running)
+	f.assertLogContains("traps ready")
+	f.cancel()
+	f.waitForStatus(Done)
+	f.assertLogContains("pre-stop done")
+	f.assertLogContains("caught TERM")
+
+	out := f.testWriter.String()
+	assert.Less(t, strings.Index(out, "pre-stop done"), strings.Index(out, "caught TERM"))
+}
+
+func TestShutdownKillsAfterStopGracePeriod(t *testing.T) {
+	f := newProcessExecFixture(t)
+
+	f.startWithStop(`
+trap 'echo "ignoring" "TERM"' TERM
+echo "traps" "ready"
+while true; do sleep 0.1; done
+`, ".", &v1alpha1.CmdStopSpec{GracePeriodSeconds: 1})
+	f.waitForStatus(Running)
+	f.assertLogContains("traps ready")
+	f.cancel()
+	sm := f.waitForStatus(Done)
+	f.assertLogContains("ignoring TERM")
+	f.assertLogContains("Time is up!")
+	assert.Equal(t, 137, sm.exitCode)
+	assert.Equal(t, "killed", sm.reason)
+}
@@ -20,13 +20,14 @@ import (
 var ErrUnsupportedProbeType = errors.New("unsupported probe type")
 
 func ProvideProberManager() ProberManager {
-	return prober.NewManager()
+	return proberManager{Manager: prober.NewManager()}
 }
 
 type ProberManager interface {
 	HTTPGet(u *url.URL, headers http.Header) prober.ProberFunc
 	TCPSocket(host string, port int) prober.ProberFunc
 	Exec(name string, args ...string) prober.ProberFunc
+	GRPC(host string, port int, service string, useTLS bool) prober.ProberFunc
 }
 
 func probeWorkerFromSpec(manager ProberManager, probeSpec *v1alpha1.Probe, resultFunc probe.ResultFunc) (*probe.Worker, error) {
@@ -86,6 +87,16 @@ func proberFromSpec(manager ProberManager, probeSpec *v1alpha1.Probe) (prober.Pr
 			host = "localhost"
 		}
 		return manager.TCPSocket(host, port), nil
+	} else if probeSpec.GRPC != nil {
+		port, err := extractPort(probeSpec.GRPC.Port)
+		if err != nil {
+			return nil, err
+		}
+		host := probeSpec.GRPC.Host
+		if host == "" {
+			host = "localhost"
+		}
+		return manager.GRPC(host, port, probeSpec.GRPC.Service, probeSpec.GRPC.TLS), nil
 	}
 
 	return nil, ErrUnsupportedProbeType
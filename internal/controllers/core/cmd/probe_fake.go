@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"net/url"
+	"sync"
 	"sync/atomic"
 
 	"github.com/tilt-dev/probe/pkg/prober"
@@ -24,6 +25,23 @@ type FakeProberManager struct {
 
 	execName string
 	execArgs []string
+
+	grpcHost    string
+	grpcPort    int
+	grpcService string
+	grpcTLS     bool
+
+	mu         sync.Mutex
+	execResult prober.Result
+	execOutput string
+}
+
+// Overrides the result of all exec probes, including ones that are already running.
+func (m *FakeProberManager) SetExecResult(result prober.Result, output string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.execResult = result
+	m.execOutput = output
 }
 
 func (m *FakeProberManager) HTTPGet(u *url.URL, headers http.Header) prober.ProberFunc {
@@ -44,6 +62,22 @@ func (m *FakeProberManager) Exec(name string, args ...string) prober.ProberFunc
 	m.execName = name
 	m.execArgs = args
 	atomic.AddInt32(&m.probeCount, 1)
+	return func(ctx context.Context) (prober.Result, string, error) {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		if m.execResult != "" {
+			return m.execResult, m.execOutput, nil
+		}
+		return successProbe(ctx)
+	}
+}
+
+func (m *FakeProberManager) GRPC(host string, port int, service string, useTLS bool) prober.ProberFunc {
+	m.grpcHost = host
+	m.grpcPort = port
+	m.grpcService = service
+	m.grpcTLS = useTLS
+	atomic.AddInt32(&m.probeCount, 1)
 	return successProbe
 }
 
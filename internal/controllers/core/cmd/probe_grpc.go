@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	grpcstatus "google.golang.org/grpc/status"
+
+	"github.com/tilt-dev/probe/pkg/prober"
+)
+
+// proberManager adds gRPC health checks to the standard probe types.
+type proberManager struct {
+	*prober.Manager
+}
+
+// GRPC returns a ProberFunc that performs gRPC health checks for a specific service.
+func (m proberManager) GRPC(host string, port int, service string, useTLS bool) prober.ProberFunc {
+	return func(ctx context.Context) (prober.Result, string, error) {
+		return doGRPCProbe(ctx, net.JoinHostPort(host, strconv.Itoa(port)), service, useTLS)
+	}
+}
+
+// doGRPCProbe checks service status with the gRPC health checking protocol.
+// If the service reports SERVING, it returns Success.
+// If the service reports any other status, or the server can't be reached, it returns Failure.
+//
+// adapted from https://github.com/kubernetes/kubernetes/blob/v1.27.0/pkg/probe/grpc/grpc.go
+func doGRPCProbe(ctx context.Context, addr string, service string, useTLS bool) (prober.Result, string, error) {
+	creds := insecure.NewCredentials()
+	if useTLS {
+		// Local services commonly use self-signed certificates, so we only
+		// care that the server speaks TLS, not who signed its certificate.
+		creds = credentials.NewTLS(&tls.Config{InsecureSkipVerify: true}) //nolint:gosec
+	}
+
+	conn, err := grpc.NewClient(addr,
+		grpc.WithTransportCredentials(creds),
+		grpc.WithUserAgent("tilt-probe"))
+	if err != nil {
+		return prober.Failure, fmt.Sprintf("failed to connect to %s: %v", addr, err), nil
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{Service: service})
+	if err != nil {
+		switch grpcstatus.Code(err) {
+		case codes.Unimplemented:
+			return prober.Failure, fmt.Sprintf("server at %s does not implement the grpc health protocol (grpc.health.v1.Health)", addr), nil
+		case codes.NotFound:
+			return prober.Failure, fmt.Sprintf("server at %s does not know about service %q", addr, service), nil
+		}
+		return prober.Failure, fmt.Sprintf("health rpc to %s failed: %v", addr, grpcstatus.Convert(err).Message()), nil
+	}
+
+	if resp.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return prober.Failure, fmt.Sprintf("service unhealthy (responded with %q)", resp.GetStatus()), nil
+	}
+	return prober.Success, "", nil
+}
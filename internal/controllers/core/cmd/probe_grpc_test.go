@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"k8s.io/client-go/util/cert"
+
+	"github.com/tilt-dev/probe/pkg/prober"
+)
+
+func TestGRPCProbeServing(t *testing.T) {
+	f := newGRPCProbeFixture(t)
+	f.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	f.assertProbe(false, "", prober.Success, "")
+}
+
+func TestGRPCProbeNotServing(t *testing.T) {
+	f := newGRPCProbeFixture(t)
+	f.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	f.assertProbe(false, "", prober.Failure, `service unhealthy (responded with "NOT_SERVING")`)
+}
+
+func TestGRPCProbeNamedService(t *testing.T) {
+	f := newGRPCProbeFixture(t)
+	f.health.SetServingStatus("frontend", healthpb.HealthCheckResponse_SERVING)
+	f.health.SetServingStatus("backend", healthpb.HealthCheckResponse_NOT_SERVING)
+
+	f.assertProbe(false, "frontend", prober.Success, "")
+	f.assertProbe(false, "backend", prober.Failure, `service unhealthy (responded with "NOT_SERVING")`)
+	f.assertProbe(false, "database", prober.Failure, `does not know about service "database"`)
+}
+
+func TestGRPCProbeUnimplemented(t *testing.T) {
+	f := newGRPCProbeFixtureWithServer(t, grpc.NewServer(), false)
+
+	f.assertProbe(false, "", prober.Failure, "does not implement the grpc health protocol")
+}
+
+func TestGRPCProbeConnectionRefused(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := l.Addr().String()
+	require.NoError(t, l.Close())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	result, output, err := doGRPCProbe(ctx, addr, "", false)
+	require.NoError(t, err)
+	assert.Equal(t, prober.Failure, result)
+	assert.Contains(t, output, "health rpc to "+addr+" failed")
+}
+
+func TestGRPCProbeTLS(t *testing.T) {
+	certPEM, keyPEM, err := cert.GenerateSelfSignedCertKey("localhost", nil, nil)
+	require.NoError(t, err)
+	keyPair, err := tls.X509KeyPair(certPEM, keyPEM)
+	require.NoError(t, err)
+
+	creds := credentials.NewServerTLSFromCert(&keyPair)
+	f := newGRPCProbeFixtureWithServer(t, grpc.NewServer(grpc.Creds(creds)), true)
+	f.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+
+	f.assertProbe(true, "", prober.Success, "")
+	f.assertProbe(false, "", prober.Failure, "health rpc to "+f.addr+" failed")
+}
+
+type grpcProbeFixture struct {
+	t      *testing.T
+	addr   string
+	health *health.Server
+}
+
+func newGRPCProbeFixture(t *testing.T) *grpcProbeFixture {
+	return newGRPCProbeFixtureWithServer(t, grpc.NewServer(), true)
+}
+
+func newGRPCProbeFixtureWithServer(t *testing.T, server *grpc.Server, registerHealth bool) *grpcProbeFixture {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	hs := health.NewServer()
+	if registerHealth {
+		healthpb.RegisterHealthServer(server, hs)
+	}
+
+	go func() {
+		_ = server.Serve(l)
+	}()
+	t.Cleanup(server.Stop)
+
+	return &grpcProbeFixture{
+		t:      t,
+		addr:   l.Addr().String(),
+		health: hs,
+	}
+}
+
+func (f *grpcProbeFixture) assertProbe(useTLS bool, service string, expectedResult prober.Result, expectedOutput string) {
+	f.t.Helper()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, output, err := doGRPCProbe(ctx, f.addr, service, useTLS)
+	require.NoError(f.t, err)
+	assert.Equal(f.t, expectedResult, result)
+	if expectedOutput == "" {
+		assert.Empty(f.t, output)
+	} else {
+		assert.Contains(f.t, output, expectedOutput)
+	}
+}
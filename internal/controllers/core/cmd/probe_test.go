@@ -160,3 +160,42 @@ func TestProbeFromSpecExec(t *testing.T) {
 		})
 	}
 }
+
+func TestProbeFromSpecGRPC(t *testing.T) {
+	type tc struct {
+		grpc        *v1alpha1.GRPCAction
+		expectedErr string
+	}
+	cases := []tc{
+		{&v1alpha1.GRPCAction{Port: 50051}, ""},
+		{&v1alpha1.GRPCAction{Host: "127.0.0.1", Port: 9090, Service: "frontend", TLS: true}, ""},
+		{&v1alpha1.GRPCAction{Port: 0}, "port number out of range: 0"},
+		{&v1alpha1.GRPCAction{Port: 65536}, "port number out of range: 65536"},
+	}
+	for i, tc := range cases {
+		t.Run(fmt.Sprintf("[%d] %v", i, tc.grpc), func(t *testing.T) {
+			probeSpec := &v1alpha1.Probe{
+				Handler: v1alpha1.Handler{
+					GRPC: tc.grpc,
+				},
+			}
+			manager := &FakeProberManager{}
+			p, err := proberFromSpec(manager, probeSpec)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.NotNil(t, p)
+			if tc.grpc.Host != "" {
+				assert.Equal(t, tc.grpc.Host, manager.grpcHost)
+			} else {
+				assert.Equal(t, "localhost", manager.grpcHost)
+			}
+			assert.Equal(t, int(tc.grpc.Port), manager.grpcPort)
+			assert.Equal(t, tc.grpc.Service, manager.grpcService)
+			assert.Equal(t, tc.grpc.TLS, manager.grpcTLS)
+		})
+	}
+}
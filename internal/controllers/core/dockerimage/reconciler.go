@@ -16,6 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
 	"github.com/tilt-dev/tilt/internal/docker"
@@ -23,6 +24,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store/dockerimages"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -97,6 +99,21 @@ func (r *Reconciler) ForceApply(
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	ps *build.PipelineState) (store.ImageBuildResult, error) {
+	return r.ForceApplyWithInputHash(ctx, iTarget, cluster, imageMaps, "", ps)
+}
+
+// Like ForceApply, but if we already built an image from inputs with the
+// given hash, use that image instead of building a new one.
+//
+// Newly built images are tagged with the hash, so that we can find them
+// the next time the inputs hash to the same value.
+func (r *Reconciler) ForceApplyWithInputHash(
+	ctx context.Context,
+	iTarget model.ImageTarget,
+	cluster *v1alpha1.Cluster,
+	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	inputHash string,
+	ps *build.PipelineState) (store.ImageBuildResult, error) {
 
 	// TODO(nick): It might make sense to reset the ImageMapStatus here
 	// to an empty image while the image is building. maybe?
@@ -112,10 +129,26 @@ func (r *Reconciler) ForceApply(
 	r.requeuer.Add(nn)
 	defer r.requeuer.Add(nn)
 
-	refs, stages, err := r.ib.Build(ctx, iTarget, nil, cluster, imageMaps, ps)
+	refs, found, err := r.ib.FindImageByInputHash(ctx, iTarget, cluster, inputHash, ps)
 	if err != nil {
-		r.setImageStatus(nn, ToCompletedFailStatus(iTarget, startTime, stages, err))
-		return store.ImageBuildResult{}, err
+		logger.Get(ctx).Debugf("Looking for image with input hash %s: %v", inputHash, err)
+	}
+
+	var stages []v1alpha1.DockerImageStageStatus
+	if found {
+		logger.Get(ctx).Infof("Skipping build: image %s was already built from the same inputs (hash %s)",
+			container.FamiliarString(refs.LocalRef), inputHash)
+	} else {
+		refs, stages, err = r.ib.Build(ctx, iTarget, nil, cluster, imageMaps, ps)
+		if err != nil {
+			r.setImageStatus(nn, ToCompletedFailStatus(iTarget, startTime, stages, err))
+			return store.ImageBuildResult{}, err
+		}
+
+		err = r.ib.TagInputHash(ctx, iTarget, cluster, refs, inputHash)
+		if err != nil {
+			logger.Get(ctx).Warnf("Tagging image with input hash %s: %v", inputHash, err)
+		}
 	}
 
 	r.setImageStatus(nn, ToCompletedSuccessStatus(iTarget, startTime, stages, refs))
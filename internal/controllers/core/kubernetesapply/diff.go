@@ -0,0 +1,152 @@
+package kubernetesapply
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// How long an apply waits for the user to confirm a diff.
+const diffConfirmTimeoutDefault = 10 * time.Minute
+
+const diffConfirmPollInterval = 500 * time.Millisecond
+
+// Shows what the apply will change, and checks for confirmation
+// if the diff changes any of the kinds that need it.
+//
+// If wait is true, blocks until the user confirms the diff.
+// Otherwise, returns an error if the diff isn't confirmed yet.
+func (r *Reconciler) previewDiff(ctx context.Context, nn types.NamespacedName, spec v1alpha1.KubernetesApplySpec,
+	entities []k8s.K8sEntity, status *applyResult, wait bool) error {
+	diffs, err := r.k8sClient.Diff(ctx, entities, FieldManager)
+	if err != nil {
+		return fmt.Errorf("diff preview: %v", err)
+	}
+
+	status.Diff = k8s.FormatDiffs(diffs)
+	printDiff(ctx, diffs)
+
+	needConfirm := diffsNeedingConfirmation(diffs, spec.DiffPreview.ConfirmKinds)
+	if len(needConfirm) == 0 {
+		return nil
+	}
+
+	hash := DiffHash(status.Diff)
+	l := logger.Get(ctx)
+	if r.isDiffConfirmed(ctx, nn, hash) {
+		l.Infof("Diff confirmed")
+		return nil
+	}
+
+	l.Warnf("Changes to %s need confirmation. To apply them, run:\n  tilt diff --confirm %s",
+		strings.Join(needConfirm, ", "), nn.Name)
+
+	if !wait {
+		status.DiffAwaitingConfirmation = hash
+		return fmt.Errorf("waiting for confirmation of changes to %s", strings.Join(needConfirm, ", "))
+	}
+
+	r.publishPendingDiff(ctx, nn, status.Diff, hash)
+	defer r.publishPendingDiff(ctx, nn, status.Diff, "")
+
+	timeout := r.diffConfirmTimeout
+	if timeout == 0 {
+		timeout = diffConfirmTimeoutDefault
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	ticker := time.NewTicker(diffConfirmPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			return fmt.Errorf("timed out after %s waiting for confirmation of changes to %s",
+				timeout, strings.Join(needConfirm, ", "))
+		case <-ticker.C:
+		}
+
+		if r.isDiffConfirmed(ctx, nn, hash) {
+			l.Infof("Diff confirmed")
+			return nil
+		}
+	}
+}
+
+func (r *Reconciler) isDiffConfirmed(ctx context.Context, nn types.NamespacedName, hash string) bool {
+	var ka v1alpha1.KubernetesApply
+	err := r.ctrlClient.Get(ctx, nn, &ka)
+	return err == nil && ka.Annotations[v1alpha1.AnnotationConfirmedDiff] == hash
+}
+
+// Publish the diff on the API object while we wait, so that
+// `tilt diff --confirm` knows what it's confirming.
+func (r *Reconciler) publishPendingDiff(ctx context.Context, nn types.NamespacedName, diff, hash string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	result := r.ensureResultExists(nn)
+	update := result.Status.DeepCopy()
+	update.Diff = diff
+	update.DiffAwaitingConfirmation = hash
+	result.Status = *update
+
+	var ka v1alpha1.KubernetesApply
+	err := r.ctrlClient.Get(ctx, nn, &ka)
+	if err != nil {
+		return
+	}
+
+	_, err = r.maybeUpdateStatus(ctx, nn, &ka)
+	if err != nil {
+		logger.Get(ctx).Debugf("updating diff status: %v", err)
+	}
+}
+
+// Returns the names of changed objects whose kinds need confirmation.
+func diffsNeedingConfirmation(diffs []k8s.EntityDiff, confirmKinds []string) []string {
+	var result []string
+	for _, d := range diffs {
+		if !d.HasChanges() {
+			continue
+		}
+		for _, kind := range confirmKinds {
+			if strings.EqualFold(d.Entity.GVK().Kind, kind) {
+				result = append(result, fmt.Sprintf("%s/%s", d.Entity.GVK().Kind, d.Entity.Name()))
+				break
+			}
+		}
+	}
+	return result
+}
+
+func printDiff(ctx context.Context, diffs []k8s.EntityDiff) {
+	l := logger.Get(ctx)
+	l.Infof("Diff against the cluster:")
+	for _, line := range strings.Split(strings.TrimSuffix(k8s.FormatDiffs(diffs), "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++"):
+			l.Infof("  %s", logger.Green(l).Sprint(line))
+		case strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---"):
+			l.Infof("  %s", logger.Red(l).Sprint(line))
+		default:
+			l.Infof("  %s", line)
+		}
+	}
+}
+
+// A short hash that identifies a diff, so that a confirmation
+// only applies to the diff the user saw.
+func DiffHash(diff string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(diff)))[:16]
+}
@@ -0,0 +1,148 @@
+package kubernetesapply
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// We never prune these kinds, because deleting them
+// deletes everything in them.
+var neverPruneKinds = map[string]bool{
+	"Namespace":                true,
+	"CustomResourceDefinition": true,
+}
+
+// PruneCandidates lists the objects in the cluster from earlier applies of
+// a KubernetesApply that aren't in the given objects anymore.
+//
+// Tilt labels each object it applies with the applyset of the KubernetesApply,
+// so we can find these objects even if Tilt restarted since it applied them.
+//
+// We only look for objects with the kinds of the given objects and the earlier
+// applied objects, in the namespaces they were deployed to. Objects without a
+// namespace are in the default namespace.
+func PruneCandidates(ctx context.Context, kCli k8s.Client, applySet string, defaultNS k8s.Namespace,
+	entities []k8s.K8sEntity, applied []k8s.K8sEntity) ([]k8s.K8sEntity, error) {
+	if applySet == "" {
+		return nil, nil
+	}
+	selector := labels.Set{k8s.ApplySetLabel: applySet}.AsSelector()
+
+	var kinds []schema.GroupVersionKind
+	seenKinds := make(map[schema.GroupKind]bool)
+	var namespaces []k8s.Namespace
+	seenNamespaces := make(map[k8s.Namespace]bool)
+	for _, e := range append(append([]k8s.K8sEntity{}, entities...), applied...) {
+		gvk := e.GVK()
+		if !seenKinds[gvk.GroupKind()] && !neverPruneKinds[gvk.Kind] {
+			seenKinds[gvk.GroupKind()] = true
+			kinds = append(kinds, gvk)
+		}
+
+		ns := k8s.Namespace(e.NamespaceOrDefault(defaultNS.String()))
+		if !seenNamespaces[ns] {
+			seenNamespaces[ns] = true
+			namespaces = append(namespaces, ns)
+		}
+	}
+
+	var result []k8s.K8sEntity
+	seenUIDs := make(map[types.UID]bool)
+	for _, gvk := range kinds {
+		for _, ns := range namespaces {
+			// Cluster-scoped kinds ignore the namespace, so we may
+			// see the same object more than once.
+			metas, err := kCli.ListMetaBySelector(ctx, gvk, ns, selector)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, m := range metas {
+				if seenUIDs[m.GetUID()] ||
+					m.GetDeletionTimestamp() != nil ||
+					m.GetAnnotations()[k8s.PruneAnnotation] == k8s.PruneDisabled ||
+					containsObject(entities, gvk, m) {
+					continue
+				}
+				seenUIDs[m.GetUID()] = true
+				result = append(result, entityFromMeta(gvk, m))
+			}
+		}
+	}
+	return result, nil
+}
+
+// Deletes objects from earlier applies that aren't in the deployed objects anymore.
+//
+// Objects that we applied in this session are garbage-collected when
+// we reconcile, so this only needs to handle objects from earlier sessions.
+// Pruning is best-effort, so we only log errors.
+func (r *Reconciler) prune(ctx context.Context, nn types.NamespacedName, applySet string, deployed []k8s.K8sEntity) {
+	r.mu.Lock()
+	var applied, known []k8s.K8sEntity
+	for key, result := range r.results {
+		for _, e := range result.AppliedObjects {
+			if key == nn {
+				applied = append(applied, e)
+			}
+			known = append(known, e)
+		}
+	}
+	r.mu.Unlock()
+
+	l := logger.Get(ctx)
+	candidates, err := PruneCandidates(ctx, r.k8sClient, applySet, k8s.DefaultNamespace, deployed, applied)
+	if err != nil {
+		l.Warnf("Listing objects to prune: %v", err)
+		return
+	}
+
+	var toPrune []k8s.K8sEntity
+	for _, c := range candidates {
+		if !containsObject(known, c.GVK(), c.Meta()) {
+			toPrune = append(toPrune, c)
+		}
+	}
+	if len(toPrune) == 0 {
+		return
+	}
+
+	r.printAppliedReport(ctx, "Pruning objects removed from the resource:", toPrune)
+	err = r.k8sClient.Delete(ctx, toPrune, 0)
+	if err != nil {
+		l.Warnf("Pruning objects: %v", err)
+	}
+}
+
+// Whether the object is one of the entities.
+//
+// Entities don't always specify a namespace, so we treat an empty namespace as a match.
+func containsObject(entities []k8s.K8sEntity, gvk schema.GroupVersionKind, m metav1.Object) bool {
+	for _, e := range entities {
+		ns := e.Meta().GetNamespace()
+		if e.GVK().GroupKind() == gvk.GroupKind() && e.Name() == m.GetName() &&
+			(ns == "" || ns == m.GetNamespace()) {
+			return true
+		}
+	}
+	return false
+}
+
+func entityFromMeta(gvk schema.GroupVersionKind, m metav1.Object) k8s.K8sEntity {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	obj.SetName(m.GetName())
+	obj.SetNamespace(m.GetNamespace())
+	obj.SetUID(m.GetUID())
+	obj.SetLabels(m.GetLabels())
+	obj.SetAnnotations(m.GetAnnotations())
+	return k8s.NewK8sEntity(obj)
+}
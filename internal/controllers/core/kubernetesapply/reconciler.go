@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 	batchv1 "k8s.io/api/batch/v1"
@@ -44,6 +46,9 @@ type deleteSpec struct {
 	cluster   *v1alpha1.Cluster
 }
 
+// FieldManager is the field manager for server-side applies and diffs.
+const FieldManager = "tilt"
+
 type Reconciler struct {
 	st         store.RStore
 	k8sClient  k8s.Client
@@ -52,6 +57,9 @@ type Reconciler struct {
 	execer     localexec.Execer
 	requeuer   *indexer.Requeuer
 
+	// How long to wait for the user to confirm a diff. Overridden in tests.
+	diffConfirmTimeout time.Duration
+
 	mu sync.Mutex
 
 	// Protected by the mutex.
@@ -159,7 +167,7 @@ func (r *Reconciler) Reconcile(ctx context.Context, request reconcile.Request) (
 		// be a reason why we're not deploying, and we should update the
 		// Status field of KubernetesApply with that reason.
 		if r.shouldDeployOnReconcile(request.NamespacedName, &ka, &cluster, imageMaps, lastRestartEvent) {
-			_ = r.forceApplyHelper(ctx, nn, ka.Spec, &cluster, imageMaps)
+			_ = r.forceApplyHelper(ctx, nn, ka.Spec, &cluster, imageMaps, false)
 			gcReason = "garbage collecting removed Kubernetes objects"
 		}
 	}
@@ -226,6 +234,12 @@ func (r *Reconciler) shouldDeployOnReconcile(
 		return true
 	}
 
+	if result.Status.DiffAwaitingConfirmation != "" &&
+		ka.Annotations[v1alpha1.AnnotationConfirmedDiff] == result.Status.DiffAwaitingConfirmation {
+		// The user confirmed the diff we're waiting on.
+		return true
+	}
+
 	imageMapNames := ka.Spec.ImageMaps
 	if len(imageMapNames) != len(result.ImageMapSpecs) ||
 		len(imageMapNames) != len(result.ImageMapStatuses) {
@@ -264,19 +278,24 @@ func (r *Reconciler) ForceApply(
 	spec v1alpha1.KubernetesApplySpec,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) v1alpha1.KubernetesApplyStatus {
-	status := r.forceApplyHelper(ctx, nn, spec, cluster, imageMaps)
+	status := r.forceApplyHelper(ctx, nn, spec, cluster, imageMaps, true)
 	r.requeuer.Add(nn)
 	return status
 }
 
 // A helper that applies the given specs to the cluster,
 // tracking the state of the deploy in the results map.
+//
+// If the diff needs confirmation, waitForDiffConfirm determines whether we
+// block until it's confirmed, or fail and let the reconciler re-apply
+// when it's confirmed.
 func (r *Reconciler) forceApplyHelper(
 	ctx context.Context,
 	nn types.NamespacedName,
 	spec v1alpha1.KubernetesApplySpec,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	waitForDiffConfirm bool,
 ) v1alpha1.KubernetesApplyStatus {
 
 	startTime := apis.NowMicro()
@@ -298,10 +317,25 @@ func (r *Reconciler) forceApplyHelper(
 	var deployed []k8s.K8sEntity
 	deployCtx := r.indentLogger(ctx)
 	if spec.YAML != "" {
-		deployed, err = r.runYAMLDeploy(deployCtx, spec, imageMaps)
+		// Create API objects.
+		entities, err := EntitiesToDeploy(deployCtx, imageMaps, spec)
+		if err != nil {
+			return recordErrorStatus(err)
+		}
+
+		if spec.DiffPreview != nil {
+			err = r.previewDiff(deployCtx, nn, spec, entities, &status, waitForDiffConfirm)
+			if err != nil {
+				return recordErrorStatus(err)
+			}
+		}
+
+		deployed, status.Conflicts, err = r.runYAMLDeploy(deployCtx, spec, entities)
 		if err != nil {
 			return recordErrorStatus(err)
 		}
+
+		r.prune(deployCtx, nn, spec.ApplySet, deployed)
 	} else {
 		deployed, err = r.runCmdDeploy(deployCtx, spec, cluster, imageMaps)
 		if err != nil {
@@ -337,13 +371,7 @@ func (r *Reconciler) printAppliedReport(ctx context.Context, msg string, deploye
 	}
 }
 
-func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec, imageMaps map[types.NamespacedName]*v1alpha1.ImageMap) ([]k8s.K8sEntity, error) {
-	// Create API objects.
-	newK8sEntities, err := r.createEntitiesToDeploy(ctx, imageMaps, spec)
-	if err != nil {
-		return newK8sEntities, err
-	}
-
+func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.KubernetesApplySpec, newK8sEntities []k8s.K8sEntity) ([]k8s.K8sEntity, []v1alpha1.KubernetesApplyConflict, error) {
 	logger.Get(ctx).Infof("Applying YAML to cluster")
 
 	timeout := spec.Timeout.Duration
@@ -351,18 +379,96 @@ func (r *Reconciler) runYAMLDeploy(ctx context.Context, spec v1alpha1.Kubernetes
 		timeout = v1alpha1.KubernetesApplyTimeoutDefault
 	}
 
-	deployed, err := r.k8sClient.Upsert(ctx, newK8sEntities, timeout, k8s.SSAOptions{
-		Enabled:      spec.ServerSideApply,
-		Force:        spec.ServerSideApply,
-		FieldManager: "tilt",
-	})
+	deployed, conflicts, err := r.upsert(ctx, spec, newK8sEntities, timeout)
 	if err != nil {
 		r.printAppliedReport(ctx, "Tried to apply objects to cluster:", newK8sEntities)
-		return nil, err
+		return nil, conflicts, err
 	}
 	r.printAppliedReport(ctx, "Objects applied to cluster:", deployed)
 
-	return deployed, nil
+	return deployed, conflicts, nil
+}
+
+// Applies the entities, handling field manager conflicts according to
+// the conflict policy of the spec.
+//
+// When yielding, we drop the conflicting fields and retry. Each retry
+// resolves the conflicts of at least one object, so the number of
+// entities bounds the number of retries.
+func (r *Reconciler) upsert(ctx context.Context, spec v1alpha1.KubernetesApplySpec, entities []k8s.K8sEntity, timeout time.Duration) ([]k8s.K8sEntity, []v1alpha1.KubernetesApplyConflict, error) {
+	policy := spec.SSAConflictPolicy
+	ssa := k8s.SSAOptions{
+		Enabled:      spec.ServerSideApply,
+		Force:        spec.ServerSideApply && (policy == "" || policy == v1alpha1.SSAConflictPolicyForce),
+		FieldManager: FieldManager,
+	}
+
+	var conflicts []v1alpha1.KubernetesApplyConflict
+	for attempt := 0; ; attempt++ {
+		deployed, err := r.k8sClient.Upsert(ctx, entities, timeout, ssa)
+		if err == nil {
+			return deployed, conflicts, nil
+		}
+
+		var conflictErr *k8s.FieldConflictError
+		if !errors.As(err, &conflictErr) {
+			return nil, conflicts, err
+		}
+
+		if policy != v1alpha1.SSAConflictPolicyYield || attempt >= len(entities) {
+			for _, c := range conflictErr.Conflicts {
+				conflicts = append(conflicts, toAPIConflict(c, false))
+			}
+			return nil, conflicts, fieldConflictsError(conflictErr.Conflicts)
+		}
+
+		l := logger.Get(ctx)
+		for _, c := range conflictErr.Conflicts {
+			entities, err = removeConflictingField(entities, c)
+			if err != nil {
+				return nil, conflicts, err
+			}
+			l.Warnf("Yielding field %s of %s to field manager %q", c.Field, c.Object(), c.Manager)
+			conflicts = append(conflicts, toAPIConflict(c, true))
+		}
+	}
+}
+
+func removeConflictingField(entities []k8s.K8sEntity, c k8s.FieldConflict) ([]k8s.K8sEntity, error) {
+	result := make([]k8s.K8sEntity, len(entities))
+	for i, e := range entities {
+		result[i] = e
+		if !c.MatchesEntity(e) {
+			continue
+		}
+
+		newEntity, _, err := k8s.RemoveFieldPath(e, c.Field)
+		if err != nil {
+			return nil, fmt.Errorf("yielding field %s of %s: %v", c.Field, c.Object(), err)
+		}
+		result[i] = newEntity
+	}
+	return result, nil
+}
+
+func toAPIConflict(c k8s.FieldConflict, yielded bool) v1alpha1.KubernetesApplyConflict {
+	return v1alpha1.KubernetesApplyConflict{
+		Object:  c.Object(),
+		Manager: c.Manager,
+		Field:   c.Field,
+		Yielded: yielded,
+	}
+}
+
+func fieldConflictsError(conflicts []k8s.FieldConflict) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Server-side apply failed with %d field conflict(s):", len(conflicts))
+	for _, c := range conflicts {
+		fmt.Fprintf(&sb, "\n  %s: %s is owned by %q", c.Object(), c.Field, c.Manager)
+	}
+	sb.WriteString("\nTo take ownership of these fields, use k8s_resource(ssa_conflicts='force'). " +
+		"To leave them to their current managers, use k8s_resource(ssa_conflicts='yield').")
+	return errors.New(sb.String())
 }
 
 func (r *Reconciler) maybeInjectKubeconfig(cmd *model.Cmd, cluster *v1alpha1.Cluster) error {
@@ -456,7 +562,9 @@ type injectResult struct {
 	imageMap *v1alpha1.ImageMap
 }
 
-func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
+// EntitiesToDeploy parses the YAML of the spec and injects the images and labels,
+// returning the objects that an apply of the spec would send to the cluster.
+func EntitiesToDeploy(ctx context.Context,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
 	spec v1alpha1.KubernetesApplySpec) ([]k8s.K8sEntity, error) {
 	newK8sEntities := []k8s.K8sEntity{}
@@ -481,6 +589,9 @@ func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
 		if err != nil {
 			return nil, errors.Wrap(err, "deploy")
 		}
+		if spec.ApplySet != "" {
+			e = k8s.InjectApplySetLabel(e, spec.ApplySet)
+		}
 
 		// If we're redeploying these workloads in response to image
 		// changes, we make sure image pull policy isn't set to "Always".
@@ -586,12 +697,15 @@ func (r *Reconciler) createEntitiesToDeploy(ctx context.Context,
 }
 
 type applyResult struct {
-	ResultYAML         string
-	Error              string
-	LastApplyTime      metav1.MicroTime
-	LastApplyStartTime metav1.MicroTime
-	AppliedInputHash   string
-	Objects            []k8s.K8sEntity
+	ResultYAML               string
+	Error                    string
+	LastApplyTime            metav1.MicroTime
+	LastApplyStartTime       metav1.MicroTime
+	AppliedInputHash         string
+	Objects                  []k8s.K8sEntity
+	Conflicts                []v1alpha1.KubernetesApplyConflict
+	Diff                     string
+	DiffAwaitingConfirmation string
 }
 
 // conditionsFromApply extracts any conditions based on the result.
@@ -658,6 +772,9 @@ func (r *Reconciler) recordApplyResult(
 	updatedStatus.LastApplyTime = applyResult.LastApplyTime
 	updatedStatus.AppliedInputHash = applyResult.AppliedInputHash
 	updatedStatus.Conditions = conditionsFromApply(applyResult)
+	updatedStatus.Conflicts = applyResult.Conflicts
+	updatedStatus.Diff = applyResult.Diff
+	updatedStatus.DiffAwaitingConfirmation = applyResult.DiffAwaitingConfirmation
 
 	result.Cluster = cluster
 	result.Spec = spec
@@ -812,6 +929,10 @@ func (r *Reconciler) garbageCollect(nn types.NamespacedName, isDeleting bool) de
 		if ann, ok := v.Annotations()["tilt.dev/down-policy"]; ok && ann == "keep" {
 			continue
 		}
+		// Objects removed from the apply aren't pruned if they opt out.
+		if !isDeleting && v.Annotations()[k8s.PruneAnnotation] == k8s.PruneDisabled {
+			continue
+		}
 		toDelete = append(toDelete, v)
 	}
 	if isDeleting {
@@ -112,6 +112,259 @@ func TestBasicApplyYAML(t *testing.T) {
 	assert.Equal(f.T(), f.kClient.Yaml, "")
 }
 
+func TestApplySSAConflictPolicyForce(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.FieldManagerConflicts = []k8s.FieldConflict{
+		{Kind: "Deployment", Namespace: "default", Name: "sancho", Manager: "hpa-controller", Field: ".spec.replicas"},
+	}
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:            testyaml.SanchoYAML,
+			ServerSideApply: true,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Empty(t, ka.Status.Error)
+	assert.Empty(t, ka.Status.Conflicts)
+	assert.True(t, f.kClient.UpsertSSA.Force)
+	assert.Contains(t, f.kClient.Yaml, "replicas: 1")
+}
+
+func TestApplySSAConflictPolicyFail(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.FieldManagerConflicts = []k8s.FieldConflict{
+		{Kind: "Deployment", Namespace: "default", Name: "sancho", Manager: "hpa-controller", Field: ".spec.replicas"},
+	}
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:              testyaml.SanchoYAML,
+			ServerSideApply:   true,
+			SSAConflictPolicy: v1alpha1.SSAConflictPolicyFail,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Contains(t, ka.Status.Error,
+		`deployment/default/sancho: .spec.replicas is owned by "hpa-controller"`)
+	assert.Equal(t, []v1alpha1.KubernetesApplyConflict{
+		{Object: "deployment/default/sancho", Manager: "hpa-controller", Field: ".spec.replicas"},
+	}, ka.Status.Conflicts)
+	assert.False(t, f.kClient.UpsertSSA.Force)
+}
+
+func TestApplySSAConflictPolicyYield(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.FieldManagerConflicts = []k8s.FieldConflict{
+		{Kind: "Deployment", Namespace: "default", Name: "sancho", Manager: "hpa-controller", Field: ".spec.replicas"},
+		{Kind: "Deployment", Namespace: "default", Name: "sancho", Manager: "kubectl-edit",
+			Field: `.spec.template.spec.containers[name="sancho"].env[name="token"]`},
+	}
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:              testyaml.SanchoYAML,
+			ServerSideApply:   true,
+			SSAConflictPolicy: v1alpha1.SSAConflictPolicyYield,
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Empty(t, ka.Status.Error)
+	assert.Equal(t, []v1alpha1.KubernetesApplyConflict{
+		{Object: "deployment/default/sancho", Manager: "hpa-controller", Field: ".spec.replicas", Yielded: true},
+		{Object: "deployment/default/sancho", Manager: "kubectl-edit",
+			Field: `.spec.template.spec.containers[name="sancho"].env[name="token"]`, Yielded: true},
+	}, ka.Status.Conflicts)
+
+	assert.NotContains(t, f.kClient.Yaml, "replicas:")
+	assert.NotContains(t, f.kClient.Yaml, "slacktoken")
+	assert.Contains(t, f.kClient.Yaml, "image: gcr.io/some-project-162817/sancho")
+	assert.Contains(t, f.Stdout(), `Yielding field .spec.replicas of deployment/default/sancho to field manager "hpa-controller"`)
+}
+
+func TestApplyDiffPreview(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.Diffs = map[string]string{"sancho": "-  replicas: 1\n+  replicas: 2\n"}
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:        testyaml.SanchoYAML,
+			DiffPreview: &v1alpha1.KubernetesApplyDiffPreview{},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Empty(t, ka.Status.Error)
+	assert.Equal(t, "sancho:deployment:\n-  replicas: 1\n+  replicas: 2\n", ka.Status.Diff)
+	assert.Empty(t, ka.Status.DiffAwaitingConfirmation)
+	assert.Contains(t, f.Stdout(), "+  replicas: 2")
+	assert.Contains(t, f.kClient.Yaml, "name: sancho")
+}
+
+func TestApplyDiffPreviewConfirm(t *testing.T) {
+	f := newFixture(t)
+	f.kClient.Diffs = map[string]string{"sancho": "-  replicas: 1\n+  replicas: 2\n"}
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML: testyaml.SanchoYAML,
+			DiffPreview: &v1alpha1.KubernetesApplyDiffPreview{
+				ConfirmKinds: []string{"deployment"},
+			},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Contains(t, ka.Status.Error, "waiting for confirmation of changes to Deployment/sancho")
+	assert.Equal(t, DiffHash(ka.Status.Diff), ka.Status.DiffAwaitingConfirmation)
+	assert.Contains(t, f.Stdout(), "tilt diff --confirm a")
+	assert.Empty(t, f.kClient.Yaml)
+
+	ka.Annotations = map[string]string{v1alpha1.AnnotationConfirmedDiff: ka.Status.DiffAwaitingConfirmation}
+	f.Update(&ka)
+	f.MustReconcile(types.NamespacedName{Name: "a"})
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Empty(t, ka.Status.Error)
+	assert.Empty(t, ka.Status.DiffAwaitingConfirmation)
+	assert.Contains(t, f.kClient.Yaml, "name: sancho")
+}
+
+func TestApplyDiffPreviewNoConfirmWithoutChanges(t *testing.T) {
+	f := newFixture(t)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML: testyaml.SanchoYAML,
+			DiffPreview: &v1alpha1.KubernetesApplyDiffPreview{
+				ConfirmKinds: []string{"Deployment"},
+			},
+		},
+	}
+	f.Create(&ka)
+
+	f.MustGet(types.NamespacedName{Name: "a"}, &ka)
+	assert.Empty(t, ka.Status.Error)
+	assert.Equal(t, "sancho:deployment: no changes\n", ka.Status.Diff)
+	assert.Contains(t, f.kClient.Yaml, "name: sancho")
+}
+
+func TestForceApplyDiffConfirmTimeout(t *testing.T) {
+	f := newFixture(t)
+	f.r.diffConfirmTimeout = 10 * time.Millisecond
+	f.kClient.Diffs = map[string]string{"sancho": "-  replicas: 1\n+  replicas: 2\n"}
+
+	spec := v1alpha1.KubernetesApplySpec{
+		YAML: testyaml.SanchoYAML,
+		DiffPreview: &v1alpha1.KubernetesApplyDiffPreview{
+			ConfirmKinds: []string{"Deployment"},
+		},
+	}
+	status := f.r.ForceApply(f.Context(), types.NamespacedName{Name: "a"}, spec, nil, nil)
+	assert.Contains(t, status.Error, "timed out after 10ms waiting for confirmation of changes to Deployment/sancho")
+	assert.Empty(t, status.DiffAwaitingConfirmation)
+	assert.Empty(t, f.kClient.Yaml)
+}
+
+func TestApplyPrunesObjectsFromEarlierApplies(t *testing.T) {
+	f := newFixture(t)
+	applySet := k8s.ApplySetID("/project/Tiltfile", "a")
+	f.injectDeployment("sancho-old", "default", applySet, nil)
+	f.injectDeployment("sancho-kept", "default", applySet, map[string]string{k8s.PruneAnnotation: k8s.PruneDisabled})
+	f.injectDeployment("sancho-other", "default", k8s.ApplySetID("/project/Tiltfile", "b"), nil)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:     testyaml.SanchoYAML,
+			ApplySet: applySet,
+		},
+	}
+	f.Create(&ka)
+
+	assert.Contains(t, f.kClient.Yaml, "tilt.dev/applyset: "+applySet)
+	assert.Contains(t, f.Stdout(), "Pruning objects removed from the resource:")
+	assert.Contains(t, f.kClient.DeletedYaml, "name: sancho-old")
+	assert.NotContains(t, f.kClient.DeletedYaml, "name: sancho-kept")
+	assert.NotContains(t, f.kClient.DeletedYaml, "name: sancho-other")
+	assert.NotContains(t, f.kClient.DeletedYaml, "name: sancho\n")
+}
+
+func TestApplyDoesNotPruneOtherSessions(t *testing.T) {
+	f := newFixture(t)
+	applySet := k8s.ApplySetID("/project/Tiltfile", "a")
+
+	// Another Tilt session with a resource of the same name.
+	f.injectDeployment("sancho-theirs", "default", k8s.ApplySetID("/other/project/Tiltfile", "a"), nil)
+
+	// An object with our applyset, in a namespace that we never deployed to.
+	f.injectDeployment("sancho-elsewhere", "other-team", applySet, nil)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML:     testyaml.SanchoYAML,
+			ApplySet: applySet,
+		},
+	}
+	f.Create(&ka)
+
+	assert.NotContains(t, f.Stdout(), "Pruning objects removed from the resource:")
+	assert.NotContains(t, f.kClient.DeletedYaml, "name: sancho-theirs")
+	assert.NotContains(t, f.kClient.DeletedYaml, "name: sancho-elsewhere")
+}
+
+func TestApplyWithoutApplySetDoesNotPrune(t *testing.T) {
+	f := newFixture(t)
+	f.injectDeployment("sancho-old", "default", k8s.ApplySetID("", "a"), nil)
+
+	ka := v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "a"},
+		Spec: v1alpha1.KubernetesApplySpec{
+			YAML: testyaml.SanchoYAML,
+		},
+	}
+	f.Create(&ka)
+
+	assert.NotContains(t, f.kClient.Yaml, "tilt.dev/applyset")
+	assert.NotContains(t, f.kClient.DeletedYaml, "name: sancho-old")
+}
+
+func TestPruneCandidates(t *testing.T) {
+	f := newFixture(t)
+	applySet := k8s.ApplySetID("/project/Tiltfile", "a")
+	f.injectDeployment("sancho", "default", applySet, nil)
+	f.injectDeployment("sancho-old", "default", applySet, nil)
+	f.injectDeployment("sancho-elsewhere", "other-team", applySet, nil)
+
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+
+	candidates, err := PruneCandidates(f.Context(), f.kClient, applySet, k8s.DefaultNamespace, entities, nil)
+	require.NoError(t, err)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "sancho-old", candidates[0].Name())
+	assert.Equal(t, "Deployment", candidates[0].GVK().Kind)
+
+	candidates, err = PruneCandidates(f.Context(), f.kClient, applySet, k8s.DefaultNamespace, nil, nil)
+	require.NoError(t, err)
+	assert.Empty(t, candidates)
+}
+
 func TestBasicApplyCmd(t *testing.T) {
 	f := newFixture(t)
 
@@ -811,6 +1064,21 @@ func newFixture(t *testing.T) *fixture {
 	return f
 }
 
+// injectDeployment adds a Deployment to the cluster, as if an earlier
+// Tilt session had applied it with the given applyset.
+func (f *fixture) injectDeployment(name string, namespace string, applySet string, annotations map[string]string) {
+	f.T().Helper()
+
+	entities, err := k8s.ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(f.T(), err)
+	e := k8s.InjectApplySetLabel(entities[0], applySet)
+	e.Meta().SetName(name)
+	e.Meta().SetNamespace(namespace)
+	e.Meta().SetAnnotations(annotations)
+	e.SetUID(string(uuid.NewUUID()))
+	f.kClient.Inject(e)
+}
+
 // createApplyCmd creates a KubernetesApplyCmd that use the passed YAML to generate simulated stdout via the FakeExecer.
 func (f *fixture) createApplyCmd(name string, yaml string) (v1alpha1.KubernetesApplyCmd, string) {
 	f.T().Helper()
@@ -774,6 +774,7 @@ func (w *Reconciler) createPodLogStream(ctx context.Context, kd *v1alpha1.Kubern
 			SinceTime:        plsTemplate.SinceTime,
 			IgnoreContainers: plsTemplate.IgnoreContainers,
 			OnlyContainers:   plsTemplate.OnlyContainers,
+			LogParser:        plsTemplate.LogParser,
 		},
 	}
 
@@ -930,6 +930,9 @@ func (r *Reconciler) applyInternal(
 
 	var result v1alpha1.LiveUpdateStatus
 	cu := r.containerUpdater(input)
+	if ds, ok := cu.(containerupdate.DeltaSyncer); ok && spec.DeltaSync != nil {
+		cu = ds.WithDeltaSync()
+	}
 	l := logger.Get(ctx)
 	containers := input.Containers
 	names := liveupdates.ContainerDisplayNames(containers)
@@ -955,11 +958,13 @@ func (r *Reconciler) applyInternal(
 	// re-walking them to build the tar.
 	var toRemove []build.PathMapping
 	var toArchive []build.PathMapping
+	var toVerify []build.PathMapping
 	archiveFilter := model.EmptyMatcher
 	if input.InitialSync {
 		// No files to remove during initial sync — all files exist locally.
 		toRemove = nil
 		toArchive = build.SyncsToPathMappings(liveupdate.SyncSteps(spec))
+		toVerify = changedFiles
 		archiveFilter = input.InitialSyncFilter
 		l.Infof("Initial sync: will copy sync paths to container%s: %s", suffix, names)
 		for _, pm := range toArchive {
@@ -976,6 +981,7 @@ func (r *Reconciler) applyInternal(
 			}
 			return result
 		}
+		toVerify = toArchive
 
 		if len(toRemove) > 0 {
 			l.Infof("Will delete %d file(s) from container%s: %s", len(toRemove), suffix, names)
@@ -1039,6 +1045,14 @@ func (r *Reconciler) applyInternal(
 				return result
 			}
 		} else {
+			if spec.Verify != nil {
+				failed := verifyContainer(ctx, cu, cInfo, toVerify, toRemove)
+				if failed != nil {
+					result.Failed = failed
+					return result
+				}
+			}
+
 			logger.Get(ctx).Infof("  → Container %s updated!", cInfo.DisplayName())
 			if lastExecErrorStatus != nil {
 				// This build succeeded, but previously at least one failed due to user error.
@@ -24,6 +24,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -907,6 +908,109 @@ func TestDockerComposeExecRunFailure(t *testing.T) {
 	}
 }
 
+func TestVerifySync(t *testing.T) {
+	f := newFixture(t)
+
+	dir := f.setupVerifyFrontend()
+	txtPath := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(txtPath, []byte("hello"), 0644))
+
+	f.addFileEvent("frontend-fw", txtPath, metav1.MicroTime{Time: apis.NowMicro().Add(time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	assert.Nil(t, lu.Status.Failed)
+	assert.Len(t, lu.Status.Containers, 1)
+	assert.Contains(t, f.cu.Files, "/app/a.txt")
+	assert.Contains(t, f.Stdout(), "Container pod-1/main updated!")
+}
+
+func TestVerifySyncDrift(t *testing.T) {
+	f := newFixture(t)
+
+	dir := f.setupVerifyFrontend()
+	txtPath := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(txtPath, []byte("hello"), 0644))
+	cu := &driftingContainerUpdater{FakeContainerUpdater: f.cu, path: "/app/a.txt"}
+	f.r.ExecUpdater = cu
+
+	f.addFileEvent("frontend-fw", txtPath, metav1.MicroTime{Time: apis.NowMicro().Add(time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	if assert.NotNil(t, lu.Status.Failed) {
+		assert.Equal(t, "FileDrift", lu.Status.Failed.Reason)
+		assert.Contains(t, lu.Status.Failed.Message, "a.txt")
+	}
+	assert.Contains(t, f.Stdout(), "has 1 file(s) that don't match the local copy")
+	assert.NotContains(t, f.Stdout(), "updated!")
+
+	if assert.NotNil(t, f.st.lastCompletedAction) {
+		assert.Error(t, f.st.lastCompletedAction.Error)
+	}
+
+	// The container stays failed until the next build.
+	f.assertSteadyState(&lu)
+}
+
+func TestVerifySyncDeletedFileDrift(t *testing.T) {
+	f := newFixture(t)
+
+	dir := f.setupVerifyFrontend()
+	cu := &driftingContainerUpdater{FakeContainerUpdater: f.cu, path: "/app/gone.txt"}
+	f.r.ExecUpdater = cu
+
+	f.addFileEvent("frontend-fw", filepath.Join(dir, "gone.txt"), metav1.MicroTime{Time: apis.NowMicro().Add(time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	if assert.NotNil(t, lu.Status.Failed) {
+		assert.Equal(t, "FileDrift", lu.Status.Failed.Reason)
+		assert.Contains(t, lu.Status.Failed.Message, "gone.txt")
+	}
+	if assert.Len(t, f.cu.Calls, 1) {
+		assert.Equal(t, []string{"/app/gone.txt"}, f.cu.Calls[0].ToDelete)
+	}
+}
+
+func TestVerifySyncHashError(t *testing.T) {
+	f := newFixture(t)
+
+	dir := f.setupVerifyFrontend()
+	txtPath := filepath.Join(dir, "a.txt")
+	require.NoError(t, os.WriteFile(txtPath, []byte("hello"), 0644))
+	f.cu.HashErr = errors.New("sha256sum not found")
+
+	f.addFileEvent("frontend-fw", txtPath, metav1.MicroTime{Time: apis.NowMicro().Add(time.Second)})
+	f.MustReconcile(types.NamespacedName{Name: "frontend-liveupdate"})
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	if assert.NotNil(t, lu.Status.Failed) {
+		assert.Equal(t, "VerifyFailed", lu.Status.Failed.Reason)
+		assert.Equal(t, "Verifying container pod-1/main: sha256sum not found", lu.Status.Failed.Message)
+	}
+}
+
+// Simulates a process in the container that rewrites
+// (or re-creates) a synced file right after the sync.
+type driftingContainerUpdater struct {
+	*containerupdate.FakeContainerUpdater
+	path string
+}
+
+func (cu *driftingContainerUpdater) HashFiles(ctx context.Context, cInfo liveupdates.Container, paths []string) (map[string]string, error) {
+	hashes, err := cu.FakeContainerUpdater.HashFiles(ctx, cInfo, paths)
+	if err != nil {
+		return nil, err
+	}
+	hashes[cu.path] = "rewritten"
+	return hashes, nil
+}
+
 type TestingStore struct {
 	*store.TestingStore
 	ctx                 context.Context
@@ -1126,6 +1230,20 @@ func (f *fixture) setupDockerComposeFrontend() {
 	})
 }
 
+// Create a frontend LiveUpdate that verifies syncs from a temp dir.
+func (f *fixture) setupVerifyFrontend() string {
+	dir := f.T().TempDir()
+	f.setupFrontend()
+
+	var lu v1alpha1.LiveUpdate
+	f.MustGet(types.NamespacedName{Name: "frontend-liveupdate"}, &lu)
+	update := lu.DeepCopy()
+	update.Spec.BasePath = dir
+	update.Spec.Verify = &v1alpha1.LiveUpdateVerify{}
+	f.Update(update)
+	return dir
+}
+
 func (f *fixture) assertSteadyState(lu *v1alpha1.LiveUpdate) {
 	startCalls := len(f.cu.Calls)
 
@@ -0,0 +1,124 @@
+package liveupdate
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/containerupdate"
+	"github.com/tilt-dev/tilt/internal/ospath"
+	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// verifyContainer checks the container's copy of the synced files,
+// and returns a failed state if they've drifted from the local files.
+func verifyContainer(ctx context.Context, cu containerupdate.ContainerUpdater, cInfo liveupdates.Container,
+	toArchive []build.PathMapping, toRemove []build.PathMapping) *v1alpha1.LiveUpdateStateFailed {
+	drifted, err := verifySync(ctx, cu, cInfo, toArchive, toRemove)
+	if err != nil {
+		return &v1alpha1.LiveUpdateStateFailed{
+			Reason:  "VerifyFailed",
+			Message: fmt.Sprintf("Verifying container %s: %v", cInfo.DisplayName(), err),
+		}
+	}
+
+	if len(drifted) == 0 {
+		return nil
+	}
+
+	l := logger.Get(ctx)
+	l.Infof("  → Container %s has %d file(s) that don't match the local copy:", cInfo.DisplayName(), len(drifted))
+	for _, p := range drifted {
+		l.Infof("- %s", p)
+	}
+	return &v1alpha1.LiveUpdateStateFailed{
+		Reason: "FileDrift",
+		Message: fmt.Sprintf("Container %s has files that don't match the local copy after sync (files: %s)",
+			cInfo.DisplayName(), ospath.FormatFileChangeList(drifted)),
+	}
+}
+
+// verifySync compares the synced files in the container against the local files.
+//
+// Returns the local paths of any files that don't match. Files in toRemove
+// are expected to be absent from the container. Directories and symlinks
+// aren't checked.
+func verifySync(ctx context.Context, cu containerupdate.ContainerUpdater, cInfo liveupdates.Container,
+	toArchive []build.PathMapping, toRemove []build.PathMapping) ([]string, error) {
+	// Maps container path -> expected checksum, where an empty checksum
+	// means the file should not exist.
+	expected := make(map[string]string, len(toArchive)+len(toRemove))
+	localPaths := make(map[string]string, len(toArchive)+len(toRemove))
+	for _, pm := range toRemove {
+		expected[pm.ContainerPath] = ""
+		localPaths[pm.ContainerPath] = pm.LocalPath
+	}
+
+	for _, pm := range toArchive {
+		info, err := os.Lstat(pm.LocalPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				// The file was deleted after we archived it. The next file
+				// event will sync the delete.
+				continue
+			}
+			return nil, fmt.Errorf("verifying %s: %v", pm.LocalPath, err)
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		hash, err := hashLocalFile(pm.LocalPath)
+		if err != nil {
+			return nil, fmt.Errorf("verifying %s: %v", pm.LocalPath, err)
+		}
+		expected[pm.ContainerPath] = hash
+		localPaths[pm.ContainerPath] = pm.LocalPath
+	}
+
+	if len(expected) == 0 {
+		return nil, nil
+	}
+
+	containerPaths := make([]string, 0, len(expected))
+	for p := range expected {
+		containerPaths = append(containerPaths, p)
+	}
+	sort.Strings(containerPaths)
+
+	actual, err := cu.HashFiles(ctx, cInfo, containerPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	var drifted []string
+	for _, p := range containerPaths {
+		if actual[p] != expected[p] {
+			drifted = append(drifted, localPaths[p])
+		}
+	}
+	return drifted, nil
+}
+
+func hashLocalFile(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/engine/runtimelog"
 	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/logparser"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -270,6 +271,7 @@ func (c *Controller) addOrUpdateContainerWatches(ctx context.Context, streamName
 			debounce:       debounce,
 			doneCh:         make(chan struct{}),
 			shouldPrefix:   shouldPrefix,
+			logParser:      stream.Spec.LogParser,
 		}
 		c.watches[key] = w
 
@@ -409,7 +411,8 @@ func (c *Controller) consumeLogs(watch *podLogWatch, st store.RStore) {
 			}
 		}()
 
-		writer := &errorCapturingWriter{underlying: logger.Get(ctx).Writer(logger.InfoLvl)}
+		parser := logparser.NewWriter(logger.Get(ctx), watch.logParser)
+		writer := &errorCapturingWriter{underlying: parser}
 		_, err = io.Copy(writer, reader)
 		_ = readCloser.Close()
 		close(done)
@@ -423,6 +426,10 @@ func (c *Controller) consumeLogs(watch *podLogWatch, st store.RStore) {
 			_, _ = writer.Write(newline)
 		}
 
+		// The parser holds back incomplete lines. When the stream ends,
+		// make sure the last line (often the crash message) is logged.
+		parser.Flush()
+
 		if err == nil && errorTerminated != "" {
 			err = errors.New(errorTerminated)
 		}
@@ -560,6 +567,8 @@ type podLogWatch struct {
 	doneCh         chan struct{}
 
 	shouldPrefix bool // if true, we'll prefix logs with the container name
+
+	logParser v1alpha1.LogParser
 }
 
 type podLogKey struct {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"sync"
 	"testing"
 	"testing/synctest"
 	"time"
@@ -101,6 +102,47 @@ func TestLogActions(t *testing.T) {
 	f.ConsumeLogActionsUntil("hello world!")
 }
 
+func TestLogParser(t *testing.T) {
+	f := newPLMFixture(t)
+
+	f.kClient.SetLogsForPodContainer(podID, cName,
+		`{"level":"warn","msg":"slow request","request_id":"abc"}`+"\nplain text\n"+`{"level":"error","msg":"boom"}`)
+
+	pb := newPodBuilder(podID).addRunningContainer(cName, cID)
+	f.kClient.UpsertPod(pb.toPod())
+
+	pls := plsFromPod("server", pb, time.Time{})
+	pls.Spec.LogParser = v1alpha1.LogParserJSON
+	f.Create(pls)
+
+	f.triggerPodEvent(podID)
+	f.AssertOutputContains("slow request request_id=abc\nplain text\nboom\n")
+
+	actions := f.store.LogActions()
+	require.Len(t, actions, 3)
+	assert.Equal(t, logger.WarnLvl, actions[0].Level())
+	assert.Equal(t, "abc", actions[0].Fields()["request_id"])
+	assert.Equal(t, logger.InfoLvl, actions[1].Level())
+	assert.Equal(t, logger.ErrorLvl, actions[2].Level())
+}
+
+func TestLogParserStreamEndsWithoutNewline(t *testing.T) {
+	f := newPLMFixture(t)
+
+	f.kClient.SetLogsForPodContainer(podID, cName,
+		`{"level":"info","msg":"starting"}`+"\npanic: runtime error: index out of range")
+
+	pb := newPodBuilder(podID).addRunningContainer(cName, cID)
+	f.kClient.UpsertPod(pb.toPod())
+
+	pls := plsFromPod("server", pb, time.Time{})
+	pls.Spec.LogParser = v1alpha1.LogParserJSON
+	f.Create(pls)
+
+	f.triggerPodEvent(podID)
+	f.AssertOutputContains("starting\npanic: runtime error: index out of range\n")
+}
+
 func TestLogsFailed(t *testing.T) {
 	f := newPLMFixture(t)
 
@@ -517,6 +559,9 @@ type plmStore struct {
 	t testing.TB
 	*store.TestingStore
 	out *bufsync.ThreadSafeBuffer
+
+	mu         sync.Mutex
+	logActions []store.LogAction
 }
 
 func newPLMStore(t testing.TB, out *bufsync.ThreadSafeBuffer) *plmStore {
@@ -533,12 +578,22 @@ func (s *plmStore) Dispatch(action store.Action) {
 		s.t.Errorf("Expected action type LogAction. Actual: %T", action)
 	}
 
+	s.mu.Lock()
+	s.logActions = append(s.logActions, event)
+	s.mu.Unlock()
+
 	_, err := s.out.Write(event.Message())
 	if err != nil {
 		fmt.Printf("error writing event: %v\n", err)
 	}
 }
 
+func (s *plmStore) LogActions() []store.LogAction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]store.LogAction{}, s.logActions...)
+}
+
 type plmFixture struct {
 	*fake.ControllerFixture
 	t       testing.TB
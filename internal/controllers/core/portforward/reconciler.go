@@ -13,6 +13,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
 	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/indexer"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/timecmp"
 	"github.com/tilt-dev/tilt/pkg/apis"
 	"github.com/tilt-dev/tilt/pkg/logger"
@@ -41,6 +42,7 @@ type Reconciler struct {
 	clients    *cluster.ClientManager
 	requeuer   *indexer.Requeuer
 	indexer    *indexer.Indexer
+	metrics    *metrics.Metrics
 
 	// map of PortForward object name --> running forward(s)
 	activeForwards map[types.NamespacedName]*portForwardEntry
@@ -54,9 +56,11 @@ func NewReconciler(
 	scheme *runtime.Scheme,
 	store store.RStore,
 	clients cluster.ClientProvider,
+	metrics *metrics.Metrics,
 ) *Reconciler {
 	return &Reconciler{
 		store:          store,
+		metrics:        metrics,
 		ctrlClient:     ctrlClient,
 		clients:        cluster.NewClientManager(clients),
 		requeuer:       indexer.NewRequeuer(),
@@ -181,9 +185,10 @@ func (r *Reconciler) maybeUpdateStatus(ctx context.Context, pf *v1alpha1.PortFor
 
 func (r *Reconciler) onePortForward(ctx context.Context, entry *portForwardEntry, forward Forward) {
 	logError := func(err error) {
+		mn := entry.meta.Annotations[v1alpha1.AnnotationManifest]
 		logger.Get(ctx).Infof("Reconnecting... Error port-forwarding %s (%d -> %d): %v",
-			entry.meta.Annotations[v1alpha1.AnnotationManifest],
-			forward.LocalPort, forward.ContainerPort, err)
+			mn, forward.LocalPort, forward.ContainerPort, err)
+		r.metrics.PortForwardReconnected(mn)
 	}
 
 	pf, err := entry.client.CreatePortForwarder(
@@ -18,6 +18,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/controllers/apis/cluster"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/pkg/apis"
 
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -351,7 +352,7 @@ type pfrFixture struct {
 func newPFRFixture(t *testing.T) *pfrFixture {
 	cfb := fake.NewControllerFixtureBuilder(t)
 	clients := cluster.NewFakeClientProvider(t, cfb.Client)
-	r := NewReconciler(cfb.Client, cfb.Scheme(), cfb.Store, clients)
+	r := NewReconciler(cfb.Client, cfb.Scheme(), cfb.Store, clients, metrics.NewMetrics(cfb.Store))
 
 	return &pfrFixture{
 		ControllerFixture: cfb.WithRequeuer(r.requeuer).Build(r),
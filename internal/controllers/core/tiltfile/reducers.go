@@ -149,6 +149,7 @@ func HandleConfigsReloaded(
 			mt.Manifest.IsDC() != m.IsDC()
 		if createNew {
 			mt = store.NewManifestTarget(m)
+			mt.State.RestoredBuildHistory = state.RestoredBuildHistory[m.Name]
 		}
 
 		configFilesThatChanged := ms.LastBuild().Edits
@@ -0,0 +1,130 @@
+package buildkit
+
+import (
+	"encoding/csv"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	CacheTypeInline   = "inline"
+	CacheTypeRegistry = "registry"
+	CacheTypeLocal    = "local"
+)
+
+// CacheSpec describes a cache import or export, in the syntax of the
+// --cache-from and --cache-to flags of `docker buildx build`. For example:
+//
+//	type=registry,ref=gcr.io/my-project/my-image:cache
+//	type=local,dest=path/to/dir
+//	type=inline
+//
+// A value without a type is shorthand for a registry ref.
+//
+// Tilt builds with the Docker engine, which can only embed cache metadata in
+// the image itself (mode=min). So registry and local caches are stored as
+// images with inline cache metadata.
+type CacheSpec struct {
+	Type string
+
+	// The image ref, for registry caches.
+	Ref string
+
+	// The directory, for local caches. Set from `dest` for exports
+	// and `src` for imports.
+	Dir string
+
+	dirKey string
+}
+
+func (s CacheSpec) WithDir(dir string) CacheSpec {
+	s.Dir = dir
+	return s
+}
+
+func (s CacheSpec) String() string {
+	switch s.Type {
+	case CacheTypeRegistry:
+		return fmt.Sprintf("type=%s,ref=%s", s.Type, s.Ref)
+	case CacheTypeLocal:
+		return fmt.Sprintf("type=%s,%s=%s", s.Type, s.dirKey, s.Dir)
+	}
+	return fmt.Sprintf("type=%s", s.Type)
+}
+
+// ParseCacheTo parses a --cache-to value.
+func ParseCacheTo(value string) (CacheSpec, error) {
+	return parseCacheSpec(value, "dest")
+}
+
+// ParseCacheFrom parses a --cache-from value.
+func ParseCacheFrom(value string) (CacheSpec, error) {
+	spec, err := parseCacheSpec(value, "src")
+	if err != nil {
+		return CacheSpec{}, err
+	}
+	if spec.Type == CacheTypeInline {
+		return CacheSpec{}, errors.Errorf("cache type %q can only be exported", spec.Type)
+	}
+	return spec, nil
+}
+
+func parseCacheSpec(value string, dirKey string) (CacheSpec, error) {
+	if !strings.Contains(value, "=") {
+		if value == "" {
+			return CacheSpec{}, errors.New("cache spec must not be empty")
+		}
+		return CacheSpec{Type: CacheTypeRegistry, Ref: value}, nil
+	}
+
+	csvReader := csv.NewReader(strings.NewReader(value))
+	fields, err := csvReader.Read()
+	if err != nil {
+		return CacheSpec{}, errors.Wrap(err, "failed to parse csv cache spec")
+	}
+
+	spec := CacheSpec{dirKey: dirKey}
+	for _, field := range fields {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return CacheSpec{}, errors.Errorf("invalid field '%s' must be a key=value pair", field)
+		}
+
+		key := strings.ToLower(parts[0])
+		value := parts[1]
+		switch key {
+		case "type":
+			spec.Type = value
+		case "ref":
+			spec.Ref = value
+		case dirKey:
+			spec.Dir = value
+		case "mode":
+			if value != "min" {
+				return CacheSpec{}, errors.Errorf("unsupported cache mode %q: the Docker engine only exports mode=min", value)
+			}
+		default:
+			return CacheSpec{}, errors.Errorf("unexpected key '%s' in '%s'", key, field)
+		}
+	}
+
+	switch spec.Type {
+	case CacheTypeInline:
+	case CacheTypeRegistry:
+		if spec.Ref == "" {
+			return CacheSpec{}, errors.Errorf("cache type %q requires a ref", spec.Type)
+		}
+	case CacheTypeLocal:
+		if spec.Dir == "" {
+			return CacheSpec{}, errors.Errorf("cache type %q requires a %s", spec.Type, dirKey)
+		}
+	case "":
+		return CacheSpec{}, errors.New("cache spec requires a type")
+	default:
+		return CacheSpec{}, errors.Errorf("unsupported cache type %q (supported: %s, %s, %s)",
+			spec.Type, CacheTypeInline, CacheTypeRegistry, CacheTypeLocal)
+	}
+	return spec, nil
+}
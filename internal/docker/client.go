@@ -108,6 +108,12 @@ type Client interface {
 	ImageInspect(ctx context.Context, imageID string, inspectOpts ...client.ImageInspectOption) (client.ImageInspectResult, error)
 	ImageList(ctx context.Context, options client.ImageListOptions) (client.ImageListResult, error)
 	ImageRemove(ctx context.Context, imageID string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error)
+	ImageSave(ctx context.Context, imageIDs []string, saveOpts ...client.ImageSaveOption) (client.ImageSaveResult, error)
+	ImageLoad(ctx context.Context, input io.Reader, loadOpts ...client.ImageLoadOption) (client.ImageLoadResult, error)
+
+	// Registry operations that the Docker engine doesn't support, for multi-arch images.
+	ManifestDescriptor(ctx context.Context, ref reference.Named, platform ocispec.Platform) (ocispec.Descriptor, error)
+	ManifestPush(ctx context.Context, ref reference.NamedTagged, desc ocispec.Descriptor, data []byte) error
 
 	NewVersionError(ctx context.Context, APIrequired, feature string) error
 	BuildCachePrune(ctx context.Context, opts client.BuildCachePruneOptions) (client.BuildCachePruneResult, error)
@@ -213,6 +219,11 @@ func SupportsBuildkit(v client.ServerVersionResult, env Env) bool {
 		return false
 	}
 
+	if IsPodman(v) {
+		// Podman builds with Buildah, and ignores requests for BuildKit.
+		return false
+	}
+
 	version, err := semver.ParseTolerant(v.APIVersion)
 	if err != nil {
 		// If the server version doesn't parse, disable buildkit
@@ -29,6 +29,7 @@ func TestSupportsBuildkit(t *testing.T) {
 		{mobyclient.ServerVersionResult{APIVersion: "1.40"}, Env{}, true},
 		{mobyclient.ServerVersionResult{APIVersion: "garbage"}, Env{}, false},
 		{mobyclient.ServerVersionResult{APIVersion: "1.39"}, Env{IsOldMinikube: true}, false},
+		{mobyclient.ServerVersionResult{APIVersion: "1.41", Components: podmanComponents}, Env{}, false},
 	}
 
 	for i, c := range cases {
@@ -157,8 +157,9 @@ func ProvideLocalEnv(
 	clusterEnv ClusterEnv,
 ) LocalEnv {
 	result := Env{}
-	client, err := creator.FromCLI(ctx)
+	client, environ, err := clientFromCLIOrPodman(ctx, creator)
 	result.Client = client
+	result.Environ = environ
 	if err != nil {
 		result.Error = err
 	}
@@ -247,8 +248,9 @@ func ProvideClusterEnv(
 	}
 
 	if env.Client == nil {
-		client, err := creator.FromCLI(ctx)
+		client, environ, err := clientFromCLIOrPodman(ctx, creator)
 		env.Client = client
+		env.Environ = environ
 		if err != nil {
 			env.Error = err
 		}
@@ -8,6 +8,7 @@ import (
 	typesbuild "github.com/moby/moby/api/types/build"
 	"github.com/moby/moby/api/types/system"
 	"github.com/moby/moby/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/tilt-dev/tilt/internal/container"
@@ -82,6 +83,18 @@ func (c explodingClient) ImageList(ctx context.Context, options client.ImageList
 func (c explodingClient) ImageRemove(ctx context.Context, imageID string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
 	return client.ImageRemoveResult{}, c.err
 }
+func (c explodingClient) ImageSave(ctx context.Context, imageIDs []string, saveOpts ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+	return nil, c.err
+}
+func (c explodingClient) ImageLoad(ctx context.Context, input io.Reader, loadOpts ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+	return nil, c.err
+}
+func (c explodingClient) ManifestDescriptor(ctx context.Context, ref reference.Named, platform ocispec.Platform) (ocispec.Descriptor, error) {
+	return ocispec.Descriptor{}, c.err
+}
+func (c explodingClient) ManifestPush(ctx context.Context, ref reference.NamedTagged, desc ocispec.Descriptor, data []byte) error {
+	return c.err
+}
 func (c explodingClient) NewVersionError(ctx context.Context, apiRequired, feature string) error {
 	return c.err
 }
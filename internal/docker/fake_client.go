@@ -15,6 +15,7 @@ import (
 
 	"github.com/docker/go-units"
 	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"github.com/pkg/errors"
 	"golang.org/x/sync/errgroup"
 
@@ -88,6 +89,9 @@ var DefaultContainerListOutput = map[string][]typescontainer.Summary{
 type ExecCall struct {
 	Container string
 	Cmd       model.Cmd
+
+	// The stdin of the exec, if any.
+	Input string
 }
 
 type ContainerLogsRequest struct {
@@ -118,16 +122,31 @@ type FakeClient struct {
 
 	ContainerListOutput map[string][]typescontainer.Summary
 
+	// Containers returned by ContainerList when filtering by label.
+	LabeledContainers []typescontainer.Summary
+
 	CopyCount     int
 	CopyContainer string
 	CopyContent   io.Reader
 
 	ExecCalls         []ExecCall
 	ExecErrorsToThrow []error // next call to exec will throw ExecError[0] (which we then pop)
+	execMu            sync.Mutex
 
 	RestartsByContainer map[string]int
 	RemovedImageIDs     []string
 
+	SavedImageIDs []string
+	SaveOutput    string
+	LoadCount     int
+	LoadInput     string
+	LoadOutput    string
+
+	// Manifests in the fake registry, by ref.
+	Manifests map[string]ocispec.Descriptor
+	// Manifest lists pushed to the fake registry, by ref.
+	PushedManifests map[string][]byte
+
 	// Images returned by ImageInspect.
 	Images map[string]typesimage.InspectResponse
 
@@ -153,6 +172,9 @@ type FakeClient struct {
 	ContainersPruned       []string
 
 	FakeDaemonInfo system.Info
+
+	// If set, returned by ServerVersion.
+	FakeServerVersion client.ServerVersionResult
 }
 
 var _ Client = &FakeClient{}
@@ -185,6 +207,9 @@ func (c *FakeClient) BuilderVersion(ctx context.Context) (typesbuild.BuilderVers
 	return typesbuild.BuilderV1, nil
 }
 func (c *FakeClient) ServerVersion(ctx context.Context) (client.ServerVersionResult, error) {
+	if len(c.FakeServerVersion.Components) > 0 || c.FakeServerVersion.Version != "" {
+		return c.FakeServerVersion, nil
+	}
 	return client.ServerVersionResult{
 		Arch:    "amd64",
 		Version: "20.10.11",
@@ -266,6 +291,17 @@ func (c *FakeClient) SetDefaultContainerListOutput() {
 }
 
 func (c *FakeClient) ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	if _, ok := options.Filters["name"]; !ok && len(options.Filters["label"]) > 0 {
+		labels, _ := splitLabelFilters(options.Filters)
+		var res []typescontainer.Summary
+		for _, c := range c.LabeledContainers {
+			if matchesLabelFilters(c.Labels, labels) {
+				res = append(res, c)
+			}
+		}
+		return client.ContainerListResult{Items: res}, nil
+	}
+
 	nameFilter := slices.Collect(maps.Keys(options.Filters["name"]))
 	if len(nameFilter) != 1 {
 		return client.ContainerListResult{}, fmt.Errorf("expected one filter for 'name', got: %v", nameFilter)
@@ -303,6 +339,16 @@ func (c *FakeClient) ExecInContainer(ctx context.Context, cID container.ID, cmd
 		Container: cID.String(),
 		Cmd:       cmd,
 	}
+	if in != nil {
+		data, err := io.ReadAll(in)
+		if err != nil {
+			return err
+		}
+		execCall.Input = string(data)
+	}
+
+	c.execMu.Lock()
+	defer c.execMu.Unlock()
 	c.ExecCalls = append(c.ExecCalls, execCall)
 
 	// If we're supposed to throw an error on this call, throw it (and pop from
@@ -389,6 +435,42 @@ func (c *FakeClient) ImageRemove(ctx context.Context, imageID string, options cl
 	}}, nil
 }
 
+func (c *FakeClient) ImageSave(ctx context.Context, imageIDs []string, saveOpts ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+	c.SavedImageIDs = append(c.SavedImageIDs, imageIDs...)
+	return NewFakeDockerResponse(c.SaveOutput), nil
+}
+
+func (c *FakeClient) ImageLoad(ctx context.Context, input io.Reader, loadOpts ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+	c.LoadCount++
+	data, err := io.ReadAll(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "ImageLoad")
+	}
+	c.LoadInput = string(data)
+	return NewFakeDockerResponse(c.LoadOutput), nil
+}
+
+func (c *FakeClient) ManifestDescriptor(ctx context.Context, ref reference.Named, platform ocispec.Platform) (ocispec.Descriptor, error) {
+	desc, ok := c.Manifests[ref.String()]
+	if !ok {
+		desc = ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Digest:    digest.FromString(ref.String()),
+			Size:      int64(len(ref.String())),
+		}
+	}
+	desc.Platform = &platform
+	return desc, nil
+}
+
+func (c *FakeClient) ManifestPush(ctx context.Context, ref reference.NamedTagged, desc ocispec.Descriptor, data []byte) error {
+	if c.PushedManifests == nil {
+		c.PushedManifests = make(map[string][]byte)
+	}
+	c.PushedManifests[ref.String()] = data
+	return nil
+}
+
 func (c *FakeClient) NewVersionError(ctx context.Context, apiRequired, feature string) error {
 	if c.ThrowNewVersionError {
 		c.ThrowNewVersionError = false
@@ -0,0 +1,137 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/containerd/platforms"
+	"github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+)
+
+// The Docker engine can't create manifest lists, so we talk to the registry
+// directly, with the same credentials that the Docker CLI uses.
+func (c *Cli) registryResolver(ctx context.Context) (remotes.Resolver, error) {
+	cli, err := newDockerCli(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "registryResolver")
+	}
+	configFile := cli.ConfigFile()
+
+	creds := func(host string) (string, string, error) {
+		// Docker stores Docker Hub credentials under the legacy index address.
+		if host == "registry-1.docker.io" {
+			host = "https://index.docker.io/v1/"
+		}
+		auth, err := configFile.GetAuthConfig(host)
+		if err != nil {
+			return "", "", err
+		}
+		if auth.IdentityToken != "" {
+			return "", auth.IdentityToken, nil
+		}
+		return auth.Username, auth.Password, nil
+	}
+
+	// Local registries often don't have TLS.
+	httpClient := &http.Client{
+		Transport: docker.NewHTTPFallback(docker.DefaultHTTPTransport(nil)),
+	}
+	return docker.NewResolver(docker.ResolverOptions{
+		Hosts: docker.ConfigureDefaultRegistries(
+			docker.WithClient(httpClient),
+			docker.WithPlainHTTP(docker.MatchLocalhost),
+			docker.WithAuthorizer(docker.NewDockerAuthorizer(
+				docker.WithAuthClient(httpClient),
+				docker.WithAuthCreds(creds))),
+		),
+	}), nil
+}
+
+// Returns the descriptor of the image manifest for the given platform in the
+// registry.
+//
+// If the ref points to a manifest list, returns the entry for the platform.
+func (c *Cli) ManifestDescriptor(ctx context.Context, ref reference.Named, platform ocispec.Platform) (ocispec.Descriptor, error) {
+	resolver, err := c.registryResolver(ctx)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+
+	_, desc, err := resolver.Resolve(ctx, ref.String())
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "resolving %s", ref)
+	}
+
+	if !images.IsIndexType(desc.MediaType) {
+		desc.Platform = &platform
+		return desc, nil
+	}
+
+	fetcher, err := resolver.Fetcher(ctx, ref.String())
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "fetching %s", ref)
+	}
+	r, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "fetching %s", ref)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+
+	var index ocispec.Index
+	err = json.NewDecoder(io.LimitReader(r, desc.Size)).Decode(&index)
+	if err != nil {
+		return ocispec.Descriptor{}, errors.Wrapf(err, "reading manifest list %s", ref)
+	}
+
+	matcher := platforms.OnlyStrict(platform)
+	for _, m := range index.Manifests {
+		if m.Platform != nil && matcher.Match(*m.Platform) {
+			return m, nil
+		}
+	}
+	return ocispec.Descriptor{}, fmt.Errorf("manifest list %s has no image for platform %s",
+		ref, platforms.Format(platform))
+}
+
+// Pushes a manifest (e.g., a manifest list) to the registry under the given tag.
+func (c *Cli) ManifestPush(ctx context.Context, ref reference.NamedTagged, desc ocispec.Descriptor, data []byte) error {
+	resolver, err := c.registryResolver(ctx)
+	if err != nil {
+		return err
+	}
+
+	pusher, err := resolver.Pusher(ctx, ref.String())
+	if err != nil {
+		return errors.Wrapf(err, "pushing %s", ref)
+	}
+
+	w, err := pusher.Push(ctx, desc)
+	if err != nil {
+		if cerrdefs.IsAlreadyExists(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "pushing %s", ref)
+	}
+	defer func() {
+		_ = w.Close()
+	}()
+
+	err = content.Copy(ctx, w, bytes.NewReader(data), desc.Size, desc.Digest)
+	if err != nil && !cerrdefs.IsAlreadyExists(err) {
+		return errors.Wrapf(err, "pushing %s", ref)
+	}
+	return nil
+}
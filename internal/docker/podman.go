@@ -0,0 +1,189 @@
+package docker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/moby/moby/client"
+
+	"github.com/tilt-dev/tilt/internal/container"
+)
+
+// Set TILT_CONTAINER_RUNTIME=podman to build with podman instead of Docker.
+//
+// Podman serves a Docker-compatible API, so we use the same client for both.
+// This env var only affects which socket we connect to when DOCKER_HOST
+// isn't set. The workarounds for podman's differences are keyed off
+// the server version, so they also apply if DOCKER_HOST points at podman.
+const ContainerRuntimeEnvVar = "TILT_CONTAINER_RUNTIME"
+
+func podmanRequested() (bool, error) {
+	v := os.Getenv(ContainerRuntimeEnvVar)
+	switch container.Runtime(v) {
+	case "", container.RuntimeDocker:
+		return false, nil
+	case container.RuntimePodman:
+		return true, nil
+	}
+	return false, fmt.Errorf("%s: unsupported container runtime %q (expected %q or %q)",
+		ContainerRuntimeEnvVar, v, container.RuntimeDocker, container.RuntimePodman)
+}
+
+// The places where podman puts its API socket, in order of preference.
+//
+// Rootless podman puts the socket in the user's runtime dir. Rootful podman
+// puts it in /run.
+func podmanSocketPaths() []string {
+	paths := []string{}
+	runtimeDir := os.Getenv("XDG_RUNTIME_DIR")
+	if runtimeDir != "" {
+		paths = append(paths, filepath.Join(runtimeDir, "podman", "podman.sock"))
+	}
+	uid := os.Getuid()
+	if uid > 0 {
+		paths = append(paths, filepath.Join("/run/user", fmt.Sprintf("%d", uid), "podman", "podman.sock"))
+	}
+	return append(paths, "/run/podman/podman.sock")
+}
+
+func podmanHost() (string, error) {
+	paths := podmanSocketPaths()
+	for _, p := range paths {
+		info, err := os.Stat(p)
+		if err == nil && info.Mode()&os.ModeSocket != 0 {
+			return "unix://" + p, nil
+		}
+	}
+	return "", fmt.Errorf("no podman socket found (looked in: %s). "+
+		"Try: systemctl --user enable --now podman.socket", strings.Join(paths, ", "))
+}
+
+// Creates a client from the Docker CLI config, or from the podman socket
+// if the user asked for podman and didn't set DOCKER_HOST.
+//
+// Returns the env vars that subprocesses need to talk to the same daemon.
+func clientFromCLIOrPodman(ctx context.Context, creator ClientCreator) (DaemonClient, []string, error) {
+	usePodman, err := podmanRequested()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !usePodman || os.Getenv("DOCKER_HOST") != "" {
+		client, err := creator.FromCLI(ctx)
+		return client, nil, err
+	}
+
+	host, err := podmanHost()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	client, err := creator.FromEnvMap(map[string]string{"DOCKER_HOST": host})
+	if err != nil {
+		return nil, nil, fmt.Errorf("connecting to podman: %v", err)
+	}
+	return client, []string{"DOCKER_HOST=" + host}, nil
+}
+
+// Determines if the server is podman's Docker-compatible API.
+func IsPodman(v client.ServerVersionResult) bool {
+	for _, c := range v.Components {
+		if strings.Contains(strings.ToLower(c.Name), "podman") {
+			return true
+		}
+	}
+	return false
+}
+
+// The container runtime that's serving the Docker API.
+func RuntimeFromServerVersion(v client.ServerVersionResult) container.Runtime {
+	if IsPodman(v) {
+		return container.RuntimePodman
+	}
+	return container.RuntimeDocker
+}
+
+func (c *Cli) isPodman(ctx context.Context) bool {
+	c.initVersion(ctx)
+	return IsPodman(c.serverVersion)
+}
+
+// Podman's Docker-compatible API doesn't reliably apply label filters
+// when listing (some versions ignore them, some only match exact key=value pairs).
+// So we do the label filtering on the client side.
+func (c *Cli) ImageList(ctx context.Context, options client.ImageListOptions) (client.ImageListResult, error) {
+	if !c.isPodman(ctx) {
+		return c.Client.ImageList(ctx, options)
+	}
+
+	var labels []string
+	labels, options.Filters = splitLabelFilters(options.Filters)
+	result, err := c.Client.ImageList(ctx, options)
+	if err != nil || len(labels) == 0 {
+		return result, err
+	}
+
+	items := result.Items[:0]
+	for _, item := range result.Items {
+		if matchesLabelFilters(item.Labels, labels) {
+			items = append(items, item)
+		}
+	}
+	result.Items = items
+	return result, nil
+}
+
+func (c *Cli) ContainerList(ctx context.Context, options client.ContainerListOptions) (client.ContainerListResult, error) {
+	if !c.isPodman(ctx) {
+		return c.Client.ContainerList(ctx, options)
+	}
+
+	var labels []string
+	labels, options.Filters = splitLabelFilters(options.Filters)
+	result, err := c.Client.ContainerList(ctx, options)
+	if err != nil || len(labels) == 0 {
+		return result, err
+	}
+
+	items := result.Items[:0]
+	for _, item := range result.Items {
+		if matchesLabelFilters(item.Labels, labels) {
+			items = append(items, item)
+		}
+	}
+	result.Items = items
+	return result, nil
+}
+
+// Removes the label filters from the filter set, without modifying the original.
+func splitLabelFilters(f client.Filters) ([]string, client.Filters) {
+	var labels []string
+	rest := make(client.Filters, len(f))
+	for term, values := range f {
+		if term != "label" {
+			rest[term] = values
+			continue
+		}
+		for v, ok := range values {
+			if ok {
+				labels = append(labels, v)
+			}
+		}
+	}
+	return labels, rest
+}
+
+// Each filter is either "key" (the label must exist) or "key=value".
+func matchesLabelFilters(labels map[string]string, filters []string) bool {
+	for _, f := range filters {
+		key, value, hasValue := strings.Cut(f, "=")
+		actual, ok := labels[key]
+		if !ok || (hasValue && actual != value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package docker
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/moby/moby/api/types/system"
+	mobyclient "github.com/moby/moby/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/clusterid"
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/k8s"
+)
+
+var podmanComponents = []system.ComponentVersion{{Name: "Podman Engine", Version: "5.2.0"}}
+
+func TestRuntimeFromServerVersion(t *testing.T) {
+	assert.Equal(t, container.RuntimePodman,
+		RuntimeFromServerVersion(mobyclient.ServerVersionResult{Components: podmanComponents}))
+	assert.Equal(t, container.RuntimeDocker,
+		RuntimeFromServerVersion(mobyclient.ServerVersionResult{
+			Components: []system.ComponentVersion{{Name: "Engine", Version: "27.0.0"}},
+		}))
+}
+
+func TestProvideEnvPodman(t *testing.T) {
+	dir := shortTempDir(t)
+	socket := filepath.Join(dir, "podman", "podman.sock")
+	require.NoError(t, os.MkdirAll(filepath.Dir(socket), 0755))
+	l, err := net.Listen("unix", socket)
+	require.NoError(t, err)
+	defer func() { _ = l.Close() }()
+
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("XDG_RUNTIME_DIR", dir)
+	t.Setenv(ContainerRuntimeEnvVar, "podman")
+
+	cluster := providePodmanTestEnv(t)
+	assert.Equal(t, "unix://"+socket, Env(cluster).DaemonHost())
+	assert.Equal(t, []string{"DOCKER_HOST=unix://" + socket}, cluster.Environ)
+	assert.NoError(t, cluster.Error)
+}
+
+func TestProvideEnvPodmanRespectsDockerHost(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "tcp://podman.example.com:8080")
+	t.Setenv(ContainerRuntimeEnvVar, "podman")
+
+	cluster := providePodmanTestEnv(t)
+	assert.Equal(t, "tcp://podman.example.com:8080", Env(cluster).DaemonHost())
+	assert.Nil(t, cluster.Environ)
+}
+
+func TestProvideEnvPodmanNoSocket(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv("XDG_RUNTIME_DIR", t.TempDir())
+	t.Setenv(ContainerRuntimeEnvVar, "podman")
+
+	cluster := providePodmanTestEnv(t)
+	if assert.Error(t, cluster.Error) {
+		assert.Contains(t, cluster.Error.Error(), "no podman socket found")
+	}
+}
+
+func TestProvideEnvUnknownRuntime(t *testing.T) {
+	t.Setenv("DOCKER_HOST", "")
+	t.Setenv(ContainerRuntimeEnvVar, "lxc")
+
+	cluster := providePodmanTestEnv(t)
+	if assert.Error(t, cluster.Error) {
+		assert.Contains(t, cluster.Error.Error(), `unsupported container runtime "lxc"`)
+	}
+}
+
+func TestMatchesLabelFilters(t *testing.T) {
+	labels := map[string]string{BuiltLabel: "true", "app": "frontend"}
+	assert.True(t, matchesLabelFilters(labels, nil))
+	assert.True(t, matchesLabelFilters(labels, []string{BuiltLabel}))
+	assert.True(t, matchesLabelFilters(labels, []string{"dev.tilt.built=true", "app=frontend"}))
+	assert.False(t, matchesLabelFilters(labels, []string{"app=backend"}))
+	assert.False(t, matchesLabelFilters(labels, []string{GCEnabledLabel}))
+}
+
+func TestSplitLabelFilters(t *testing.T) {
+	f := make(mobyclient.Filters).
+		Add("label", BuiltLabel).
+		Add("reference", "gcr.io/foo")
+	labels, rest := splitLabelFilters(f)
+	assert.Equal(t, []string{BuiltLabel}, labels)
+	assert.Equal(t, mobyclient.Filters{"reference": {"gcr.io/foo": true}}, rest)
+
+	// The original filters are unchanged.
+	assert.Len(t, f, 2)
+}
+
+func providePodmanTestEnv(t *testing.T) ClusterEnv {
+	kCli := k8s.NewFakeK8sClient(t)
+	kCli.Runtime = container.RuntimeContainerd
+	return ProvideClusterEnv(context.Background(), fakeClientCreator{}, "kind-kind",
+		clusterid.ProductKIND, kCli, k8s.FakeMinikube{})
+}
+
+// Unix socket paths have a short max length, so we can't always use t.TempDir().
+func shortTempDir(t *testing.T) string {
+	dir, err := os.MkdirTemp("", "podman")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+	return dir
+}
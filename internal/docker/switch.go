@@ -9,6 +9,7 @@ import (
 	typesbuild "github.com/moby/moby/api/types/build"
 	"github.com/moby/moby/api/types/system"
 	"github.com/moby/moby/client"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/tilt-dev/tilt/internal/container"
@@ -117,6 +118,18 @@ func (c *switchCli) ImageList(ctx context.Context, options client.ImageListOptio
 func (c *switchCli) ImageRemove(ctx context.Context, imageID string, options client.ImageRemoveOptions) (client.ImageRemoveResult, error) {
 	return c.client(ctx).ImageRemove(ctx, imageID, options)
 }
+func (c *switchCli) ImageSave(ctx context.Context, imageIDs []string, saveOpts ...client.ImageSaveOption) (client.ImageSaveResult, error) {
+	return c.client(ctx).ImageSave(ctx, imageIDs, saveOpts...)
+}
+func (c *switchCli) ImageLoad(ctx context.Context, input io.Reader, loadOpts ...client.ImageLoadOption) (client.ImageLoadResult, error) {
+	return c.client(ctx).ImageLoad(ctx, input, loadOpts...)
+}
+func (c *switchCli) ManifestDescriptor(ctx context.Context, ref reference.Named, platform ocispec.Platform) (ocispec.Descriptor, error) {
+	return c.client(ctx).ManifestDescriptor(ctx, ref, platform)
+}
+func (c *switchCli) ManifestPush(ctx context.Context, ref reference.NamedTagged, desc ocispec.Descriptor, data []byte) error {
+	return c.client(ctx).ManifestPush(ctx, ref, desc, data)
+}
 func (c *switchCli) NewVersionError(ctx context.Context, apiRequired, feature string) error {
 	return c.client(context.Background()).NewVersionError(ctx, apiRequired, feature)
 }
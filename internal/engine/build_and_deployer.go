@@ -11,6 +11,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -58,6 +59,8 @@ func (composite *CompositeBuildAndDeployer) BuildAndDeploy(ctx context.Context,
 		specNames = append(specNames, s.ID().String())
 	}
 	span.SetAttributes(attribute.KeyValue{Key: attribute.Key("targetNames"), Value: attribute.StringValue(strings.Join(specNames, ","))})
+	span.SetAttributes(tracer.AttrTargetIDs.StringSlice(specNames))
+	span.SetAttributes(tracer.AttributesFromContext(ctx)...)
 
 	logger.Get(ctx).Debugf("Building with BuildOrder: %s", composite.builders.String())
 	for i, builder := range composite.builders {
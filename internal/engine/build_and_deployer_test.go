@@ -513,3 +513,8 @@ func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Clus
 	kl.loadCount++
 	return nil
 }
+
+func (kl *fakeKINDLoader) LoadArchiveToKIND(ctx context.Context, cluster *v1alpha1.Cluster, path string) error {
+	kl.loadCount++
+	return nil
+}
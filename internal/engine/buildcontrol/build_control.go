@@ -1,6 +1,7 @@
 package buildcontrol
 
 import (
+	"sort"
 	"time"
 
 	v1 "k8s.io/api/core/v1"
@@ -10,6 +11,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/internal/store/liveupdates"
+	"github.com/tilt-dev/tilt/internal/timecmp"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
@@ -79,6 +81,7 @@ func NextTargetToBuild(state store.EngineState) (*store.ManifestTarget, HoldSet)
 	HoldTargetsWithBuildingComponents(state, targets, holds)
 	HoldTargetsWaitingOnDependencies(state, targets, holds)
 	HoldTargetsWaitingOnCluster(state, targets, holds)
+	HoldTargetsInFullConcurrencyGroups(state, targets, holds)
 
 	// If any of the manifest targets haven't been built yet, build them now.
 	targets = holds.RemoveIneligibleTargets(targets)
@@ -93,12 +96,19 @@ func NextTargetToBuild(state store.EngineState) (*store.ManifestTarget, HoldSet)
 	HoldLiveUpdateTargetsHandledByReconciler(state, targets, holds)
 
 	// Next prioritize builds that have been manually triggered.
+	var triggered *store.ManifestTarget
 	for _, mn := range state.TriggerQueue {
 		mt, ok := state.ManifestTargets[mn]
-		if ok && holds.IsEligible(mt) {
-			return mt, holds
+		if !ok || !holds.IsEligible(mt) {
+			continue
+		}
+		if triggered == nil || mt.Manifest.Priority > triggered.Manifest.Priority {
+			triggered = mt
 		}
 	}
+	if triggered != nil {
+		return triggered, holds
+	}
 
 	// Check to see if any targets
 	//
@@ -282,6 +292,45 @@ func HoldTargetsWaitingOnCluster(state store.EngineState, mts []*store.ManifestT
 	}
 }
 
+// Concurrency groups limit how many resources that share something
+// (like a test database) can build at once. Hold any resource in a group
+// that's already at its limit.
+func HoldTargetsInFullConcurrencyGroups(state store.EngineState, mts []*store.ManifestTarget, holds HoldSet) {
+	building := make(map[string]int)
+	for _, mt := range state.Targets() {
+		if !mt.State.IsBuilding() {
+			continue
+		}
+		for _, g := range mt.Manifest.ConcurrencyGroups {
+			building[g.Name]++
+		}
+	}
+
+	if len(building) == 0 {
+		return
+	}
+
+	for _, mt := range mts {
+		var onRefs []v1alpha1.UIResourceStateWaitingOnRef
+		for _, g := range mt.Manifest.ConcurrencyGroups {
+			if building[g.Name] < g.Limit {
+				continue
+			}
+			onRefs = append(onRefs, v1alpha1.UIResourceStateWaitingOnRef{
+				Kind: "ConcurrencyGroup",
+				Name: g.Name,
+			})
+		}
+
+		if len(onRefs) > 0 {
+			holds.AddHold(mt, store.Hold{
+				Reason: store.HoldReasonConcurrencyGroup,
+				OnRefs: onRefs,
+			})
+		}
+	}
+}
+
 func HoldTargetsWaitingOnDependencies(state store.EngineState, mts []*store.ManifestTarget, holds HoldSet) {
 	for _, mt := range mts {
 		if waitingOn := waitingOnDependencies(state, mt); len(waitingOn) != 0 {
@@ -294,13 +343,16 @@ func HoldTargetsWaitingOnDependencies(state store.EngineState, mts []*store.Mani
 }
 
 // Helper function for ordering targets that have never been built before.
+//
+// Priority only decides between targets of the same kind, so that a
+// high-priority resource can't jump ahead of the namespaces and CRDs it needs.
 func NextUnbuiltTargetToBuild(unbuilt []*store.ManifestTarget) *store.ManifestTarget {
 	// Local resources come before all cluster resources, because they
 	// can't be parallelized. (LR's may change things on disk that cluster
 	// resources then pull in).
 	localTargets := FindLocalTargets(unbuilt)
 	if len(localTargets) > 0 {
-		return localTargets[0]
+		return HighestPriorityTargets(localTargets)[0]
 	}
 
 	// unresourced YAML goes next
@@ -314,10 +366,10 @@ func NextUnbuiltTargetToBuild(unbuilt []*store.ManifestTarget) *store.ManifestTa
 	// that docker-compose put things in.)
 	deployOnlyK8sTargets := FindDeployOnlyK8sManifestTargets(unbuilt)
 	if len(deployOnlyK8sTargets) > 0 {
-		return deployOnlyK8sTargets[0]
+		return HighestPriorityTargets(deployOnlyK8sTargets)[0]
 	}
 
-	return unbuilt[0]
+	return HighestPriorityTargets(unbuilt)[0]
 }
 
 func FindUnresourcedYAML(targets []*store.ManifestTarget) *store.ManifestTarget {
@@ -413,24 +465,30 @@ func IsBuildingUncategorizedYAML(state store.EngineState) bool {
 // 1) all pending file changes
 // 2) all pending dependency changes (where an image has been rebuilt by another manifest), and
 // 3) all pending manifest changes
-// The earliest one is the one we want.
+// The earliest one with the highest priority is the one we want.
 //
 // If no targets are pending, return nil
 func EarliestPendingAutoTriggerTarget(targets []*store.ManifestTarget) *store.ManifestTarget {
 	var choice *store.ManifestTarget
-	earliest := time.Now()
+	now := time.Now()
+	earliest := now
 
 	for _, mt := range targets {
-		ok, newTime := mt.State.HasPendingChangesBeforeOrEqual(earliest)
+		ok, newTime := mt.State.HasPendingChangesBeforeOrEqual(now)
 		if ok {
 			if !mt.Manifest.TriggerMode.AutoOnChange() {
 				// Don't trigger update of a manual manifest just b/c if has
 				// pending changes; must come through the TriggerQueue, above.
 				continue
 			}
-			if choice != nil && newTime.Equal(earliest) {
-				// If two choices are equal, use the first one in target order.
-				continue
+			if choice != nil {
+				if mt.Manifest.Priority < choice.Manifest.Priority {
+					continue
+				}
+				if mt.Manifest.Priority == choice.Manifest.Priority && !newTime.Before(earliest) {
+					// If two choices are equal, use the first one in target order.
+					continue
+				}
 			}
 			choice = mt
 			earliest = newTime
@@ -440,6 +498,180 @@ func EarliestPendingAutoTriggerTarget(targets []*store.ManifestTarget) *store.Ma
 	return choice
 }
 
+// Returns the targets with the highest priority, in their original order.
+func HighestPriorityTargets(targets []*store.ManifestTarget) []*store.ManifestTarget {
+	var result []*store.ManifestTarget
+	for _, mt := range targets {
+		if len(result) > 0 && mt.Manifest.Priority < result[0].Manifest.Priority {
+			continue
+		}
+		if len(result) > 0 && mt.Manifest.Priority > result[0].Manifest.Priority {
+			result = nil
+		}
+		result = append(result, mt)
+	}
+	return result
+}
+
+// Manual triggers outrank automatic builds. Otherwise, the
+// resource with the higher priority wins.
+type buildPriority struct {
+	manual   bool
+	priority int
+}
+
+func (p buildPriority) higherThan(other buildPriority) bool {
+	if p.manual != other.manual {
+		return p.manual
+	}
+	return p.priority > other.priority
+}
+
+// The priority of the next build of a target.
+func pendingBuildPriority(mt *store.ManifestTarget) buildPriority {
+	return buildPriority{
+		manual:   mt.NextBuildReason().HasTrigger(),
+		priority: mt.Manifest.Priority,
+	}
+}
+
+// The priority of the build in progress.
+func currentBuildPriority(mt *store.ManifestTarget) buildPriority {
+	return buildPriority{
+		manual:   mt.State.EarliestCurrentBuild().Reason.HasTrigger(),
+		priority: mt.Manifest.Priority,
+	}
+}
+
+// If preemption is enabled, and a target is waiting on a local build
+// with a lower priority, returns the local build to cancel and the
+// target that's waiting on it.
+//
+// A target waits on a local build if there are no build slots free,
+// or if the local build can't run in parallel with other builds.
+func NextBuildToPreempt(state store.EngineState) (preempt model.ManifestName, waiting model.ManifestName) {
+	if !state.UpdateSettings.PreemptLocalUpdates() {
+		return "", ""
+	}
+
+	next, holds := NextTargetToBuild(state)
+	var waitingMT *store.ManifestTarget
+	var candidates []*store.ManifestTarget
+	if next != nil {
+		if state.AvailableBuildSlots() > 0 {
+			return "", ""
+		}
+
+		// We have something to build, but no slots to build it in.
+		waitingMT = next
+		for _, mt := range state.Targets() {
+			if mt.State.IsBuilding() && mt.Manifest.IsLocal() {
+				candidates = append(candidates, mt)
+			}
+		}
+	} else {
+		for _, mt := range FindTargetsNeedingAnyBuild(state) {
+			hold := holds[mt.Manifest.Name]
+			if hold.Reason != store.HoldReasonWaitingForUnparallelizableTarget {
+				continue
+			}
+			if waitingMT == nil || pendingBuildPriority(mt).higherThan(pendingBuildPriority(waitingMT)) {
+				waitingMT = mt
+			}
+		}
+		if waitingMT == nil {
+			return "", ""
+		}
+
+		for _, id := range holds[waitingMT.Manifest.Name].HoldOn {
+			mt, ok := state.ManifestTargets[model.ManifestName(id.Name)]
+			if ok && mt.State.IsBuilding() && mt.Manifest.IsLocal() {
+				candidates = append(candidates, mt)
+			}
+		}
+	}
+
+	// Preempt the lowest-priority build. If there's a tie, preempt the
+	// most recently started one, because it's done the least work.
+	var choice *store.ManifestTarget
+	for _, mt := range candidates {
+		if !pendingBuildPriority(waitingMT).higherThan(currentBuildPriority(mt)) {
+			continue
+		}
+		if choice == nil {
+			choice = mt
+			continue
+		}
+
+		p, choiceP := currentBuildPriority(mt), currentBuildPriority(choice)
+		if choiceP.higherThan(p) ||
+			(!p.higherThan(choiceP) &&
+				mt.State.EarliestCurrentBuild().StartTime.After(choice.State.EarliestCurrentBuild().StartTime)) {
+			choice = mt
+		}
+	}
+
+	if choice == nil {
+		return "", ""
+	}
+	return choice.Manifest.Name, waitingMT.Manifest.Name
+}
+
+// Returns the in-progress builds that should be canceled because files
+// changed while they were building images, mapped to the files that changed.
+//
+// We only cancel builds that are still building images. Once a build has
+// moved on to deploying, we let it finish.
+func StaleImageBuilds(state store.EngineState) map[model.ManifestName][]string {
+	result := make(map[model.ManifestName][]string)
+	for _, mt := range state.Targets() {
+		if !mt.Manifest.CancelStaleImageBuilds ||
+			!mt.Manifest.TriggerMode.AutoOnChange() ||
+			!mt.State.IsBuilding() {
+			continue
+		}
+
+		startTime := mt.State.EarliestCurrentBuild().StartTime
+		if !isBuildingImagesSince(state, mt, startTime) {
+			continue
+		}
+
+		var files []string
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			status, ok := mt.State.BuildStatus(iTarget.ID())
+			if !ok {
+				continue
+			}
+			for f, t := range status.PendingFileChanges() {
+				if t.After(startTime) {
+					files = append(files, f)
+				}
+			}
+		}
+		if len(files) > 0 {
+			sort.Strings(files)
+			result[mt.Manifest.Name] = files
+		}
+	}
+	return result
+}
+
+// Whether any image of this target started building at or after the given time
+// and hasn't finished yet.
+func isBuildingImagesSince(state store.EngineState, mt *store.ManifestTarget, startTime time.Time) bool {
+	for _, iTarget := range mt.Manifest.ImageTargets {
+		if di, ok := state.DockerImages[iTarget.DockerImageName]; ok && di.Status.Building != nil &&
+			timecmp.AfterOrEqual(di.Status.Building.StartedAt, startTime) {
+			return true
+		}
+		if ci, ok := state.CmdImages[iTarget.CmdImageName]; ok && ci.Status.Building != nil &&
+			timecmp.AfterOrEqual(ci.Status.Building.StartedAt, startTime) {
+			return true
+		}
+	}
+	return false
+}
+
 // Grab all the targets that are build-eligible from
 // the engine state.
 //
@@ -227,6 +227,235 @@ func TestCurrentlyBuildingParallelLocalResource(t *testing.T) {
 	f.assertNextTargetToBuild("k8s1")
 }
 
+func TestConcurrencyGroup(t *testing.T) {
+	f := newTestFixture(t)
+
+	db := []model.ConcurrencyGroup{{Name: "db", Limit: 1}}
+	k8s1 := f.upsertK8sManifest("k8s1", withConcurrencyGroups(db...))
+	f.upsertK8sManifest("k8s2", withConcurrencyGroups(db...))
+	f.upsertK8sManifest("k8s3")
+
+	f.assertNextTargetToBuild("k8s1")
+
+	k8s1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.assertNextTargetToBuild("k8s3")
+	f.assertHoldOnRefs("k8s2", store.HoldReasonConcurrencyGroup, v1alpha1.UIResourceStateWaitingOnRef{
+		Kind: "ConcurrencyGroup",
+		Name: "db",
+	})
+
+	delete(k8s1.State.CurrentBuilds, "buildcontrol")
+	k8s1.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  time.Now(),
+		FinishTime: time.Now(),
+	})
+	f.assertNextTargetToBuild("k8s2")
+}
+
+func TestConcurrencyGroupLimit(t *testing.T) {
+	f := newTestFixture(t)
+
+	ports := model.ConcurrencyGroup{Name: "ports", Limit: 2}
+	db := model.ConcurrencyGroup{Name: "db", Limit: 1}
+	k8s1 := f.upsertK8sManifest("k8s1", withConcurrencyGroups(ports))
+	k8s2 := f.upsertK8sManifest("k8s2", withConcurrencyGroups(ports, db))
+	f.upsertK8sManifest("k8s3", withConcurrencyGroups(ports, db))
+
+	k8s1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.assertNextTargetToBuild("k8s2")
+
+	k8s2.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.assertNoTargetNextToBuild()
+	f.assertHoldOnRefs("k8s3", store.HoldReasonConcurrencyGroup,
+		v1alpha1.UIResourceStateWaitingOnRef{Kind: "ConcurrencyGroup", Name: "ports"},
+		v1alpha1.UIResourceStateWaitingOnRef{Kind: "ConcurrencyGroup", Name: "db"})
+}
+
+func TestPriorityUnbuilt(t *testing.T) {
+	f := newTestFixture(t)
+
+	f.upsertK8sManifest("k8s1")
+	f.upsertK8sManifest("k8s2", withPriority(10))
+	f.upsertK8sManifest("k8s3", withPriority(-1))
+
+	f.assertNextTargetToBuild("k8s2")
+}
+
+func TestPriorityUnbuiltAfterUncategorized(t *testing.T) {
+	f := newTestFixture(t)
+
+	f.upsertK8sManifest("k8s1", withPriority(10))
+	unresourced := f.upsertK8sManifest(model.UnresourcedYAMLManifestName)
+	local1 := f.upsertLocalManifest("local1")
+	local2 := f.upsertLocalManifest("local2", withPriority(5))
+
+	// Priority orders targets within each tier, but doesn't
+	// let a k8s resource skip ahead of local resources or
+	// the namespaces in uncategorized.
+	for _, mt := range []*store.ManifestTarget{local2, local1, unresourced} {
+		f.assertNextTargetToBuild(mt.Manifest.Name)
+		mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	}
+	f.assertNextTargetToBuild("k8s1")
+}
+
+func TestPriorityTriggerQueue(t *testing.T) {
+	f := newTestFixture(t)
+
+	k8s1 := f.upsertK8sManifest("k8s1")
+	k8s2 := f.upsertK8sManifest("k8s2", withPriority(10))
+	k8s3 := f.upsertK8sManifest("k8s3", withPriority(10))
+	for _, mt := range []*store.ManifestTarget{k8s1, k8s2, k8s3} {
+		mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	}
+
+	f.st.AppendToTriggerQueue("k8s1", model.BuildReasonFlagTriggerCLI)
+	f.st.AppendToTriggerQueue("k8s3", model.BuildReasonFlagTriggerCLI)
+	f.st.AppendToTriggerQueue("k8s2", model.BuildReasonFlagTriggerCLI)
+
+	// Equal priorities are built in the order they were triggered.
+	f.assertNextTargetToBuild("k8s3")
+}
+
+func TestPriorityPendingChanges(t *testing.T) {
+	f := newTestFixture(t)
+
+	k8s1 := f.upsertK8sManifest("k8s1")
+	k8s2 := f.upsertK8sManifest("k8s2", withPriority(5))
+	for _, mt := range []*store.ManifestTarget{k8s1, k8s2} {
+		mt.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	}
+
+	f.st.AppendToTriggerQueue("k8s1", model.BuildReasonFlagTriggerCLI)
+	k8s2.State.PendingManifestChange = time.Now()
+
+	// Manual triggers still go before automatic builds.
+	f.assertNextTargetToBuild("k8s1")
+
+	f.st.RemoveFromTriggerQueue("k8s1")
+	k8s1.State.PendingManifestChange = time.Now().Add(-time.Minute)
+	f.assertNextTargetToBuild("k8s2")
+}
+
+func TestPreemptDisabledByDefault(t *testing.T) {
+	f := newTestFixture(t)
+
+	local1 := f.upsertLocalManifest("local1")
+	local1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.upsertK8sManifest("k8s1", withPriority(10))
+
+	f.assertNoBuildToPreempt()
+}
+
+func TestPreemptLowerPriorityLocalBuild(t *testing.T) {
+	f := newTestFixture(t)
+	f.st.UpdateSettings = f.st.UpdateSettings.WithPreemptLocalUpdates(true)
+
+	local1 := f.upsertLocalManifest("local1")
+	local1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.upsertK8sManifest("k8s1", withPriority(10))
+
+	f.assertBuildToPreempt("local1", "k8s1")
+}
+
+func TestPreemptNotForEqualPriority(t *testing.T) {
+	f := newTestFixture(t)
+	f.st.UpdateSettings = f.st.UpdateSettings.WithPreemptLocalUpdates(true)
+
+	local1 := f.upsertLocalManifest("local1", withPriority(10))
+	local1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	f.upsertK8sManifest("k8s1", withPriority(10))
+
+	f.assertNoBuildToPreempt()
+}
+
+func TestPreemptForManualTrigger(t *testing.T) {
+	f := newTestFixture(t)
+	f.st.UpdateSettings = f.st.UpdateSettings.WithPreemptLocalUpdates(true)
+
+	local1 := f.upsertLocalManifest("local1", withPriority(10))
+	local1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: time.Now()}
+	k8s1 := f.upsertK8sManifest("k8s1")
+	k8s1.State.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	f.assertNoBuildToPreempt()
+
+	f.st.AppendToTriggerQueue("k8s1", model.BuildReasonFlagTriggerWeb)
+	f.assertBuildToPreempt("local1", "k8s1")
+}
+
+func TestPreemptWhenNoSlots(t *testing.T) {
+	f := newTestFixture(t)
+	f.st.UpdateSettings = f.st.UpdateSettings.WithPreemptLocalUpdates(true).WithMaxParallelUpdates(2)
+
+	start := time.Now()
+	local1 := f.upsertLocalManifest("local1", withLocalAllowParallel(true))
+	local1.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: start}
+	local2 := f.upsertLocalManifest("local2", withLocalAllowParallel(true))
+	local2.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: start.Add(time.Second)}
+	f.st.CurrentBuildSet["local1"] = true
+	f.st.CurrentBuildSet["local2"] = true
+	f.upsertK8sManifest("k8s1", withPriority(1))
+
+	// Preempt the build that started most recently.
+	f.assertBuildToPreempt("local2", "k8s1")
+}
+
+func TestStaleImageBuilds(t *testing.T) {
+	f := newTestFixture(t)
+
+	sancho := f.upsertManifest(manifestbuilder.New(f, "sancho").
+		WithImageTarget(newDockerImageTarget("sancho")).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build().
+		WithCancelStaleImageBuilds(true))
+	iTarget := sancho.Manifest.ImageTargetAt(0)
+
+	start := time.Now()
+	sancho.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: start}
+	status, ok := sancho.State.BuildStatus(iTarget.ID())
+	require.True(t, ok)
+	status.FileChanges["b.go"] = start.Add(time.Second)
+	status.FileChanges["a.go"] = start.Add(2 * time.Second)
+
+	// The build hasn't started building images yet.
+	assert.Empty(t, StaleImageBuilds(*f.st))
+
+	f.st.DockerImages[iTarget.DockerImageName] = &v1alpha1.DockerImage{
+		Status: v1alpha1.DockerImageStatus{
+			Building: &v1alpha1.DockerImageStateBuilding{StartedAt: metav1.NewMicroTime(start)},
+		},
+	}
+	assert.Equal(t, map[model.ManifestName][]string{"sancho": {"a.go", "b.go"}}, StaleImageBuilds(*f.st))
+
+	// Changes from before the build started are already part of the build.
+	status.FileChanges["a.go"] = start.Add(-time.Second)
+	status.FileChanges["b.go"] = start.Add(-time.Second)
+	assert.Empty(t, StaleImageBuilds(*f.st))
+}
+
+func TestStaleImageBuildsNotOptedIn(t *testing.T) {
+	f := newTestFixture(t)
+
+	sancho := f.upsertManifest(manifestbuilder.New(f, "sancho").
+		WithImageTarget(newDockerImageTarget("sancho")).
+		WithK8sYAML(testyaml.SanchoYAML).
+		Build())
+	iTarget := sancho.Manifest.ImageTargetAt(0)
+
+	start := time.Now()
+	sancho.State.CurrentBuilds["buildcontrol"] = model.BuildRecord{StartTime: start}
+	status, ok := sancho.State.BuildStatus(iTarget.ID())
+	require.True(t, ok)
+	status.FileChanges["a.go"] = start.Add(time.Second)
+	f.st.DockerImages[iTarget.DockerImageName] = &v1alpha1.DockerImage{
+		Status: v1alpha1.DockerImageStatus{
+			Building: &v1alpha1.DockerImageStateBuilding{StartedAt: metav1.NewMicroTime(start)},
+		},
+	}
+
+	assert.Empty(t, StaleImageBuilds(*f.st))
+}
+
 func TestTriggerIneligibleResource(t *testing.T) {
 	f := newTestFixture(t)
 
@@ -770,6 +999,19 @@ func (f *testFixture) assertNoTargetNextToBuild() {
 	}
 }
 
+func (f *testFixture) assertBuildToPreempt(expected model.ManifestName, waiting model.ManifestName) {
+	f.T().Helper()
+	actual, actualWaiting := NextBuildToPreempt(*f.st)
+	assert.Equal(f.t, expected, actual, "build to preempt")
+	assert.Equal(f.t, waiting, actualWaiting, "build waiting")
+}
+
+func (f *testFixture) assertNoBuildToPreempt() {
+	f.T().Helper()
+	actual, _ := NextBuildToPreempt(*f.st)
+	assert.Equal(f.t, model.ManifestName(""), actual, "expected no build to preempt")
+}
+
 func (f *testFixture) upsertManifest(m model.Manifest) *store.ManifestTarget {
 	mt := store.NewManifestTarget(m)
 	mt.State.DisableState = v1alpha1.DisableStateEnabled
@@ -808,6 +1050,21 @@ func withResourceDeps(deps ...string) manifestOption {
 		return m.WithResourceDeps(deps...)
 	})
 }
+func withConcurrencyGroups(groups ...model.ConcurrencyGroup) manifestOption {
+	return manifestOption(func(m manifestbuilder.ManifestBuilder) manifestbuilder.ManifestBuilder {
+		return m.WithConcurrencyGroups(groups...)
+	})
+}
+func withPriority(priority int) manifestOption {
+	return manifestOption(func(m manifestbuilder.ManifestBuilder) manifestbuilder.ManifestBuilder {
+		return m.WithPriority(priority)
+	})
+}
+func withLocalAllowParallel(allowParallel bool) manifestOption {
+	return manifestOption(func(m manifestbuilder.ManifestBuilder) manifestbuilder.ManifestBuilder {
+		return m.WithLocalAllowParallel(allowParallel)
+	})
+}
 func withK8sPodReadiness(pr model.PodReadinessMode) manifestOption {
 	return manifestOption(func(m manifestbuilder.ManifestBuilder) manifestbuilder.ManifestBuilder {
 		return m.WithK8sPodReadiness(pr)
@@ -16,8 +16,10 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -120,6 +122,10 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 	dcTargetNN := types.NamespacedName{Name: dcTarget.ID().Name.String()}
 	ctx = docker.WithOrchestrator(ctx, model.OrchestratorDC)
 
+	state := st.RLockState()
+	contentAddressed := state.Features[feature.ContentAddressedBuilds]
+	st.RUnlockState()
+
 	iTargets := plan.tiltManagedImageTargets
 	q, err := NewImageTargetQueue(ctx, plan.tiltManagedImageTargets, currentState, bd.ib.CanReuseRef)
 	if err != nil {
@@ -193,7 +199,16 @@ func (bd *DockerComposeBuildAndDeployer) BuildAndDeploy(ctx context.Context, st
 		}
 
 		cluster := currentState[target.ID()].ClusterOrEmpty()
-		return bd.build(ctx, iTarget, cmd, cluster, imageMapSet, ps)
+		tagHash := ""
+		if contentAddressed {
+			inputHash, err := imageInputHash(iTarget, cluster)
+			if err != nil {
+				logger.Get(ctx).Debugf("Hashing inputs of %s: %v", iTarget.ID(), err)
+			}
+			tagHash = inputHashWithDeps(inputHash, depResults)
+		}
+
+		return bd.build(ctx, iTarget, cmd, cluster, imageMapSet, tagHash, ps)
 	})
 
 	newResults := q.NewResults().ToBuildResultSet()
@@ -225,10 +240,11 @@ func (bd *DockerComposeBuildAndDeployer) build(
 	customBuildCmd *v1alpha1.Cmd,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	inputHash string,
 	ps *build.PipelineState) (store.ImageBuildResult, error) {
 	switch iTarget.BuildDetails.(type) {
 	case model.DockerBuild:
-		return bd.dr.ForceApply(ctx, iTarget, cluster, imageMaps, ps)
+		return bd.dr.ForceApplyWithInputHash(ctx, iTarget, cluster, imageMaps, inputHash, ps)
 	case model.CustomBuild:
 		return bd.cr.ForceApply(ctx, iTarget, customBuildCmd, cluster, imageMaps, ps)
 	}
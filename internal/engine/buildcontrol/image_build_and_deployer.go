@@ -13,9 +13,12 @@ import (
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmdimage"
 	"github.com/tilt-dev/tilt/internal/controllers/core/dockerimage"
 	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
+	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -29,6 +32,10 @@ type ImageBuildAndDeployer struct {
 	clock      build.Clock
 	ctrlClient ctrlclient.Client
 	r          *kubernetesapply.Reconciler
+
+	// Whether build results are saved across restarts, and so
+	// need the hash of their inputs.
+	persistState persist.EnabledFlag
 }
 
 func NewImageBuildAndDeployer(
@@ -39,15 +46,17 @@ func NewImageBuildAndDeployer(
 	c build.Clock,
 	ctrlClient ctrlclient.Client,
 	r *kubernetesapply.Reconciler,
+	persistState persist.EnabledFlag,
 ) *ImageBuildAndDeployer {
 	return &ImageBuildAndDeployer{
-		dr:         dr,
-		cr:         cr,
-		ib:         ib,
-		analytics:  analytics,
-		clock:      c,
-		ctrlClient: ctrlClient,
-		r:          r,
+		dr:           dr,
+		cr:           cr,
+		ib:           ib,
+		analytics:    analytics,
+		clock:        c,
+		ctrlClient:   ctrlClient,
+		r:            r,
+		persistState: persistState,
 	}
 }
 
@@ -67,6 +76,11 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 		})
 	}()
 
+	state := st.RLockState()
+	contentAddressed := state.Features[feature.ContentAddressedBuilds]
+	st.RUnlockState()
+
+	stateSet, restored := restoreImageResults(ctx, iTargets, stateSet)
 	q, err := NewImageTargetQueue(ctx, iTargets, stateSet, ibd.ib.CanReuseRef)
 	if err != nil {
 		return store.BuildResultSet{}, err
@@ -119,6 +133,16 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 			return nil, err
 		}
 		imageMapSet[nn] = im.DeepCopy()
+
+		// Images from before Tilt restarted aren't in the ImageMap yet.
+		result, ok := reused[iTarget.ID()]
+		if ok && restored[iTarget.ID()] {
+			imageMapSet[nn].Status = result.ImageMapStatus
+			err := ibd.ctrlClient.Status().Update(ctx, imageMapSet[nn])
+			if err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	err = q.RunBuilds(func(target model.TargetSpec, depResults []store.ImageBuildResult) (store.ImageBuildResult, error) {
@@ -137,11 +161,45 @@ func (ibd *ImageBuildAndDeployer) BuildAndDeploy(ctx context.Context, st store.R
 			}
 		}
 
+		// Hash the inputs before we build, so that if they change
+		// mid-build, the hash won't match the image.
+		//
+		// Hashing reads the whole build context, so skip it
+		// unless something is going to use the hash.
 		cluster := stateSet[target.ID()].ClusterOrEmpty()
-		return ibd.build(ctx, iTarget, cmd, cluster, imageMapSet, ps)
+		inputHash := ""
+		if contentAddressed || bool(ibd.persistState) {
+			hash, err := imageInputHash(iTarget, cluster)
+			if err != nil {
+				logger.Get(ctx).Debugf("Hashing inputs of %s: %v", iTarget.ID(), err)
+			}
+			inputHash = hash
+		}
+
+		// With content-addressed builds, we tag images with the hash of their
+		// inputs, and skip the build if we find an image with that tag.
+		tagHash := ""
+		if contentAddressed {
+			tagHash = inputHashWithDeps(inputHash, depResults)
+		}
+
+		result, err := ibd.build(ctx, iTarget, cmd, cluster, imageMapSet, tagHash, ps)
+		if err != nil {
+			return result, err
+		}
+		result.InputHash = inputHash
+		if inputHash != "" {
+			result.ClusterKey = imageClusterKey(cluster)
+		}
+		return result, nil
 	})
 
 	newResults := q.NewResults().ToBuildResultSet()
+	for id := range restored {
+		if result, ok := reused[id]; ok {
+			newResults[id] = result
+		}
+	}
 	if err != nil {
 		return newResults, WrapDontFallBackError(err)
 	}
@@ -162,10 +220,11 @@ func (ibd *ImageBuildAndDeployer) build(
 	customBuildCmd *v1alpha1.Cmd,
 	cluster *v1alpha1.Cluster,
 	imageMaps map[types.NamespacedName]*v1alpha1.ImageMap,
+	inputHash string,
 	ps *build.PipelineState) (store.ImageBuildResult, error) {
 	switch iTarget.BuildDetails.(type) {
 	case model.DockerBuild:
-		return ibd.dr.ForceApply(ctx, iTarget, cluster, imageMaps, ps)
+		return ibd.dr.ForceApplyWithInputHash(ctx, iTarget, cluster, imageMaps, inputHash, ps)
 	case model.CustomBuild:
 		return ibd.cr.ForceApply(ctx, iTarget, customBuildCmd, cluster, imageMaps, ps)
 	}
@@ -203,3 +262,39 @@ func (ibd *ImageBuildAndDeployer) delete(ctx context.Context, k8sTarget model.K8
 	kTargetNN := types.NamespacedName{Name: k8sTarget.ID().Name.String()}
 	return ibd.r.ForceDelete(ctx, kTargetNN, k8sTarget.KubernetesApplySpec, cluster, "force update")
 }
+
+// Re-uses image builds from before Tilt restarted, if their inputs haven't
+// changed since.
+//
+// Returns the new state set, and the IDs of the targets we restored.
+func restoreImageResults(ctx context.Context, iTargets []model.ImageTarget, stateSet store.BuildStateSet) (store.BuildStateSet, map[model.TargetID]bool) {
+	restored := make(map[model.TargetID]bool)
+	result := make(store.BuildStateSet, len(stateSet))
+	for id, state := range stateSet {
+		result[id] = state
+	}
+
+	for _, iTarget := range iTargets {
+		id := iTarget.ID()
+		state, ok := result[id]
+		if !ok || state.RestoredResult == nil || state.HasLastResult() || state.FullBuildTriggered {
+			continue
+		}
+
+		inputHash, err := imageInputHash(iTarget, state.ClusterOrEmpty())
+		if err != nil || inputHash != state.RestoredResult.InputHash {
+			continue
+		}
+
+		if imageClusterKey(state.ClusterOrEmpty()) != state.RestoredResult.ClusterKey {
+			logger.Get(ctx).Debugf("Cluster changed since %s was built, rebuilding", id)
+			continue
+		}
+
+		logger.Get(ctx).Debugf("Inputs of %s unchanged since last run (hash %s)", id, inputHash)
+		state.LastResult = *state.RestoredResult
+		result[id] = state
+		restored[id] = true
+	}
+	return result, restored
+}
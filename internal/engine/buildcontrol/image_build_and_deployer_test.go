@@ -12,7 +12,10 @@ import (
 
 	"github.com/distribution/reference"
 	"github.com/jonboulle/clockwork"
+	typescontainer "github.com/moby/moby/api/types/container"
 	typesimage "github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/system"
+	mobyclient "github.com/moby/moby/client"
 	"github.com/opencontainers/go-digest"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -27,6 +30,8 @@ import (
 	"github.com/tilt-dev/tilt/internal/container"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
 	"github.com/tilt-dev/tilt/internal/docker"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
+	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -260,6 +265,137 @@ func TestImageIsClean(t *testing.T) {
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
+func TestRestoredImageReusedIfInputsUnchanged(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	inputHash, err := imageInputHash(iTarget, f.cluster)
+	require.NoError(t, err)
+
+	restored := store.NewImageBuildResultSingleRef(iTarget.ID(),
+		container.MustParseNamedTagged("gcr.io/some-project-162817/sancho:tilt-restored"))
+	restored.InputHash = inputHash
+	restored.ClusterKey = imageClusterKey(f.cluster)
+	stateSet := store.BuildStateSet{
+		iTarget.ID(): store.BuildState{RestoredResult: &restored},
+	}
+	results, err := f.BuildAndDeploy(BuildTargets(manifest), stateSet)
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, f.docker.BuildCount)
+	assert.Equal(t, restored, results[iTarget.ID()])
+	assert.Contains(t, f.k8s.Yaml, "sancho:tilt-restored")
+
+	var im v1alpha1.ImageMap
+	require.NoError(t, f.ctrlClient.Get(f.ctx, ktypes.NamespacedName{Name: iTarget.ImageMapName()}, &im))
+	assert.Equal(t, "gcr.io/some-project-162817/sancho:tilt-restored", im.Status.Image)
+}
+
+func TestRestoredImageRebuiltIfInputsChanged(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	restored := store.NewImageBuildResultSingleRef(iTarget.ID(),
+		container.MustParseNamedTagged("gcr.io/some-project-162817/sancho:tilt-restored"))
+	restored.InputHash = "stale"
+	stateSet := store.BuildStateSet{
+		iTarget.ID(): store.BuildState{RestoredResult: &restored},
+	}
+	results, err := f.BuildAndDeploy(BuildTargets(manifest), stateSet)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, f.docker.BuildCount)
+	result := results[iTarget.ID()].(store.ImageBuildResult)
+	assert.NotEmpty(t, result.InputHash)
+	assert.NotEqual(t, "stale", result.InputHash)
+	assert.NotContains(t, f.k8s.Yaml, "sancho:tilt-restored")
+}
+
+func TestRestoredImageRebuiltIfClusterChanged(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	inputHash, err := imageInputHash(iTarget, f.cluster)
+	require.NoError(t, err)
+
+	otherCluster := f.cluster.DeepCopy()
+	otherCluster.Status.Connection.Kubernetes.Context = "other-context"
+
+	restored := store.NewImageBuildResultSingleRef(iTarget.ID(),
+		container.MustParseNamedTagged("gcr.io/some-project-162817/sancho:tilt-restored"))
+	restored.InputHash = inputHash
+	restored.ClusterKey = imageClusterKey(otherCluster)
+	stateSet := store.BuildStateSet{
+		iTarget.ID(): store.BuildState{RestoredResult: &restored},
+	}
+	results, err := f.BuildAndDeploy(BuildTargets(manifest), stateSet)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, f.docker.BuildCount)
+	result := results[iTarget.ID()].(store.ImageBuildResult)
+	assert.Equal(t, imageClusterKey(f.cluster), result.ClusterKey)
+	assert.NotContains(t, f.k8s.Yaml, "sancho:tilt-restored")
+}
+
+func TestNoInputHashWithoutPersistence(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.ibd.persistState = false
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	iTarget := manifest.ImageTargets[0]
+	results, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, f.docker.BuildCount)
+	result := results[iTarget.ID()].(store.ImageBuildResult)
+	assert.Empty(t, result.InputHash)
+}
+
+func TestContentAddressedBuildReusesLocalImage(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductDockerDesktop)
+	f.docker.ImageAlwaysExists = false
+	f.enableContentAddressedBuilds()
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount)
+	assert.Equal(t, 2, f.docker.TagCount)
+	inputHashRef := f.docker.TagTarget
+	assert.Contains(t, inputHashRef, ":tilt-inputs-")
+
+	// Once the image exists, we don't need to build it again.
+	f.docker.Images[inputHashRef] = typesimage.InspectResponse{}
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, f.docker.BuildCount)
+	assert.Contains(t, f.k8s.Yaml, inputHashRef)
+	assert.Contains(t, f.out.String(), "Skipping build: image "+inputHashRef+" was already built from the same inputs")
+
+	f.WriteFile("sancho/main.go", "package main")
+	_, err = f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, f.docker.BuildCount)
+	assert.NotEqual(t, inputHashRef, f.docker.TagTarget)
+}
+
+func TestContentAddressedBuildReusesRegistryImage(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductGKE)
+	f.docker.ImageAlwaysExists = false
+	f.enableContentAddressedBuilds()
+
+	// The fake registry has every image, so we don't need to build or push.
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 0, f.docker.BuildCount)
+	assert.Equal(t, 0, f.docker.PushCount)
+	assert.Contains(t, f.k8s.Yaml, "gcr.io/some-project-162817/sancho:tilt-inputs-")
+}
+
 func TestMultiStageDockerBuild(t *testing.T) {
 	f := newIBDFixture(t, clusterid.ProductGKE)
 
@@ -434,6 +570,99 @@ func TestKINDLoad(t *testing.T) {
 	assert.Equal(t, 0, f.docker.PushCount)
 }
 
+func TestContainerdImportKIND(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductKIND)
+	f.cluster.Status.Connection.Kubernetes.Cluster = "kind-kind"
+	f.docker.SaveOutput = "image-tarball"
+	f.docker.LabeledContainers = []typescontainer.Summary{
+		{ID: "kind-control-plane", Labels: map[string]string{"io.x-k8s.kind.cluster": "kind"}},
+		{ID: "kind-worker", Labels: map[string]string{"io.x-k8s.kind.cluster": "kind"}},
+		{ID: "other-control-plane", Labels: map[string]string{"io.x-k8s.kind.cluster": "other"}},
+	}
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, f.kl.loadCount)
+	assert.Equal(t, 0, f.docker.PushCount)
+
+	nodes := []string{}
+	for _, call := range f.docker.ExecCalls {
+		assert.Equal(t, "ctr --namespace=k8s.io images import --all-platforms --digests -", call.Cmd.String())
+		assert.Equal(t, "image-tarball", call.Input)
+		nodes = append(nodes, call.Container)
+	}
+	assert.ElementsMatch(t, []string{"kind-control-plane", "kind-worker"}, nodes)
+}
+
+func TestContainerdImportK3D(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductK3D)
+	f.cluster.Status.Connection.Kubernetes.Cluster = "k3d-dev"
+	f.docker.LabeledContainers = []typescontainer.Summary{
+		{ID: "k3d-dev-server-0", Labels: map[string]string{"k3d.cluster": "dev", "k3d.role": "server"}},
+		{ID: "k3d-dev-agent-0", Labels: map[string]string{"k3d.cluster": "dev", "k3d.role": "agent"}},
+		{ID: "k3d-dev-serverlb", Labels: map[string]string{"k3d.cluster": "dev", "k3d.role": "loadbalancer"}},
+	}
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, f.docker.PushCount)
+	nodes := []string{}
+	for _, call := range f.docker.ExecCalls {
+		nodes = append(nodes, call.Container)
+	}
+	assert.ElementsMatch(t, []string{"k3d-dev-server-0", "k3d-dev-agent-0"}, nodes)
+}
+
+func TestContainerdImportFailureFallsBackToPush(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductMinikube)
+	f.cluster.Status.Connection.Kubernetes.Cluster = "minikube"
+	f.docker.LabeledContainers = []typescontainer.Summary{
+		{ID: "minikube", Labels: map[string]string{"name.minikube.sigs.k8s.io": "minikube"}},
+	}
+	f.docker.SetExecError(fmt.Errorf("ctr: not found"))
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Contains(t, f.out.String(), "Containerd import failed, falling back: importing image on node minikube: ctr: not found")
+	assert.Equal(t, 1, f.docker.PushCount)
+}
+
+func TestContainerdImportNoNodesFallsBackToPush(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductK3D)
+	f.cluster.Status.Connection.Kubernetes.Cluster = "k3d-dev"
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	require.NoError(t, err)
+
+	assert.Empty(t, f.docker.ExecCalls)
+	assert.Equal(t, 1, f.docker.PushCount)
+}
+
+func TestKINDLoadPodman(t *testing.T) {
+	f := newIBDFixture(t, clusterid.ProductKIND)
+	f.docker.FakeServerVersion = mobyclient.ServerVersionResult{
+		Components: []system.ComponentVersion{{Name: "Podman Engine"}},
+	}
+
+	manifest := NewSanchoDockerBuildManifest(f)
+	_, err := f.BuildAndDeploy(BuildTargets(manifest), store.BuildStateSet{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, 1, f.docker.BuildCount)
+	assert.Equal(t, 0, f.kl.loadCount)
+	assert.Equal(t, 1, f.kl.archiveLoadCount)
+	assert.Equal(t, []string{"gcr.io/some-project-162817/sancho:tilt-11cd0b38bc3ceb95"}, f.docker.SavedImageIDs)
+	assert.Equal(t, 0, f.docker.PushCount)
+}
+
 func TestDockerPushIfKINDAndClusterRef(t *testing.T) {
 	f := newIBDFixture(t, clusterid.ProductKIND)
 	f.cluster.Spec.DefaultRegistry = &v1alpha1.RegistryHosting{
@@ -1121,7 +1350,7 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	st := store.NewTestingStore()
 	cclock := clockwork.NewFakeClock()
 	ibd, err := ProvideImageBuildAndDeployer(ctx, dockerClient, kClient, env, kubeContext,
-		clusterEnv, dir, clock, cclock, kl, ta, ctrlClient, st)
+		clusterEnv, dir, clock, cclock, kl, ta, ctrlClient, st, persist.EnabledFlag(true))
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -1152,6 +1381,12 @@ func newIBDFixture(t *testing.T, env clusterid.Product) *ibdFixture {
 	return ret
 }
 
+func (f *ibdFixture) enableContentAddressedBuilds() {
+	f.st.WithState(func(state *store.EngineState) {
+		state.Features = map[string]bool{feature.ContentAddressedBuilds: true}
+	})
+}
+
 func (f *ibdFixture) upsertSpec(obj ctrlclient.Object) {
 	fake.UpsertSpec(f.ctx, f.T(), f.ctrlClient, obj)
 }
@@ -1252,7 +1487,8 @@ status: {}`, name, name)
 }
 
 type fakeKINDLoader struct {
-	loadCount int
+	loadCount        int
+	archiveLoadCount int
 }
 
 func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Cluster, ref reference.NamedTagged) error {
@@ -1260,6 +1496,11 @@ func (kl *fakeKINDLoader) LoadToKIND(ctx context.Context, cluster *v1alpha1.Clus
 	return nil
 }
 
+func (kl *fakeKINDLoader) LoadArchiveToKIND(ctx context.Context, cluster *v1alpha1.Cluster, path string) error {
+	kl.archiveLoadCount++
+	return nil
+}
+
 type fakeClock struct {
 	now time.Time
 }
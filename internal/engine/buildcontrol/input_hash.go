@@ -0,0 +1,199 @@
+package buildcontrol
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/tilt-dev/tilt/internal/build"
+	"github.com/tilt-dev/tilt/internal/ignore"
+	"github.com/tilt-dev/tilt/internal/sliceutils"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Computes a hash of everything that goes into an image build: the build
+// spec, and the contents of the files in the build context.
+//
+// Files excluded from the build context don't count, so that editing
+// an ignored file doesn't change the hash.
+//
+// For docker_build(), we hash the spec with the cluster's platform injected,
+// since that's what we actually build.
+func imageInputHash(iTarget model.ImageTarget, cluster *v1alpha1.Cluster) (string, error) {
+	var filter model.PathMatcher
+	buildDetails := iTarget.BuildDetails
+	switch bd := iTarget.BuildDetails.(type) {
+	case model.DockerBuild:
+		filter = ignore.CreateBuildContextFilter(bd.DockerImageSpec.ContextIgnores)
+		bd.DockerImageSpec = build.InjectClusterPlatform(bd.DockerImageSpec, cluster)
+		buildDetails = bd
+	case model.CustomBuild:
+		filter = ignore.CreateFileChangeFilter(iTarget.FileWatchIgnores)
+	default:
+		return "", fmt.Errorf("can't hash inputs of image %s", iTarget.ID())
+	}
+
+	spec := struct {
+		Selector     string
+		BuildDetails model.BuildDetails
+	}{iTarget.ImageMapSpec.Selector, buildDetails}
+	return hashInputs(spec, iTarget.Dependencies(), filter)
+}
+
+// Identifies the cluster that an image was built for: where it was pushed
+// or loaded, and for which architecture.
+//
+// An image from before Tilt restarted may not exist in a different cluster,
+// so we only re-use it if this still matches.
+func imageClusterKey(cluster *v1alpha1.Cluster) string {
+	var key struct {
+		Connection *v1alpha1.ClusterConnection
+		Context    string
+		Cluster    string
+		Registry   *v1alpha1.RegistryHosting
+		Arch       string
+	}
+	if cluster != nil {
+		key.Connection = cluster.Spec.Connection
+		key.Registry = cluster.Status.Registry
+		key.Arch = cluster.Status.Arch
+		if conn := cluster.Status.Connection; conn != nil && conn.Kubernetes != nil {
+			key.Context = conn.Kubernetes.Context
+			key.Cluster = conn.Kubernetes.Cluster
+		}
+	}
+	keyJSON, err := json.Marshal(key)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(keyJSON)
+	return hex.EncodeToString(sum[:])
+}
+
+// Computes a hash of everything that goes into a local_resource's update
+// command: the command itself, and the contents of its deps.
+func localInputHash(lt model.LocalTarget) (string, error) {
+	if lt.UpdateCmdSpec == nil {
+		return "", fmt.Errorf("local resource %s has no update command", lt.ID())
+	}
+	filter := ignore.CreateFileChangeFilter(lt.FileWatchIgnores)
+	return hashInputs(lt.UpdateCmdSpec, sliceutils.DedupedAndSorted(lt.Deps), filter)
+}
+
+// Computes a hash of the files that a local_resource's update command
+// generates. Returns an error if any of them are missing.
+func localOutputHash(lt model.LocalTarget) (string, error) {
+	outputs := sliceutils.DedupedAndSorted(lt.Outputs)
+	for _, p := range outputs {
+		_, err := os.Stat(p)
+		if err != nil {
+			return "", err
+		}
+	}
+	return hashInputs(nil, outputs, model.EmptyMatcher)
+}
+
+func hashInputs(spec interface{}, paths []string, filter model.PathMatcher) (string, error) {
+	h := sha256.New()
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	_, _ = h.Write(specJSON)
+
+	for _, p := range paths {
+		err := hashPath(h, p, filter)
+		if err != nil {
+			return "", err
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// Writes the names, types, and contents of the files under the given path
+// to the hash, in lexical order.
+func hashPath(h hash.Hash, root string, filter model.PathMatcher) error {
+	return filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == root {
+				_, _ = fmt.Fprintf(h, "missing %s\n", p)
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			if p == root {
+				return nil
+			}
+			ignored, err := filter.MatchesEntireDir(p)
+			if err != nil {
+				return err
+			}
+			if ignored {
+				return filepath.SkipDir
+			}
+			_, _ = fmt.Fprintf(h, "dir %s\n", p)
+			return nil
+		}
+
+		ignored, err := filter.Matches(p)
+		if err != nil {
+			return err
+		}
+		if ignored {
+			return nil
+		}
+
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(p)
+			if err != nil {
+				return err
+			}
+			_, _ = fmt.Fprintf(h, "link %s %s\n", p, target)
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		_, _ = fmt.Fprintf(h, "file %s %o %d\n", p, info.Mode().Perm(), info.Size())
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = f.Close()
+		}()
+		_, err = io.Copy(h, f)
+		return err
+	})
+}
+
+// Mixes the images that an image is built on into the hash of its inputs,
+// so that rebuilding a base image changes the hash of the images built on it.
+func inputHashWithDeps(inputHash string, depResults []store.ImageBuildResult) string {
+	if inputHash == "" || len(depResults) == 0 {
+		return inputHash
+	}
+
+	h := sha256.New()
+	_, _ = fmt.Fprintf(h, "%s\n", inputHash)
+	for _, dep := range depResults {
+		_, _ = fmt.Fprintf(h, "dep %s %s\n", dep.TargetID(), dep.ImageMapStatus.ImageFromLocal)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,119 @@
+package buildcontrol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils/tempdir"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestImageInputHashFileContents(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("main.go", "package main")
+	iTarget := NewSanchoDockerBuildImageTarget(f)
+
+	h1 := mustImageInputHash(t, iTarget)
+	assert.Equal(t, h1, mustImageInputHash(t, iTarget))
+
+	// Touching a file without changing it doesn't change the hash.
+	f.WriteFile("main.go", "package main")
+	assert.Equal(t, h1, mustImageInputHash(t, iTarget))
+
+	f.WriteFile("main.go", "package main\n\nfunc main() {}")
+	h2 := mustImageInputHash(t, iTarget)
+	assert.NotEqual(t, h1, h2)
+
+	f.WriteFile("lib/lib.go", "package lib")
+	assert.NotEqual(t, h2, mustImageInputHash(t, iTarget))
+}
+
+func TestImageInputHashIgnoresExcludedFiles(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("main.go", "package main")
+	iTarget := NewSanchoDockerBuildImageTarget(f).WithIgnores([]v1alpha1.IgnoreDef{
+		{BasePath: f.Path(), Patterns: []string{"*.log"}},
+		{BasePath: f.JoinPath("tmp")},
+	})
+
+	h1 := mustImageInputHash(t, iTarget)
+	f.WriteFile("debug.log", "hello")
+	f.WriteFile("tmp/scratch.txt", "hello")
+	assert.Equal(t, h1, mustImageInputHash(t, iTarget))
+}
+
+func TestImageInputHashSpec(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	iTarget := NewSanchoDockerBuildImageTarget(f)
+	h1 := mustImageInputHash(t, iTarget)
+
+	spec := iTarget.DockerBuildInfo().DockerImageSpec
+	spec.Args = []string{"DEBUG=1"}
+	assert.NotEqual(t, h1, mustImageInputHash(t, iTarget.WithDockerImage(spec)))
+}
+
+func TestImageInputHashClusterPlatform(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	iTarget := NewSanchoDockerBuildImageTarget(f)
+
+	amd64 := &v1alpha1.Cluster{Status: v1alpha1.ClusterStatus{Arch: "amd64"}}
+	arm64 := &v1alpha1.Cluster{Status: v1alpha1.ClusterStatus{Arch: "arm64"}}
+	h1, err := imageInputHash(iTarget, amd64)
+	require.NoError(t, err)
+	h2, err := imageInputHash(iTarget, arm64)
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h2)
+
+	// An explicit platform doesn't depend on the cluster.
+	spec := iTarget.DockerBuildInfo().DockerImageSpec
+	spec.Platform = "linux/amd64"
+	iTarget = iTarget.WithDockerImage(spec)
+	h3, err := imageInputHash(iTarget, amd64)
+	require.NoError(t, err)
+	h4, err := imageInputHash(iTarget, arm64)
+	require.NoError(t, err)
+	assert.Equal(t, h3, h4)
+}
+
+func TestInputHashWithDeps(t *testing.T) {
+	base := store.NewImageBuildResultSingleRef(model.ImageID(container.MustParseSelector("sancho-base")),
+		container.MustParseNamedTagged("sancho-base:tilt-1"))
+	h1 := inputHashWithDeps("abc", []store.ImageBuildResult{base})
+	assert.NotEqual(t, "abc", h1)
+	assert.Equal(t, "abc", inputHashWithDeps("abc", nil))
+
+	rebuilt := store.NewImageBuildResultSingleRef(base.TargetID(),
+		container.MustParseNamedTagged("sancho-base:tilt-2"))
+	assert.NotEqual(t, h1, inputHashWithDeps("abc", []store.ImageBuildResult{rebuilt}))
+}
+
+func TestLocalInputHash(t *testing.T) {
+	f := tempdir.NewTempDirFixture(t)
+	f.WriteFile("src/main.go", "package main")
+	lt := model.NewLocalTarget("gen", model.ToHostCmd("make gen"), model.Cmd{}, []string{f.JoinPath("src")})
+
+	h1, err := localInputHash(lt)
+	require.NoError(t, err)
+
+	f.WriteFile("src/main.go", "package main // changed")
+	h2, err := localInputHash(lt)
+	require.NoError(t, err)
+	assert.NotEqual(t, h1, h2)
+
+	lt = model.NewLocalTarget("gen", model.ToHostCmd("make gen2"), model.Cmd{}, []string{f.JoinPath("src")})
+	h3, err := localInputHash(lt)
+	require.NoError(t, err)
+	assert.NotEqual(t, h2, h3)
+}
+
+func mustImageInputHash(t *testing.T, iTarget model.ImageTarget) string {
+	t.Helper()
+	h, err := imageInputHash(iTarget, nil)
+	require.NoError(t, err)
+	return h
+}
@@ -11,8 +11,10 @@ import (
 	"github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/build"
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
+	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 )
 
@@ -47,7 +49,7 @@ func (bd *LocalTargetBuildAndDeployer) BuildAndDeploy(ctx context.Context, st st
 	if targ.UpdateCmdSpec == nil {
 		// Even if a LocalResource has no update command, we push it through the build-and-deploy
 		// pipeline so that it gets all the appropriate logs.
-		return bd.successfulBuildResult(targ), nil
+		return bd.successfulBuildResult(targ, "", ""), nil
 	}
 
 	startTime := time.Now()
@@ -57,6 +59,43 @@ func (bd *LocalTargetBuildAndDeployer) BuildAndDeploy(ctx context.Context, st st
 		})
 	}()
 
+	// With content-addressed builds, skip the command if the deps haven't
+	// changed since it last succeeded, e.g., if a file was touched or a git
+	// checkout put back the same contents.
+	//
+	// If the command declares outputs, those need to be unchanged too.
+	state := st.RLockState()
+	contentAddressed := state.Features[feature.ContentAddressedBuilds]
+	st.RUnlockState()
+
+	hasOutputs := len(targ.Outputs) > 0
+	inputHash := ""
+	if contentAddressed || hasOutputs {
+		inputHash, err = localInputHash(targ)
+		if err != nil {
+			logger.Get(ctx).Debugf("Hashing inputs of %s: %v", targ.ID(), err)
+			inputHash = ""
+		}
+
+		buildState := stateSet[targ.ID()]
+		lastResult, ok := buildState.LastResult.(store.LocalBuildResult)
+		canSkip := ok && inputHash != "" && lastResult.InputHash == inputHash && !buildState.FullBuildTriggered
+		if canSkip && hasOutputs {
+			outputHash, err := localOutputHash(targ)
+			if err != nil {
+				logger.Get(ctx).Infof("Outputs not cached: %v", err)
+			} else if outputHash != lastResult.OutputHash {
+				logger.Get(ctx).Infof("Outputs not cached: outputs changed since the last successful run")
+			} else {
+				logger.Get(ctx).Infof("Skipping update: outputs cached (deps hash %s)", inputHash)
+				return bd.successfulBuildResult(targ, inputHash, outputHash), nil
+			}
+		} else if canSkip {
+			logger.Get(ctx).Infof("Skipping update: deps unchanged since the last successful run (hash %s)", inputHash)
+			return bd.successfulBuildResult(targ, inputHash, ""), nil
+		}
+	}
+
 	var cmd v1alpha1.Cmd
 	err = bd.ctrlClient.Get(ctx, types.NamespacedName{Name: targ.UpdateCmdName()}, &cmd)
 	if err != nil {
@@ -75,6 +114,16 @@ func (bd *LocalTargetBuildAndDeployer) BuildAndDeploy(ctx context.Context, st st
 			model.ArgListToString(cmd.Spec.Args), status.Terminated.Reason)
 	}
 
+	outputHash := ""
+	if hasOutputs && inputHash != "" {
+		outputHash, err = localOutputHash(targ)
+		if err != nil {
+			logger.Get(ctx).Warnf("Not caching outputs of %s: %v", targ.ID(), err)
+			outputHash = ""
+			err = nil
+		}
+	}
+
 	// HACK(maia) Suppose target A modifies file X and target B depends on file X.
 	//
 	// Consider this sequence:
@@ -108,7 +157,7 @@ func (bd *LocalTargetBuildAndDeployer) BuildAndDeploy(ctx context.Context, st st
 	//   in our watch tests).
 	time.Sleep(250 * time.Millisecond)
 
-	return bd.successfulBuildResult(targ), nil
+	return bd.successfulBuildResult(targ, inputHash, outputHash), nil
 }
 
 // Extract the targets we can apply -- i.e. LocalTargets
@@ -122,7 +171,9 @@ func (bd *LocalTargetBuildAndDeployer) extract(specs []model.TargetSpec) []model
 	return targs
 }
 
-func (bd *LocalTargetBuildAndDeployer) successfulBuildResult(t model.LocalTarget) store.BuildResultSet {
+func (bd *LocalTargetBuildAndDeployer) successfulBuildResult(t model.LocalTarget, inputHash, outputHash string) store.BuildResultSet {
 	br := store.NewLocalBuildResult(t.ID())
+	br.InputHash = inputHash
+	br.OutputHash = outputHash
 	return store.BuildResultSet{t.ID(): br}
 }
@@ -7,6 +7,7 @@ import (
 	"io"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,6 +19,7 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/controllers/core/cmd"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/feature"
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
@@ -121,6 +123,81 @@ func TestFailedCommand(t *testing.T) {
 	assert.Contains(t, f.out.String(), "oh no", "expect cmd stdout in logs")
 }
 
+func TestContentAddressedSkipsUnchangedDeps(t *testing.T) {
+	f := newLTFixture(t)
+	f.st.WithState(func(state *store.EngineState) {
+		state.Features = map[string]bool{feature.ContentAddressedBuilds: true}
+	})
+
+	f.WriteFile("src/main.go", "package main")
+	targ := f.localTarget("echo hello world")
+	targ.Deps = []string{f.JoinPath("src")}
+
+	res, err := f.ltbad.BuildAndDeploy(f.ctx, f.st, []model.TargetSpec{targ}, store.BuildStateSet{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(f.out.String(), "Running cmd"))
+
+	// Touch the file without changing it.
+	f.WriteFile("src/main.go", "package main")
+	stateSet := store.BuildStateSet{
+		targ.ID(): store.NewBuildState(res[targ.ID()], []string{f.JoinPath("src/main.go")}, nil),
+	}
+	res, err = f.ltbad.BuildAndDeploy(f.ctx, f.st, []model.TargetSpec{targ}, stateSet)
+	require.NoError(t, err)
+	assert.Equal(t, 1, strings.Count(f.out.String(), "Running cmd"))
+	assert.Contains(t, f.out.String(), "Skipping update: deps unchanged since the last successful run (hash ")
+
+	f.WriteFile("src/main.go", "package main // changed")
+	stateSet = store.BuildStateSet{
+		targ.ID(): store.NewBuildState(res[targ.ID()], []string{f.JoinPath("src/main.go")}, nil),
+	}
+	_, err = f.ltbad.BuildAndDeploy(f.ctx, f.st, []model.TargetSpec{targ}, stateSet)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(f.out.String(), "Running cmd"))
+}
+
+func TestOutputsCached(t *testing.T) {
+	f := newLTFixture(t)
+
+	f.WriteFile("src/main.proto", "syntax = \"proto3\";")
+	targ := f.localTarget("mkdir -p gen && echo generated > gen/main.pb.go")
+	targ.Deps = []string{f.JoinPath("src")}
+	targ = targ.WithOutputs([]string{f.JoinPath("gen")})
+
+	build := func(lastResult store.BuildResult) store.BuildResult {
+		t.Helper()
+		stateSet := store.BuildStateSet{}
+		if lastResult != nil {
+			stateSet[targ.ID()] = store.NewBuildState(lastResult, nil, nil)
+		}
+		res, err := f.ltbad.BuildAndDeploy(f.ctx, f.st, []model.TargetSpec{targ}, stateSet)
+		require.NoError(t, err)
+		return res[targ.ID()]
+	}
+
+	res := build(nil)
+	assert.Equal(t, 1, strings.Count(f.out.String(), "Running cmd"))
+	assert.NotEmpty(t, res.(store.LocalBuildResult).OutputHash)
+
+	res = build(res)
+	assert.Equal(t, 1, strings.Count(f.out.String(), "Running cmd"))
+	assert.Contains(t, f.out.String(), "Skipping update: outputs cached (deps hash ")
+
+	// If the outputs change, we need to run the cmd again.
+	f.WriteFile("gen/main.pb.go", "corrupted")
+	res = build(res)
+	assert.Equal(t, 2, strings.Count(f.out.String(), "Running cmd"))
+	assert.Contains(t, f.out.String(), "outputs changed since the last successful run")
+
+	f.Rm("gen")
+	res = build(res)
+	assert.Equal(t, 3, strings.Count(f.out.String(), "Running cmd"))
+
+	f.WriteFile("src/main.proto", "syntax = \"proto2\";")
+	_ = build(res)
+	assert.Equal(t, 4, strings.Count(f.out.String(), "Running cmd"))
+}
+
 type testStore struct {
 	*store.TestingStore
 	out io.Writer
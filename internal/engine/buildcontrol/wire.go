@@ -26,6 +26,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -70,7 +71,8 @@ func ProvideImageBuildAndDeployer(
 	kp build.KINDLoader,
 	analytics *analytics.TiltAnalytics,
 	ctrlclient ctrlclient.Client,
-	st store.RStore) (*ImageBuildAndDeployer, error) {
+	st store.RStore,
+	persistState persist.EnabledFlag) (*ImageBuildAndDeployer, error) {
 	wire.Build(
 		BaseWireSet,
 		kubernetesapply.NewReconciler,
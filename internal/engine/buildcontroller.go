@@ -12,10 +12,13 @@ import (
 
 	"github.com/tilt-dev/tilt/internal/controllers/apis/uibutton"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/store/buildcontrols"
 	"github.com/tilt-dev/tilt/internal/store/k8sconv"
+	"github.com/tilt-dev/tilt/internal/tracer"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
@@ -30,6 +33,10 @@ type BuildController struct {
 	// CancelFuncs for in-progress builds
 	mu           sync.Mutex
 	stopBuildFns map[model.ManifestName]context.CancelFunc
+
+	// In-progress builds we've canceled on purpose (rather than at the
+	// user's request), mapped to the error to record in the build history.
+	interrupted map[model.ManifestName]error
 }
 
 type buildEntry struct {
@@ -49,6 +56,7 @@ func NewBuildController(b buildcontrol.BuildAndDeployer) *BuildController {
 	return &BuildController{
 		b:            b,
 		stopBuildFns: make(map[model.ManifestName]context.CancelFunc),
+		interrupted:  make(map[model.ManifestName]error),
 	}
 }
 
@@ -85,6 +93,7 @@ func (c *BuildController) needsBuild(ctx context.Context, st store.RStore) (buil
 		state.Clusters[manifest.ClusterName()],
 		targets,
 		ms,
+		state.RestoredImageResults,
 		buildReason)
 
 	return buildEntry{
@@ -111,6 +120,10 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 	if c.disabledForTesting {
 		return nil
 	}
+
+	c.preemptLowerPriorityBuilds(ctx, st)
+	c.cancelStaleImageBuilds(ctx, st)
+
 	entry, ok := c.needsBuild(ctx, st)
 	if !ok {
 		return nil
@@ -128,6 +141,7 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 
 	go func() {
 		ctx = c.buildContext(ctx, entry, st)
+		defer c.clearInterrupted(entry.name)
 		defer c.cleanupBuildContext(entry.name)
 
 		buildcontrols.LogBuildEntry(ctx, buildcontrols.BuildEntry{
@@ -139,6 +153,9 @@ func (c *BuildController) OnChange(ctx context.Context, st store.RStore, summary
 		result, err := c.buildAndDeploy(ctx, st, entry)
 		if ctx.Err() == context.Canceled {
 			err = errors.New("build canceled")
+			if interruptErr := c.interruptError(entry.name); interruptErr != nil {
+				err = interruptErr
+			}
 		}
 		st.Dispatch(buildcontrols.NewBuildCompleteAction(entry.name, BuildControlSource, entry.spanID, result, err))
 	}()
@@ -154,6 +171,9 @@ func (c *BuildController) buildAndDeploy(ctx context.Context, st store.RStore, e
 			return store.BuildResultSet{}, err
 		}
 	}
+	ctx = tracer.WithAttributes(ctx,
+		tracer.AttrManifestName.String(entry.name.String()),
+		tracer.AttrBuildReason.String(entry.buildReason.String()))
 	return c.b.BuildAndDeploy(ctx, st, targets, entry.buildStateSet)
 }
 
@@ -180,6 +200,63 @@ func (c *BuildController) cleanUpCanceledBuilds(st store.RStore) {
 	}
 }
 
+// cancel an in-progress local build if a build with a higher priority is
+// waiting on it
+func (c *BuildController) preemptLowerPriorityBuilds(ctx context.Context, st store.RStore) {
+	state := st.RLockState()
+	preempt, waiting := buildcontrol.NextBuildToPreempt(state)
+	st.RUnlockState()
+
+	if preempt == "" {
+		return
+	}
+
+	if c.interruptBuild(preempt, buildcontrols.PreemptedError{By: waiting}) {
+		logger.Get(ctx).Infof("Canceling update of %s to make room for higher-priority update of %s", preempt, waiting)
+	}
+}
+
+// cancel in-progress image builds that have new file changes, for
+// resources that opted in, so that we can start over with all the changes
+func (c *BuildController) cancelStaleImageBuilds(ctx context.Context, st store.RStore) {
+	state := st.RLockState()
+	stale := buildcontrol.StaleImageBuilds(state)
+	st.RUnlockState()
+
+	for mn, files := range stale {
+		if c.interruptBuild(mn, buildcontrols.SupersededError{FilesChanged: files}) {
+			logger.Get(ctx).Infof("Canceling image build of %s because files changed: %s",
+				mn, ospath.FormatFileChangeList(files))
+		}
+	}
+}
+
+// Cancels an in-progress build and records the error it should complete with.
+//
+// Returns false if the build isn't running or was already interrupted.
+func (c *BuildController) interruptBuild(mn model.ManifestName, err error) bool {
+	c.mu.Lock()
+	_, alreadyInterrupted := c.interrupted[mn]
+	_, isRunning := c.stopBuildFns[mn]
+	if !alreadyInterrupted && isRunning {
+		c.interrupted[mn] = err
+	}
+	c.mu.Unlock()
+
+	if alreadyInterrupted || !isRunning {
+		return false
+	}
+
+	c.cleanupBuildContext(mn)
+	return true
+}
+
+func (c *BuildController) interruptError(mn model.ManifestName) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.interrupted[mn]
+}
+
 func (c *BuildController) buildContext(ctx context.Context, entry buildEntry, st store.RStore) context.Context {
 	// Send the logs to both the EngineState and the normal log stream.
 	ctx = store.WithManifestLogHandler(ctx, st, entry.name, entry.spanID)
@@ -200,6 +277,12 @@ func (c *BuildController) cleanupBuildContext(mn model.ManifestName) {
 	}
 }
 
+func (c *BuildController) clearInterrupted(mn model.ManifestName) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.interrupted, mn)
+}
+
 func SpanIDForBuildLog(buildCount int) logstore.SpanID {
 	return logstore.SpanID(fmt.Sprintf("build:%d", buildCount))
 }
@@ -210,7 +293,9 @@ func buildStateSet(ctx context.Context, manifest model.Manifest,
 	dcs *v1alpha1.DockerComposeService,
 	cluster *v1alpha1.Cluster,
 	specs []model.TargetSpec,
-	ms *store.ManifestState, reason model.BuildReason) store.BuildStateSet {
+	ms *store.ManifestState,
+	restored map[model.TargetID]store.ImageBuildResult,
+	reason model.BuildReason) store.BuildStateSet {
 	result := store.BuildStateSet{}
 
 	for _, spec := range specs {
@@ -229,6 +314,9 @@ func buildStateSet(ctx context.Context, manifest model.Manifest,
 
 		state := store.NewBuildState(status.LastResult, filesChanged, depsChanged)
 		state.Cluster = cluster
+		if r, ok := restored[id]; ok && status.LastResult == nil {
+			state.RestoredResult = &r
+		}
 		result[id] = state
 	}
 
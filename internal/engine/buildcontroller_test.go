@@ -1001,6 +1001,78 @@ local_resource('local', 'sleep 10000')
 	require.NoError(t, err)
 }
 
+func TestPreemptLowerPriorityLocalBuild(t *testing.T) {
+	t.Parallel()
+	f := newTestFixture(t)
+	f.b.completeBuildsManually = true
+	f.useRealTiltfileLoader()
+	f.WriteFile("Tiltfile", `
+update_settings(preempt_local_updates=True)
+local_resource('slow', 'sleep 10000')
+local_resource('urgent', 'echo hi', priority=10, auto_init=False)
+`)
+	f.loadAndStart()
+	f.waitUntilManifestBuilding("slow")
+
+	f.store.Dispatch(store.AppendToTriggerQueueAction{Name: "urgent"})
+	f.waitUntilManifestBuilding("urgent")
+
+	f.withManifestState("slow", func(ms store.ManifestState) {
+		require.EqualError(t, ms.LastBuild().Error,
+			"build preempted by higher-priority update of urgent")
+	})
+
+	// Once the higher-priority build finishes, the preempted build runs again.
+	f.withManifestTarget("urgent", func(mt store.ManifestTarget) {
+		f.completeBuildForManifest(mt.Manifest)
+	})
+	f.waitUntilManifestBuilding("slow")
+
+	err := f.Stop()
+	require.NoError(t, err)
+}
+
+func TestCancelStaleImageBuild(t *testing.T) {
+	t.Parallel()
+	f := newTestFixture(t)
+	f.b.completeBuildsManually = true
+
+	manA := f.newDockerBuildManifestWithBuildPath("manA", f.JoinPath("a")).
+		WithCancelStaleImageBuilds(true)
+	f.Start([]model.Manifest{manA})
+	f.completeAndCheckBuildsForManifests(manA)
+
+	f.editFileAndWaitForManifestBuilding("manA", "a/main.go")
+
+	// Simulate the image build in progress.
+	state := f.store.LockMutableStateForTesting()
+	state.DockerImages[manA.ImageTargetAt(0).DockerImageName] = &v1alpha1.DockerImage{
+		Status: v1alpha1.DockerImageStatus{
+			Building: &v1alpha1.DockerImageStateBuilding{StartedAt: metav1.NowMicro()},
+		},
+	}
+	f.store.UnlockMutableState()
+
+	f.fsWatcher.Events <- watch.NewFileEvent(f.JoinPath("a/other.go"))
+	call := f.nextCall("expect canceled manA build")
+	f.assertCallIsForManifestAndFiles(call, manA, "a/main.go")
+
+	f.waitForCompletedBuildCount(2)
+	f.withManifestState("manA", func(ms store.ManifestState) {
+		require.EqualError(t, ms.LastBuild().Error, "build canceled: superseded by 1 new file change(s)")
+	})
+
+	// The next build has all the changes.
+	f.waitUntilManifestBuilding("manA")
+	f.completeBuildForManifest(manA)
+	call = f.nextCall("expect manA rebuild")
+	f.assertCallIsForManifestAndFiles(call, manA, "a/main.go", "a/other.go")
+
+	err := f.Stop()
+	assert.NoError(t, err)
+	f.assertAllBuildsConsumed()
+}
+
 func TestCancelButtonClickedBeforeBuild(t *testing.T) {
 	t.Parallel()
 	f := newTestFixture(t)
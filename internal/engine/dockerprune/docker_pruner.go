@@ -164,6 +164,12 @@ func (dp *DockerPruner) prune(ctx context.Context, maxAge time.Duration, keepRec
 	prettyPrintImagesPruneReport(imageReport, l)
 
 	// PRUNE BUILD CACHE
+	if dp.isPodman(ctx) {
+		// Podman doesn't use BuildKit, so there's no build cache to prune.
+		l.Debugf("[Docker Prune] skipping build cache prune on podman")
+		return nil
+	}
+
 	opts := mobyclient.BuildCachePruneOptions{Filters: f}
 	cacheReport, err := dp.dCli.BuildCachePrune(ctx, opts)
 	if err != nil {
@@ -298,6 +304,11 @@ func (dp *DockerPruner) deleteOldImages(ctx context.Context, maxAge time.Duratio
 	}, nil
 }
 
+func (dp *DockerPruner) isPodman(ctx context.Context) bool {
+	v, err := dp.dCli.ServerVersion(ctx)
+	return err == nil && docker.IsPodman(v)
+}
+
 func (dp *DockerPruner) sufficientVersionError() error {
 	return dp.dCli.NewVersionError(context.Background(),
 		"1.30", "image | container prune with filter: label")
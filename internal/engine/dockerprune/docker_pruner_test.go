@@ -10,6 +10,7 @@ import (
 	"github.com/distribution/reference"
 	"github.com/docker/go-units"
 	typesimage "github.com/moby/moby/api/types/image"
+	"github.com/moby/moby/api/types/system"
 	mobyclient "github.com/moby/moby/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -99,6 +100,24 @@ func TestPruneSkipCachePruneIfVersionTooLow(t *testing.T) {
 	assert.NotEmpty(t, f.dCli.RemovedImageIDs)
 }
 
+func TestPruneSkipCachePruneOnPodman(t *testing.T) {
+	f, imgSelectors := newFixture(t).withPruneOutput(cachesPruned, containersPruned, numImages)
+	f.dCli.FakeServerVersion = mobyclient.ServerVersionResult{
+		Components: []system.ComponentVersion{{Name: "Podman Engine"}},
+	}
+	err := f.dp.prune(f.ctx, maxAge, keep0, imgSelectors)
+	require.NoError(t, err)
+
+	logs := f.logs.String()
+	assert.Contains(t, logs, "skipping build cache prune on podman")
+	assert.Empty(t, f.dCli.BuildCachePruneOpts)
+
+	// Should have called previous prune funcs as normal
+	assert.NotEmpty(t, f.dCli.ContainersPruneFilters)
+	assert.NotEmpty(t, f.dCli.ImageListOpts)
+	assert.NotEmpty(t, f.dCli.RemovedImageIDs)
+}
+
 func TestPruneReturnsCachePruneError(t *testing.T) {
 	f, imgSelectors := newFixture(t).withPruneOutput(cachesPruned, containersPruned, numImages)
 	f.dCli.BuildCachePruneErr = fmt.Errorf("this is a real error, NOT an API version error")
@@ -0,0 +1,34 @@
+package k8srollout
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// HealthyDeployAction records that the pods of the most recent deploy
+// of a manifest stayed healthy for the auto-rollback window.
+type HealthyDeployAction struct {
+	ManifestName model.ManifestName
+
+	// The deployed YAML, with image refs injected.
+	YAML string
+}
+
+func (HealthyDeployAction) Action() {}
+
+func NewHealthyDeployAction(mn model.ManifestName, yaml string) HealthyDeployAction {
+	return HealthyDeployAction{ManifestName: mn, YAML: yaml}
+}
+
+// RollbackAction records that we re-applied the last healthy deploy
+// of a manifest because the pods of its most recent deploy failed.
+type RollbackAction struct {
+	ManifestName model.ManifestName
+	Rollback     store.Rollback
+}
+
+func (RollbackAction) Action() {}
+
+func NewRollbackAction(mn model.ManifestName, rollback store.Rollback) RollbackAction {
+	return RollbackAction{ManifestName: mn, Rollback: rollback}
+}
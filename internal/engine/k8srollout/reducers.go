@@ -0,0 +1,22 @@
+package k8srollout
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+)
+
+func HandleHealthyDeployAction(state *store.EngineState, action HealthyDeployAction) {
+	ms, ok := state.ManifestState(action.ManifestName)
+	if !ok {
+		return
+	}
+	ms.LastHealthyDeployYAML = action.YAML
+}
+
+func HandleRollbackAction(state *store.EngineState, action RollbackAction) {
+	ms, ok := state.ManifestState(action.ManifestName)
+	if !ok {
+		return
+	}
+	rollback := action.Rollback
+	ms.LastRollback = &rollback
+}
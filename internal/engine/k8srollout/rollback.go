@@ -0,0 +1,291 @@
+package k8srollout
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/k8sconv"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// How many lines of the failed pod's logs we print when we roll back.
+const rollbackLogTailLines = 20
+
+// AutoRollback watches the pods of each deploy of a resource with
+// auto-rollback enabled.
+//
+// If the pods crash, or aren't ready by the end of the rollback window,
+// we re-apply the YAML of the last deploy whose pods stayed healthy.
+type AutoRollback struct {
+	clock clockwork.Clock
+	kar   *kubernetesapply.Reconciler
+
+	mu sync.Mutex
+
+	// The start time of the last build we decided on, by manifest.
+	decided map[model.ManifestName]time.Time
+
+	// The start time of the build we're waiting on a re-check for, by manifest.
+	scheduled map[model.ManifestName]time.Time
+}
+
+func NewAutoRollback(clock clockwork.Clock, kar *kubernetesapply.Reconciler) *AutoRollback {
+	return &AutoRollback{
+		clock:     clock,
+		kar:       kar,
+		decided:   make(map[model.ManifestName]time.Time),
+		scheduled: make(map[model.ManifestName]time.Time),
+	}
+}
+
+type rollbackTarget struct {
+	manifestName model.ManifestName
+	nn           types.NamespacedName
+	spec         v1alpha1.KubernetesApplySpec
+	cluster      *v1alpha1.Cluster
+	yaml         string
+	failure      deployFailure
+	failureLogs  string
+	buildStart   time.Time
+}
+
+type deployFailure struct {
+	reason string
+	podID  k8s.PodID
+}
+
+func (m *AutoRollback) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.check(ctx, st)
+	return nil
+}
+
+func (m *AutoRollback) check(ctx context.Context, st store.RStore) {
+	var healthy []HealthyDeployAction
+	var rollbacks []rollbackTarget
+	var noRollbacks []model.ManifestName
+
+	now := m.clock.Now()
+	state := st.RLockState()
+	for _, mt := range state.Targets() {
+		if !mt.Manifest.IsK8s() {
+			continue
+		}
+
+		kTarget := mt.Manifest.K8sTarget()
+		window := kTarget.AutoRollbackWindow
+		mn := mt.Manifest.Name
+		ms := mt.State
+		build := ms.LastBuild()
+		if window == 0 || ms.IsBuilding() || build.Empty() || build.Error != nil ||
+			!build.HasBuildType(model.BuildTypeK8s) || m.decided[mn].Equal(build.StartTime) {
+			continue
+		}
+
+		ka := state.KubernetesApplys[kTarget.ID().Name.String()]
+		if ka == nil || ka.Status.ResultYAML == "" {
+			continue
+		}
+
+		krs := ms.K8sRuntimeState()
+		deadline := build.FinishTime.Add(window)
+		failure := podFailure(krs, build.StartTime)
+		if failure.reason == "" && !now.Before(deadline) && krs.RuntimeStatus() != v1alpha1.RuntimeStatusOK {
+			failure.reason = fmt.Sprintf("pods not ready after %s", window)
+		}
+
+		if failure.reason == "" {
+			if now.Before(deadline) {
+				m.scheduleCheck(ctx, st, mn, build.StartTime, deadline.Sub(now))
+				continue
+			}
+			m.decided[mn] = build.StartTime
+			healthy = append(healthy, NewHealthyDeployAction(mn, ka.Status.ResultYAML))
+			continue
+		}
+
+		m.decided[mn] = build.StartTime
+		if ms.LastHealthyDeployYAML == "" || ms.LastHealthyDeployYAML == ka.Status.ResultYAML {
+			noRollbacks = append(noRollbacks, mn)
+			continue
+		}
+
+		var cluster *v1alpha1.Cluster
+		if c, ok := state.Clusters[ka.Spec.Cluster]; ok {
+			cluster = c.DeepCopy()
+		}
+
+		var failureLogs string
+		if failure.podID != "" {
+			failureLogs = state.LogStore.TailSpan(rollbackLogTailLines, k8sconv.SpanIDForPod(mn, failure.podID))
+		}
+
+		rollbacks = append(rollbacks, rollbackTarget{
+			manifestName: mn,
+			nn:           types.NamespacedName{Name: ka.Name},
+			spec:         *ka.Spec.DeepCopy(),
+			cluster:      cluster,
+			yaml:         ms.LastHealthyDeployYAML,
+			failure:      failure,
+			failureLogs:  failureLogs,
+			buildStart:   build.StartTime,
+		})
+	}
+	st.RUnlockState()
+
+	for _, a := range healthy {
+		st.Dispatch(a)
+	}
+
+	for _, mn := range noRollbacks {
+		ctx := store.WithManifestLogHandler(ctx, st, mn, spanIDForRollback(mn))
+		logger.Get(ctx).Warnf("Deploy of %s failed, but there's no earlier healthy deploy to roll back to", mn)
+	}
+
+	for _, r := range rollbacks {
+		m.rollback(ctx, st, r)
+	}
+}
+
+// Re-checks the manifest after the given delay, so that we can
+// decide on a deploy even if nothing else changes.
+func (m *AutoRollback) scheduleCheck(ctx context.Context, st store.RStore, mn model.ManifestName, buildStart time.Time, delay time.Duration) {
+	if m.scheduled[mn].Equal(buildStart) {
+		return
+	}
+	m.scheduled[mn] = buildStart
+
+	m.clock.AfterFunc(delay, func() {
+		if ctx.Err() != nil {
+			return
+		}
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.check(ctx, st)
+	})
+}
+
+func (m *AutoRollback) rollback(ctx context.Context, st store.RStore, r rollbackTarget) {
+	spanID := spanIDForRollback(r.manifestName)
+	ctx = store.WithManifestLogHandler(ctx, st, r.manifestName, spanID)
+	l := logger.Get(ctx)
+	l.Warnf("Rolling back %s to its last healthy deploy: %s", r.manifestName, r.failure.reason)
+	if r.failureLogs != "" {
+		l.Infof("Last logs of pod %s:\n%s", r.failure.podID, strings.TrimRight(r.failureLogs, "\n"))
+	}
+
+	spec, err := rollbackSpec(r.spec, r.yaml)
+	if err != nil {
+		l.Errorf("Rolling back %s: %v", r.manifestName, err)
+		return
+	}
+
+	status := m.kar.ForceApply(ctx, r.nn, spec, r.cluster, nil)
+	if status.Error != "" {
+		l.Errorf("Rolling back %s: %s", r.manifestName, status.Error)
+		return
+	}
+
+	rollback := store.Rollback{
+		BuildStartTime: r.buildStart,
+		Time:           m.clock.Now(),
+		Reason:         r.failure.reason,
+		PodName:        r.failure.podID,
+	}
+	if r.failure.podID != "" {
+		rollback.SpanID = k8sconv.SpanIDForPod(r.manifestName, r.failure.podID)
+	}
+	st.Dispatch(NewRollbackAction(r.manifestName, rollback))
+}
+
+// Finds a pod created by the deploy that crashed or failed.
+func podFailure(krs store.K8sRuntimeState, deployStart time.Time) deployFailure {
+	// Pod creation times only have second precision.
+	deployStart = deployStart.Truncate(time.Second)
+	for _, pod := range krs.GetPods() {
+		if pod.CreatedAt.Time.Before(deployStart) {
+			continue
+		}
+
+		podID := k8s.PodID(pod.Name)
+		if v1.PodPhase(pod.Phase) == v1.PodFailed {
+			return deployFailure{reason: fmt.Sprintf("pod %s failed", podID), podID: podID}
+		}
+
+		restarts := krs.VisiblePodContainerRestarts(podID)
+		if restarts > 0 {
+			return deployFailure{
+				reason: fmt.Sprintf("pod %s crashed (restarts: %d)", podID, restarts),
+				podID:  podID,
+			}
+		}
+
+		for _, c := range store.AllPodContainers(pod) {
+			if k8sconv.ContainerStatusToRuntimeState(c) != v1alpha1.RuntimeStatusError {
+				continue
+			}
+			reason := "error"
+			if c.State.Waiting != nil {
+				reason = c.State.Waiting.Reason
+			} else if c.State.Terminated != nil {
+				reason = c.State.Terminated.Reason
+			}
+			return deployFailure{
+				reason: fmt.Sprintf("pod %s: container %s in %s", podID, c.Name, reason),
+				podID:  podID,
+			}
+		}
+	}
+	return deployFailure{}
+}
+
+// Copies the spec to re-apply the given YAML.
+//
+// The YAML comes from an earlier apply, so the image refs are already
+// injected, and we need to strip the metadata that the server set.
+func rollbackSpec(spec v1alpha1.KubernetesApplySpec, yaml string) (v1alpha1.KubernetesApplySpec, error) {
+	entities, err := k8s.ParseYAMLFromString(yaml)
+	if err != nil {
+		return v1alpha1.KubernetesApplySpec{}, err
+	}
+	for _, e := range entities {
+		e.CleanServerMetadata()
+	}
+
+	spec.YAML, err = k8s.SerializeSpecYAML(entities)
+	if err != nil {
+		return v1alpha1.KubernetesApplySpec{}, err
+	}
+
+	spec.ImageMaps = nil
+	spec.ImageLocators = nil
+
+	// The user asked for the rollback, so there's nothing to confirm.
+	spec.DiffPreview = nil
+	return spec, nil
+}
+
+func spanIDForRollback(mn model.ManifestName) logstore.SpanID {
+	return logstore.SpanID(fmt.Sprintf("rollback:%s", mn))
+}
+
+var _ store.Subscriber = &AutoRollback{}
@@ -0,0 +1,220 @@
+package k8srollout
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jonboulle/clockwork"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/controllers/core/kubernetesapply"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/k8s"
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/localexec"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/k8sconv"
+	"github.com/tilt-dev/tilt/internal/testutils/bufsync"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const rollbackWindow = time.Minute
+
+var healthyYAML = strings.Replace(testyaml.SanchoYAML,
+	"image: gcr.io/some-project-162817/sancho",
+	"image: gcr.io/some-project-162817/sancho:healthy", 1)
+
+func TestRollbackHealthyDeploy(t *testing.T) {
+	f := newRBFixture(t)
+
+	f.setUpDeploy("", runningPod("pod-a", f.buildStart, 0))
+	_ = f.rb.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Empty(t, f.store.Actions())
+
+	// Wait for the re-check at the end of the rollback window.
+	f.clock.BlockUntil(1)
+	f.clock.Advance(rollbackWindow)
+
+	a := f.store.WaitForAction(t, reflect.TypeOf(HealthyDeployAction{})).(HealthyDeployAction)
+	assert.Equal(t, model.ManifestName("sancho"), a.ManifestName)
+	assert.Equal(t, testyaml.SanchoYAML, a.YAML)
+	assert.Equal(t, "", f.kClient.Yaml)
+}
+
+func TestRollbackCrashedPod(t *testing.T) {
+	f := newRBFixture(t)
+
+	f.setUpDeploy(healthyYAML, runningPod("pod-a", f.buildStart, 2))
+	f.store.WithState(func(state *store.EngineState) {
+		state.LogStore.Append(store.NewLogAction("sancho", k8sconv.SpanIDForPod("sancho", "pod-a"),
+			logger.InfoLvl, nil, []byte("panic: missing config\n")), nil)
+	})
+	_ = f.rb.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	a := f.store.WaitForAction(t, reflect.TypeOf(RollbackAction{})).(RollbackAction)
+	assert.Equal(t, model.ManifestName("sancho"), a.ManifestName)
+	assert.Equal(t, f.buildStart, a.Rollback.BuildStartTime)
+	assert.Equal(t, "pod pod-a crashed (restarts: 2)", a.Rollback.Reason)
+	assert.Equal(t, k8s.PodID("pod-a"), a.Rollback.PodName)
+	assert.Equal(t, k8sconv.SpanIDForPod("sancho", "pod-a"), a.Rollback.SpanID)
+
+	assert.Contains(t, f.kClient.Yaml, "sancho:healthy")
+	assert.NotContains(t, f.kClient.Yaml, "resourceVersion")
+	assert.Contains(t, f.out.String(), "Rolling back sancho to its last healthy deploy: pod pod-a crashed")
+	assert.Contains(t, f.out.String(), "panic: missing config")
+
+	// Don't roll back the same deploy twice.
+	f.kClient.Yaml = ""
+	_ = f.rb.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Equal(t, "", f.kClient.Yaml)
+}
+
+func TestRollbackNotReady(t *testing.T) {
+	f := newRBFixture(t)
+
+	pod := runningPod("pod-a", f.buildStart, 0)
+	pod.Containers[0].Ready = false
+	f.setUpDeploy(healthyYAML, pod)
+	_ = f.rb.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+	assert.Empty(t, f.store.Actions())
+
+	f.clock.BlockUntil(1)
+	f.clock.Advance(rollbackWindow)
+
+	a := f.store.WaitForAction(t, reflect.TypeOf(RollbackAction{})).(RollbackAction)
+	assert.Equal(t, "pods not ready after 1m0s", a.Rollback.Reason)
+	assert.Contains(t, f.kClient.Yaml, "sancho:healthy")
+}
+
+func TestRollbackNoHealthyDeploy(t *testing.T) {
+	f := newRBFixture(t)
+
+	f.setUpDeploy("", runningPod("pod-a", f.buildStart, 2))
+	_ = f.rb.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	assert.Contains(t, f.out.String(), "no earlier healthy deploy to roll back to")
+	assert.Equal(t, "", f.kClient.Yaml)
+}
+
+func TestRollbackIgnoresOldPods(t *testing.T) {
+	f := newRBFixture(t)
+
+	// A pod from before the deploy restarted, but the deploy's pod is fine.
+	f.setUpDeploy(healthyYAML,
+		runningPod("pod-old", f.buildStart.Add(-time.Hour), 3),
+		runningPod("pod-new", f.buildStart, 0))
+	_ = f.rb.OnChange(f.ctx, f.store, store.LegacyChangeSummary())
+
+	assert.Empty(t, f.store.Actions())
+	assert.Equal(t, "", f.kClient.Yaml)
+}
+
+func TestRollbackSpec(t *testing.T) {
+	spec := v1alpha1.KubernetesApplySpec{
+		YAML:        testyaml.SanchoYAML,
+		ImageMaps:   []string{"sancho-image"},
+		DiffPreview: &v1alpha1.KubernetesApplyDiffPreview{},
+		Cluster:     "default",
+	}
+
+	entities, err := k8s.ParseYAMLFromString(healthyYAML)
+	require.NoError(t, err)
+	entities[0].Meta().SetResourceVersion("123")
+	entities[0].Meta().SetUID("abc")
+	deployed, err := k8s.SerializeSpecYAML(entities)
+	require.NoError(t, err)
+
+	result, err := rollbackSpec(spec, deployed)
+	require.NoError(t, err)
+	assert.Contains(t, result.YAML, "sancho:healthy")
+	assert.NotContains(t, result.YAML, "resourceVersion")
+	assert.NotContains(t, result.YAML, "uid")
+	assert.Empty(t, result.ImageMaps)
+	assert.Nil(t, result.DiffPreview)
+	assert.Equal(t, "default", result.Cluster)
+
+	// Make sure we didn't modify the original.
+	assert.Equal(t, []string{"sancho-image"}, spec.ImageMaps)
+}
+
+type rbFixture struct {
+	ctx        context.Context
+	out        *bufsync.ThreadSafeBuffer
+	store      *testStore
+	clock      *clockwork.FakeClock
+	kClient    *k8s.FakeK8sClient
+	rb         *AutoRollback
+	buildStart time.Time
+}
+
+func newRBFixture(t *testing.T) *rbFixture {
+	out := bufsync.NewThreadSafeBuffer()
+	st := NewTestingStore(out)
+	clock := clockwork.NewFakeClock()
+	kClient := k8s.NewFakeK8sClient(t)
+	cfb := fake.NewControllerFixtureBuilder(t)
+	kar := kubernetesapply.NewReconciler(cfb.Client, kClient, v1alpha1.NewScheme(), cfb.Store, localexec.NewFakeExecer(t))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ctx = logger.WithLogger(ctx, logger.NewTestLogger(out))
+	t.Cleanup(cancel)
+
+	return &rbFixture{
+		ctx:        ctx,
+		out:        out,
+		store:      st,
+		clock:      clock,
+		kClient:    kClient,
+		rb:         NewAutoRollback(clock, kar),
+		buildStart: clock.Now().Add(-5 * time.Second),
+	}
+}
+
+// Sets up state as if we just deployed SanchoYAML with the given pods.
+func (f *rbFixture) setUpDeploy(lastHealthyYAML string, pods ...v1alpha1.Pod) {
+	kTarget := model.NewK8sTargetForTesting(testyaml.SanchoYAML)
+	kTarget.Name = "sancho"
+	kTarget.AutoRollbackWindow = rollbackWindow
+	m := model.Manifest{Name: "sancho"}.WithDeployTarget(kTarget)
+
+	state := store.NewState()
+	mt := store.NewManifestTarget(m)
+	mt.State.RuntimeState = store.NewK8sRuntimeStateWithPods(m, pods...)
+	mt.State.LastHealthyDeployYAML = lastHealthyYAML
+	mt.State.AddCompletedBuild(model.BuildRecord{
+		StartTime:  f.buildStart,
+		FinishTime: f.clock.Now(),
+		BuildTypes: []model.BuildType{model.BuildTypeImage, model.BuildTypeK8s},
+	})
+	state.UpsertManifestTarget(mt)
+	state.KubernetesApplys["sancho"] = &v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "sancho"},
+		Spec:       kTarget.KubernetesApplySpec,
+		Status:     v1alpha1.KubernetesApplyStatus{ResultYAML: testyaml.SanchoYAML},
+	}
+	f.store.SetState(*state)
+}
+
+func runningPod(name string, createdAt time.Time, restarts int32) v1alpha1.Pod {
+	return v1alpha1.Pod{
+		Name:      name,
+		CreatedAt: apis.NewTime(createdAt),
+		Phase:     "Running",
+		Containers: []v1alpha1.Container{
+			{
+				Name:     "main",
+				Ready:    true,
+				Restarts: restarts,
+				State:    v1alpha1.ContainerState{Running: &v1alpha1.ContainerStateRunning{}},
+			},
+		},
+	}
+}
@@ -75,6 +75,8 @@ func updateLocalRuntimeStatus(state *store.EngineState, cmd *v1alpha1.Cmd) {
 			lrs.LastReadyOrSucceededTime = time.Now()
 		}
 	}
+	lrs.RestartCount = int(status.RestartCount)
+	lrs.LastRestartReason = status.LastRestartReason
 	lrs.SpanID = model.LogSpanID(cmd.ObjectMeta.Annotations[v1alpha1.AnnotationSpanID])
 
 	ms.RuntimeState = lrs
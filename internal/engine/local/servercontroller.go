@@ -137,7 +137,11 @@ func (c *ServerController) determineServers(ctx context.Context, st store.RStore
 				Env:            lt.ServeCmd.Env,
 				TriggerTime:    mt.State.LastSuccessfulDeployTime,
 				ReadinessProbe: lt.ReadinessProbe,
+				LivenessProbe:  lt.LivenessProbe,
+				StartupProbe:   lt.StartupProbe,
 				DisableSource:  lt.ServeCmdDisableSource,
+				Stop:           lt.ServeCmdStop,
+				LogParser:      lt.ServeCmdLogParser,
 			},
 		}
 
@@ -265,6 +269,10 @@ func (c *ServerController) reconcile(ctx context.Context, server CmdServer, owne
 		Dir:            server.Spec.Dir,
 		Env:            server.Spec.Env,
 		ReadinessProbe: server.Spec.ReadinessProbe,
+		LivenessProbe:  server.Spec.LivenessProbe,
+		StartupProbe:   server.Spec.StartupProbe,
+		Stop:           server.Spec.Stop,
+		LogParser:      server.Spec.LogParser,
 	}
 
 	triggerTime := c.createdTriggerTime[name]
@@ -336,12 +344,18 @@ type CmdServerSpec struct {
 	Dir            string
 	Env            []string
 	ReadinessProbe *v1alpha1.Probe
+	LivenessProbe  *v1alpha1.Probe
+	StartupProbe   *v1alpha1.Probe
 
 	// Kubernetes tends to represent this as a "generation" field
 	// to force an update.
 	TriggerTime time.Time
 
 	DisableSource *v1alpha1.DisableSource
+
+	Stop *v1alpha1.CmdStopSpec
+
+	LogParser v1alpha1.LogParser
 }
 
 type CmdServerStatus struct {
@@ -0,0 +1,17 @@
+package logarchive
+
+import (
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+)
+
+// AttachAction attaches the archive to the LogStore, so that
+// the LogStore spills truncated logs into it.
+type AttachAction struct {
+	Spillover logstore.Spillover
+}
+
+func (AttachAction) Action() {}
+
+func NewAttachAction(spillover logstore.Spillover) AttachAction {
+	return AttachAction{Spillover: spillover}
+}
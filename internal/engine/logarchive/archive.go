@@ -0,0 +1,556 @@
+package logarchive
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/spf13/afero"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// Whether to keep logs truncated from memory on disk.
+type EnabledFlag bool
+
+const (
+	// Logs are written to a series of files, so that we can skip whole
+	// files when querying, and delete the oldest logs when we run out of room.
+	defaultMaxFileBytes = 8 * 1000 * 1000
+
+	// Stop the archive from filling up the disk in very long sessions.
+	defaultMaxBytes = 1000 * 1000 * 1000
+)
+
+// Archive keeps the logs that the LogStore truncates from memory,
+// so that the full history of each span can still be queried.
+//
+// Logs are stored under the xdg state dir, one directory per
+// Tilt instance. The directory is cleared when Tilt starts.
+//
+// Only a small index of each file is kept in memory. Queries
+// stream the files from disk.
+//
+// The LogStore spills while the engine state is locked, so spilled
+// segments are queued and written to disk in the background.
+type Archive struct {
+	base    xdg.Base
+	fs      afero.Fs
+	name    model.APIServerName
+	enabled EnabledFlag
+
+	maxFileBytes int64
+	maxBytes     int64
+
+	mu  sync.Mutex
+	dir string
+
+	// Incremented on each spill, so that queries can tell which segments
+	// were spilled (rather than in memory) when they read the LogStore.
+	generation int
+
+	// Spilled segments that haven't been written to disk yet.
+	pending []spillBatch
+	wake    chan struct{}
+
+	// Written to the log on the next OnChange, so we don't log
+	// while the LogStore is being modified.
+	err error
+
+	// Held while writing to disk. Never held with the engine state locked.
+	filesMu sync.Mutex
+	files   []*segmentFile
+
+	// The last generation written to disk.
+	written int
+}
+
+// Segments from a single spill, with their manifests, since
+// the spans may change before the segments are written.
+type spillBatch struct {
+	generation int
+	segments   []logstore.LogSegment
+	manifests  []model.ManifestName
+}
+
+var _ store.SetUpper = &Archive{}
+var _ store.Subscriber = &Archive{}
+var _ logstore.Spillover = &Archive{}
+
+func NewArchive(base xdg.Base, fs afero.Fs, name model.APIServerName, enabled EnabledFlag) *Archive {
+	return &Archive{
+		base:         base,
+		fs:           fs,
+		name:         name,
+		enabled:      enabled,
+		maxFileBytes: defaultMaxFileBytes,
+		maxBytes:     defaultMaxBytes,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+// Clears out logs from previous runs, then attaches the archive to the LogStore.
+func (a *Archive) SetUp(ctx context.Context, st store.RStore) error {
+	if !a.enabled {
+		return nil
+	}
+
+	p, err := a.base.StateFile(filepath.Join("logs", string(a.name), "index"))
+	if err != nil {
+		logger.Get(ctx).Warnf("Archiving logs: %v", err)
+		return nil
+	}
+
+	dir := filepath.Dir(p)
+	err = a.fs.RemoveAll(dir)
+	if err != nil {
+		logger.Get(ctx).Warnf("Archiving logs: clearing %s: %v", dir, err)
+		return nil
+	}
+
+	a.mu.Lock()
+	a.dir = dir
+	a.mu.Unlock()
+
+	go a.writeLoop(ctx)
+
+	st.Dispatch(NewAttachAction(a))
+	return nil
+}
+
+func (a *Archive) writeLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-a.wake:
+			a.flush()
+		}
+	}
+}
+
+func (a *Archive) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	a.mu.Lock()
+	err := a.err
+	a.err = nil
+	a.mu.Unlock()
+
+	if err != nil {
+		logger.Get(ctx).Warnf("Archiving logs: %v", err)
+	}
+	return nil
+}
+
+// Queues segments truncated from the LogStore to be written to disk.
+func (a *Archive) Spill(spans map[logstore.SpanID]*logstore.Span, segments []logstore.LogSegment) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.dir == "" || len(segments) == 0 {
+		return
+	}
+
+	manifests := make([]model.ManifestName, len(segments))
+	for i, segment := range segments {
+		if span, ok := spans[segment.SpanID]; ok {
+			manifests[i] = span.ManifestName
+		}
+	}
+
+	a.generation++
+	a.pending = append(a.pending, spillBatch{
+		generation: a.generation,
+		segments:   append([]logstore.LogSegment{}, segments...),
+		manifests:  manifests,
+	})
+
+	select {
+	case a.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Writes all the pending segments to disk.
+func (a *Archive) flush() {
+	a.filesMu.Lock()
+	defer a.filesMu.Unlock()
+
+	for {
+		a.mu.Lock()
+		if len(a.pending) == 0 {
+			a.mu.Unlock()
+			return
+		}
+		batch := a.pending[0]
+		a.mu.Unlock()
+
+		err := a.write(batch)
+		a.written = batch.generation
+
+		a.mu.Lock()
+		a.pending = a.pending[1:]
+		if err != nil && a.err == nil {
+			a.err = err
+		}
+		a.mu.Unlock()
+	}
+}
+
+func (a *Archive) write(batch spillBatch) error {
+	segments, manifests := batch.segments, batch.manifests
+	for len(segments) > 0 {
+		f := a.currentFile()
+		if f == nil {
+			var err error
+			f, err = a.newFile()
+			if err != nil {
+				return err
+			}
+		}
+
+		n, err := f.append(a.fs, batch.generation, segments, manifests, a.maxFileBytes)
+		if err != nil {
+			return err
+		}
+		segments = segments[n:]
+		manifests = manifests[n:]
+		if len(segments) > 0 {
+			// The current file is full.
+			f.full = true
+		}
+	}
+
+	a.removeOldFiles()
+	return nil
+}
+
+func (a *Archive) currentFile() *segmentFile {
+	if len(a.files) == 0 {
+		return nil
+	}
+	f := a.files[len(a.files)-1]
+	if f.full || f.size >= a.maxFileBytes {
+		return nil
+	}
+	return f
+}
+
+func (a *Archive) newFile() (*segmentFile, error) {
+	index := 0
+	if len(a.files) > 0 {
+		index = a.files[len(a.files)-1].index + 1
+	}
+
+	p, err := a.base.StateFile(filepath.Join("logs", string(a.name), fmt.Sprintf("%06d.jsonl", index)))
+	if err != nil {
+		return nil, err
+	}
+
+	f := &segmentFile{
+		index:     index,
+		path:      p,
+		manifests: model.ManifestNameSet{},
+	}
+	a.files = append(a.files, f)
+	return f, nil
+}
+
+func (a *Archive) removeOldFiles() {
+	total := int64(0)
+	for _, f := range a.files {
+		total += f.size
+	}
+
+	for total > a.maxBytes && len(a.files) > 1 {
+		f := a.files[0]
+		_ = a.fs.Remove(f.path)
+		total -= f.size
+		a.files = a.files[1:]
+	}
+}
+
+// Returns the logs that match the query, from both the LogStore in memory
+// and the archive on disk, ordered by time.
+func (a *Archive) Query(st store.RStore, q logstore.Query) (*webview.LogList, error) {
+	state := st.RLockState()
+	result := state.LogStore.Query(q)
+
+	// Spill is only called while the LogStore is being modified, so all the
+	// segments from this generation or earlier are spilled, not in memory.
+	a.mu.Lock()
+	generation := a.generation
+	pending := a.pending
+	a.mu.Unlock()
+	st.RUnlockState()
+
+	a.filesMu.Lock()
+	written := a.written
+	files := make([]segmentFile, 0, len(a.files))
+	for _, f := range a.files {
+		files = append(files, f.snapshot())
+	}
+	a.filesMu.Unlock()
+
+	// Anything written since we read the LogStore is still in pending.
+	onDisk := generation
+	if written < onDisk {
+		onDisk = written
+	}
+
+	// Lines may be split across spills, so match them across files.
+	m := logstore.NewLineMatcher(q)
+	var archived []*webview.LogSegment
+	for _, f := range files {
+		if !f.mayMatch(q, onDisk) {
+			continue
+		}
+
+		var err error
+		archived, err = f.query(a.fs, m, onDisk, result.Spans, archived)
+		if err != nil {
+			return nil, err
+		}
+		if q.Limit > 0 && len(archived) > 2*q.Limit {
+			archived = append([]*webview.LogSegment{}, archived[len(archived)-q.Limit:]...)
+		}
+	}
+
+	for _, batch := range pending {
+		if batch.generation <= onDisk {
+			continue
+		}
+		for i, segment := range batch.segments {
+			archived = appendMatches(m, batch.manifests[i], segment, result.Spans, archived)
+		}
+	}
+
+	if len(archived) == 0 {
+		return result, nil
+	}
+
+	segments := append(archived, result.Segments...)
+	sort.SliceStable(segments, func(i, j int) bool {
+		return segments[i].Time.Before(&segments[j].Time)
+	})
+	if q.Limit > 0 && len(segments) > q.Limit {
+		segments = segments[len(segments)-q.Limit:]
+	}
+
+	spans := make(map[string]*webview.LogSpan, len(result.Spans))
+	for _, segment := range segments {
+		spans[segment.SpanId] = result.Spans[segment.SpanId]
+	}
+
+	result.Segments = segments
+	result.Spans = spans
+	return result, nil
+}
+
+// A file of archived segments, stored as JSON lines.
+type segmentFile struct {
+	index int
+	path  string
+	size  int64
+	full  bool
+
+	// An index of the file's contents, so that queries can skip it.
+	firstTime       time.Time
+	lastTime        time.Time
+	firstGeneration int
+	manifests       model.ManifestNameSet
+	maxLevel        logger.Level
+
+	// Build events match queries at any level.
+	hasBuildEvents bool
+}
+
+// A segment on disk.
+type record struct {
+	Generation int               `json:"g"`
+	SpanID     string            `json:"s"`
+	Manifest   string            `json:"m,omitempty"`
+	Time       time.Time         `json:"t"`
+	Level      string            `json:"l,omitempty"`
+	Text       string            `json:"x"`
+	Fields     map[string]string `json:"f,omitempty"`
+	Anchor     bool              `json:"a,omitempty"`
+	Continues  bool              `json:"c,omitempty"`
+}
+
+// Appends as many segments as fit in the file, and returns how many were written.
+//
+// Always writes at least one segment, so that a huge segment can't get stuck.
+func (f *segmentFile) append(fs afero.Fs, generation int, segments []logstore.LogSegment, manifests []model.ManifestName, maxBytes int64) (int, error) {
+	file, err := fs.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	w := bufio.NewWriter(file)
+	n := 0
+	for i, segment := range segments {
+		if n > 0 && f.size >= maxBytes {
+			break
+		}
+
+		mn := manifests[i]
+
+		line, err := json.Marshal(record{
+			Generation: generation,
+			SpanID:     string(segment.SpanID),
+			Manifest:   mn.String(),
+			Time:       segment.Time,
+			Level:      segment.Level.Name(),
+			Text:       string(segment.Text),
+			Fields:     segment.Fields,
+			Anchor:     segment.Anchor,
+			Continues:  segment.ContinuesLine,
+		})
+		if err != nil {
+			return n, err
+		}
+		line = append(line, '\n')
+
+		_, err = w.Write(line)
+		if err != nil {
+			return n, err
+		}
+
+		f.observe(generation, mn, segment)
+		f.size += int64(len(line))
+		n++
+	}
+	return n, w.Flush()
+}
+
+// Copies the index, so that it can be read while more segments are appended.
+func (f *segmentFile) snapshot() segmentFile {
+	clone := *f
+	clone.manifests = make(model.ManifestNameSet, len(f.manifests))
+	for mn := range f.manifests {
+		clone.manifests[mn] = true
+	}
+	return clone
+}
+
+func (f *segmentFile) observe(generation int, mn model.ManifestName, segment logstore.LogSegment) {
+	if f.firstGeneration == 0 {
+		f.firstGeneration = generation
+	}
+	if f.firstTime.IsZero() || segment.Time.Before(f.firstTime) {
+		f.firstTime = segment.Time
+	}
+	if segment.Time.After(f.lastTime) {
+		f.lastTime = segment.Time
+	}
+	f.manifests[mn] = true
+	if segment.Level.AsSevereAs(f.maxLevel) {
+		f.maxLevel = segment.Level
+	}
+	if segment.Fields[logger.FieldNameBuildEvent] != "" {
+		f.hasBuildEvents = true
+	}
+}
+
+// Checks the index to see if any segment in the file could match.
+func (f *segmentFile) mayMatch(q logstore.Query, generation int) bool {
+	if f.firstGeneration == 0 || f.firstGeneration > generation {
+		return false
+	}
+	if !q.Since.IsZero() && f.lastTime.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !f.firstTime.Before(q.Until) {
+		return false
+	}
+	if !f.hasBuildEvents && !f.maxLevel.AsSevereAs(q.Level) {
+		return false
+	}
+	if len(q.ManifestNames) != 0 {
+		for mn := range q.ManifestNames {
+			if f.manifests[mn] {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Reads the segments that match from disk, and appends them to result.
+func (f *segmentFile) query(fs afero.Fs, m *logstore.LineMatcher, generation int, spans map[string]*webview.LogSpan, result []*webview.LogSegment) ([]*webview.LogSegment, error) {
+	file, err := fs.Open(f.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Removed to make room for newer logs.
+			return result, nil
+		}
+		return result, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	reader := bufio.NewReader(file)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			// Either the end of the file, or a line from a spill
+			// that's still being written.
+			return result, nil
+		} else if err != nil {
+			return result, err
+		}
+
+		var r record
+		err = json.Unmarshal(line, &r)
+		if err != nil {
+			return result, fmt.Errorf("reading %s: %v", f.path, err)
+		}
+		if r.Generation > generation {
+			continue
+		}
+
+		level, ok := logger.LevelByName(r.Level)
+		if !ok {
+			level = logger.InfoLvl
+		}
+		segment := logstore.LogSegment{
+			SpanID: logstore.SpanID(r.SpanID),
+			Time:   r.Time,
+			Text:   []byte(r.Text),
+			Level:  level,
+			Fields: r.Fields,
+			Anchor: r.Anchor,
+
+			ContinuesLine: r.Continues,
+		}
+		result = appendMatches(m, model.ManifestName(r.Manifest), segment, spans, result)
+	}
+}
+
+// Adds the segment to the matcher, and appends the segments that match to result.
+func appendMatches(m *logstore.LineMatcher, mn model.ManifestName, segment logstore.LogSegment, spans map[string]*webview.LogSpan, result []*webview.LogSegment) []*webview.LogSegment {
+	for _, match := range m.Add(mn, segment) {
+		result = append(result, logstore.SegmentToWebview(match))
+		if _, ok := spans[string(match.SpanID)]; !ok {
+			spans[string(match.SpanID)] = &webview.LogSpan{ManifestName: mn.String()}
+		}
+	}
+	return result
+}
@@ -0,0 +1,295 @@
+package logarchive
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/testutils"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+func TestSetUpAttachesArchive(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	actions := f.st.Actions()
+	require.Len(t, actions, 1)
+	assert.Equal(t, NewAttachAction(f.a), actions[0])
+}
+
+func TestSetUpDisabled(t *testing.T) {
+	f := newFixture(t, false)
+	f.setUp()
+
+	assert.Empty(t, f.st.Actions())
+
+	f.spill("fe", "hello\n")
+	assert.Empty(t, f.query(logstore.Query{}))
+}
+
+func TestSetUpClearsOldLogs(t *testing.T) {
+	f := newFixture(t, true)
+	p, err := f.base.StateFile("logs/tilt-default/000000.jsonl")
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(f.fs, p, []byte("old logs\n"), 0600))
+
+	f.setUp()
+
+	exists, err := afero.Exists(f.fs, p)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestQueryMergesArchiveAndMemory(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	f.spill("fe", "fe old\n")
+	f.spill("be", "be old\n")
+	f.appendInMemory("fe", "fe new\n")
+
+	assert.Equal(t, []string{"fe old\n", "be old\n", "fe new\n"}, f.query(logstore.Query{}))
+	assert.Equal(t, []string{"fe old\n", "fe new\n"}, f.query(logstore.Query{
+		ManifestNames: model.ManifestNameSet{"fe": true},
+	}))
+	assert.Equal(t, []string{"be old\n"}, f.query(logstore.Query{
+		Regexp: regexp.MustCompile("^be"),
+	}))
+	assert.Equal(t, []string{"be old\n", "fe new\n"}, f.query(logstore.Query{
+		Limit: 2,
+	}))
+}
+
+func TestQueryTimeRangeAndLevel(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	f.spill("fe", "one\n")
+	mid := f.clock.Add(time.Second)
+	f.spillLevel("fe", logger.WarnLvl, "two\n")
+	f.spill("fe", "three\n")
+
+	assert.Equal(t, []string{"one\n"}, f.query(logstore.Query{Until: mid}))
+	assert.Equal(t, []string{"two\n", "three\n"}, f.query(logstore.Query{Since: mid}))
+	assert.Equal(t, []string{"two\n"}, f.query(logstore.Query{Level: logger.WarnLvl}))
+}
+
+func TestQueryLevelKeepsBuildEvents(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	spanID := logstore.SpanID("build:1")
+	spans := map[logstore.SpanID]*logstore.Span{spanID: {ManifestName: "fe"}}
+	f.a.Spill(spans, []logstore.LogSegment{{
+		SpanID: spanID,
+		Time:   f.tick(),
+		Text:   []byte("Initial Build\n"),
+		Level:  logger.InfoLvl,
+		Fields: logger.Fields{logger.FieldNameBuildEvent: "init"},
+	}})
+	f.flush()
+	f.spill("fe", "Step 1\n")
+
+	assert.Equal(t, []string{"Initial Build\n"}, f.query(logstore.Query{Level: logger.WarnLvl}))
+}
+
+func TestQueryMatchesLineSplitAcrossSpills(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	spanID := logstore.SpanID("fe")
+	spans := map[logstore.SpanID]*logstore.Span{spanID: {ManifestName: "fe"}}
+	f.a.Spill(spans, []logstore.LogSegment{{SpanID: spanID, Time: f.tick(), Text: []byte("connection ")}})
+	f.flush()
+	f.spill("be", "be 1\n")
+	f.a.Spill(spans, []logstore.LogSegment{{SpanID: spanID, Time: f.tick(), Text: []byte("refused\n"), ContinuesLine: true}})
+	f.flush()
+
+	assert.Equal(t, []string{"connection ", "refused\n"}, f.query(logstore.Query{
+		Regexp: regexp.MustCompile("refused"),
+	}))
+}
+
+func TestRotatesAndRemovesOldFiles(t *testing.T) {
+	f := newFixture(t, true)
+	f.a.maxFileBytes = 200
+	f.a.maxBytes = 500
+	f.setUp()
+
+	for i := 0; i < 20; i++ {
+		f.spill("fe", fmt.Sprintf("line %d\n", i))
+	}
+
+	f.a.filesMu.Lock()
+	fileCount := len(f.a.files)
+	f.a.filesMu.Unlock()
+	assert.Less(t, fileCount, 5)
+
+	lines := f.query(logstore.Query{})
+	require.NotEmpty(t, lines)
+	assert.NotEqual(t, "line 0\n", lines[0])
+	assert.Equal(t, "line 19\n", lines[len(lines)-1])
+
+	// Files that don't match the query are skipped.
+	assert.Empty(t, f.query(logstore.Query{ManifestNames: model.ManifestNameSet{"be": true}}))
+}
+
+func TestQueryIgnoresSegmentsSpilledAfterRead(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	f.spill("fe", "one\n")
+	f.a.mu.Lock()
+	generation := f.a.generation
+	f.a.mu.Unlock()
+	f.spill("fe", "two\n")
+
+	var texts []string
+	for _, file := range f.a.files {
+		result, err := file.query(f.fs, logstore.NewLineMatcher(logstore.Query{}), generation, map[string]*webview.LogSpan{}, nil)
+		require.NoError(t, err)
+		for _, segment := range result {
+			texts = append(texts, segment.Text)
+		}
+	}
+	assert.Equal(t, []string{"one\n"}, texts)
+}
+
+func TestQueryIncludesSegmentsNotYetWritten(t *testing.T) {
+	f := newFixture(t, true)
+
+	// Attach the archive without starting the writer, so that
+	// spilled segments stay pending until we flush.
+	f.a.dir = "/tmp/xdg/logs/tilt-default"
+
+	f.spill("fe", "one\n")
+	f.spillPending("fe", "two\n")
+	f.appendInMemory("fe", "three\n")
+	assert.Equal(t, []string{"one\n", "two\n", "three\n"}, f.query(logstore.Query{}))
+
+	f.flush()
+	assert.Equal(t, []string{"one\n", "two\n", "three\n"}, f.query(logstore.Query{}))
+}
+
+func TestSpillDoesNotWaitForDisk(t *testing.T) {
+	f := newFixture(t, true)
+	f.setUp()
+
+	// Hold the files lock, as if a write were stuck on a slow disk.
+	f.a.filesMu.Lock()
+	done := make(chan struct{})
+	go func() {
+		f.spillPending("fe", "hello\n")
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Spill blocked on the disk write")
+	}
+	f.a.filesMu.Unlock()
+
+	assert.Equal(t, []string{"hello\n"}, f.query(logstore.Query{}))
+}
+
+type fixture struct {
+	t     *testing.T
+	ctx   context.Context
+	fs    afero.Fs
+	base  xdg.Base
+	st    *store.TestingStore
+	a     *Archive
+	clock time.Time
+}
+
+func newFixture(t *testing.T, enabled bool) *fixture {
+	fs := afero.NewMemMapFs()
+	base := xdg.NewFakeBase("/tmp/xdg", fs)
+	ctx, cancel := context.WithCancel(testutils.LoggerCtx())
+	t.Cleanup(cancel)
+	return &fixture{
+		t:     t,
+		ctx:   ctx,
+		fs:    fs,
+		base:  base,
+		st:    store.NewTestingStore(),
+		a:     NewArchive(base, fs, "tilt-default", EnabledFlag(enabled)),
+		clock: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fixture) setUp() {
+	require.NoError(f.t, f.a.SetUp(f.ctx, f.st))
+}
+
+func (f *fixture) tick() time.Time {
+	f.clock = f.clock.Add(time.Minute)
+	return f.clock
+}
+
+func (f *fixture) spill(mn model.ManifestName, text string) {
+	f.spillLevel(mn, logger.InfoLvl, text)
+}
+
+func (f *fixture) spillLevel(mn model.ManifestName, level logger.Level, text string) {
+	f.spillPendingLevel(mn, level, text)
+	f.flush()
+}
+
+// Spills without waiting for the segments to be written to disk.
+func (f *fixture) spillPending(mn model.ManifestName, text string) {
+	f.spillPendingLevel(mn, logger.InfoLvl, text)
+}
+
+func (f *fixture) spillPendingLevel(mn model.ManifestName, level logger.Level, text string) {
+	spanID := logstore.SpanID(mn)
+	spans := map[logstore.SpanID]*logstore.Span{spanID: {ManifestName: mn}}
+	f.a.Spill(spans, []logstore.LogSegment{{
+		SpanID: spanID,
+		Time:   f.tick(),
+		Text:   []byte(text),
+		Level:  level,
+	}})
+}
+
+func (f *fixture) flush() {
+	f.a.flush()
+
+	f.a.mu.Lock()
+	err := f.a.err
+	f.a.mu.Unlock()
+	require.NoError(f.t, err)
+}
+
+// Logs in memory are timestamped with the real time, so they're newer
+// than anything in the archive.
+func (f *fixture) appendInMemory(mn model.ManifestName, text string) {
+	f.st.WithState(func(state *store.EngineState) {
+		state.LogStore.Append(store.NewLogAction(mn, logstore.SpanID(mn), logger.InfoLvl, nil, []byte(text)), nil)
+	})
+}
+
+func (f *fixture) query(q logstore.Query) []string {
+	result, err := f.a.Query(f.st, q)
+	require.NoError(f.t, err)
+
+	texts := []string{}
+	for _, segment := range result.Segments {
+		texts = append(texts, segment.Text)
+		assert.Contains(f.t, result.Spans, segment.SpanId)
+	}
+	return texts
+}
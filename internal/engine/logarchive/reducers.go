@@ -0,0 +1,9 @@
+package logarchive
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+)
+
+func HandleAttachAction(state *store.EngineState, action AttachAction) {
+	state.LogStore.SetSpillover(action.Spillover)
+}
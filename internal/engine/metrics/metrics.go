@@ -0,0 +1,205 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const (
+	outcomeSuccess = "success"
+	outcomeError   = "error"
+)
+
+// Metrics exports dev-loop metrics in the Prometheus format.
+//
+// Counters and histograms are updated as builds finish and files change.
+// Gauges (like pod restarts) are read from the engine state on each scrape.
+type Metrics struct {
+	st       store.RStore
+	registry *prometheus.Registry
+
+	buildDuration         *prometheus.HistogramVec
+	builds                *prometheus.CounterVec
+	liveUpdateDuration    *prometheus.HistogramVec
+	portForwardReconnects *prometheus.CounterVec
+	fileWatchEvents       *prometheus.CounterVec
+
+	mu sync.Mutex
+
+	// The finish time of the most recent build we've counted, by manifest.
+	lastBuildFinish map[model.ManifestName]time.Time
+
+	// The time of the most recent file event we've counted, by FileWatch name.
+	lastFileEvent map[string]time.Time
+}
+
+var _ store.Subscriber = &Metrics{}
+
+func NewMetrics(st store.RStore) *Metrics {
+	m := &Metrics{
+		st:       st,
+		registry: prometheus.NewRegistry(),
+		buildDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tilt_build_duration_seconds",
+			Help:    "How long builds took, by resource.",
+			Buckets: prometheus.ExponentialBuckets(0.25, 2, 12),
+		}, []string{"manifest"}),
+		builds: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tilt_builds_total",
+			Help: "Number of finished builds, by resource and outcome (success or error).",
+		}, []string{"manifest", "outcome"}),
+		liveUpdateDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "tilt_live_update_duration_seconds",
+			Help:    "How long live updates took, by resource.",
+			Buckets: prometheus.ExponentialBuckets(0.05, 2, 12),
+		}, []string{"manifest"}),
+		portForwardReconnects: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tilt_port_forward_reconnects_total",
+			Help: "Number of times a port-forward was reconnected after an error, by resource.",
+		}, []string{"manifest"}),
+		fileWatchEvents: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tilt_file_watch_events_total",
+			Help: "Number of batches of file changes seen by file watches, by resource.",
+		}, []string{"manifest"}),
+		lastBuildFinish: make(map[model.ManifestName]time.Time),
+		lastFileEvent:   make(map[string]time.Time),
+	}
+
+	m.registry.MustRegister(
+		m.buildDuration,
+		m.builds,
+		m.liveUpdateDuration,
+		m.portForwardReconnects,
+		m.fileWatchEvents,
+		stateCollector{st: st})
+	return m
+}
+
+// Serves the metrics for a Prometheus scraper.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) PortForwardReconnected(mn string) {
+	m.portForwardReconnects.WithLabelValues(mn).Inc()
+}
+
+func (m *Metrics) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if summary.IsLogOnly() {
+		return nil
+	}
+
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, mt := range state.ManifestTargets {
+		m.observeBuilds(mt.Manifest.Name, mt.State.BuildHistory)
+	}
+
+	for name, fw := range state.FileWatches {
+		m.observeFileEvents(name, fw)
+	}
+	return nil
+}
+
+func (m *Metrics) observeBuilds(mn model.ManifestName, history []model.BuildRecord) {
+	last := m.lastBuildFinish[mn]
+
+	// BuildHistory is ordered newest first.
+	for _, record := range history {
+		if !record.FinishTime.After(last) {
+			break
+		}
+
+		outcome := outcomeSuccess
+		if record.Error != nil {
+			outcome = outcomeError
+		}
+
+		seconds := record.Duration().Seconds()
+		m.builds.WithLabelValues(mn.String(), outcome).Inc()
+		m.buildDuration.WithLabelValues(mn.String()).Observe(seconds)
+		for _, bt := range record.BuildTypes {
+			if bt == model.BuildTypeLiveUpdate {
+				m.liveUpdateDuration.WithLabelValues(mn.String()).Observe(seconds)
+			}
+		}
+	}
+
+	if len(history) > 0 && history[0].FinishTime.After(last) {
+		m.lastBuildFinish[mn] = history[0].FinishTime
+	}
+}
+
+func (m *Metrics) observeFileEvents(name string, fw *v1alpha1.FileWatch) {
+	last := m.lastFileEvent[name]
+	mn := fw.Annotations[v1alpha1.AnnotationManifest]
+
+	// FileEvents are ordered oldest first.
+	for _, e := range fw.Status.FileEvents {
+		if e.Time.Time.After(last) {
+			m.fileWatchEvents.WithLabelValues(mn).Inc()
+		}
+	}
+
+	if fw.Status.LastEventTime.Time.After(last) {
+		m.lastFileEvent[name] = fw.Status.LastEventTime.Time
+	}
+}
+
+var (
+	podRestartsDesc = prometheus.NewDesc(
+		"tilt_pod_restarts",
+		"Number of container restarts across the current pods of a resource.",
+		[]string{"manifest"}, nil)
+	logStoreBytesDesc = prometheus.NewDesc(
+		"tilt_log_store_bytes",
+		"Size of the logs Tilt is holding in memory, by resource.",
+		[]string{"manifest"}, nil)
+)
+
+// Reads gauges from the engine state at scrape time.
+type stateCollector struct {
+	st store.RStore
+}
+
+func (c stateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- podRestartsDesc
+	ch <- logStoreBytesDesc
+}
+
+func (c stateCollector) Collect(ch chan<- prometheus.Metric) {
+	state := c.st.RLockState()
+	defer c.st.RUnlockState()
+
+	for _, mt := range state.ManifestTargets {
+		if !mt.Manifest.IsK8s() {
+			continue
+		}
+		restarts := int32(0)
+		for _, pod := range mt.State.K8sRuntimeState().GetPods() {
+			restarts += store.AllPodContainerRestarts(pod)
+		}
+		ch <- prometheus.MustNewConstMetric(podRestartsDesc, prometheus.GaugeValue,
+			float64(restarts), mt.Manifest.Name.String())
+	}
+
+	if state.LogStore != nil {
+		for mn, bytes := range state.LogStore.BytesByManifest() {
+			ch <- prometheus.MustNewConstMetric(logStoreBytesDesc, prometheus.GaugeValue,
+				float64(bytes), mn.String())
+		}
+	}
+}
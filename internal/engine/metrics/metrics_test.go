@@ -0,0 +1,167 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestBuildMetrics(t *testing.T) {
+	f := newFixture(t)
+	f.addBuild("fe", 2*time.Second, nil, model.BuildTypeImage, model.BuildTypeK8s)
+	f.onChange()
+
+	out := f.scrape()
+	assert.Contains(t, out, `tilt_builds_total{manifest="fe",outcome="success"} 1`)
+	assert.Contains(t, out, `tilt_build_duration_seconds_sum{manifest="fe"} 2`)
+	assert.NotContains(t, out, `tilt_live_update_duration_seconds_count{manifest="fe"}`)
+
+	// Builds are only counted once.
+	f.onChange()
+	out = f.scrape()
+	assert.Contains(t, out, `tilt_builds_total{manifest="fe",outcome="success"} 1`)
+
+	f.addBuild("fe", time.Second, fmt.Errorf("oh no"), model.BuildTypeLiveUpdate)
+	f.addBuild("fe", 500*time.Millisecond, nil, model.BuildTypeLiveUpdate)
+	f.onChange()
+
+	out = f.scrape()
+	assert.Contains(t, out, `tilt_builds_total{manifest="fe",outcome="success"} 2`)
+	assert.Contains(t, out, `tilt_builds_total{manifest="fe",outcome="error"} 1`)
+	assert.Contains(t, out, `tilt_live_update_duration_seconds_count{manifest="fe"} 2`)
+	assert.Contains(t, out, `tilt_live_update_duration_seconds_sum{manifest="fe"} 1.5`)
+}
+
+func TestFileWatchMetrics(t *testing.T) {
+	f := newFixture(t)
+	f.addFileEvent("fe", "main.go")
+	f.addFileEvent("fe", "util.go")
+	f.onChange()
+	assert.Contains(t, f.scrape(), `tilt_file_watch_events_total{manifest="fe"} 2`)
+
+	f.onChange()
+	assert.Contains(t, f.scrape(), `tilt_file_watch_events_total{manifest="fe"} 2`)
+
+	f.addFileEvent("fe", "main.go")
+	f.onChange()
+	assert.Contains(t, f.scrape(), `tilt_file_watch_events_total{manifest="fe"} 3`)
+}
+
+func TestPortForwardReconnects(t *testing.T) {
+	f := newFixture(t)
+	f.m.PortForwardReconnected("fe")
+	f.m.PortForwardReconnected("fe")
+	assert.Contains(t, f.scrape(), `tilt_port_forward_reconnects_total{manifest="fe"} 2`)
+}
+
+func TestPodRestarts(t *testing.T) {
+	f := newFixture(t)
+	m := model.Manifest{Name: "fe"}.WithDeployTarget(model.NewK8sTargetForTesting(testyaml.SanchoYAML))
+	f.st.WithState(func(state *store.EngineState) {
+		mt := store.NewManifestTarget(m)
+		mt.State.RuntimeState = store.NewK8sRuntimeStateWithPods(m,
+			v1alpha1.Pod{Name: "pod-a", Containers: []v1alpha1.Container{{Restarts: 2}, {Restarts: 1}}},
+			v1alpha1.Pod{Name: "pod-b", Containers: []v1alpha1.Container{{Restarts: 1}}})
+		state.UpsertManifestTarget(mt)
+	})
+
+	assert.Contains(t, f.scrape(), `tilt_pod_restarts{manifest="fe"} 4`)
+}
+
+func TestLogStoreBytes(t *testing.T) {
+	f := newFixture(t)
+	f.st.WithState(func(state *store.EngineState) {
+		state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("hello\n")), nil)
+	})
+
+	assert.Contains(t, f.scrape(), `tilt_log_store_bytes{manifest="fe"} 6`)
+}
+
+type fixture struct {
+	t     *testing.T
+	st    *store.TestingStore
+	m     *Metrics
+	clock time.Time
+}
+
+func newFixture(t *testing.T) *fixture {
+	st := store.NewTestingStore()
+	return &fixture{
+		t:     t,
+		st:    st,
+		m:     NewMetrics(st),
+		clock: time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (f *fixture) tick() time.Time {
+	f.clock = f.clock.Add(time.Minute)
+	return f.clock
+}
+
+func (f *fixture) addBuild(mn model.ManifestName, d time.Duration, err error, types ...model.BuildType) {
+	finish := f.tick()
+	f.st.WithState(func(state *store.EngineState) {
+		mt, ok := state.ManifestTargets[mn]
+		if !ok {
+			mt = store.NewManifestTarget(model.Manifest{Name: mn})
+			state.UpsertManifestTarget(mt)
+		}
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  finish.Add(-d),
+			FinishTime: finish,
+			Error:      err,
+			BuildTypes: types,
+		})
+	})
+}
+
+func (f *fixture) addFileEvent(mn model.ManifestName, path string) {
+	ts := f.tick()
+	f.st.WithState(func(state *store.EngineState) {
+		name := "image:" + mn.String()
+		fw, ok := state.FileWatches[name]
+		if !ok {
+			fw = &v1alpha1.FileWatch{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        name,
+					Annotations: map[string]string{v1alpha1.AnnotationManifest: mn.String()},
+				},
+			}
+			state.FileWatches[name] = fw
+		}
+		fw.Status.LastEventTime = metav1.NewMicroTime(ts)
+		fw.Status.FileEvents = append(fw.Status.FileEvents, v1alpha1.FileEvent{
+			Time:      metav1.NewMicroTime(ts),
+			SeenFiles: []string{path},
+		})
+	})
+}
+
+func (f *fixture) onChange() {
+	require.NoError(f.t, f.m.OnChange(context.Background(), f.st, store.LegacyChangeSummary()))
+}
+
+func (f *fixture) scrape() string {
+	w := httptest.NewRecorder()
+	f.m.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(f.t, http.StatusOK, w.Code)
+
+	body, err := io.ReadAll(w.Body)
+	require.NoError(f.t, err)
+	return string(body)
+}
@@ -0,0 +1,19 @@
+package persist
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// RestoreAction restores the build history and image builds
+// saved by an earlier run of Tilt.
+type RestoreAction struct {
+	BuildHistory map[model.ManifestName][]model.BuildRecord
+	ImageResults map[model.TargetID]store.ImageBuildResult
+}
+
+func (RestoreAction) Action() {}
+
+func NewRestoreAction(history map[model.ManifestName][]model.BuildRecord, images map[model.TargetID]store.ImageBuildResult) RestoreAction {
+	return RestoreAction{BuildHistory: history, ImageResults: images}
+}
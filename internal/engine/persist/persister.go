@@ -0,0 +1,285 @@
+package persist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/controllers/apicmp"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Whether to save engine state to disk and restore it on restart.
+type EnabledFlag bool
+
+// Persister saves build history, image builds, and UI state (like which
+// resources are disabled) under the xdg state dir, and restores them
+// the next time Tilt starts with the same Tiltfile.
+//
+// Restored image builds are only re-used if their inputs haven't changed
+// and Tilt is still using the same cluster, so that restarting Tilt doesn't
+// rebuild images that are still up-to-date.
+type Persister struct {
+	base    xdg.Base
+	fs      afero.Fs
+	client  ctrlclient.Client
+	enabled EnabledFlag
+
+	loaded         bool
+	tiltfileLoaded bool
+	path           string
+	lastSaved      []byte
+
+	// Saved ConfigMap data that we haven't written back to the
+	// API server yet, because the ConfigMaps don't exist yet.
+	pendingConfigMaps map[string]map[string]string
+}
+
+func NewPersister(base xdg.Base, fs afero.Fs, client ctrlclient.Client, enabled EnabledFlag) *Persister {
+	return &Persister{
+		base:    base,
+		fs:      fs,
+		client:  client,
+		enabled: enabled,
+	}
+}
+
+func (p *Persister) OnChange(ctx context.Context, st store.RStore, summary store.ChangeSummary) error {
+	if !bool(p.enabled) || summary.IsLogOnly() {
+		return nil
+	}
+
+	if !p.loaded {
+		state := st.RLockState()
+		tiltfilePath := state.DesiredTiltfilePath
+		st.RUnlockState()
+		if tiltfilePath == "" {
+			return nil
+		}
+
+		p.loaded = true
+		err := p.load(ctx, st, tiltfilePath)
+		if err != nil {
+			logger.Get(ctx).Warnf("Restoring saved state: %v", err)
+		}
+
+		// Wait for the restore to land before we save anything.
+		return nil
+	}
+
+	if p.path == "" {
+		return nil
+	}
+
+	err := p.restoreConfigMaps(ctx, st)
+	if err != nil {
+		return err
+	}
+
+	stateConfigMaps, err := p.stateConfigMapNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	saved, ok := p.snapshot(st, stateConfigMaps)
+	if !ok {
+		return nil
+	}
+	return p.save(saved)
+}
+
+func (p *Persister) load(ctx context.Context, st store.RStore, tiltfilePath string) error {
+	path, err := p.base.StateFile(stateFileName(tiltfilePath))
+	if err != nil {
+		return err
+	}
+	p.path = path
+
+	contents, err := afero.ReadFile(p.fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var saved savedState
+	err = json.Unmarshal(contents, &saved)
+	if err != nil {
+		return fmt.Errorf("reading %s: %v", path, err)
+	}
+	if saved.Version != stateVersion {
+		logger.Get(ctx).Debugf("Ignoring saved state with version %d", saved.Version)
+		return nil
+	}
+
+	history := make(map[model.ManifestName][]model.BuildRecord, len(saved.Manifests))
+	for mn, m := range saved.Manifests {
+		for _, b := range m.BuildHistory {
+			history[mn] = append(history[mn], b.toBuildRecord())
+		}
+	}
+
+	images := make(map[model.TargetID]store.ImageBuildResult, len(saved.Images))
+	for name, image := range saved.Images {
+		result, err := image.toImageBuildResult(name)
+		if err != nil {
+			logger.Get(ctx).Debugf("Ignoring saved image %s: %v", name, err)
+			continue
+		}
+		images[result.TargetID()] = result
+	}
+
+	p.pendingConfigMaps = saved.ConfigMaps
+	st.Dispatch(NewRestoreAction(history, images))
+	return nil
+}
+
+// Writes the saved ConfigMap data back to the API server once
+// the Tiltfile has created the ConfigMaps.
+func (p *Persister) restoreConfigMaps(ctx context.Context, st store.RStore) error {
+	if len(p.pendingConfigMaps) == 0 {
+		return nil
+	}
+
+	state := st.RLockState()
+	var ready []string
+	for name := range p.pendingConfigMaps {
+		if _, ok := state.ConfigMaps[name]; ok {
+			ready = append(ready, name)
+		}
+	}
+	st.RUnlockState()
+
+	for _, name := range ready {
+		var cm v1alpha1.ConfigMap
+		err := p.client.Get(ctx, types.NamespacedName{Name: name}, &cm)
+		if err != nil {
+			return ctrlclient.IgnoreNotFound(err)
+		}
+
+		data := p.pendingConfigMaps[name]
+		if !apicmp.DeepEqual(cm.Data, data) {
+			cm.Data = data
+			err = p.client.Update(ctx, &cm)
+			if err != nil {
+				return err
+			}
+		}
+		delete(p.pendingConfigMaps, name)
+	}
+	return nil
+}
+
+// The names of the ConfigMaps that back ToggleButtons.
+func (p *Persister) stateConfigMapNames(ctx context.Context) (map[string]bool, error) {
+	var buttons v1alpha1.ToggleButtonList
+	err := p.client.List(ctx, &buttons)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]bool, len(buttons.Items))
+	for _, b := range buttons.Items {
+		if b.Spec.StateSource.ConfigMap != nil {
+			result[b.Spec.StateSource.ConfigMap.Name] = true
+		}
+	}
+	return result, nil
+}
+
+func (p *Persister) snapshot(st store.RStore, stateConfigMaps map[string]bool) (savedState, bool) {
+	state := st.RLockState()
+	defer st.RUnlockState()
+
+	// Don't save anything until the Tiltfile has loaded successfully,
+	// so that a Tiltfile error on startup doesn't wipe out the saved state.
+	if !p.tiltfileLoaded {
+		ms, ok := state.TiltfileStates[model.MainTiltfileManifestName]
+		if !ok || ms.LastBuild().Empty() || ms.LastBuild().Error != nil {
+			return savedState{}, false
+		}
+		p.tiltfileLoaded = true
+	}
+
+	saved := savedState{
+		Version:    stateVersion,
+		Manifests:  make(map[model.ManifestName]savedManifest),
+		Images:     make(map[model.TargetName]savedImage),
+		ConfigMaps: make(map[string]map[string]string),
+	}
+	for _, mt := range state.Targets() {
+		ms := mt.State
+		var history []savedBuild
+		for _, b := range ms.DisplayBuildHistory() {
+			history = append(history, toSavedBuild(b))
+		}
+		saved.Manifests[mt.Manifest.Name] = savedManifest{BuildHistory: history}
+
+		for _, iTarget := range mt.Manifest.ImageTargets {
+			id := iTarget.ID()
+			var result store.ImageBuildResult
+			if status, ok := ms.BuildStatus(id); ok {
+				result, _ = status.LastResult.(store.ImageBuildResult)
+			}
+
+			// If we haven't built the image since we restarted,
+			// hang on to the old build.
+			if result.InputHash == "" {
+				result = state.RestoredImageResults[id]
+			}
+			if result.InputHash != "" {
+				saved.Images[id.Name] = toSavedImage(result)
+			}
+		}
+	}
+
+	for name := range stateConfigMaps {
+		if data, ok := p.pendingConfigMaps[name]; ok {
+			saved.ConfigMaps[name] = data
+		} else if cm, ok := state.ConfigMaps[name]; ok {
+			data := make(map[string]string, len(cm.Data))
+			for k, v := range cm.Data {
+				data[k] = v
+			}
+			saved.ConfigMaps[name] = data
+		}
+	}
+	return saved, true
+}
+
+func (p *Persister) save(saved savedState) error {
+	contents, err := json.MarshalIndent(saved, "", "  ")
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(contents, p.lastSaved) {
+		return nil
+	}
+
+	// Write to a temp file and rename, so that we never leave a
+	// partially-written state file behind.
+	tmpPath := p.path + ".tmp"
+	err = afero.WriteFile(p.fs, tmpPath, contents, 0600)
+	if err != nil {
+		return fmt.Errorf("saving state: %v", err)
+	}
+	err = p.fs.Rename(tmpPath, p.path)
+	if err != nil {
+		return fmt.Errorf("saving state: %v", err)
+	}
+	p.lastSaved = contents
+	return nil
+}
+
+var _ store.Subscriber = &Persister{}
@@ -0,0 +1,196 @@
+package persist
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/xdg"
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const tiltfilePath = "/code/Tiltfile"
+
+func TestSaveAndRestore(t *testing.T) {
+	f := newFixture(t)
+
+	start := time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC)
+	iTarget := model.MustNewImageTarget(container.MustParseSelector("gcr.io/fe"))
+	image := store.NewImageBuildResultSingleRef(iTarget.ID(), container.MustParseNamedTagged("gcr.io/fe:tilt-123"))
+	image.InputHash = "abc"
+	image.ClusterKey = "def"
+
+	f.loadTiltfile()
+	f.st.WithState(func(state *store.EngineState) {
+		mt := store.NewManifestTarget(model.Manifest{Name: "fe"}.WithImageTarget(iTarget))
+		mt.State.AddCompletedBuild(model.BuildRecord{
+			StartTime:  start,
+			FinishTime: start.Add(time.Second),
+			Error:      errors.New("oops"),
+			BuildTypes: []model.BuildType{model.BuildTypeImage},
+		})
+		mt.State.BuildStatuses[iTarget.ID()].LastResult = image
+		state.UpsertManifestTarget(mt)
+	})
+	f.createToggleButton("fe-disable", "true")
+
+	f.onChange()
+	f.onChange()
+
+	f = f.restart()
+	f.onChange()
+
+	require.Len(t, f.st.Actions(), 1)
+	action := f.st.Actions()[0].(RestoreAction)
+	history := action.BuildHistory["fe"]
+	require.Len(t, history, 1)
+	assert.Equal(t, start, history[0].StartTime)
+	assert.Equal(t, "oops", history[0].Error.Error())
+	assert.Equal(t, []model.BuildType{model.BuildTypeImage}, history[0].BuildTypes)
+	assert.Equal(t, image, action.ImageResults[iTarget.ID()])
+
+	// The Tiltfile creates the ConfigMap with its default value,
+	// then we restore the saved value.
+	f.createToggleButton("fe-disable", "false")
+	f.onChange()
+
+	var cm v1alpha1.ConfigMap
+	require.NoError(t, f.client.Get(f.ctx, types.NamespacedName{Name: "fe-disable"}, &cm))
+	assert.Equal(t, "true", cm.Data["isDisabled"])
+}
+
+func TestDontSaveBeforeTiltfileLoads(t *testing.T) {
+	f := newFixture(t)
+	f.onChange()
+	f.onChange()
+
+	exists, err := afero.Exists(f.fs, f.statePath())
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDisabled(t *testing.T) {
+	f := newFixture(t)
+	f.p.enabled = false
+	f.loadTiltfile()
+	f.onChange()
+	f.onChange()
+
+	exists, err := afero.Exists(f.fs, f.statePath())
+	require.NoError(t, err)
+	assert.False(t, exists)
+	assert.Empty(t, f.st.Actions())
+}
+
+func TestRestoredHistoryIsKeptUntilRebuilt(t *testing.T) {
+	f := newFixture(t)
+	f.loadTiltfile()
+
+	start := time.Date(2024, 1, 1, 1, 1, 1, 0, time.UTC)
+	restored := model.BuildRecord{StartTime: start, FinishTime: start.Add(time.Second)}
+	f.st.WithState(func(state *store.EngineState) {
+		HandleRestoreAction(state, NewRestoreAction(
+			map[model.ManifestName][]model.BuildRecord{"fe": {restored}}, nil))
+		mt := store.NewManifestTarget(model.Manifest{Name: "fe"})
+		mt.State.RestoredBuildHistory = state.RestoredBuildHistory["fe"]
+		state.UpsertManifestTarget(mt)
+	})
+
+	// Skip the load.
+	f.p.loaded = true
+	f.p.path = f.statePath()
+	f.onChange()
+
+	contents, err := afero.ReadFile(f.fs, f.statePath())
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `"startTime": "2024-01-01T01:01:01Z"`)
+}
+
+type fixture struct {
+	t      *testing.T
+	ctx    context.Context
+	fs     afero.Fs
+	base   xdg.Base
+	client ctrlclient.Client
+	st     *store.TestingStore
+	p      *Persister
+}
+
+func newFixture(t *testing.T) *fixture {
+	fs := afero.NewMemMapFs()
+	base := xdg.NewFakeBase("/tmp/xdg", fs)
+	return newFixtureWithFS(t, fs, base)
+}
+
+func newFixtureWithFS(t *testing.T, fs afero.Fs, base xdg.Base) *fixture {
+	st := store.NewTestingStore()
+	st.WithState(func(state *store.EngineState) {
+		state.DesiredTiltfilePath = tiltfilePath
+	})
+	client := fake.NewFakeTiltClient()
+	return &fixture{
+		t:      t,
+		ctx:    context.Background(),
+		fs:     fs,
+		base:   base,
+		client: client,
+		st:     st,
+		p:      NewPersister(base, fs, client, true),
+	}
+}
+
+// Simulates restarting Tilt, with a fresh engine and API server.
+func (f *fixture) restart() *fixture {
+	return newFixtureWithFS(f.t, f.fs, f.base)
+}
+
+func (f *fixture) onChange() {
+	f.t.Helper()
+	require.NoError(f.t, f.p.OnChange(f.ctx, f.st, store.ChangeSummary{}))
+}
+
+func (f *fixture) statePath() string {
+	p, err := f.base.StateFile(stateFileName(tiltfilePath))
+	require.NoError(f.t, err)
+	return p
+}
+
+func (f *fixture) loadTiltfile() {
+	f.st.WithState(func(state *store.EngineState) {
+		state.TiltfileStates[model.MainTiltfileManifestName].AddCompletedBuild(model.BuildRecord{
+			StartTime:  time.Now(),
+			FinishTime: time.Now(),
+		})
+	})
+}
+
+func (f *fixture) createToggleButton(cmName string, value string) {
+	cm := &v1alpha1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName},
+		Data:       map[string]string{"isDisabled": value},
+	}
+	fake.UpsertSpec(f.ctx, f.t, f.client, cm)
+	fake.UpsertSpec(f.ctx, f.t, f.client, &v1alpha1.ToggleButton{
+		ObjectMeta: metav1.ObjectMeta{Name: cmName},
+		Spec: v1alpha1.ToggleButtonSpec{
+			StateSource: v1alpha1.StateSource{
+				ConfigMap: &v1alpha1.ConfigMapStateSource{Name: cmName, Key: "isDisabled"},
+			},
+		},
+	})
+	f.st.WithState(func(state *store.EngineState) {
+		state.ConfigMaps[cmName] = cm
+	})
+}
@@ -0,0 +1,16 @@
+package persist
+
+import (
+	"github.com/tilt-dev/tilt/internal/store"
+)
+
+func HandleRestoreAction(state *store.EngineState, action RestoreAction) {
+	state.RestoredBuildHistory = action.BuildHistory
+	state.RestoredImageResults = action.ImageResults
+
+	// Manifests created before the restore won't pick up
+	// their history on creation.
+	for _, mt := range state.Targets() {
+		mt.State.RestoredBuildHistory = action.BuildHistory[mt.Manifest.Name]
+	}
+}
@@ -0,0 +1,126 @@
+package persist
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/pkg/apis"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// Bump this when the format changes incompatibly. We ignore
+// saved state from other versions.
+const stateVersion = 1
+
+// The on-disk format of the saved state.
+type savedState struct {
+	Version   int                                  `json:"version"`
+	Manifests map[model.ManifestName]savedManifest `json:"manifests,omitempty"`
+
+	// Image builds, by image target name.
+	Images map[model.TargetName]savedImage `json:"images,omitempty"`
+
+	// The data of the ConfigMaps that back UI state, like
+	// the disable toggles, by ConfigMap name.
+	ConfigMaps map[string]map[string]string `json:"configMaps,omitempty"`
+}
+
+type savedManifest struct {
+	BuildHistory []savedBuild `json:"buildHistory,omitempty"`
+}
+
+type savedBuild struct {
+	StartTime    time.Time         `json:"startTime"`
+	FinishTime   time.Time         `json:"finishTime"`
+	Reason       model.BuildReason `json:"reason,omitempty"`
+	BuildTypes   []model.BuildType `json:"buildTypes,omitempty"`
+	Edits        []string          `json:"edits,omitempty"`
+	Error        string            `json:"error,omitempty"`
+	SpanID       model.LogSpanID   `json:"spanID,omitempty"`
+	WarningCount int               `json:"warningCount,omitempty"`
+}
+
+type savedImage struct {
+	InputHash      string    `json:"inputHash"`
+	ClusterKey     string    `json:"clusterKey,omitempty"`
+	LocalRef       string    `json:"localRef"`
+	ClusterRef     string    `json:"clusterRef"`
+	BuildStartTime time.Time `json:"buildStartTime,omitempty"`
+}
+
+// The state file for a Tiltfile, relative to the xdg state dir.
+func stateFileName(tiltfilePath string) string {
+	sum := sha256.Sum256([]byte(tiltfilePath))
+	return filepath.Join("engine-state", fmt.Sprintf("%x.json", sum[:8]))
+}
+
+func toSavedBuild(b model.BuildRecord) savedBuild {
+	result := savedBuild{
+		StartTime:    b.StartTime,
+		FinishTime:   b.FinishTime,
+		Reason:       b.Reason,
+		BuildTypes:   b.BuildTypes,
+		Edits:        b.Edits,
+		SpanID:       b.SpanID,
+		WarningCount: b.WarningCount,
+	}
+	if b.Error != nil {
+		result.Error = b.Error.Error()
+	}
+	return result
+}
+
+func (b savedBuild) toBuildRecord() model.BuildRecord {
+	result := model.BuildRecord{
+		StartTime:    b.StartTime,
+		FinishTime:   b.FinishTime,
+		Reason:       b.Reason,
+		BuildTypes:   b.BuildTypes,
+		Edits:        b.Edits,
+		SpanID:       b.SpanID,
+		WarningCount: b.WarningCount,
+	}
+	if b.Error != "" {
+		result.Error = errors.New(b.Error)
+	}
+	return result
+}
+
+func toSavedImage(r store.ImageBuildResult) savedImage {
+	result := savedImage{
+		InputHash:  r.InputHash,
+		ClusterKey: r.ClusterKey,
+		LocalRef:   r.ImageMapStatus.ImageFromLocal,
+		ClusterRef: r.ImageMapStatus.ImageFromCluster,
+	}
+	if r.ImageMapStatus.BuildStartTime != nil {
+		result.BuildStartTime = r.ImageMapStatus.BuildStartTime.Time
+	}
+	return result
+}
+
+func (i savedImage) toImageBuildResult(name model.TargetName) (store.ImageBuildResult, error) {
+	localRef, err := container.ParseNamedTagged(i.LocalRef)
+	if err != nil {
+		return store.ImageBuildResult{}, err
+	}
+	clusterRef, err := container.ParseNamedTagged(i.ClusterRef)
+	if err != nil {
+		return store.ImageBuildResult{}, err
+	}
+
+	id := model.TargetID{Type: model.TargetTypeImage, Name: name}
+	result := store.NewImageBuildResult(id, localRef, clusterRef)
+	result.InputHash = i.InputHash
+	result.ClusterKey = i.ClusterKey
+	if !i.BuildStartTime.IsZero() {
+		startTime := apis.NewMicroTime(i.BuildStartTime)
+		result.ImageMapStatus.BuildStartTime = &startTime
+	}
+	return result, nil
+}
@@ -9,6 +9,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -60,6 +63,10 @@ func ProvideSubscribers(
 	tc *telemetry.Controller,
 	lsc *local.ServerController,
 	podm *k8srollout.PodMonitor,
+	rb *k8srollout.AutoRollback,
+	ps *persist.Persister,
+	m *metrics.Metrics,
+	la *logarchive.Archive,
 	sc *session.Controller,
 	uss *uisession.Subscriber,
 	urs *uiresource.Subscriber,
@@ -81,6 +88,10 @@ func ProvideSubscribers(
 		tc,
 		lsc,
 		podm,
+		rb,
+		ps,
+		m,
+		la,
 		sc,
 		uss,
 		urs,
@@ -17,15 +17,17 @@ import (
 
 type Controller struct {
 	spans      tracer.SpanSource
+	otlp       *tracer.OTLPExporter
 	clock      build.Clock
 	runCounter int
 	lastRunAt  time.Time
 }
 
-func NewController(clock build.Clock, spans tracer.SpanSource) *Controller {
+func NewController(clock build.Clock, spans tracer.SpanSource, otlp *tracer.OTLPExporter) *Controller {
 	return &Controller{
 		clock:      clock,
 		spans:      spans,
+		otlp:       otlp,
 		runCounter: 0,
 	}
 }
@@ -36,6 +38,8 @@ func (t *Controller) OnChange(ctx context.Context, st store.RStore, _ store.Chan
 	tc := ts.Cmd
 	st.RUnlockState()
 
+	t.updateOTLP(ctx, st, ts.OTLP)
+
 	period := ts.Period
 	if period == 0 {
 		period = model.DefaultTelemetryPeriod
@@ -74,6 +78,22 @@ func (t *Controller) OnChange(ctx context.Context, st store.RStore, _ store.Chan
 	return nil
 }
 
+func (t *Controller) updateOTLP(ctx context.Context, st store.RStore, settings model.OTLPSettings) {
+	if t.otlp == nil {
+		return
+	}
+
+	err := t.otlp.SetSettings(ctx, settings)
+	if err != nil {
+		t.logError(st, err)
+	}
+
+	err = t.otlp.TakeError()
+	if err != nil {
+		t.logError(st, err)
+	}
+}
+
 func (t *Controller) logError(st store.RStore, err error) {
 	spanID := logstore.SpanID(fmt.Sprintf("telemetry:%d", t.runCounter))
 	st.Dispatch(store.NewLogAction(model.MainTiltfileManifestName, spanID, logger.InfoLvl, nil, []byte(err.Error())))
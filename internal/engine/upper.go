@@ -12,8 +12,11 @@ import (
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/controllers/core/filewatch"
 	ctrltiltfile "github.com/tilt-dev/tilt/internal/controllers/core/tiltfile"
+	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/hud"
 	"github.com/tilt-dev/tilt/internal/hud/prompt"
 	"github.com/tilt-dev/tilt/internal/hud/server"
@@ -134,6 +137,14 @@ func upperReducerFn(ctx context.Context, state *store.EngineState, action store.
 		handleServiceEvent(ctx, state, action)
 	case store.K8sEventAction:
 		handleK8sEvent(ctx, state, action)
+	case k8srollout.HealthyDeployAction:
+		k8srollout.HandleHealthyDeployAction(state, action)
+	case k8srollout.RollbackAction:
+		k8srollout.HandleRollbackAction(state, action)
+	case persist.RestoreAction:
+		persist.HandleRestoreAction(state, action)
+	case logarchive.AttachAction:
+		logarchive.HandleAttachAction(state, action)
 	case buildcontrols.BuildCompleteAction:
 		buildcontrols.HandleBuildCompleted(ctx, state, action)
 	case buildcontrols.BuildStartedAction:
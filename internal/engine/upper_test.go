@@ -69,6 +69,9 @@ import (
 	"github.com/tilt-dev/tilt/internal/engine/k8srollout"
 	"github.com/tilt-dev/tilt/internal/engine/k8swatch"
 	"github.com/tilt-dev/tilt/internal/engine/local"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/engine/session"
 	"github.com/tilt-dev/tilt/internal/engine/telemetry"
 	"github.com/tilt-dev/tilt/internal/engine/uiresource"
@@ -3298,7 +3301,7 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 	lsc := local.NewServerController(cdc)
 	sr := ctrlsession.NewReconciler(cdc, st, clock)
 	sessionController := session.NewController(sr)
-	logFilter := hudclient.NewLogFilter(hudclient.FilterSourceAll, nil, hudclient.FilterLevel(logger.NoneLvl), hudclient.FilterSince{}, hudclient.FilterTail(-1), hudclient.FilterJSON(false))
+	logFilter := hudclient.NewLogFilter(hudclient.FilterSourceAll, nil, hudclient.FilterLevel(logger.NoneLvl), nil, nil, hudclient.FilterSince{}, hudclient.FilterTail(-1), hudclient.FilterJSON(false))
 	ts := hudclient.NewTerminalStream(hudclient.NewIncrementalPrinter(hudclient.Stdout(log)), logFilter, st)
 	tp := prompt.NewTerminalPrompt(ta, prompt.TTYOpen, openurl.BrowserOpen,
 		hudclient.Stdout(log), "localhost", model.WebURL{})
@@ -3315,7 +3318,8 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 		cdc,
 		uncached)
 	require.NoError(t, err, "Failed to create Tilt API server controller manager")
-	pfr := apiportforward.NewReconciler(cdc, sch, st, clusterClients)
+	m := metrics.NewMetrics(st)
+	pfr := apiportforward.NewReconciler(cdc, sch, st, clusterClients, m)
 
 	wsl := server.NewWebsocketList()
 
@@ -3414,13 +3418,16 @@ func newTestFixture(t *testing.T, options ...fixtureOptions) *testFixture {
 
 	ret.disableEnvAnalyticsOpt()
 
-	tc := telemetry.NewController(clock, tracer.NewSpanCollector(ctx))
+	tc := telemetry.NewController(clock, tracer.NewSpanCollector(ctx), nil)
 	podm := k8srollout.NewPodMonitor(clock)
+	rb := k8srollout.NewAutoRollback(clock, kar)
+	ps := persist.NewPersister(base, fs, cdc, false)
+	la := logarchive.NewArchive(base, fs, "tilt-default", false)
 
 	uss := uisession.NewSubscriber(cdc)
 	urs := uiresource.NewSubscriber(cdc)
 
-	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, ar, au, ewm, tcum, dp, tc, lsc, podm, sessionController, uss, urs)
+	subs := ProvideSubscribers(hudsc, tscm, cb, h, ts, tp, sw, bc, cc, tqs, ar, au, ewm, tcum, dp, tc, lsc, podm, rb, ps, m, la, sessionController, uss, urs)
 	ret.upper, err = NewUpper(ctx, st, subs)
 	require.NoError(t, err)
 
@@ -27,6 +27,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/docker"
 	"github.com/tilt-dev/tilt/internal/dockercompose"
 	"github.com/tilt-dev/tilt/internal/engine/buildcontrol"
+	"github.com/tilt-dev/tilt/internal/engine/persist"
 	"github.com/tilt-dev/tilt/internal/k8s"
 	"github.com/tilt-dev/tilt/internal/localexec"
 	"github.com/tilt-dev/tilt/internal/store"
@@ -47,6 +48,7 @@ var DeployerBaseWireSet = wire.NewSet(
 var DeployerWireSetTest = wire.NewSet(
 	DeployerBaseWireSet,
 	wire.InterfaceValue(new(sdktrace.SpanExporter), (sdktrace.SpanExporter)(nil)),
+	wire.Value(persist.EnabledFlag(false)),
 )
 
 var DeployerWireSet = wire.NewSet(
@@ -3,6 +3,7 @@ package client
 import (
 	"encoding/json"
 	"io"
+	"regexp"
 	"strings"
 	"time"
 
@@ -22,14 +23,23 @@ type JSONLogLine struct {
 	ProgressID *string `json:"progressID,omitempty"`
 	BuildEvent *string `json:"buildEvent,omitempty"`
 	Source     *string `json:"source,omitempty"`
+
+	// Fields of structured logs, if any.
+	Fields map[string]string `json:"fields,omitempty"`
 }
 
+// ansiRegexp matches ANSI escape sequences, like colors and cursor movement.
+var ansiRegexp = regexp.MustCompile(`\x1b\[[0-9;?]*[ -/]*[@-~]|\x1b\][^\x07\x1b]*(?:\x07|\x1b\\)`)
+
 // stringPtr returns a pointer to the given string.
 func stringPtr(s string) *string {
 	return &s
 }
 
 // JSONPrinter outputs log lines as JSON Lines (one JSON object per line).
+//
+// ANSI escape sequences are removed from messages, so that they're easy to
+// consume from scripts.
 type JSONPrinter struct {
 	stdout Stdout
 }
@@ -57,7 +67,7 @@ func (p *JSONPrinter) Print(lines []logstore.LogLine) {
 
 func (p *JSONPrinter) toJSONLine(line logstore.LogLine) JSONLogLine {
 	source := "runtime"
-	if isBuildSpanID(line.SpanID) {
+	if logstore.IsBuildSpanID(line.SpanID) {
 		source = "build"
 	}
 
@@ -65,11 +75,12 @@ func (p *JSONPrinter) toJSONLine(line logstore.LogLine) JSONLogLine {
 		Time:       stringPtr(line.Time.Format(time.RFC3339)),
 		Resource:   stringPtr(string(line.ManifestName)),
 		Level:      stringPtr(levelToString(line.Level)),
-		Message:    stringPtr(strings.TrimSuffix(line.Text, "\n")),
+		Message:    stringPtr(ansiRegexp.ReplaceAllString(strings.TrimSuffix(line.Text, "\n"), "")),
 		SpanID:     stringPtr(string(line.SpanID)),
 		ProgressID: stringPtr(line.ProgressID),
 		BuildEvent: stringPtr(line.BuildEvent),
 		Source:     stringPtr(source),
+		Fields:     line.Fields,
 	}
 }
 
@@ -51,6 +51,40 @@ func TestJSONPrinterAllFields(t *testing.T) {
 	assert.Equal(t, "build", result["source"])
 }
 
+func TestJSONPrinterFields(t *testing.T) {
+	buf := &bytes.Buffer{}
+	printer := NewJSONPrinter(Stdout(buf))
+
+	printer.Print([]logstore.LogLine{
+		{Text: "slow request\n", SpanID: "pod:default:api", Level: logger.WarnLvl, Fields: logger.Fields{"request_id": "abc"}},
+		{Text: "plain text\n", SpanID: "pod:default:api", Level: logger.InfoLvl},
+	})
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+
+	var structured, plain map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &structured))
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &plain))
+
+	assert.Equal(t, map[string]interface{}{"request_id": "abc"}, structured["fields"])
+	assert.Equal(t, "warn", structured["level"])
+	assert.NotContains(t, plain, "fields")
+}
+
+func TestJSONPrinterStripsANSI(t *testing.T) {
+	buf := &bytes.Buffer{}
+	printer := NewJSONPrinter(Stdout(buf))
+
+	printer.Print([]logstore.LogLine{
+		{Text: "\x1b[1;31mfailed\x1b[0m to connect\n", SpanID: "pod:default:api", Level: logger.InfoLvl},
+	})
+
+	var result map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	assert.Equal(t, "failed to connect", result["message"])
+}
+
 func TestJSONPrinterMultipleLines(t *testing.T) {
 	buf := &bytes.Buffer{}
 	printer := NewJSONPrinter(Stdout(buf))
@@ -1,6 +1,7 @@
 package client
 
 import (
+	"regexp"
 	"strings"
 	"time"
 
@@ -37,6 +38,14 @@ func (r FilterResources) Matches(name model.ManifestName) bool {
 
 type FilterLevel logger.Level
 
+// FilterFields matches logs that have all of these fields, with these values.
+// Only structured logs have fields (see the log_parser option in the Tiltfile).
+type FilterFields map[string]string
+
+// FilterGrep matches logs whose text matches the regular expression.
+// Nil means no filter.
+type FilterGrep *regexp.Regexp
+
 // FilterSince represents an absolute timestamp for time-based log filtering.
 // Zero value (time.Time{}) means no time filter.
 // The CLI layer converts duration flags (e.g., "5m") to timestamps.
@@ -53,6 +62,8 @@ func NewLogFilter(
 	source FilterSource,
 	resources FilterResources,
 	level FilterLevel,
+	fields FilterFields,
+	grep FilterGrep,
 	since FilterSince,
 	tail FilterTail,
 	jsonOutput FilterJSON,
@@ -61,6 +72,8 @@ func NewLogFilter(
 		source:     source,
 		resources:  resources,
 		level:      logger.Level(level),
+		fields:     fields,
+		grep:       (*regexp.Regexp)(grep),
 		since:      time.Time(since),
 		tail:       int(tail),
 		jsonOutput: bool(jsonOutput),
@@ -71,16 +84,13 @@ type LogFilter struct {
 	source     FilterSource
 	resources  FilterResources
 	level      logger.Level
-	since      time.Time // zero value means no filter
-	tail       int       // -1 means no limit
+	fields     FilterFields
+	grep       *regexp.Regexp // nil means no filter
+	since      time.Time      // zero value means no filter
+	tail       int            // -1 means no limit
 	jsonOutput bool
 }
 
-// The implementation is identical to isBuildSpanId in web/src/logs.ts.
-func isBuildSpanID(spanID logstore.SpanID) bool {
-	return strings.HasPrefix(string(spanID), "build:") || strings.HasPrefix(string(spanID), "cmdimage:")
-}
-
 // The implementation is identical to matchesLevelFilter in web/src/OverviewLogPane.tsx
 func (f LogFilter) matchesLevelFilter(line logstore.LogLine) bool {
 	if !f.level.AsSevereAs(logger.WarnLvl) {
@@ -97,6 +107,23 @@ func (f LogFilter) SuppressPrefix() bool {
 	return len(f.resources) == 1 || f.jsonOutput
 }
 
+func (f LogFilter) matchesFieldsFilter(line logstore.LogLine) bool {
+	for k, v := range f.fields {
+		actual, ok := line.Fields[k]
+		if !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
+func (f LogFilter) matchesGrepFilter(line logstore.LogLine) bool {
+	if f.grep == nil {
+		return true
+	}
+	return f.grep.MatchString(strings.TrimRight(line.Text, "\r\n"))
+}
+
 // matchesSinceFilter checks if the log line is at or after the since timestamp.
 func (f LogFilter) matchesSinceFilter(line logstore.LogLine) bool {
 	if f.since.IsZero() {
@@ -106,21 +133,31 @@ func (f LogFilter) matchesSinceFilter(line logstore.LogLine) bool {
 }
 
 // Matches Checks if this line matches the current filter.
-// The implementation is identical to matchesFilter in web/src/OverviewLogPane.tsx.
-// except for term filtering as tools like grep can be used from the CLI.
+// The implementation is identical to matchesFilter in web/src/OverviewLogPane.tsx,
+// except that the term filter is a regular expression (--grep).
 func (f LogFilter) Matches(line logstore.LogLine) bool {
 	// Check resource filter first - build events should also respect resource filtering
 	if !f.resources.Matches(line.ManifestName) {
 		return false
 	}
 
+	// Build events don't have any application fields, so they're
+	// hidden by a field filter.
+	if !f.matchesFieldsFilter(line) {
+		return false
+	}
+
+	if !f.matchesGrepFilter(line) {
+		return false
+	}
+
 	if line.BuildEvent != "" {
 		// Include build event logs that match the resource filter.
 		// This makes it easier to see which logs belong to which builds.
 		return true
 	}
 
-	isBuild := isBuildSpanID(line.SpanID)
+	isBuild := logstore.IsBuildSpanID(line.SpanID)
 	if f.source == FilterSourceRuntime && isBuild {
 		return false
 	}
@@ -153,13 +190,15 @@ func (f LogFilter) ApplyWithoutTail(lines []logstore.LogLine) []logstore.LogLine
 // matchesAllLines reports whether no per-line constraint is active, i.e.
 // MatchesAll is true for every possible line: no resource filter, no
 // build/runtime source restriction (those are the only two sources Matches
-// checks), no severe level filter, and no since bound. This is the default
-// `tilt up` filter, which runs on every store notification.
+// checks), no severe level filter, no field or grep filter, and no since bound.
+// This is the default `tilt up` filter, which runs on every store notification.
 func (f LogFilter) matchesAllLines() bool {
 	return len(f.resources) == 0 &&
 		f.source != FilterSourceRuntime &&
 		f.source != FilterSourceBuild &&
 		!f.level.AsSevereAs(logger.WarnLvl) &&
+		len(f.fields) == 0 &&
+		f.grep == nil &&
 		f.since.IsZero()
 }
 
@@ -194,6 +233,34 @@ func (f LogFilter) ApplyWithOptions(lines []logstore.LogLine, applyTail bool) []
 	return filtered
 }
 
+// Query returns a server-side log query for the filter, except for the tail.
+//
+// The server may return more lines than the filter matches (for example,
+// a level filter matches more severe levels too), and older servers ignore
+// the query, so the filter still needs to be applied.
+func (f LogFilter) Query() logstore.Query {
+	q := logstore.Query{Since: f.since, Regexp: f.grep}
+	if f.level.AsSevereAs(logger.WarnLvl) {
+		q.Level = f.level
+	}
+	switch f.source {
+	case FilterSourceBuild:
+		q.Source = logstore.SourceBuild
+	case FilterSourceRuntime:
+		q.Source = logstore.SourceRuntime
+	}
+	if len(f.fields) != 0 {
+		q.Fields = logger.Fields(f.fields)
+	}
+	if len(f.resources) != 0 {
+		q.ManifestNames = model.ManifestNameSet{}
+		for _, r := range f.resources {
+			q.ManifestNames[r] = true
+		}
+	}
+	return q
+}
+
 // JSONOutput returns whether JSON output is enabled.
 func (f LogFilter) JSONOutput() bool {
 	return f.jsonOutput
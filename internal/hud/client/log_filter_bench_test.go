@@ -14,7 +14,7 @@ import (
 // constraint on any axis.
 func noopBenchFilter() LogFilter {
 	return NewLogFilter(FilterSourceAll, nil, FilterLevel(logger.NoneLvl),
-		FilterSince(time.Time{}), FilterTail(-1), false)
+		nil, nil, FilterSince(time.Time{}), FilterTail(-1), false)
 }
 
 func benchFilterLines(n int) []logstore.LogLine {
@@ -61,7 +61,7 @@ func BenchmarkLogFilterApplyNoopLarge(b *testing.B) {
 // A real filter that keeps a subset (one resource out of twenty).
 func BenchmarkLogFilterApplyResourceFilter(b *testing.B) {
 	f := NewLogFilter(FilterSourceAll, FilterResources{"res-001"},
-		FilterLevel(logger.NoneLvl), FilterSince(time.Time{}), FilterTail(-1), false)
+		FilterLevel(logger.NoneLvl), nil, nil, FilterSince(time.Time{}), FilterTail(-1), false)
 	lines := benchFilterLines(2000)
 
 	b.ReportAllocs()
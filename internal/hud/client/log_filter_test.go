@@ -1,12 +1,14 @@
 package client
 
 import (
+	"regexp"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
@@ -156,6 +158,42 @@ func TestLogFilterMatches(t *testing.T) {
 			input:       logstore.LogLine{SpanID: "pod:default:nginx", ManifestName: "nginx"},
 			expected:    false,
 		},
+		{
+			description: "fields match logs with the same fields",
+			logFilter:   LogFilter{source: FilterSourceAll, fields: FilterFields{"request_id": "abc"}},
+			input:       logstore.LogLine{SpanID: "pod:default:nginx", Fields: logger.Fields{"request_id": "abc", "level": "info"}},
+			expected:    true,
+		},
+		{
+			description: "fields do not match logs with different fields",
+			logFilter:   LogFilter{source: FilterSourceAll, fields: FilterFields{"request_id": "abc"}},
+			input:       logstore.LogLine{SpanID: "pod:default:nginx", Fields: logger.Fields{"request_id": "xyz"}},
+			expected:    false,
+		},
+		{
+			description: "fields do not match build events",
+			logFilter:   LogFilter{source: FilterSourceAll, fields: FilterFields{"request_id": "abc"}},
+			input:       logstore.LogLine{SpanID: "build:1", BuildEvent: "init"},
+			expected:    false,
+		},
+		{
+			description: "grep matches logs with matching text",
+			logFilter:   LogFilter{source: FilterSourceAll, grep: regexp.MustCompile("panic$")},
+			input:       logstore.LogLine{SpanID: "pod:default:nginx", Text: "goroutine panic\n"},
+			expected:    true,
+		},
+		{
+			description: "grep does not match logs with other text",
+			logFilter:   LogFilter{source: FilterSourceAll, grep: regexp.MustCompile("panic$")},
+			input:       logstore.LogLine{SpanID: "pod:default:nginx", Text: "all good\n"},
+			expected:    false,
+		},
+		{
+			description: "grep does not match build events with other text",
+			logFilter:   LogFilter{source: FilterSourceAll, grep: regexp.MustCompile("panic$")},
+			input:       logstore.LogLine{SpanID: "build:1", BuildEvent: "init", Text: "Initial Build\n"},
+			expected:    false,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -455,7 +493,7 @@ func TestLogFilterMatchesAllLines(t *testing.T) {
 		{
 			description: "default tilt up filter constrains nothing",
 			logFilter: NewLogFilter(FilterSourceAll, nil, FilterLevel(logger.NoneLvl),
-				FilterSince(time.Time{}), FilterTail(-1), false),
+				nil, nil, FilterSince(time.Time{}), FilterTail(-1), false),
 			expected: true,
 		},
 		{
@@ -488,6 +526,16 @@ func TestLogFilterMatchesAllLines(t *testing.T) {
 			logFilter:   LogFilter{level: logger.ErrorLvl},
 			expected:    false,
 		},
+		{
+			description: "field filter is a constraint",
+			logFilter:   LogFilter{fields: FilterFields{"request_id": "abc"}},
+			expected:    false,
+		},
+		{
+			description: "grep filter is a constraint",
+			logFilter:   LogFilter{grep: regexp.MustCompile("error")},
+			expected:    false,
+		},
 		{
 			description: "since bound is a constraint",
 			logFilter:   LogFilter{since: time.Unix(1, 0)},
@@ -501,3 +549,22 @@ func TestLogFilterMatchesAllLines(t *testing.T) {
 		})
 	}
 }
+
+func TestLogFilterQuery(t *testing.T) {
+	since := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	grep := regexp.MustCompile("error")
+	f := NewLogFilter(FilterSourceRuntime, FilterResources{"fe"}, FilterLevel(logger.WarnLvl),
+		FilterFields{"request_id": "abc"}, FilterGrep(grep), FilterSince(since), FilterTail(10), true)
+
+	assert.Equal(t, logstore.Query{
+		Since:         since,
+		ManifestNames: model.ManifestNameSet{"fe": true},
+		Level:         logger.WarnLvl,
+		Regexp:        grep,
+		Fields:        logger.Fields{"request_id": "abc"},
+		Source:        logstore.SourceRuntime,
+	}, f.Query())
+
+	f = NewLogFilter(FilterSourceAll, nil, FilterLevel(logger.InfoLvl), nil, nil, FilterSince{}, FilterTail(-1), false)
+	assert.True(t, f.Query().IsZero())
+}
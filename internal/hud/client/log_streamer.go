@@ -5,7 +5,7 @@ import (
 	"encoding/json"
 	"io"
 	"net/http"
-	"net/url"
+	"strings"
 
 	"github.com/gorilla/websocket"
 	"github.com/pkg/errors"
@@ -38,6 +38,21 @@ func NewLogStreamer(follow FollowFlag, url model.WebURL, filter LogFilter, print
 }
 
 func (ls *LogStreamer) Stream(ctx context.Context) error {
+	if !ls.follow {
+		// Query the server, so that we get logs that have been archived to disk.
+		logList, err := ls.fetchLogs(ctx)
+		if err == nil {
+			handler := newLogViewHandler(ls.filter, ls.printer)
+			handler.logstore = logstore.NewUnboundedLogStore()
+			return handler.Handle(&proto_webview.View{LogList: logList})
+		}
+		if err != errLogQueryNotSupported {
+			return errors.Wrap(err, "fetching logs")
+		}
+		// Older versions of Tilt don't support log queries, so fall back to
+		// reading the logs in memory over the websocket.
+	}
+
 	csrfToken, err := ls.fetchWebsocketToken(ctx)
 	if err != nil {
 		return errors.Wrap(err, "fetching websocket token")
@@ -46,7 +61,11 @@ func (ls *LogStreamer) Stream(ctx context.Context) error {
 	wsURL := ls.url
 	wsURL.Scheme = "ws"
 	wsURL.Path = "/ws/view"
-	wsURL.RawQuery = url.Values{"csrf": []string{csrfToken}}.Encode()
+	// Filter the logs on the server, so that it doesn't send logs we'd
+	// throw away.
+	params := ls.filter.Query().Values()
+	params.Set("csrf", csrfToken)
+	wsURL.RawQuery = params.Encode()
 	logger.Get(ctx).Debugf("connecting to %s", wsURL.String())
 
 	conn, _, err := websocket.DefaultDialer.Dial(wsURL.String(), nil)
@@ -59,6 +78,42 @@ func (ls *LogStreamer) Stream(ctx context.Context) error {
 	return wsr.Listen(ctx)
 }
 
+var errLogQueryNotSupported = errors.New("log queries not supported")
+
+func (ls *LogStreamer) fetchLogs(ctx context.Context) (*proto_webview.LogList, error) {
+	logsURL := ls.url
+	logsURL.Scheme = "http"
+	logsURL.Path = "/api/logs"
+	logsURL.RawQuery = ls.filter.Query().Values().Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, logsURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(server.TiltTokenHeaderName, token.Load())
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrapf(err, "connecting to Tilt at %s", logsURL.String())
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, errLogQueryNotSupported
+	}
+	if res.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(res.Body)
+		return nil, errors.Errorf("request to %s failed with status %q: %s", logsURL.String(), res.Status, strings.TrimSpace(string(body)))
+	}
+
+	logList := &proto_webview.LogList{}
+	err = json.NewDecoder(res.Body).Decode(logList)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing logs")
+	}
+	return logList, nil
+}
+
 // our websocket is protected by a csrf token, so we need to fetch it.
 func (ls *LogStreamer) fetchWebsocketToken(ctx context.Context) (string, error) {
 	tokenURL := ls.url
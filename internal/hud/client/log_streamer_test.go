@@ -190,6 +190,8 @@ func newLogStreamerFixture(t *testing.T) *logStreamerFixture {
 		FilterSourceAll,
 		FilterResources{},
 		FilterLevel(logger.InfoLvl),
+		FilterFields{},
+		nil,
 		FilterSince{},
 		FilterTail(-1),
 		FilterJSON(false))
@@ -210,6 +212,8 @@ func (f *logStreamerFixture) withResourceNames(resourceNames ...string) *logStre
 		FilterSourceAll,
 		FilterResources(resources),
 		FilterLevel(logger.InfoLvl),
+		FilterFields{},
+		nil,
 		FilterSince{},
 		FilterTail(-1),
 		FilterJSON(false))
@@ -222,6 +226,8 @@ func (f *logStreamerFixture) withTail(tail int) *logStreamerFixture {
 		FilterSourceAll,
 		FilterResources{},
 		FilterLevel(logger.InfoLvl),
+		FilterFields{},
+		nil,
 		FilterSince{},
 		FilterTail(tail),
 		FilterJSON(false))
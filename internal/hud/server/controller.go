@@ -297,8 +297,7 @@ func newAPIServerProxyHandler(config *rest.Config) (http.Handler, error) {
 // access to sensitive endpoints like /debug/pprof.
 func loopbackOnly(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		host, _, err := net.SplitHostPort(r.RemoteAddr)
-		if err != nil || !net.ParseIP(host).IsLoopback() {
+		if !isLoopbackRequest(r) {
 			http.Error(w, "forbidden", http.StatusForbidden)
 			return
 		}
@@ -306,5 +305,11 @@ func loopbackOnly(next http.Handler) http.Handler {
 	})
 }
 
+// Whether the request came from this machine.
+func isLoopbackRequest(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	return err == nil && net.ParseIP(host).IsLoopback()
+}
+
 var _ store.SetUpper = &HeadsUpServerController{}
 var _ store.TearDowner = &HeadsUpServerController{}
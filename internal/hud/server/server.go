@@ -3,6 +3,7 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -17,15 +18,20 @@ import (
 	"github.com/gorilla/mux"
 	_ "github.com/gorilla/websocket"
 	jsoniter "github.com/json-iterator/go"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/hud/webview"
 	"github.com/tilt-dev/tilt/internal/store"
+	"github.com/tilt-dev/tilt/internal/store/kubernetesapplys"
 	"github.com/tilt-dev/tilt/internal/store/tiltfiles"
 	"github.com/tilt-dev/tilt/pkg/assets"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
@@ -53,6 +59,11 @@ type triggerPayload struct {
 	BuildReason   model.BuildReason `json:"build_reason"`
 }
 
+type confirmDiffPayload struct {
+	ManifestName string `json:"manifest_name"`
+	Hash         string `json:"hash"`
+}
+
 type overrideTriggerModePayload struct {
 	ManifestNames []string `json:"manifest_names"`
 	TriggerMode   int      `json:"trigger_mode"`
@@ -65,6 +76,8 @@ type HeadsUpServer struct {
 	a          *tiltanalytics.TiltAnalytics
 	wsList     *WebsocketList
 	ctrlClient ctrlclient.Client
+	metrics    *metrics.Metrics
+	logArchive *logarchive.Archive
 }
 
 func ProvideHeadsUpServer(
@@ -73,7 +86,9 @@ func ProvideHeadsUpServer(
 	assetServer assets.Server,
 	analytics *tiltanalytics.TiltAnalytics,
 	wsList *WebsocketList,
-	ctrlClient ctrlclient.Client) (*HeadsUpServer, error) {
+	ctrlClient ctrlclient.Client,
+	metrics *metrics.Metrics,
+	logArchive *logarchive.Archive) (*HeadsUpServer, error) {
 	r := mux.NewRouter().UseEncodedPath()
 	r.Use(originCheckMiddleware)
 	s := &HeadsUpServer{
@@ -83,9 +98,12 @@ func ProvideHeadsUpServer(
 		a:          analytics,
 		wsList:     wsList,
 		ctrlClient: ctrlClient,
+		metrics:    metrics,
+		logArchive: logArchive,
 	}
 
 	r.Handle("/api/view", s.requireToken(http.HandlerFunc(s.ViewJSON)))
+	r.Handle("/api/logs", s.requireToken(http.HandlerFunc(s.LogsJSON)))
 	r.Handle("/api/dump/engine", s.requireToken(http.HandlerFunc(s.DumpEngineJSON)))
 	r.Handle("/api/analytics", s.requireToken(http.HandlerFunc(s.HandleAnalytics)))
 	r.Handle("/api/analytics_opt", s.requireToken(http.HandlerFunc(s.HandleAnalyticsOpt)))
@@ -95,6 +113,11 @@ func ProvideHeadsUpServer(
 	r.Handle("/api/websocket_token", s.requireToken(http.HandlerFunc(s.WebsocketToken)))
 	r.HandleFunc("/ws/view", s.ViewWebsocket)
 	r.Handle("/api/set_tiltfile_args", s.requireToken(http.HandlerFunc(s.HandleSetTiltfileArgs))).Methods("POST")
+	r.Handle("/api/confirm_diff", s.requireToken(http.HandlerFunc(s.HandleConfirmDiff))).Methods("POST")
+
+	// Prometheus scrapers can't easily send the Tilt token, so scrapers on
+	// this machine may skip it. Remote scrapers need it, like the /api routes.
+	r.Handle("/metrics", s.requireTokenUnlessLoopback(metrics.Handler()))
 
 	r.PathPrefix("/").Handler(s.cookieWrapper(assetServer))
 
@@ -182,10 +205,27 @@ func (s *HeadsUpServer) requireToken(next http.Handler) http.Handler {
 	})
 }
 
+// requireTokenUnlessLoopback lets requests from this machine through without
+// a token, and validates the token of every other request with requireToken.
+func (s *HeadsUpServer) requireTokenUnlessLoopback(next http.Handler) http.Handler {
+	withToken := s.requireToken(next)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isLoopbackRequest(r) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		withToken.ServeHTTP(w, r)
+	})
+}
+
 func (s *HeadsUpServer) Router() http.Handler {
 	return s.router
 }
 
+// Renders the complete view.
+//
+// If the request has log query parameters (see logstore.ParseQuery),
+// the view's logs are the results of the query rather than the logs in memory.
 func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 	view, err := webview.CompleteView(req.Context(), s.ctrlClient, s.store)
 	if err != nil {
@@ -193,6 +233,14 @@ func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	if len(req.URL.Query()) > 0 {
+		logList, ok := s.queryLogs(w, req)
+		if !ok {
+			return
+		}
+		view.LogList = logList
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	err = json.NewEncoder(w).Encode(view)
 	if err != nil {
@@ -200,6 +248,36 @@ func (s *HeadsUpServer) ViewJSON(w http.ResponseWriter, req *http.Request) {
 	}
 }
 
+// Queries the logs, including logs that have been archived to disk.
+// See logstore.ParseQuery for the query parameters.
+func (s *HeadsUpServer) LogsJSON(w http.ResponseWriter, req *http.Request) {
+	logList, ok := s.queryLogs(w, req)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	err := json.NewEncoder(w).Encode(logList)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error rendering logs payload: %v", err), http.StatusInternalServerError)
+	}
+}
+
+func (s *HeadsUpServer) queryLogs(w http.ResponseWriter, req *http.Request) (*proto_webview.LogList, bool) {
+	q, err := logstore.ParseQuery(req.URL.Query())
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid log query: %v", err), http.StatusBadRequest)
+		return nil, false
+	}
+
+	logList, err := s.logArchive.Query(s.store, q)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Error querying logs: %v", err), http.StatusInternalServerError)
+		return nil, false
+	}
+	return logList, true
+}
+
 // Dump the JSON engine over http. Only intended for 'tilt dump engine'.
 func (s *HeadsUpServer) DumpEngineJSON(w http.ResponseWriter, req *http.Request) {
 	state := s.store.RLockState()
@@ -300,6 +378,35 @@ func (s *HeadsUpServer) HandleSetTiltfileArgs(w http.ResponseWriter, req *http.R
 	}
 }
 
+// Confirms the diff that a resource's apply is waiting on.
+//
+// Responds with:
+// * 200/empty body on success
+// * 404 if the resource has no Kubernetes apply
+// * 409 if the diff isn't awaiting confirmation anymore
+func (s *HeadsUpServer) HandleConfirmDiff(w http.ResponseWriter, req *http.Request) {
+	var payload confirmDiffPayload
+	err := json.NewDecoder(req.Body).Decode(&payload)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error parsing JSON payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	err = kubernetesapplys.ConfirmDiff(req.Context(), s.ctrlClient, payload.ManifestName, payload.Hash)
+	if apierrors.IsNotFound(err) {
+		http.Error(w, fmt.Sprintf("resource %q has no Kubernetes apply", payload.ManifestName), http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, kubernetesapplys.ErrDiffNotAwaiting) {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("error updating apiserver: %v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
 // Responds with:
 // * 200/empty body on success
 // * 200/error message in body on well-formed, unservicable requests (e.g. resource is disabled or doesn't exist)
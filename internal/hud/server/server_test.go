@@ -3,6 +3,7 @@ package server_test
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -20,15 +22,20 @@ import (
 
 	tiltanalytics "github.com/tilt-dev/tilt/internal/analytics"
 	"github.com/tilt-dev/tilt/internal/controllers/fake"
+	"github.com/tilt-dev/tilt/internal/engine/logarchive"
+	"github.com/tilt-dev/tilt/internal/engine/metrics"
 	"github.com/tilt-dev/tilt/internal/hud/server"
 	"github.com/tilt-dev/tilt/internal/hud/view"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/internal/store"
 	"github.com/tilt-dev/tilt/internal/testutils"
 	"github.com/tilt-dev/tilt/internal/token"
+	"github.com/tilt-dev/tilt/internal/xdg"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/assets"
+	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 	"github.com/tilt-dev/wmclient/pkg/analytics"
 )
 
@@ -313,6 +320,49 @@ func TestSetTiltfileArgs(t *testing.T) {
 	)
 }
 
+func TestConfirmDiff(t *testing.T) {
+	f := newTestFixture(t)
+	f.withDiffAwaitingConfirmation("foo", "abc123")
+
+	status, body := f.makeReq("/api/confirm_diff", f.serv.HandleConfirmDiff, http.MethodPost,
+		`{"manifest_name": "foo", "hash": "abc123"}`)
+	require.Equal(t, http.StatusOK, status, body)
+
+	var ka v1alpha1.KubernetesApply
+	require.NoError(t, f.ctrlClient.Get(f.ctx, types.NamespacedName{Name: "foo"}, &ka))
+	assert.Equal(t, "abc123", ka.Annotations[v1alpha1.AnnotationConfirmedDiff])
+}
+
+func TestConfirmDiffStaleHash(t *testing.T) {
+	f := newTestFixture(t)
+	f.withDiffAwaitingConfirmation("foo", "def456")
+
+	status, body := f.makeReq("/api/confirm_diff", f.serv.HandleConfirmDiff, http.MethodPost,
+		`{"manifest_name": "foo", "hash": "abc123"}`)
+	require.Equal(t, http.StatusConflict, status)
+	assert.Contains(t, body, "no longer awaiting confirmation")
+
+	var ka v1alpha1.KubernetesApply
+	require.NoError(t, f.ctrlClient.Get(f.ctx, types.NamespacedName{Name: "foo"}, &ka))
+	assert.Empty(t, ka.Annotations[v1alpha1.AnnotationConfirmedDiff])
+}
+
+func TestConfirmDiffNoSuchResource(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, _ := f.makeReq("/api/confirm_diff", f.serv.HandleConfirmDiff, http.MethodPost,
+		`{"manifest_name": "foo", "hash": "abc123"}`)
+	require.Equal(t, http.StatusNotFound, status)
+}
+
+func TestConfirmDiffRequiresToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	code, _ := f.routerReq(http.MethodPost, "/api/confirm_diff", nil)
+	assert.Equal(t, http.StatusForbidden, code)
+}
+
 type serverFixture struct {
 	t            *testing.T
 	ctx          context.Context
@@ -342,7 +392,9 @@ func newTestFixture(t *testing.T) *serverFixture {
 
 	ctx := context.Background()
 
-	serv, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta, wsl, ctrlClient)
+	fs := afero.NewMemMapFs()
+	archive := logarchive.NewArchive(xdg.NewFakeBase("/tmp", fs), fs, "test", false)
+	serv, err := server.ProvideHeadsUpServer(ctx, st, assets.NewFakeServer(), ta, wsl, ctrlClient, metrics.NewMetrics(st), archive)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -402,6 +454,14 @@ func (f *fakeHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+func (f *serverFixture) withDiffAwaitingConfirmation(name string, hash string) {
+	ka := &v1alpha1.KubernetesApply{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	require.NoError(f.t, f.ctrlClient.Create(f.ctx, ka))
+	ka.Status.Diff = "~ Secret/default/foo\n"
+	ka.Status.DiffAwaitingConfirmation = hash
+	require.NoError(f.t, f.ctrlClient.Status().Update(f.ctx, ka))
+}
+
 func (f *serverFixture) assertIncrement(name string, count int) {
 	runningCount := 0
 	for _, c := range f.a.Counts {
@@ -510,6 +570,77 @@ func TestSnapshotTokenHeaderAccepted(t *testing.T) {
 	require.Equal(t, http.StatusOK, status)
 }
 
+func TestMetricsFromLoopbackDoesNotRequireToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	for _, addr := range []string{"127.0.0.1:51234", "[::1]:51234"} {
+		status, _ := f.routerReq(http.MethodGet, "/metrics", func(r *http.Request) {
+			r.RemoteAddr = addr
+		})
+		require.Equal(t, http.StatusOK, status, addr)
+	}
+}
+
+func TestMetricsFromRemoteRequiresToken(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	status, body := f.routerReq(http.MethodGet, "/metrics", func(r *http.Request) {
+		r.RemoteAddr = "192.168.1.20:51234"
+	})
+	require.Equal(t, http.StatusForbidden, status)
+	require.Contains(t, body, "missing session token")
+
+	status, _ = f.routerReq(http.MethodGet, "/metrics", func(r *http.Request) {
+		r.RemoteAddr = "192.168.1.20:51234"
+		r.Header.Set(server.TiltTokenHeaderName, testToken)
+	})
+	require.Equal(t, http.StatusOK, status)
+}
+
+func TestLogsQuery(t *testing.T) {
+	f := newTestFixture(t)
+	f.setToken(testToken)
+
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.InfoLvl, nil, []byte("fe started\n")), nil)
+	state.LogStore.Append(store.NewLogAction("be", "be-span", logger.InfoLvl, nil, []byte("be started\n")), nil)
+	state.LogStore.Append(store.NewLogAction("fe", "fe-span", logger.ErrorLvl, nil, []byte("fe crashed\n")), nil)
+	f.st.UnlockMutableState()
+
+	withToken := func(r *http.Request) {
+		r.Header.Set(server.TiltTokenHeaderName, testToken)
+	}
+	status, body := f.routerReq(http.MethodGet, "/api/logs?manifest=fe&level=error", withToken)
+	require.Equal(t, http.StatusOK, status)
+
+	var logList proto_webview.LogList
+	require.NoError(t, json.Unmarshal([]byte(body), &logList))
+	require.Len(t, logList.Segments, 1)
+	assert.Equal(t, "fe crashed\n", logList.Segments[0].Text)
+	assert.Equal(t, "fe", logList.Spans["fe-span"].ManifestName)
+
+	status, body = f.routerReq(http.MethodGet, "/api/view?grep=started$", withToken)
+	require.Equal(t, http.StatusOK, status)
+
+	var view proto_webview.View
+	require.NoError(t, json.Unmarshal([]byte(body), &view))
+	require.Len(t, view.LogList.Segments, 2)
+
+	status, body = f.routerReq(http.MethodGet, "/api/logs?grep=(", withToken)
+	require.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, "invalid grep")
+}
+
+func TestWebsocketInvalidLogQuery(t *testing.T) {
+	f := newTestFixture(t)
+
+	status, body := f.routerReq(http.MethodGet, "/ws/view?csrf=abc&source=cluster", nil)
+	require.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, body, `invalid source: "cluster"`)
+}
+
 func TestWebsocketTokenRequiresToken(t *testing.T) {
 	f := newTestFixture(t)
 	f.setToken(testToken)
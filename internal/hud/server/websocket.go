@@ -65,6 +65,9 @@ type WebsocketSubscriber struct {
 
 	tiltStartTime    metav1.MicroTime
 	clientCheckpoint logstore.Checkpoint
+
+	// Only send logs that match this query.
+	logQuery logstore.Query
 }
 
 type WebsocketConn interface {
@@ -75,12 +78,13 @@ type WebsocketConn interface {
 
 var _ WebsocketConn = &websocket.Conn{}
 
-func NewWebsocketSubscriber(ctx context.Context, ctrlClient ctrlclient.Client, st store.RStore, conn WebsocketConn) *WebsocketSubscriber {
+func NewWebsocketSubscriber(ctx context.Context, ctrlClient ctrlclient.Client, st store.RStore, conn WebsocketConn, logQuery logstore.Query) *WebsocketSubscriber {
 	return &WebsocketSubscriber{
 		ctx:              ctx,
 		ctrlClient:       ctrlClient,
 		st:               st,
 		conn:             conn,
+		logQuery:         logQuery,
 		q:                workqueue.NewTyped[bool](),
 		dirtyUIButtons:   make(map[string]*v1alpha1.UIButton),
 		dirtyUIResources: make(map[string]*v1alpha1.UIResource),
@@ -118,6 +122,14 @@ func (ws *WebsocketSubscriber) Stream(ctx context.Context) {
 		return
 	}
 
+	if !ws.logQuery.IsZero() {
+		logView, err := webview.LogUpdate(ws.st, 0, ws.logQuery)
+		if err != nil {
+			return
+		}
+		view.LogList = logView.LogList
+	}
+
 	ws.sendView(ctx, view)
 
 	if view.UiSession != nil {
@@ -254,7 +266,7 @@ func (ws *WebsocketSubscriber) SendClusterUpdate(
 
 // Sends all the objects that have changed since the last send.
 func (ws *WebsocketSubscriber) toViewUpdate() *proto_webview.View {
-	view, err := webview.LogUpdate(ws.st, ws.clientCheckpoint)
+	view, err := webview.LogUpdate(ws.st, ws.clientCheckpoint, ws.logQuery)
 	if err != nil {
 		return nil // Not much we can do on error right now.
 	}
@@ -265,6 +277,10 @@ func (ws *WebsocketSubscriber) toViewUpdate() *proto_webview.View {
 	// [-1,-1) means there are no logs
 	if view.LogList.ToCheckpoint == -1 && view.LogList.FromCheckpoint == -1 {
 		view.LogList = nil
+	} else if len(view.LogList.Segments) == 0 {
+		// None of the new logs match the query, so skip past them.
+		ws.clientCheckpoint = logstore.Checkpoint(view.LogList.ToCheckpoint)
+		view.LogList = nil
 	}
 	hasChanges := view.LogList != nil
 
@@ -337,14 +353,26 @@ func (ws *WebsocketSubscriber) sendView(ctx context.Context, view *proto_webview
 	}
 }
 
+// Streams the view over a websocket.
+//
+// If the request has log query parameters (see logstore.ParseQuery),
+// only the logs that match the query are sent. The query's limit is ignored.
 func (s *HeadsUpServer) ViewWebsocket(w http.ResponseWriter, req *http.Request) {
+	params := req.URL.Query()
+	params.Del("csrf")
+	logQuery, err := logstore.ParseQuery(params)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid log query: %v", err), http.StatusBadRequest)
+		return
+	}
+
 	conn, err := upgrader.Upgrade(w, req, nil)
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Error upgrading websocket: %v", err), http.StatusInternalServerError)
 		return
 	}
 
-	ws := NewWebsocketSubscriber(s.ctx, s.ctrlClient, s.store, conn)
+	ws := NewWebsocketSubscriber(s.ctx, s.ctrlClient, s.store, conn, logQuery)
 	s.wsList.Add(ws)
 	_ = s.store.AddSubscriber(s.ctx, ws)
 
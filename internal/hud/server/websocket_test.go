@@ -18,6 +18,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/testutils/fakeconn"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 )
 
 func TestWebsocketCloseOnReadErr(t *testing.T) {
@@ -30,7 +31,7 @@ func TestWebsocketCloseOnReadErr(t *testing.T) {
 
 	conn := fakeconn.NewFakeConn()
 	ctrlClient := fake.NewFakeTiltClient()
-	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn)
+	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn, logstore.Query{})
 	require.NoError(t, st.AddSubscriber(ctx, ws))
 
 	done := make(chan bool)
@@ -60,7 +61,7 @@ func TestWebsocketReadErrDuringMsg(t *testing.T) {
 
 	conn := fakeconn.NewFakeConn()
 	ctrlClient := fake.NewFakeTiltClient()
-	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn)
+	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn, logstore.Query{})
 	require.NoError(t, st.AddSubscriber(ctx, ws))
 
 	done := make(chan bool)
@@ -96,7 +97,7 @@ func TestWebsocketNextWriterError(t *testing.T) {
 	conn := fakeconn.NewFakeConn()
 	conn.NextWriterError = fmt.Errorf("fake NextWriter error")
 	ctrlClient := fake.NewFakeTiltClient()
-	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn)
+	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn, logstore.Query{})
 	require.NoError(t, st.AddSubscriber(ctx, ws))
 
 	done := make(chan bool)
@@ -186,6 +187,22 @@ func TestMergeUpdates(t *testing.T) {
 	assert.Len(t, view4.Clusters, 1, "Cluster updates")
 }
 
+func TestWebsocketLogQuery(t *testing.T) {
+	f := newWSFixtureWithLogQuery(t, logstore.Query{Level: logger.WarnLvl})
+
+	f.appendLog(logger.InfoLvl, "info\n")
+	assert.Nil(t, f.ws.toViewUpdate())
+	assert.Equal(t, logstore.Checkpoint(1), f.ws.clientCheckpoint)
+
+	f.appendLog(logger.WarnLvl, "warning\n")
+	view := f.ws.toViewUpdate()
+	require.NotNil(t, view)
+	require.Len(t, view.LogList.Segments, 1)
+	assert.Equal(t, "warning\n", view.LogList.Segments[0].Text)
+	assert.Equal(t, int32(1), view.LogList.FromCheckpoint)
+	assert.Equal(t, int32(2), view.LogList.ToCheckpoint)
+}
+
 type wsFixture struct {
 	ws   *WebsocketSubscriber
 	ctx  context.Context
@@ -194,13 +211,17 @@ type wsFixture struct {
 }
 
 func newWSFixture(t *testing.T) *wsFixture {
+	return newWSFixtureWithLogQuery(t, logstore.Query{})
+}
+
+func newWSFixtureWithLogQuery(t *testing.T, logQuery logstore.Query) *wsFixture {
 	ctx, _, _ := testutils.CtxAndAnalyticsForTest()
 	st, _ := store.NewStoreWithFakeReducer()
 	_ = st.SetUpSubscribersForTesting(ctx)
 
 	conn := fakeconn.NewFakeConn()
 	ctrlClient := fake.NewFakeTiltClient()
-	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn)
+	ws := NewWebsocketSubscriber(ctx, ctrlClient, st, conn, logQuery)
 	require.NoError(t, st.AddSubscriber(ctx, ws))
 	return &wsFixture{
 		ctx:  ctx,
@@ -210,6 +231,12 @@ func newWSFixture(t *testing.T) *wsFixture {
 	}
 }
 
+func (f *wsFixture) appendLog(level logger.Level, text string) {
+	state := f.st.LockMutableStateForTesting()
+	state.LogStore.Append(store.NewGlobalLogAction(level, []byte(text)), nil)
+	f.st.UnlockMutableState()
+}
+
 func writeLogAndNotify(ctx context.Context, st *store.Store) {
 	state := st.LockMutableStateForTesting()
 	state.LogStore.Append(store.NewGlobalLogAction(logger.InfoLvl, []byte("test")), nil)
@@ -86,13 +86,14 @@ func CompleteView(ctx context.Context, client ctrlclient.Client, st store.RStore
 	return ret, nil
 }
 
-// Create a view that only contains logs since the given checkpoint.
-func LogUpdate(st store.RStore, checkpoint logstore.Checkpoint) (*proto_webview.View, error) {
+// Create a view that only contains logs since the given checkpoint
+// that match the query.
+func LogUpdate(st store.RStore, checkpoint logstore.Checkpoint, q logstore.Query) (*proto_webview.View, error) {
 	ret := &proto_webview.View{}
 
 	s := st.RLockState()
 	defer st.RUnlockState()
-	logList, err := s.LogStore.ToLogList(checkpoint)
+	logList, err := s.LogStore.ToLogListMatching(checkpoint, q)
 	if err != nil {
 		return nil, err
 	}
@@ -246,7 +247,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 	ms := mt.State
 	endpoints := store.ManifestTargetEndpoints(mt)
 
-	bh := ToBuildsTerminated(ms.BuildHistory, s.LogStore)
+	bh := ToBuildsTerminated(ms.DisplayBuildHistory(), s.LogStore)
 	lastDeploy := metav1.NewMicroTime(ms.LastSuccessfulDeployTime)
 	currentBuild := ms.EarliestCurrentBuild()
 	cb := ToBuildRunning(currentBuild)
@@ -287,7 +288,7 @@ func toUIResource(mt *store.ManifestTarget, s store.EngineState, disableSources
 		},
 	}
 
-	populateResourceInfoView(mt, r)
+	populateResourceInfoView(mt, s, r)
 
 	r.Status.Conditions = []v1alpha1.UIResourceCondition{
 		UIResourceUpToDateCondition(r.Status),
@@ -405,7 +406,7 @@ func TiltfileResource(name model.ManifestName, ms *store.ManifestState, logStore
 	return tr
 }
 
-func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource) {
+func populateResourceInfoView(mt *store.ManifestTarget, s store.EngineState, r *v1alpha1.UIResource) {
 	r.Status.UpdateStatus = mt.UpdateStatus()
 	r.Status.RuntimeStatus = mt.RuntimeStatus()
 
@@ -416,7 +417,11 @@ func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource)
 
 	if mt.Manifest.IsLocal() {
 		lState := mt.State.LocalRuntimeState()
-		r.Status.LocalResourceInfo = &v1alpha1.UIResourceLocal{PID: int64(lState.PID)}
+		r.Status.LocalResourceInfo = &v1alpha1.UIResourceLocal{
+			PID:               int64(lState.PID),
+			RestartCount:      int32(lState.RestartCount),
+			LastRestartReason: lState.LastRestartReason,
+		}
 	}
 	if mt.Manifest.IsDC() {
 		r.Status.ComposeResourceInfo = &v1alpha1.UIResourceCompose{
@@ -440,6 +445,20 @@ func populateResourceInfoView(mt *store.ManifestTarget, r *v1alpha1.UIResource)
 		if podID != "" {
 			rK8s.SpanID = string(k8sconv.SpanIDForPod(mt.Manifest.Name, podID))
 		}
+		if mt.State.IsRolledBack() {
+			rollback := mt.State.LastRollback
+			rK8s.Rollback = &v1alpha1.UIResourceKubernetesRollback{
+				Time:    apis.NewMicroTime(rollback.Time),
+				Reason:  rollback.Reason,
+				PodName: string(rollback.PodName),
+				SpanID:  string(rollback.SpanID),
+			}
+		}
+		if ka, ok := s.KubernetesApplys[mt.Manifest.K8sTarget().ID().Name.String()]; ok {
+			rK8s.Conflicts = ka.Status.Conflicts
+			rK8s.Diff = ka.Status.Diff
+			rK8s.DiffAwaitingConfirmation = ka.Status.DiffAwaitingConfirmation
+		}
 		r.Status.K8sResourceInfo = rK8s
 	}
 }
@@ -451,9 +470,11 @@ func LogSegmentToEvent(seg *proto_webview.LogSegment, spans map[string]*proto_we
 		return store.LogAction{}
 	}
 
-	// TODO(maia): actually get level (just spoofing for now)
-	spoofedLevel := logger.InfoLvl
-	return store.NewLogAction(model.ManifestName(span.ManifestName), logstore.SpanID(seg.SpanId), spoofedLevel, seg.Fields, []byte(seg.Text))
+	level, ok := logger.LevelByName(string(seg.Level))
+	if !ok {
+		level = logger.InfoLvl
+	}
+	return store.NewLogAction(model.ManifestName(span.ManifestName), logstore.SpanID(seg.SpanId), level, seg.Fields, []byte(seg.Text))
 }
 
 func holdToWaiting(hold store.Hold) *v1alpha1.UIResourceStateWaiting {
@@ -22,6 +22,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/timecmp"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/model/logstore"
 	proto_webview "github.com/tilt-dev/tilt/pkg/webview"
 )
 
@@ -31,7 +32,7 @@ func completeProtoView(t *testing.T, s store.EngineState) *proto_webview.View {
 	st := store.NewTestingStore()
 	st.SetState(s)
 
-	view, err := LogUpdate(st, 0)
+	view, err := LogUpdate(st, 0, logstore.Query{})
 	require.NoError(t, err)
 
 	view.UiSession = ToUISession(s)
@@ -181,6 +182,70 @@ func TestStateToViewK8sTargetsIncludeDisplayNames(t *testing.T) {
 	assert.Equal(t, []string{"foo:namespace", "foo:secret"}, r.K8sResourceInfo.DisplayNames)
 }
 
+func TestStateToViewK8sRollback(t *testing.T) {
+	m := model.Manifest{Name: "foo"}.WithDeployTarget(model.K8sTarget{})
+	state := newState([]model.Manifest{m})
+	ms := state.ManifestTargets["foo"].State
+	buildStart := time.Now().Add(-time.Minute)
+	ms.AddCompletedBuild(model.BuildRecord{StartTime: buildStart, FinishTime: time.Now()})
+	ms.LastRollback = &store.Rollback{
+		BuildStartTime: buildStart,
+		Time:           time.Now(),
+		Reason:         "pod foo-pod crashed (restarts: 1)",
+		PodName:        "foo-pod",
+		SpanID:         "pod:foo:foo-pod",
+	}
+
+	v := completeProtoView(t, *state)
+	r, _ := findResource(m.Name, v)
+	require.NotNil(t, r.K8sResourceInfo.Rollback)
+	assert.Equal(t, "pod foo-pod crashed (restarts: 1)", r.K8sResourceInfo.Rollback.Reason)
+	assert.Equal(t, "foo-pod", r.K8sResourceInfo.Rollback.PodName)
+	assert.Equal(t, "pod:foo:foo-pod", r.K8sResourceInfo.Rollback.SpanID)
+
+	// Once we build again, the rollback is stale.
+	ms.AddCompletedBuild(model.BuildRecord{StartTime: time.Now(), FinishTime: time.Now()})
+	v = completeProtoView(t, *state)
+	r, _ = findResource(m.Name, v)
+	assert.Nil(t, r.K8sResourceInfo.Rollback)
+}
+
+func TestStateToViewK8sConflicts(t *testing.T) {
+	m := model.Manifest{Name: "foo"}.WithDeployTarget(model.K8sTarget{Name: "foo"})
+	state := newState([]model.Manifest{m})
+	conflicts := []v1alpha1.KubernetesApplyConflict{{
+		Object:  "Deployment/default/foo",
+		Manager: "kube-controller-manager",
+		Field:   ".spec.replicas",
+		Yielded: true,
+	}}
+	state.KubernetesApplys["foo"] = &v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Status:     v1alpha1.KubernetesApplyStatus{Conflicts: conflicts},
+	}
+
+	v := completeProtoView(t, *state)
+	r, _ := findResource(m.Name, v)
+	assert.Equal(t, conflicts, r.K8sResourceInfo.Conflicts)
+}
+
+func TestStateToViewK8sDiff(t *testing.T) {
+	m := model.Manifest{Name: "foo"}.WithDeployTarget(model.K8sTarget{Name: "foo"})
+	state := newState([]model.Manifest{m})
+	state.KubernetesApplys["foo"] = &v1alpha1.KubernetesApply{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Status: v1alpha1.KubernetesApplyStatus{
+			Diff:                     "~ Secret/default/foo\n",
+			DiffAwaitingConfirmation: "abc123",
+		},
+	}
+
+	v := completeProtoView(t, *state)
+	r, _ := findResource(m.Name, v)
+	assert.Equal(t, "~ Secret/default/foo\n", r.K8sResourceInfo.Diff)
+	assert.Equal(t, "abc123", r.K8sResourceInfo.DiffAwaitingConfirmation)
+}
+
 func TestStateToViewTiltfileLog(t *testing.T) {
 	es := newState([]model.Manifest{})
 	spanID := ctrltiltfile.SpanIDForLoadCount("(Tiltfile)", 1)
@@ -20,6 +20,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/validation"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/duration"
 	utilerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -107,6 +108,9 @@ type Client interface {
 	// than they were passed in) and with UUIDs from the Kube API
 	Upsert(ctx context.Context, entities []K8sEntity, timeout time.Duration, ssa SSAOptions) ([]K8sEntity, error)
 
+	// Computes what an Upsert of the entities would change, without changing anything.
+	Diff(ctx context.Context, entities []K8sEntity, fieldManager string) ([]EntityDiff, error)
+
 	// Delete all given entities, optionally waiting for them to be fully deleted.
 	//
 	// Currently ignores any "not found" errors, because that seems like the correct
@@ -116,6 +120,10 @@ type Client interface {
 	GetMetaByReference(ctx context.Context, ref v1.ObjectReference) (metav1.Object, error)
 	ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error)
 
+	// Lists the objects of a kind that match the label selector.
+	// An empty namespace lists the objects in all namespaces.
+	ListMetaBySelector(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace, selector labels.Selector) ([]metav1.Object, error)
+
 	// Streams the container logs
 	ContainerLogs(ctx context.Context, podID PodID, cName container.Name, n Namespace, startTime time.Time) (io.ReadCloser, error)
 
@@ -795,6 +803,14 @@ func (k *K8sClient) waitForDelete(ctx context.Context, list kube.ResourceList, d
 }
 
 func (k *K8sClient) ListMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace) ([]metav1.Object, error) {
+	return k.listMeta(ctx, gvk, ns, metav1.ListOptions{})
+}
+
+func (k *K8sClient) ListMetaBySelector(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace, selector labels.Selector) ([]metav1.Object, error) {
+	return k.listMeta(ctx, gvk, ns, metav1.ListOptions{LabelSelector: selector.String()})
+}
+
+func (k *K8sClient) listMeta(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace, opts metav1.ListOptions) ([]metav1.Object, error) {
 	mapping, err := k.forceDiscovery(ctx, gvk)
 	if err != nil {
 		return nil, err
@@ -804,9 +820,9 @@ func (k *K8sClient) ListMeta(ctx context.Context, gvk schema.GroupVersionKind, n
 	isRoot := mapping.Scope != nil && mapping.Scope.Name() == meta.RESTScopeNameRoot
 	var metaList *metav1.PartialObjectMetadataList
 	if isRoot {
-		metaList, err = k.metadata.Resource(gvr).List(ctx, metav1.ListOptions{})
+		metaList, err = k.metadata.Resource(gvr).List(ctx, opts)
 	} else {
-		metaList, err = k.metadata.Resource(gvr).Namespace(ns.String()).List(ctx, metav1.ListOptions{})
+		metaList, err = k.metadata.Resource(gvr).Namespace(ns.String()).List(ctx, opts)
 	}
 
 	if err != nil {
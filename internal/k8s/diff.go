@@ -0,0 +1,162 @@
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+	"sigs.k8s.io/yaml"
+)
+
+// The difference between an object we want to apply and the live object in the cluster.
+type EntityDiff struct {
+	Entity K8sEntity
+
+	// True if the object doesn't exist in the cluster yet.
+	Create bool
+
+	// A unified diff of the live object against the object after the apply,
+	// or empty if the apply wouldn't change anything.
+	Diff string
+}
+
+func (d EntityDiff) HasChanges() bool {
+	return d.Create || d.Diff != ""
+}
+
+// Computes what an apply would change, with a server-side dry-run apply.
+//
+// The server merges our object with the live object (including defaults,
+// mutating webhooks, and fields owned by other managers), so this is more
+// accurate than diffing against the YAML.
+func (k *K8sClient) Diff(ctx context.Context, entities []K8sEntity, fieldManager string) ([]EntityDiff, error) {
+	result := make([]EntityDiff, 0, len(entities))
+	for _, e := range entities {
+		resources, err := k.prepareUpdateList(ctx, e)
+		if err != nil {
+			return nil, errors.Wrapf(err, "diff %s", e.Name())
+		}
+
+		for _, info := range resources {
+			d, err := diffInfo(info, e, fieldManager)
+			if err != nil {
+				return nil, errors.Wrapf(err, "diff %s", e.Name())
+			}
+			result = append(result, d)
+		}
+	}
+	return result, nil
+}
+
+func diffInfo(info *resource.Info, e K8sEntity, fieldManager string) (EntityDiff, error) {
+	helper := resource.NewHelper(info.Client, info.Mapping)
+
+	live, err := helper.Get(info.Namespace, info.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return EntityDiff{}, err
+	}
+	if apierrors.IsNotFound(err) {
+		live = nil
+	}
+
+	data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, info.Object)
+	if err != nil {
+		return EntityDiff{}, err
+	}
+
+	force := true
+	merged, err := helper.
+		DryRun(true).
+		WithFieldManager(fieldManager).
+		Patch(info.Namespace, info.Name, types.ApplyPatchType, data, &metav1.PatchOptions{Force: &force})
+	if err != nil {
+		return EntityDiff{}, err
+	}
+
+	diff, err := renderObjectDiff(live, merged)
+	if err != nil {
+		return EntityDiff{}, err
+	}
+	return EntityDiff{Entity: e, Create: live == nil, Diff: diff}, nil
+}
+
+// Renders a unified diff between two versions of an object, ignoring the
+// fields that change on every write.
+func renderObjectDiff(before, after runtime.Object) (string, error) {
+	beforeYAML, err := diffableYAML(before)
+	if err != nil {
+		return "", err
+	}
+	afterYAML, err := diffableYAML(after)
+	if err != nil {
+		return "", err
+	}
+	if beforeYAML == afterYAML {
+		return "", nil
+	}
+
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(beforeYAML),
+		B:        difflib.SplitLines(afterYAML),
+		FromFile: "live",
+		ToFile:   "merged",
+		Context:  3,
+	})
+}
+
+func diffableYAML(obj runtime.Object) (string, error) {
+	if obj == nil {
+		return "", nil
+	}
+
+	m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj.DeepCopyObject())
+	if err != nil {
+		return "", err
+	}
+
+	delete(m, "status")
+	if metadata, ok := m["metadata"].(map[string]interface{}); ok {
+		for _, f := range []string{"managedFields", "resourceVersion", "generation", "uid", "creationTimestamp"} {
+			delete(metadata, f)
+		}
+	}
+
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Formats the diffs for display, one object at a time.
+func FormatDiffs(diffs []EntityDiff) string {
+	var sb strings.Builder
+	names := UniqueNames(entitiesOfDiffs(diffs), 2)
+	for i, d := range diffs {
+		switch {
+		case d.Create:
+			fmt.Fprintf(&sb, "%s (new):\n%s", names[i], d.Diff)
+		case d.Diff != "":
+			fmt.Fprintf(&sb, "%s:\n%s", names[i], d.Diff)
+		default:
+			fmt.Fprintf(&sb, "%s: no changes\n", names[i])
+		}
+	}
+	return sb.String()
+}
+
+func entitiesOfDiffs(diffs []EntityDiff) []K8sEntity {
+	result := make([]K8sEntity, len(diffs))
+	for i, d := range diffs {
+		result[i] = d.Entity
+	}
+	return result
+}
@@ -0,0 +1,50 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/internal/k8s/testyaml"
+)
+
+func TestRenderObjectDiff(t *testing.T) {
+	entities, err := ParseYAMLFromString(testyaml.SanchoYAML)
+	require.NoError(t, err)
+	live := entities[0]
+	live.SetUID("abc")
+
+	merged := live.DeepCopy()
+	merged.SetUID("def")
+	merged.Labels()["app"] = "sancho-2"
+
+	diff, err := renderObjectDiff(live.Obj, merged.Obj)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "--- live\n+++ merged\n")
+	assert.Contains(t, diff, "\n-    app: sancho\n")
+	assert.Contains(t, diff, "\n+    app: sancho-2\n")
+	assert.NotContains(t, diff, "uid")
+
+	diff, err = renderObjectDiff(live.Obj, live.Obj)
+	require.NoError(t, err)
+	assert.Equal(t, "", diff)
+
+	diff, err = renderObjectDiff(nil, merged.Obj)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "+kind: Deployment\n")
+}
+
+func TestFormatDiffs(t *testing.T) {
+	entities, err := ParseYAMLFromString(testyaml.SanchoYAML + "\n---\n" + testyaml.PodDisruptionBudgetYAML)
+	require.NoError(t, err)
+
+	out := FormatDiffs([]EntityDiff{
+		{Entity: entities[0], Diff: "-a\n+b\n"},
+		{Entity: entities[1], Create: true, Diff: "+c\n"},
+	})
+	assert.Equal(t, "sancho:deployment:\n-a\n+b\ninfra-kafka-zookeeper:poddisruptionbudget (new):\n+c\n", out)
+
+	out = FormatDiffs([]EntityDiff{{Entity: entities[0]}})
+	assert.Equal(t, "sancho:deployment: no changes\n", out)
+}
@@ -159,6 +159,19 @@ func (e K8sEntity) Clean() {
 	}
 }
 
+// Removes the metadata that the server sets on an object,
+// so that we can re-apply the object as if it were new YAML.
+func (e K8sEntity) CleanServerMetadata() {
+	e.Clean()
+
+	m := e.Meta()
+	m.SetUID("")
+	m.SetResourceVersion("")
+	m.SetGeneration(0)
+	m.SetCreationTimestamp(metav1.Time{})
+	m.SetSelfLink("")
+}
+
 func (e K8sEntity) SetUID(uid string) {
 	e.Meta().SetUID(types.UID(uid))
 }
@@ -254,6 +254,41 @@ spec:
 
 }
 
+func TestCleanServerMetadata(t *testing.T) {
+	yaml := `apiVersion: v1
+kind: ConfigMap
+metadata:
+  creationTimestamp: "2020-07-07T14:50:17Z"
+  generation: 3
+  labels:
+    app.kubernetes.io/managed-by: tilt
+  name: config
+  namespace: default
+  resourceVersion: "617"
+  selfLink: /api/v1/namespaces/default/configmaps/config
+  uid: fa9710ff-7b19-499c-b0f9-faedd1c84969
+data:
+  foo: bar
+`
+	entities := mustParseYAML(t, yaml)
+	entities[0].CleanServerMetadata()
+
+	result, err := SerializeSpecYAML(entities)
+	require.NoError(t, err)
+
+	expected := `apiVersion: v1
+data:
+  foo: bar
+kind: ConfigMap
+metadata:
+  labels:
+    app.kubernetes.io/managed-by: tilt
+  name: config
+  namespace: default
+`
+	assert.Equal(t, expected, result)
+}
+
 func entitiesWithKinds(kinds []string) []K8sEntity {
 	entities := make([]K8sEntity, len(kinds))
 	for i, k := range kinds {
@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
@@ -35,6 +36,10 @@ func (ec *explodingClient) Upsert(ctx context.Context, entities []K8sEntity, tim
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
 
+func (ec *explodingClient) Diff(ctx context.Context, entities []K8sEntity, fieldManager string) ([]EntityDiff, error) {
+	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
+}
+
 func (ec *explodingClient) Delete(ctx context.Context, entities []K8sEntity, wait time.Duration) error {
 	return errors.Wrap(ec.err, "could not set up kubernetes client")
 }
@@ -47,6 +52,10 @@ func (ec *explodingClient) ListMeta(ctx context.Context, gvk schema.GroupVersion
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
 
+func (ec *explodingClient) ListMetaBySelector(ctx context.Context, gvk schema.GroupVersionKind, ns Namespace, selector labels.Selector) ([]metav1.Object, error) {
+	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
+}
+
 func (ec *explodingClient) PodsWithImage(ctx context.Context, image reference.NamedTagged, n Namespace, lp []model.LabelPair) ([]v1.Pod, error) {
 	return nil, errors.Wrap(ec.err, "could not set up kubernetes client")
 }
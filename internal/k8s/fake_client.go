@@ -16,6 +16,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/version"
@@ -77,6 +78,17 @@ type FakeK8sClient struct {
 	UpsertResult     []K8sEntity
 	LastUpsertResult []K8sEntity
 	UpsertTimeout    time.Duration
+	UpsertSSA        SSAOptions
+
+	// Fields owned by other managers. A non-forced server-side apply
+	// fails if any of the entities still sets one of these fields.
+	FieldManagerConflicts []FieldConflict
+
+	// Diffs to return from Diff, keyed by entity name.
+	// Entities without a diff have no changes.
+	Diffs     map[string]string
+	DiffError error
+	DiffCount int
 
 	Runtime    container.Runtime
 	Registry   *v1alpha1.RegistryHosting
@@ -400,10 +412,29 @@ func (c *FakeK8sClient) Upsert(_ context.Context, entities []K8sEntity, timeout
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.UpsertSSA = ssa
 	if c.UpsertError != nil {
 		return nil, c.UpsertError
 	}
 
+	if ssa.Enabled && !ssa.Force {
+		var conflicts []FieldConflict
+		for _, conflict := range c.FieldManagerConflicts {
+			for _, e := range entities {
+				has, _ := HasFieldPath(e, conflict.Field)
+				if has && conflict.MatchesEntity(e) {
+					conflicts = append(conflicts, conflict)
+				}
+			}
+		}
+		if len(conflicts) > 0 {
+			return nil, &FieldConflictError{
+				Conflicts: conflicts,
+				Err:       fmt.Errorf("Apply failed with %d conflicts", len(conflicts)),
+			}
+		}
+	}
+
 	var result []K8sEntity
 	if c.UpsertResult != nil {
 		result = c.UpsertResult
@@ -427,6 +458,22 @@ func (c *FakeK8sClient) Upsert(_ context.Context, entities []K8sEntity, timeout
 	return result, nil
 }
 
+func (c *FakeK8sClient) Diff(_ context.Context, entities []K8sEntity, fieldManager string) ([]EntityDiff, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.DiffCount++
+	if c.DiffError != nil {
+		return nil, c.DiffError
+	}
+
+	result := make([]EntityDiff, 0, len(entities))
+	for _, e := range entities {
+		result = append(result, EntityDiff{Entity: e, Diff: c.Diffs[e.Name()]})
+	}
+	return result, nil
+}
+
 func (c *FakeK8sClient) Delete(_ context.Context, entities []K8sEntity, wait time.Duration) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -498,6 +545,27 @@ func (c *FakeK8sClient) ListMeta(_ context.Context, gvk schema.GroupVersionKind,
 	return result, nil
 }
 
+func (c *FakeK8sClient) ListMetaBySelector(_ context.Context, gvk schema.GroupVersionKind, ns Namespace, selector labels.Selector) ([]metav1.Object, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make([]metav1.Object, 0)
+	for _, uid := range c.currentVersions {
+		entity := c.entities[uid]
+		if ns != "" && entity.Namespace().String() != ns.String() {
+			continue
+		}
+		if entity.GVK() != gvk {
+			continue
+		}
+		if !selector.Matches(labels.Set(entity.Labels())) {
+			continue
+		}
+		result = append(result, entity.Meta())
+	}
+	return result, nil
+}
+
 func (c *FakeK8sClient) SetLogsForPodContainer(pID PodID, cName container.Name, logs string) {
 
 	c.SetLogReaderForPodContainer(pID, cName, strings.NewReader(logs))
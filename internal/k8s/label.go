@@ -221,3 +221,17 @@ func (e K8sEntity) MatchesMetadataLabels(labels map[string]string) (bool, error)
 
 	return true, nil
 }
+
+// InjectApplySetLabel adds the applyset label to the object itself,
+// but not to its pod templates, because the pods aren't part of the applyset.
+func InjectApplySetLabel(entity K8sEntity, id string) K8sEntity {
+	entity = entity.DeepCopy()
+	meta := entity.Meta()
+	labels := meta.GetLabels()
+	if labels == nil {
+		labels = make(map[string]string, 1)
+	}
+	labels[ApplySetLabel] = id
+	meta.SetLabels(labels)
+	return entity
+}
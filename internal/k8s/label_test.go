@@ -5,6 +5,7 @@ import (
 
 	extbeta1 "k8s.io/api/extensions/v1beta1"
 	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/util/validation"
 
 	"k8s.io/api/apps/v1beta1"
 
@@ -80,6 +81,21 @@ func TestInjectLabelDeployment(t *testing.T) {
 	})
 }
 
+func TestInjectApplySetLabel(t *testing.T) {
+	entity := parseOneEntity(t, testyaml.SanchoYAML)
+	id := ApplySetID("/home/me/project/Tiltfile", "my-resource:with/odd chars")
+	assert.Empty(t, validation.IsValidLabelValue(id))
+	assert.NotEqual(t, id, ApplySetID("/home/you/project/Tiltfile", "my-resource:with/odd chars"))
+
+	newEntity := InjectApplySetLabel(entity, id)
+	d, ok := newEntity.Obj.(*appsv1.Deployment)
+	require.True(t, ok)
+
+	assert.Equal(t, map[string]string{"app": "sancho", ApplySetLabel: id}, d.Labels)
+	assert.Equal(t, map[string]string{"app": "sancho"}, d.Spec.Template.Labels)
+	assert.Equal(t, map[string]string{"app": "sancho"}, entity.Labels())
+}
+
 func TestInjectLabelDeploymentMakeSelectorMatchOnConflict(t *testing.T) {
 	entity := parseOneEntity(t, testyaml.SanchoYAML)
 	lps := []model.LabelPair{
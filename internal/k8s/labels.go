@@ -1,6 +1,9 @@
 package k8s
 
 import (
+	"crypto/sha256"
+	"fmt"
+
 	"k8s.io/apimachinery/pkg/labels"
 
 	"github.com/tilt-dev/tilt/pkg/model"
@@ -12,6 +15,26 @@ const ManagedByValue = "tilt"
 
 const ManifestNameLabel = "tilt-manifest"
 
+// ApplySetLabel marks the objects that Tilt applied for a KubernetesApply,
+// so that Tilt can find and prune them when they're removed from the apply.
+//
+// The value is the ApplySetID of the KubernetesApply.
+const ApplySetLabel = "tilt.dev/applyset"
+
+// PruneAnnotation set to "disabled" on an object stops Tilt from
+// pruning it when it's removed from the apply.
+const PruneAnnotation = "tilt.dev/prune"
+const PruneDisabled = "disabled"
+
+// ApplySetID returns the value of the ApplySetLabel for a KubernetesApply.
+//
+// Other Tilt sessions on the same cluster may have resources with the same
+// name, so the ID is scoped to the Tiltfile too. Object names and paths may
+// not be valid label values, so we hash them.
+func ApplySetID(tiltfilePath string, name string) string {
+	return fmt.Sprintf("ka-%x", sha256.Sum256([]byte(tiltfilePath+"\x00"+name)))[:35]
+}
+
 func TiltManagedByLabel() model.LabelPair {
 	return model.LabelPair{
 		Key:   ManagedByLabel,
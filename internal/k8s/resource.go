@@ -108,6 +108,12 @@ func (c *resourceClient) Apply(target kube.ResourceList, ssa SSAOptions) (*kube.
 	o.SetObjects(target)
 	err = o.Run()
 	if err != nil {
+		if ssa.Enabled && !ssa.Force && maybeFieldConflictError(err) {
+			conflicts, conflictErr := fieldConflicts(target, ssa)
+			if conflictErr == nil && len(conflicts) > 0 {
+				return nil, &FieldConflictError{Conflicts: conflicts, Err: err}
+			}
+		}
 		return nil, err
 	}
 	return &kube.Result{Updated: target}, nil
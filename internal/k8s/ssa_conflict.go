@@ -0,0 +1,337 @@
+package k8s
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/cli-runtime/pkg/resource"
+)
+
+// A field owned by another field manager, which blocked a non-forced server-side apply.
+type FieldConflict struct {
+	Kind      string
+	Namespace string
+	Name      string
+
+	// The field manager that owns the field.
+	Manager string
+
+	// The path of the field, in the format of the server-side apply
+	// field paths (e.g., .spec.containers[name="app"].image).
+	Field string
+}
+
+// The object with the conflict, in the form kind/namespace/name.
+func (c FieldConflict) Object() string {
+	if c.Namespace == "" {
+		return fmt.Sprintf("%s/%s", strings.ToLower(c.Kind), c.Name)
+	}
+	return fmt.Sprintf("%s/%s/%s", strings.ToLower(c.Kind), c.Namespace, c.Name)
+}
+
+// Whether the conflict is on this entity.
+//
+// Entities don't always specify a namespace, so we treat an empty namespace as a match.
+func (c FieldConflict) MatchesEntity(e K8sEntity) bool {
+	ns := e.Namespace().String()
+	return e.GVK().Kind == c.Kind && e.Name() == c.Name && (ns == "" || ns == c.Namespace)
+}
+
+// FieldConflictError is returned by Upsert when a server-side apply
+// without force fails because other managers own some of the fields.
+type FieldConflictError struct {
+	Conflicts []FieldConflict
+	Err       error
+}
+
+func (e *FieldConflictError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *FieldConflictError) Unwrap() error {
+	return e.Err
+}
+
+// kubectl wraps the conflict errors from the server in a string,
+// so we lose the structured causes.
+func maybeFieldConflictError(err error) bool {
+	return apierrors.IsConflict(err) || strings.Contains(err.Error(), "Apply failed with")
+}
+
+// Re-runs the server-side apply as a dry-run to get the conflicts in structured form.
+//
+// Returns nil if none of the resources conflict.
+func fieldConflicts(target []*resource.Info, ssa SSAOptions) ([]FieldConflict, error) {
+	var result []FieldConflict
+	force := false
+	for _, info := range target {
+		data, err := runtime.Encode(unstructured.UnstructuredJSONScheme, info.Object)
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = resource.NewHelper(info.Client, info.Mapping).
+			DryRun(true).
+			WithFieldManager(ssa.FieldManager).
+			Patch(info.Namespace, info.Name, types.ApplyPatchType, data, &metav1.PatchOptions{Force: &force})
+		if err == nil {
+			continue
+		}
+
+		status, ok := err.(apierrors.APIStatus)
+		if !ok || !apierrors.IsConflict(err) || status.Status().Details == nil {
+			return nil, err
+		}
+
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type != metav1.CauseTypeFieldManagerConflict {
+				continue
+			}
+			result = append(result, FieldConflict{
+				Kind:      info.Mapping.GroupVersionKind.Kind,
+				Namespace: info.Namespace,
+				Name:      info.Name,
+				Manager:   managerFromConflictMessage(cause.Message),
+				Field:     cause.Field,
+			})
+		}
+	}
+	return result, nil
+}
+
+// The server formats the cause message as:
+//
+//	conflict with "manager"
+//	conflict with "manager" using apps/v1
+func managerFromConflictMessage(msg string) string {
+	rest := strings.TrimPrefix(msg, "conflict with ")
+	quoted, err := strconv.QuotedPrefix(rest)
+	if err != nil {
+		return rest
+	}
+	manager, err := strconv.Unquote(quoted)
+	if err != nil {
+		return rest
+	}
+	return manager
+}
+
+// Returns a copy of the entity without the field at the given path,
+// and whether the field existed.
+func RemoveFieldPath(e K8sEntity, path string) (K8sEntity, bool, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(e.Obj.DeepCopyObject())
+	if err != nil {
+		return e, false, err
+	}
+
+	removed, err := walkFieldPath(obj, path, true, nil)
+	if err != nil || !removed {
+		return e, false, err
+	}
+	return NewK8sEntity(&unstructured.Unstructured{Object: obj}), true, nil
+}
+
+// Whether the entity has a field at the given path.
+func HasFieldPath(e K8sEntity, path string) (bool, error) {
+	obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(e.Obj.DeepCopyObject())
+	if err != nil {
+		return false, err
+	}
+	return walkFieldPath(obj, path, false, nil)
+}
+
+// Walks a field path in the string format of server-side apply, where each
+// element is one of:
+//
+//	.name                a field of a map
+//	[key="value",...]    the item of an associative list with these keys
+//	[="value"]           the item of a set with this value
+//	[0]                  the item of a list at this index
+//
+// Field names may contain dots (e.g., labels), so we match them against the object.
+//
+// If remove is true, removes the last element. The set func replaces
+// the node in its parent, which we need when removing from a list.
+func walkFieldPath(node interface{}, path string, remove bool, set func(interface{})) (bool, error) {
+	switch {
+	case strings.HasPrefix(path, "."):
+		m, ok := node.(map[string]interface{})
+		if !ok {
+			return false, nil
+		}
+		key, rest, ok := matchFieldName(m, path[1:])
+		if !ok {
+			return false, nil
+		}
+		if rest == "" {
+			if remove {
+				delete(m, key)
+			}
+			return true, nil
+		}
+		return walkFieldPath(m[key], rest, remove, func(v interface{}) { m[key] = v })
+
+	case strings.HasPrefix(path, "["):
+		list, ok := node.([]interface{})
+		if !ok {
+			return false, nil
+		}
+		end := selectorEnd(path)
+		if end == -1 {
+			return false, fmt.Errorf("malformed field path: %s", path)
+		}
+		selector, rest := path[1:end], path[end+1:]
+		i, err := findListItem(list, selector)
+		if err != nil {
+			return false, fmt.Errorf("malformed field path: %s: %v", path, err)
+		}
+		if i == -1 {
+			return false, nil
+		}
+		if rest == "" {
+			if remove && set != nil {
+				set(append(list[:i:i], list[i+1:]...))
+			}
+			return true, nil
+		}
+		return walkFieldPath(list[i], rest, remove, func(v interface{}) { list[i] = v })
+	}
+	return false, fmt.Errorf("malformed field path: %s", path)
+}
+
+// Finds the longest key of the map that's a prefix of the path, up to
+// the start of the next element.
+func matchFieldName(m map[string]interface{}, path string) (string, string, bool) {
+	best := -1
+	for key := range m {
+		if !strings.HasPrefix(path, key) || len(key) <= best {
+			continue
+		}
+		rest := path[len(key):]
+		if rest == "" || rest[0] == '.' || rest[0] == '[' {
+			best = len(key)
+		}
+	}
+	if best == -1 {
+		return "", "", false
+	}
+	return path[:best], path[best:], true
+}
+
+// The index of the bracket that closes the selector at the start of the path,
+// skipping over brackets in quoted strings.
+func selectorEnd(path string) int {
+	inString := false
+	for i := 1; i < len(path); i++ {
+		switch path[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case ']':
+			if !inString {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Returns the index of the item that matches the selector, or -1 if none match.
+func findListItem(list []interface{}, selector string) (int, error) {
+	if index, err := strconv.Atoi(selector); err == nil {
+		if index < 0 || index >= len(list) {
+			return -1, nil
+		}
+		return index, nil
+	}
+
+	if strings.HasPrefix(selector, "=") {
+		var want interface{}
+		if err := json.Unmarshal([]byte(selector[1:]), &want); err != nil {
+			return -1, err
+		}
+		for i, item := range list {
+			if jsonEqual(item, want) {
+				return i, nil
+			}
+		}
+		return -1, nil
+	}
+
+	keys := map[string]interface{}{}
+	for _, pair := range splitSelector(selector) {
+		name, v, ok := strings.Cut(pair, "=")
+		if !ok {
+			return -1, fmt.Errorf("expected key=value, got %q", pair)
+		}
+		var want interface{}
+		if err := json.Unmarshal([]byte(v), &want); err != nil {
+			return -1, err
+		}
+		keys[name] = want
+	}
+
+	for i, item := range list {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		match := true
+		for name, want := range keys {
+			if !jsonEqual(m[name], want) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return i, nil
+		}
+	}
+	return -1, nil
+}
+
+// Splits key=value pairs on the commas outside quoted strings.
+func splitSelector(selector string) []string {
+	var result []string
+	start := 0
+	inString := false
+	for i := 0; i < len(selector); i++ {
+		switch selector[i] {
+		case '\\':
+			if inString {
+				i++
+			}
+		case '"':
+			inString = !inString
+		case ',':
+			if !inString {
+				result = append(result, selector[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(result, selector[start:])
+}
+
+// Compares values from different decoders (e.g., int64 vs float64).
+func jsonEqual(a, b interface{}) bool {
+	aJSON, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	bJSON, err := json.Marshal(b)
+	if err != nil {
+		return false
+	}
+	return string(aJSON) == string(bJSON)
+}
@@ -0,0 +1,100 @@
+package k8s
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const ssaConflictYAML = `
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  labels:
+    app.kubernetes.io/name: web
+    app: web
+  finalizers:
+  - example.com/a
+  - example.com/b
+spec:
+  ports:
+  - port: 80
+    protocol: TCP
+    targetPort: 8080
+  - port: 443
+    protocol: TCP
+    targetPort: 8443
+`
+
+func TestRemoveFieldPath(t *testing.T) {
+	type tc struct {
+		path     string
+		removed  string
+		retained []string
+	}
+	tcs := []tc{
+		{".metadata.labels.app", "app: web", []string{"app.kubernetes.io/name: web"}},
+		{".metadata.labels.app.kubernetes.io/name", "app.kubernetes.io/name: web", []string{"app: web"}},
+		{`.metadata.finalizers[="example.com/a"]`, "example.com/a", []string{"example.com/b"}},
+		{`.spec.ports[port=80,protocol="TCP"].targetPort`, "targetPort: 8080", []string{"port: 80", "targetPort: 8443"}},
+		{`.spec.ports[port=443,protocol="TCP"]`, "port: 443", []string{"port: 80", "targetPort: 8080"}},
+		{`.spec.ports[1]`, "port: 443", []string{"port: 80"}},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.path, func(t *testing.T) {
+			entities, err := ParseYAMLFromString(ssaConflictYAML)
+			require.NoError(t, err)
+
+			result, ok, err := RemoveFieldPath(entities[0], tc.path)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			yaml, err := SerializeSpecYAML([]K8sEntity{result})
+			require.NoError(t, err)
+			assert.NotContains(t, yaml, tc.removed)
+			for _, r := range tc.retained {
+				assert.Contains(t, yaml, r)
+			}
+
+			// The original is unchanged.
+			has, err := HasFieldPath(entities[0], tc.path)
+			require.NoError(t, err)
+			assert.True(t, has)
+		})
+	}
+}
+
+func TestRemoveFieldPathMissing(t *testing.T) {
+	entities, err := ParseYAMLFromString(ssaConflictYAML)
+	require.NoError(t, err)
+
+	for _, path := range []string{
+		".spec.clusterIP",
+		".metadata.labels.app.kubernetes",
+		`.spec.ports[port=8080,protocol="TCP"]`,
+		`.metadata.finalizers[="example.com/c"]`,
+		`.spec.ports[5]`,
+	} {
+		_, ok, err := RemoveFieldPath(entities[0], path)
+		require.NoError(t, err)
+		assert.False(t, ok, path)
+	}
+
+	_, _, err = RemoveFieldPath(entities[0], `.spec.ports[port=80`)
+	assert.Error(t, err)
+}
+
+func TestManagerFromConflictMessage(t *testing.T) {
+	assert.Equal(t, "kubectl-edit", managerFromConflictMessage(`conflict with "kubectl-edit" using apps/v1`))
+	assert.Equal(t, "hpa-controller", managerFromConflictMessage(`conflict with "hpa-controller"`))
+	assert.Equal(t, "something else", managerFromConflictMessage(`something else`))
+}
+
+func TestFieldConflictObject(t *testing.T) {
+	assert.Equal(t, "deployment/default/web",
+		FieldConflict{Kind: "Deployment", Namespace: "default", Name: "web"}.Object())
+	assert.Equal(t, "namespace/web",
+		FieldConflict{Kind: "Namespace", Name: "web"}.Object())
+}
@@ -0,0 +1,248 @@
+package logparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Parses a single line, without its trailing newline.
+//
+// Returns false if the line isn't in the expected format.
+type parseFunc func(line []byte) (entry, bool)
+
+var parseFuncs = map[v1alpha1.LogParser]parseFunc{
+	v1alpha1.LogParserJSON:   parseJSON,
+	v1alpha1.LogParserLogfmt: parseLogfmt,
+}
+
+// Keys that commonly hold the message, level, and timestamp, in order of preference.
+var messageKeys = []string{"msg", "message"}
+var levelKeys = []string{"level", "lvl", "severity"}
+var timeKeys = []string{"time", "ts", "timestamp"}
+
+// Fields that Tilt uses for its own logs. Applications can't set these,
+// because they change how the logs are displayed.
+var reservedFields = map[string]bool{
+	logger.FieldNameProgressID:        true,
+	logger.FieldNameBuildEvent:        true,
+	logger.FieldNameProgressMustPrint: true,
+}
+
+// A parsed log line.
+type entry struct {
+	level   logger.Level
+	message string
+
+	// All the keys in the line except the message.
+	fields logger.Fields
+
+	// The original line, for lines without a message.
+	raw string
+}
+
+func newEntry(raw []byte, values map[string]string) entry {
+	e := entry{
+		level:  logger.InfoLvl,
+		fields: logger.Fields{},
+		raw:    string(raw),
+	}
+	for k, v := range values {
+		if !reservedFields[k] {
+			e.fields[k] = v
+		}
+	}
+
+	if k, ok := firstKey(values, messageKeys); ok {
+		e.message = values[k]
+		delete(e.fields, k)
+	}
+	if k, ok := firstKey(values, levelKeys); ok {
+		e.level = levelFromString(values[k])
+	}
+	return e
+}
+
+// The text to log: the message, followed by the other fields in logfmt,
+// so that they're still visible (and greppable) in the log.
+//
+// Level and time are left out, since Tilt shows them itself.
+func (e entry) text() string {
+	if e.message == "" {
+		return e.raw
+	}
+
+	hidden := map[string]bool{}
+	for _, keys := range [][]string{levelKeys, timeKeys} {
+		if k, ok := firstKey(e.fields, keys); ok {
+			hidden[k] = true
+		}
+	}
+
+	keys := make([]string, 0, len(e.fields))
+	for k := range e.fields {
+		if !hidden[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	sb := strings.Builder{}
+	sb.WriteString(e.message)
+	for _, k := range keys {
+		sb.WriteString(" ")
+		sb.WriteString(k)
+		sb.WriteString("=")
+		sb.WriteString(quoteLogfmt(e.fields[k]))
+	}
+	return sb.String()
+}
+
+func firstKey(values map[string]string, keys []string) (string, bool) {
+	for _, k := range keys {
+		if _, ok := values[k]; ok {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// Maps common level names (and the numeric levels used by bunyan and pino)
+// to Tilt log levels.
+//
+// Debug and trace logs are logged at info level. Tilt's debug level is for
+// Tilt's own logs, and would hide them unless Tilt was started with --debug.
+func levelFromString(s string) logger.Level {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n >= 50 {
+			return logger.ErrorLvl
+		} else if n >= 40 {
+			return logger.WarnLvl
+		}
+		return logger.InfoLvl
+	}
+
+	switch strings.ToLower(s) {
+	case "warn", "warning":
+		return logger.WarnLvl
+	case "error", "err", "fatal", "panic", "critical", "crit", "alert", "emerg", "emergency":
+		return logger.ErrorLvl
+	}
+	return logger.InfoLvl
+}
+
+// Parses a line that contains a single JSON object.
+//
+// Nested values are stored as JSON.
+func parseJSON(line []byte) (entry, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return entry{}, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(line))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	err := decoder.Decode(&obj)
+	if err != nil {
+		return entry{}, false
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		// Trailing text after the object.
+		return entry{}, false
+	}
+
+	values := make(map[string]string, len(obj))
+	for k, v := range obj {
+		switch v := v.(type) {
+		case string:
+			values[k] = v
+		case json.Number:
+			values[k] = v.String()
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return entry{}, false
+			}
+			values[k] = string(b)
+		}
+	}
+	return newEntry(line, values), true
+}
+
+// Parses a line of logfmt key/value pairs, like
+//
+//	level=info msg="hello world" request_id=abc
+//
+// Every word must be a key=value pair, so that plain text isn't mistaken for logfmt.
+func parseLogfmt(line []byte) (entry, bool) {
+	s := string(bytes.TrimSpace(line))
+	if s == "" {
+		return entry{}, false
+	}
+
+	values := map[string]string{}
+	for s != "" {
+		eq := strings.IndexAny(s, "= \"")
+		if eq <= 0 || s[eq] != '=' {
+			return entry{}, false
+		}
+		key := s[:eq]
+		s = s[eq+1:]
+
+		var value string
+		if strings.HasPrefix(s, "\"") {
+			end := closingQuote(s)
+			if end == -1 {
+				return entry{}, false
+			}
+			unquoted, err := strconv.Unquote(s[:end+1])
+			if err != nil {
+				return entry{}, false
+			}
+			value = unquoted
+			s = s[end+1:]
+			if s != "" && s[0] != ' ' {
+				return entry{}, false
+			}
+		} else {
+			end := strings.IndexByte(s, ' ')
+			if end == -1 {
+				end = len(s)
+			}
+			value = s[:end]
+			s = s[end:]
+		}
+
+		values[key] = value
+		s = strings.TrimLeft(s, " ")
+	}
+	return newEntry(line, values), true
+}
+
+// Returns the index of the quote that closes the quoted string at the start of s,
+// or -1 if there isn't one.
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+func quoteLogfmt(s string) string {
+	if s == "" || strings.ContainsAny(s, " =\"\\\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
@@ -0,0 +1,86 @@
+package logparser
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+// Lines longer than this are logged as-is, so that a process that never
+// writes a newline can't make us buffer forever.
+const maxLineBytes = 64 * 1024
+
+// Writer parses each line written to it, and logs the level, message,
+// and fields that it finds.
+//
+// Lines that can't be parsed are logged as-is, at info level.
+type Writer struct {
+	l     logger.Logger
+	parse parseFunc
+
+	mu  sync.Mutex
+	buf []byte
+}
+
+var _ io.Writer = &Writer{}
+
+// Creates a Writer that logs to l. If parser is empty, output is logged as-is.
+func NewWriter(l logger.Logger, parser v1alpha1.LogParser) *Writer {
+	return &Writer{
+		l:     l,
+		parse: parseFuncs[parser],
+	}
+}
+
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.parse == nil {
+		w.l.Write(logger.InfoLvl, p)
+		return len(p), nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.buf = append(w.buf, p...)
+	start := 0
+	for {
+		i := bytes.IndexByte(w.buf[start:], '\n')
+		if i == -1 {
+			break
+		}
+		end := start + i + 1
+		w.writeLine(w.buf[start:end])
+		start = end
+	}
+
+	if len(w.buf)-start > maxLineBytes {
+		w.l.Write(logger.InfoLvl, w.buf[start:])
+		start = len(w.buf)
+	}
+
+	w.buf = append(w.buf[:0], w.buf[start:]...)
+	return len(p), nil
+}
+
+// Logs any incomplete line left in the buffer.
+func (w *Writer) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = w.buf[:0]
+	}
+}
+
+func (w *Writer) writeLine(line []byte) {
+	e, ok := w.parse(bytes.TrimRight(line, "\r\n"))
+	if !ok {
+		w.l.Write(logger.InfoLvl, line)
+		return
+	}
+	w.l.WithFields(e.fields).Write(e.level, []byte(e.text()+"\n"))
+}
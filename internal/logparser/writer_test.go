@@ -0,0 +1,159 @@
+package logparser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+	"github.com/tilt-dev/tilt/pkg/logger"
+)
+
+type logLine struct {
+	level  logger.Level
+	fields logger.Fields
+	text   string
+}
+
+func newTestWriter(parser v1alpha1.LogParser) (*Writer, *[]logLine) {
+	lines := &[]logLine{}
+	l := logger.NewFuncLogger(false, logger.DebugLvl, func(level logger.Level, fields logger.Fields, b []byte) error {
+		*lines = append(*lines, logLine{level: level, fields: fields, text: string(b)})
+		return nil
+	})
+	return NewWriter(l, parser), lines
+}
+
+func TestWriterNoParser(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserNone)
+	_, _ = w.Write([]byte(`{"level":"error","msg":"hi"`))
+
+	assert.Equal(t, []logLine{{level: logger.InfoLvl, text: `{"level":"error","msg":"hi"`}}, *lines)
+}
+
+func TestWriterJSON(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserJSON)
+	_, _ = w.Write([]byte(`{"level":"warn","msg":"slow request","request_id":"abc","ms":1200,"time":"2026-01-01T00:00:00Z"}` + "\n"))
+
+	assert.Equal(t, []logLine{{
+		level: logger.WarnLvl,
+		fields: logger.Fields{
+			"level":      "warn",
+			"request_id": "abc",
+			"ms":         "1200",
+			"time":       "2026-01-01T00:00:00Z",
+		},
+		text: "slow request ms=1200 request_id=abc\n",
+	}}, *lines)
+}
+
+func TestWriterJSONNested(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserJSON)
+	_, _ = w.Write([]byte(`{"message":"hi","user":{"id":1},"ok":true}` + "\n"))
+
+	assert.Equal(t, []logLine{{
+		level:  logger.InfoLvl,
+		fields: logger.Fields{"user": `{"id":1}`, "ok": "true"},
+		text:   `hi ok=true user="{\"id\":1}"` + "\n",
+	}}, *lines)
+}
+
+func TestWriterLogfmt(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserLogfmt)
+	_, _ = w.Write([]byte(`level=error msg="connection refused" host=db:5432` + "\n"))
+
+	assert.Equal(t, []logLine{{
+		level:  logger.ErrorLvl,
+		fields: logger.Fields{"level": "error", "host": "db:5432"},
+		text:   "connection refused host=db:5432\n",
+	}}, *lines)
+}
+
+func TestWriterUnparseableLines(t *testing.T) {
+	for _, tc := range []struct {
+		parser v1alpha1.LogParser
+		line   string
+	}{
+		{v1alpha1.LogParserJSON, "Listening on :8080\n"},
+		{v1alpha1.LogParserJSON, `{"msg":"hi"} trailing` + "\n"},
+		{v1alpha1.LogParserJSON, "[1, 2]\n"},
+		{v1alpha1.LogParserLogfmt, "Listening on :8080\n"},
+		{v1alpha1.LogParserLogfmt, `msg="unterminated` + "\n"},
+		{v1alpha1.LogParserLogfmt, "=value\n"},
+	} {
+		t.Run(string(tc.parser)+" "+tc.line, func(t *testing.T) {
+			w, lines := newTestWriter(tc.parser)
+			_, _ = w.Write([]byte(tc.line))
+			assert.Equal(t, []logLine{{level: logger.InfoLvl, text: tc.line}}, *lines)
+		})
+	}
+}
+
+func TestWriterNoMessage(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserLogfmt)
+	_, _ = w.Write([]byte("level=warn disk=90%\n"))
+
+	assert.Equal(t, []logLine{{
+		level:  logger.WarnLvl,
+		fields: logger.Fields{"level": "warn", "disk": "90%"},
+		text:   "level=warn disk=90%\n",
+	}}, *lines)
+}
+
+func TestWriterReservedFields(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserJSON)
+	_, _ = w.Write([]byte(`{"msg":"hi","buildEvent":"init"}` + "\n"))
+
+	assert.Equal(t, []logLine{{
+		level: logger.InfoLvl,
+		text:  "hi\n",
+	}}, *lines)
+}
+
+func TestWriterLevels(t *testing.T) {
+	for input, expected := range map[string]logger.Level{
+		"DEBUG":   logger.InfoLvl,
+		"info":    logger.InfoLvl,
+		"Warning": logger.WarnLvl,
+		"fatal":   logger.ErrorLvl,
+		"30":      logger.InfoLvl,
+		"40":      logger.WarnLvl,
+		"60":      logger.ErrorLvl,
+		"unknown": logger.InfoLvl,
+	} {
+		assert.Equal(t, expected, levelFromString(input), input)
+	}
+}
+
+func TestWriterBuffersPartialLines(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserLogfmt)
+	_, _ = w.Write([]byte("msg=one\nmsg=t"))
+	_, _ = w.Write([]byte("wo\nmsg=three"))
+
+	assert.Equal(t, []string{"one\n", "two\n"}, texts(*lines))
+
+	w.Flush()
+	assert.Equal(t, []string{"one\n", "two\n", "three\n"}, texts(*lines))
+}
+
+func TestWriterLongLine(t *testing.T) {
+	w, lines := newTestWriter(v1alpha1.LogParserJSON)
+	long := make([]byte, maxLineBytes+1)
+	for i := range long {
+		long[i] = 'a'
+	}
+	_, _ = w.Write(long)
+
+	assert.Equal(t, []string{string(long)}, texts(*lines))
+
+	w.Flush()
+	assert.Len(t, *lines, 1)
+}
+
+func texts(lines []logLine) []string {
+	result := []string{}
+	for _, l := range lines {
+		result = append(result, l.text)
+	}
+	return result
+}
@@ -31,6 +31,14 @@ type BuildResult interface {
 
 type LocalBuildResult struct {
 	id model.TargetID
+
+	// A hash of the update command and the contents of its deps,
+	// so that we can tell whether running it again would do anything new.
+	InputHash string
+
+	// A hash of the contents of the command's declared outputs, so that we
+	// can tell whether they've changed since the command ran.
+	OutputHash string
 }
 
 func (r LocalBuildResult) TargetID() model.TargetID   { return r.id }
@@ -45,6 +53,14 @@ func NewLocalBuildResult(id model.TargetID) LocalBuildResult {
 type ImageBuildResult struct {
 	id             model.TargetID
 	ImageMapStatus v1alpha1.ImageMapStatus
+
+	// A hash of the build inputs, so that we can tell whether
+	// the image is still up-to-date after Tilt restarts.
+	InputHash string
+
+	// Identifies the cluster the image was pushed or loaded into, so that
+	// we don't re-use it after Tilt restarts against a different cluster.
+	ClusterKey string
 }
 
 func (r ImageBuildResult) TargetID() model.TargetID   { return r.id }
@@ -199,6 +215,10 @@ type BuildState struct {
 
 	// The default cluster.
 	Cluster *v1alpha1.Cluster
+
+	// An image build from before Tilt restarted. Only safe to re-use
+	// if the build inputs still match its InputHash.
+	RestoredResult *ImageBuildResult
 }
 
 func NewBuildState(result BuildResult, files []string, pendingDeps []model.TargetID) BuildState {
@@ -2,8 +2,11 @@ package buildcontrols
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/pkg/errors"
+
+	"github.com/tilt-dev/tilt/pkg/model"
 )
 
 // A permanent error indicates that the whole build pipeline needs to stop.
@@ -12,3 +15,34 @@ func IsFatalError(err error) bool {
 	cause := errors.Cause(err)
 	return cause == context.Canceled
 }
+
+// A preempted error indicates that the build was canceled to make room
+// for a build with a higher priority. The build should run again later.
+type PreemptedError struct {
+	By model.ManifestName
+}
+
+func (e PreemptedError) Error() string {
+	return fmt.Sprintf("build preempted by higher-priority update of %s", e.By)
+}
+
+func IsPreemptedError(err error) bool {
+	var preempted PreemptedError
+	return errors.As(err, &preempted)
+}
+
+// A superseded error indicates that the build was canceled because
+// new file changes arrived while it was building images. The next build
+// includes both the old and the new changes.
+type SupersededError struct {
+	FilesChanged []string
+}
+
+func (e SupersededError) Error() string {
+	return fmt.Sprintf("build canceled: superseded by %d new file change(s)", len(e.FilesChanged))
+}
+
+func IsSupersededError(err error) bool {
+	var superseded SupersededError
+	return errors.As(err, &superseded)
+}
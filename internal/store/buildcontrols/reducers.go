@@ -83,8 +83,12 @@ func handleBuildResults(engineState *store.EngineState,
 	}
 
 	// Remove pending file changes that were consumed by this build.
-	for _, status := range ms.BuildStatuses {
-		status.ConsumeChangesBefore(br.StartTime)
+	// A preempted or superseded build never finished, so its changes are
+	// still pending.
+	if !IsPreemptedError(br.Error) && !IsSupersededError(br.Error) {
+		for _, status := range ms.BuildStatuses {
+			status.ConsumeChangesBefore(br.StartTime)
+		}
 	}
 
 	if isBuildSuccess {
@@ -218,6 +222,11 @@ func HandleBuildCompleted(ctx context.Context, engineState *store.EngineState, c
 			engineState.FatalError = err
 			return
 		}
+
+		// Run the build again once the higher-priority build is done.
+		if IsPreemptedError(err) {
+			engineState.AppendToTriggerQueue(mn, bs.Reason)
+		}
 	}
 
 	manifest := mt.Manifest
@@ -42,6 +42,11 @@ type EngineState struct {
 	// How many builds have been completed (pass or fail) since starting tilt
 	CompletedBuildCount int
 
+	// Build history and image builds from before Tilt restarted.
+	// Only populated when state persistence is enabled.
+	RestoredBuildHistory map[model.ManifestName][]model.BuildRecord `json:"-"`
+	RestoredImageResults map[model.TargetID]ImageBuildResult        `json:"-"`
+
 	UpdateSettings model.UpdateSettings
 
 	FatalError error
@@ -551,6 +556,22 @@ type ManifestState struct {
 	// The last `BuildHistoryLimit` builds. The most recent build is first in the slice.
 	BuildHistory []model.BuildRecord
 
+	// Builds from before Tilt restarted, restored from disk.
+	//
+	// These are only for display. They don't count as builds of this
+	// session, so the manifest still gets its initial build.
+	RestoredBuildHistory []model.BuildRecord
+
+	// The deployed YAML (with image refs injected) of the most recent build
+	// whose pods stayed healthy for the auto-rollback window.
+	//
+	// We keep this separately from BuildHistory, because a few failed
+	// builds in a row would push the healthy build out of the history.
+	LastHealthyDeployYAML string
+
+	// The most recent automatic rollback of a failed deploy.
+	LastRollback *Rollback
+
 	// If this manifest was changed, which config files led to the most recent change in manifest definition
 	ConfigFilesThatCausedChange []string
 
@@ -560,6 +581,20 @@ type ManifestState struct {
 	DisableState v1alpha1.DisableState
 }
 
+// Rollback records that Tilt re-applied the last healthy deploy
+// of a resource because the pods of a new deploy failed.
+type Rollback struct {
+	// The start time of the build whose deploy we rolled back.
+	BuildStartTime time.Time
+
+	Time   time.Time
+	Reason string
+
+	// The failed pod, if we rolled back because of a particular pod.
+	PodName k8s.PodID
+	SpanID  logstore.SpanID
+}
+
 func NewState() *EngineState {
 	ret := &EngineState{}
 	ret.LogStore = logstore.NewLogStore()
@@ -707,6 +742,12 @@ func (ms *ManifestState) LastBuild() model.BuildRecord {
 	return ms.BuildHistory[0]
 }
 
+// Whether the most recent build of the manifest was rolled back.
+func (ms *ManifestState) IsRolledBack() bool {
+	return ms.LastRollback != nil && !ms.IsBuilding() &&
+		ms.LastRollback.BuildStartTime.Equal(ms.LastBuild().StartTime)
+}
+
 func (ms *ManifestState) AddCompletedBuild(bs model.BuildRecord) {
 	ms.BuildHistory = append([]model.BuildRecord{bs}, ms.BuildHistory...)
 	if len(ms.BuildHistory) > model.BuildHistoryLimit {
@@ -714,6 +755,19 @@ func (ms *ManifestState) AddCompletedBuild(bs model.BuildRecord) {
 	}
 }
 
+// The build history to display, padded with builds from before Tilt restarted.
+func (ms *ManifestState) DisplayBuildHistory() []model.BuildRecord {
+	if len(ms.RestoredBuildHistory) == 0 {
+		return ms.BuildHistory
+	}
+	result := append([]model.BuildRecord{}, ms.BuildHistory...)
+	result = append(result, ms.RestoredBuildHistory...)
+	if len(result) > model.BuildHistoryLimit {
+		result = result[:model.BuildHistoryLimit]
+	}
+	return result
+}
+
 func (ms *ManifestState) StartedFirstBuild() bool {
 	return ms.IsBuilding() || len(ms.BuildHistory) > 0
 }
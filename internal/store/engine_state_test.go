@@ -79,6 +79,23 @@ func TestNextBuildReason(t *testing.T) {
 		mt.NextBuildReason().String())
 }
 
+func TestDisplayBuildHistory(t *testing.T) {
+	ms := NewManifestState(model.Manifest{Name: "fe"})
+	t0 := time.Now()
+	restored := model.BuildRecord{StartTime: t0.Add(-time.Hour)}
+	ms.RestoredBuildHistory = []model.BuildRecord{restored}
+	assert.Equal(t, []model.BuildRecord{restored}, ms.DisplayBuildHistory())
+	assert.False(t, ms.StartedFirstBuild())
+
+	b1 := model.BuildRecord{StartTime: t0}
+	ms.AddCompletedBuild(b1)
+	assert.Equal(t, []model.BuildRecord{b1, restored}, ms.DisplayBuildHistory())
+
+	b2 := model.BuildRecord{StartTime: t0.Add(time.Second)}
+	ms.AddCompletedBuild(b2)
+	assert.Equal(t, []model.BuildRecord{b2, b1}, ms.DisplayBuildHistory())
+}
+
 func TestBuildStatusGC(t *testing.T) {
 	start := time.Now()
 	bs := newBuildStatus()
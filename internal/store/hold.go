@@ -39,4 +39,8 @@ const (
 
 	// We're waiting on the cluster connection to be established.
 	HoldReasonCluster HoldReason = "waiting-for-cluster"
+
+	// We're waiting for other resources in the same concurrency group
+	// to finish building.
+	HoldReasonConcurrencyGroup HoldReason = "waiting-for-concurrency-group"
 )
@@ -0,0 +1,39 @@
+package kubernetesapplys
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// ErrDiffNotAwaiting means the diff the user saw isn't awaiting
+// confirmation anymore (e.g., the YAML changed), so we didn't confirm it.
+var ErrDiffNotAwaiting = errors.New("diff is no longer awaiting confirmation")
+
+// ConfirmDiff marks the diff that the KubernetesApply is waiting on as confirmed.
+//
+// The hash must match the diff awaiting confirmation, so that we never
+// confirm a diff that the user hasn't seen.
+func ConfirmDiff(ctx context.Context, ctrlClient client.Client, name string, hash string) error {
+	var ka v1alpha1.KubernetesApply
+	err := ctrlClient.Get(ctx, types.NamespacedName{Name: name}, &ka)
+	if err != nil {
+		return err
+	}
+
+	if hash == "" || ka.Status.DiffAwaitingConfirmation != hash {
+		return fmt.Errorf("%s: %w", name, ErrDiffNotAwaiting)
+	}
+
+	patch := client.MergeFrom(ka.DeepCopy())
+	if ka.Annotations == nil {
+		ka.Annotations = map[string]string{}
+	}
+	ka.Annotations[v1alpha1.AnnotationConfirmedDiff] = hash
+	return ctrlClient.Patch(ctx, &ka, patch)
+}
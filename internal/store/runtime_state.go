@@ -47,6 +47,8 @@ type LocalRuntimeState struct {
 	SpanID                   model.LogSpanID
 	LastReadyOrSucceededTime time.Time
 	Ready                    bool
+	RestartCount             int
+	LastRestartReason        string
 }
 
 var _ RuntimeState = LocalRuntimeState{}
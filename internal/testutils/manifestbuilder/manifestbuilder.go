@@ -41,6 +41,8 @@ type ManifestBuilder struct {
 	localAllowParallel bool
 	resourceDeps       []string
 	triggerMode        model.TriggerMode
+	concurrencyGroups  []model.ConcurrencyGroup
+	priority           int
 
 	iTargets []model.ImageTarget
 }
@@ -138,6 +140,16 @@ func (b ManifestBuilder) WithLiveUpdateAtIndex(lu v1alpha1.LiveUpdateSpec, index
 	return b
 }
 
+func (b ManifestBuilder) WithConcurrencyGroups(groups ...model.ConcurrencyGroup) ManifestBuilder {
+	b.concurrencyGroups = groups
+	return b
+}
+
+func (b ManifestBuilder) WithPriority(priority int) ManifestBuilder {
+	b.priority = priority
+	return b
+}
+
 func (b ManifestBuilder) WithResourceDeps(deps ...string) ManifestBuilder {
 	b.resourceDeps = deps
 	return b
@@ -231,6 +243,10 @@ func (b ManifestBuilder) Build() model.Manifest {
 		return model.Manifest{}
 	}
 	m = m.WithTriggerMode(b.triggerMode)
+	if len(b.concurrencyGroups) > 0 {
+		m = m.WithConcurrencyGroups(b.concurrencyGroups)
+	}
+	m = m.WithPriority(b.priority)
 
 	err := model.InferImageProperties([]model.Manifest{m})
 	require.NoError(b.f.T(), err)
@@ -1,4 +1,4 @@
-from typing import Dict, Union, List, Callable, Any, Optional
+from typing import Dict, Union, List, Callable, Any, Optional, Tuple
 
 # Our documentation generation framework doesn't properly handle __file__,
 # so we call it __file__ and edit it later.
@@ -64,6 +64,14 @@ class TCPSocketAction:
   pass
 
 
+class GRPCAction:
+  """Specification for a gRPC health check to perform that determines resource readiness.
+
+  For details, see the :func:`probe` and :func:`grpc_action` functions.
+  """
+  pass
+
+
 def port_forward(local_port: int,
                  container_port: Optional[int] = None,
                  name: Optional[str] = None,
@@ -154,6 +162,46 @@ def initial_sync() -> LiveUpdateStep:
   """
   pass
 
+def verify_sync() -> LiveUpdateStep:
+  """Check that the files in the container match the local files after each live update.
+
+  When included in a ``live_update``, Tilt runs ``sha256sum`` in the container
+  after every update, and compares the checksums of the updated files against
+  the local copies. Files that were deleted locally must also be gone from the
+  container. If anything has drifted (for example, because a process in the
+  container rewrote a synced file), Tilt falls back to a full image build
+  rather than leave the container in an inconsistent state.
+
+  The container image must include ``sh`` and ``sha256sum``. Note that ``run``
+  steps that rewrite synced files will also be reported as drift.
+
+  ``verify_sync`` must appear at most once, before any ``sync``, ``run``, or
+  ``restart_container`` steps.
+
+  For more info, see the `Live Update Reference <live_update_reference.html>`_.
+  """
+  pass
+
+def delta_sync() -> LiveUpdateStep:
+  """Copy only the changed parts of large files.
+
+  By default, Tilt copies every changed file in full. When ``delta_sync`` is
+  included in a ``live_update``, Tilt first asks the container for checksums of
+  each 1 MiB block of large files it already has, then sends only the blocks
+  that changed. This can make live updates of large binaries and asset bundles
+  much faster on remote clusters.
+
+  Only applies when Tilt copies files with ``kubectl exec``. The container image
+  must include ``sh``, ``dd``, and ``sha256sum``; if it doesn't, Tilt copies
+  whole files instead.
+
+  ``delta_sync`` must appear at most once, before any ``sync``, ``run``, or
+  ``restart_container`` steps.
+
+  For more info, see the `Live Update Reference <live_update_reference.html>`_.
+  """
+  pass
+
 def sync(local_path: str, remote_path: str) -> LiveUpdateStep:
   """Specify that any changes to `localPath` should be synced to `remotePath`
 
@@ -216,8 +264,9 @@ def docker_build(ref: str,
                  extra_tag: Union[str, List[str]] = "",
                  container_args: List[str] = None,
                  cache_from: Union[str, List[str]] = [],
+                 cache_to: Union[str, List[str]] = [],
                  pull: bool = False,
-                 platform: str = "",
+                 platform: Union[str, List[str]] = "",
                  extra_hosts: Union[str, List[str]] = []) -> None:
   """Builds a docker image.
 
@@ -260,9 +309,10 @@ def docker_build(ref: str,
     secret: Include secrets in your build in a way that won't show up in the image. Uses the same syntax as the `docker build --secret flag <https://docs.docker.com/develop/develop-images/build_enhancements/#new-docker-build-secret-information>`_.
     extra_tag: Tag an image with one or more extra references after each build. Useful when running Tilt in a CI pipeline, where you want each image to be tagged with the pipeline ID so you can find it later. Uses the same syntax as the ``docker build --tag`` flag.
     container_args: args to run when this container starts. Takes precedence over a `container args specified in k8s YAML <https://kubernetes.io/docs/tasks/inject-data-application/define-command-argument-container/>`_.
-    cache_from: Cache image builds from a remote registry. Uses the same syntax as `docker build --cache-from flag <https://docs.docker.com/engine/reference/commandline/build/#specifying-external-cache-sources>`_.
+    cache_from: Cache image builds from a remote registry. Uses the same syntax as `docker build --cache-from flag <https://docs.docker.com/engine/reference/commandline/build/#specifying-external-cache-sources>`_. Also accepts ``type=local,src=path/to/dir`` to import a cache exported with ``cache_to``.
+    cache_to: Export the build cache after each successful build, so that other builds (e.g., in ``tilt ci``) can import it with ``cache_from``. Uses the syntax of the `docker buildx build --cache-to flag <https://docs.docker.com/reference/cli/docker/buildx/build/#cache-to>`_. Supports ``type=inline``, ``type=registry,ref=<image>``, and ``type=local,dest=<dir>``, in ``mode=min`` only. Local paths are relative to the Tiltfile. Requires BuildKit.
     pull: Force pull the latest version of parent images. Equivalent to the ``docker build --pull`` flag.
-    platform: Target platform for build (e.g. ``linux/amd64``). Defaults to the value of the ``DOCKER_DEFAULT_PLATFORM`` environment variable. Equivalent to the ``docker build --platform`` flag.
+    platform: Target platform for build (e.g. ``linux/amd64``). Defaults to the value of the ``DOCKER_DEFAULT_PLATFORM`` environment variable. Equivalent to the ``docker build --platform`` flag. To build a multi-arch image, pass a list of platforms (e.g. ``['linux/amd64', 'linux/arm64']``), or ``'cluster-all'`` to build for every node architecture in the cluster. Tilt builds and pushes each platform, then pushes a manifest list that refers to all of them, so multi-arch images need a registry.
     extra_hosts: Add a custom host-to-IP mapping (host:ip). Equivalent to the ``docker build --add-host`` flag.
   """
   pass
@@ -321,6 +371,14 @@ def k8s_yaml(yaml: Union[str, List[str], Blob], allow_duplicates: bool = False)
 
   Any YAML files are watched (See ``watch_file``).
 
+  Tilt labels the objects it applies with ``tilt.dev/applyset``. When you remove an
+  object from the YAML of a resource, the next apply of the resource deletes (prunes) it
+  from the cluster, even if Tilt restarted in between. The label is unique to the Tiltfile
+  and the resource, and Tilt only prunes in the namespaces the resource deploys to, so
+  other Tilt sessions on the same cluster are never affected. Namespaces and CRDs are never pruned.
+  To keep an object when it's removed, annotate it with ``tilt.dev/prune: disabled``.
+  Run ``tilt diff`` to see which objects the next apply will prune.
+
   Examples:
 
   .. code-block:: python
@@ -459,7 +517,9 @@ def dc_resource(name: str,
                 auto_init: bool = True,
                 project_name: str = "",
                 new_name: str = "",
-                infer_links: bool = True) -> None:
+                infer_links: bool = True,
+                priority: int = 0,
+                cancel_stale_image_builds: bool = False) -> None:
   """Configures the Docker Compose resource of the given name. Note: Tilt does an amount of resource configuration
   for you(for more info, see `Tiltfile Concepts: Resources <tiltfile_concepts.html#resources>`_); you only need
   to invoke this function if you want to configure your resource beyond what Tilt does automatically.
@@ -479,6 +539,11 @@ def dc_resource(name: str,
       ``docker_compose``, if necessary for disambiguation.
     new_name: If non-empty, will be used as the new name for this resource.
     infer_links: whether to include the default localhost links. Defaults to ``True``. If ``False``, only links explicitly provided via the links argument will be displayed.
+    priority: resources with a higher priority are updated first when several are waiting to update.
+      Defaults to 0. May be negative.
+    cancel_stale_image_builds: if ``True``, file changes that arrive while Tilt is building an image for this
+      resource cancel the image build. Tilt then starts a new build that includes all the changes. Only applies to
+      resources with an automatic trigger mode. Defaults to ``False``.
   """
 
   pass
@@ -492,7 +557,15 @@ def k8s_resource(workload: str = "", new_name: str = "",
                  pod_readiness: str = "",
                  links: Union[str, Link, List[Union[str, Link]]]=[],
                  labels: Union[str, List[str]] = [],
-                 discovery_strategy: str = "") -> None:
+                 discovery_strategy: str = "",
+                 priority: int = 0,
+                 cancel_stale_image_builds: bool = False,
+                 ssa_conflicts: str = "",
+                 preview_diff: bool = False,
+                 confirm_diff_kinds: Union[str, List[str]] = [],
+                 auto_rollback: bool = False,
+                 auto_rollback_window: str = "",
+                 log_parser: str = "") -> None:
   """
 
   Configures or creates the specified Kubernetes resource.
@@ -591,6 +664,33 @@ def k8s_resource(workload: str = "", new_name: str = "",
       `Accessing Resource Endpoints <accessing_resource_endpoints.html#arbitrary-links>`_.
     labels: used to group resources in the Web UI, (e.g. you want all frontend services displayed together, while test and backend services are displayed separately). A label must start and end with an alphanumeric character, can include ``_``, ``-``, and ``.``, and must be 63 characters or less. For an example, see `Resource Grouping <tiltfile_concepts.html#resource-groups>`_.
     discovery_strategy: Possible values: '', 'default', 'selectors-only'. When '' or 'default', Tilt both uses `extra_pod_selectors` and traces k8s owner references to identify this resource's pods. When 'selectors-only', Tilt uses only `extra_pod_selectors`.
+    priority: resources with a higher priority are updated first when several are waiting to update.
+      Defaults to 0. May be negative.
+    cancel_stale_image_builds: if ``True``, file changes that arrive while Tilt is building an image for this
+      resource cancel the image build. Tilt then starts a new build that includes all the changes. Only applies to
+      resources with an automatic trigger mode. Defaults to ``False``.
+    ssa_conflicts: Possible values: 'force', 'fail', 'yield'. Controls what happens when a server-side apply
+      changes a field that another field manager owns (e.g., the replicas of a Deployment managed by an HPA,
+      or a field changed with ``kubectl edit``). When 'force' (the default), Tilt takes ownership of the field.
+      When 'fail', the apply fails with an error that lists the conflicting fields and their managers.
+      When 'yield', Tilt leaves the conflicting fields to their managers and applies everything else.
+      'fail' and 'yield' require server-side apply, enabled with ``update_settings(k8s_server_side_apply='true')``.
+    preview_diff: if ``True``, before each apply, Tilt runs a server-side dry-run apply and shows
+      how the objects will change in the resource log and in the web UI. Run ``tilt diff <resource>``
+      to see the same diff from the command line. Defaults to ``False``.
+    confirm_diff_kinds: one or more object kinds (e.g., ``['Secret', 'PersistentVolumeClaim']``). When an apply
+      would change an object of one of these kinds, Tilt shows the diff and waits for you to confirm it,
+      with ``tilt diff --confirm <resource>`` or from the resource in the web UI, before it applies.
+      Implies ``preview_diff=True``.
+    auto_rollback: if ``True``, Tilt watches the pods of each deploy. If they crash or don't become ready
+      within ``auto_rollback_window``, Tilt re-applies the last deploy whose pods stayed healthy
+      (including its image refs), and marks the resource as rolled back. Defaults to ``False``.
+    auto_rollback_window: how long to watch the pods of a deploy before Tilt considers it healthy, as a
+      duration string (e.g., ``'90s'``). Implies ``auto_rollback=True``. Defaults to ``'1m'``.
+    log_parser: how to parse the logs of the resource's pods. One of ``'json'`` or ``'logfmt'``.
+      When set, Tilt reads the level, message, and fields of each log line, so warnings and errors are
+      shown as such, and you can filter logs by field (e.g., ``tilt logs --field request_id=abc``).
+      Lines that can't be parsed are shown as-is. By default, logs are shown as plain text.
   """
   pass
 
@@ -766,6 +866,60 @@ def helm(pathToChartDir: str, name: str = "", namespace: str = "", values: Union
 """
   pass
 
+def helm_release(name: str,
+                 chart: str,
+                 namespace: str = "",
+                 values: Union[str, List[str]]=[],
+                 set: Union[str, List[str]]=[],
+                 version: str = "",
+                 flags: Union[str, List[str]]=[],
+                 image_deps: List[str]=[],
+                 image_keys: Optional[List[Union[str, Tuple[str, str]]]]=None,
+                 deps: Union[str, List[str]]=[]) -> None:
+  """Deploy a chart with `helm upgrade --install <https://helm.sh/docs/helm/helm_upgrade/>`_.
+
+  Unlike :meth:`helm`, which renders the chart with ``helm template`` and applies the YAML
+  itself, ``helm_release`` lets Helm manage the release. Hooks, ``lookup`` functions,
+  and release history work the same as they do outside Tilt.
+
+  Tilt tracks the objects in the release manifest (from ``helm get manifest``), as
+  they exist in the cluster, so workload status and pod logs work like any other
+  Kubernetes resource.
+  On ``tilt down``, Tilt runs ``helm uninstall``.
+
+  Images from ``image_deps`` are passed to the chart with ``--set-string``, using
+  the chart values named by ``image_keys``.
+
+  Example ::
+
+    docker_build('my-app', '.')
+    helm_release('my-app', './chart', namespace='dev',
+                 values=['./chart/values-dev.yaml'],
+                 image_deps=['my-app'],
+                 image_keys=[('image.repository', 'image.tag')])
+
+  Port forwards and other behavior can be configured using :meth:`k8s_resource`
+  using the ``name`` as specified here.
+
+  Args:
+    name: The release name. Also used as the resource name in the Tilt UI.
+    chart: Path to a local chart directory (absolute, or relative to the location of the Tiltfile),
+      or a chart reference that Helm understands (e.g., ``bitnami/redis`` or ``oci://...``).
+      A local chart directory is watched.
+    namespace: The namespace of the release. Created if it doesn't exist.
+    values: Specify one or more values files. Equivalent to the Helm ``--values`` flag. Values files are watched.
+    set: Specify one or more values. Equivalent to the Helm ``--set`` flag.
+    version: The chart version, for charts from a repository. Equivalent to the Helm ``--version`` flag.
+    flags: Additional flags to pass to ``helm upgrade``, e.g., ``['--wait']``.
+    image_deps: Images built by Tilt that the chart uses.
+    image_keys: The chart values to set to each image in ``image_deps``, in the same order.
+      Each entry is either a single key for the full image reference (e.g., ``'image'``),
+      or a pair of keys for the repository and the tag (e.g., ``('image.repository', 'image.tag')``).
+      Defaults to ``['image']`` when there's exactly one image.
+    deps: Additional paths to watch and trigger a re-deploy on change.
+"""
+  pass
+
 def blob(contents: str) -> Blob:
   """Creates a Blob object that wraps the provided string. Useful for passing strings in to functions that expect a `Blob`, e.g. ``k8s_yaml``."""
   pass
@@ -1151,7 +1305,15 @@ def local_resource(name: str,
                    serve_env: Dict[str, str] = {},
                    readiness_probe: Probe = None,
                    dir: str = "",
-                   serve_dir: str = "") -> None:
+                   serve_dir: str = "",
+                   serve_stop_signal: str = "",
+                   serve_stop_grace_period: str = "",
+                   serve_pre_stop_cmd: Union[str, List[str]] = "",
+                   liveness_probe: Probe = None,
+                   startup_probe: Probe = None,
+                   priority: int = 0,
+                   outputs: Union[str, List[str]] = None,
+                   log_parser: str = "") -> None:
   """Configures one or more commands to run on the *host* machine (not in a remote cluster).
 
   By default, Tilt performs an update on local resources on ``tilt up`` and whenever any of their ``deps`` change.
@@ -1194,6 +1356,27 @@ def local_resource(name: str,
     readiness_probe: Optional readiness probe to use for determining ``serve_cmd`` health state. Fore more info, see the :meth:`probe` function.
     dir: Working directory for ``cmd``. Defaults to the Tiltfile directory.
     serve_dir: Working directory for ``serve_cmd``. Defaults to the Tiltfile directory.
+    serve_stop_signal: Signal sent to the ``serve_cmd`` process group when Tilt stops it (on restart, disable, or exit).
+      One of ``SIGTERM``, ``SIGINT``, ``SIGQUIT``, ``SIGHUP``, or ``SIGKILL``. Defaults to ``SIGTERM``. Ignored on Windows.
+    serve_stop_grace_period: How long to wait for ``serve_cmd`` to exit after it's asked to stop, before killing it
+      abruptly, e.g., ``'90s'``. Includes the time spent on ``serve_pre_stop_cmd``. Defaults to ``'30s'``.
+    serve_pre_stop_cmd: Optional command to run before ``serve_cmd`` is sent the stop signal (e.g., to flush a database to disk).
+      Runs with the same working directory and environment as ``serve_cmd``. If a string, executed with ``sh -c`` on
+      macOS/Linux, or ``cmd /S /C`` on Windows; if a list, will be passed to the operating system as program name and args.
+    liveness_probe: Optional liveness probe for ``serve_cmd``. When the probe fails, Tilt restarts ``serve_cmd``,
+      backing off exponentially (up to one minute) between consecutive restarts. For more info, see the :meth:`probe` function.
+    startup_probe: Optional startup probe for ``serve_cmd``. The readiness and liveness probes don't run until the
+      startup probe succeeds. If the startup probe fails, Tilt restarts ``serve_cmd``, the same as for a failed liveness probe.
+    priority: resources with a higher priority are updated first when several are waiting to update.
+      Defaults to 0. May be negative.
+    outputs: a list of files or directories that ``cmd`` generates. If set, Tilt skips ``cmd`` when the
+      contents of its ``deps`` and the command itself are the same as on its last successful run, and
+      the outputs still exist unchanged. Outputs are never treated as ``deps``, even if they're under a
+      ``deps`` directory. Only accepts real paths, not file globs.
+    log_parser: how to parse the output of ``serve_cmd``. One of ``'json'`` or ``'logfmt'``.
+      When set, Tilt reads the level, message, and fields of each log line, so warnings and errors are
+      shown as such, and you can filter logs by field (e.g., ``tilt logs --field request_id=abc``).
+      Lines that can't be parsed are shown as-is. By default, output is shown as plain text.
   """
   pass
 
@@ -1246,6 +1429,32 @@ def analytics_settings(enable: bool) -> None:
   """
   pass
 
+def otlp_exporter(endpoint: str, protocol: str = "http/protobuf", headers: Dict[str, str] = {}) -> None:
+  """Sends Tilt's build and deploy traces to an OpenTelemetry collector,
+  like Jaeger or Tempo.
+
+  Each build is a span, with the attributes ``tilt.manifest.name``,
+  ``tilt.target.ids``, and ``tilt.build.reason``.
+
+  Tilt passes the trace context to ``custom_build`` and ``local_resource`` commands
+  in the ``TRACEPARENT`` environment variable, so that the commands can add their own spans
+  to the trace.
+
+  If the Tiltfile doesn't call ``otlp_exporter``, Tilt reads the standard
+  ``OTEL_EXPORTER_OTLP_ENDPOINT``, ``OTEL_EXPORTER_OTLP_PROTOCOL``, and
+  ``OTEL_EXPORTER_OTLP_HEADERS`` environment variables.
+
+  Example ::
+
+    otlp_exporter('http://localhost:4318')
+
+  Args:
+    endpoint: The URL of the collector. If the URL has no scheme, Tilt connects without TLS.
+    protocol: Either ``"http/protobuf"`` or ``"grpc"``.
+    headers: Extra headers to send with each request, e.g., for authentication.
+  """
+  pass
+
 def version_settings(check_updates: bool = True, constraint: str = "") -> None:
   """Controls Tilt's behavior with regard to its own version.
 
@@ -1290,7 +1499,8 @@ def update_settings(
     max_parallel_updates: int=3,
     k8s_upsert_timeout_secs: int=30,
     suppress_unused_image_warnings: Union[str, List[str]]=None,
-    k8s_server_side_apply: str="auto") -> None:
+    k8s_server_side_apply: str="auto",
+    preempt_local_updates: bool=False) -> None:
   """Configures Tilt's updates to your resources. (An update is any execution of or
   change to a resource. Examples of updates include: doing a docker build + deploy to
   Kubernetes; running a live update on an existing container; and executing
@@ -1303,8 +1513,32 @@ def update_settings(
       Accepts a list of image names, or '*' to suppress warnings for all images.
     k8s_server_side_apply: controls whether Kubernetes applies use server-side apply.
       Accepts values ``true``, ``false`` and ``auto``. Default is ``auto``.
+    preempt_local_updates: if ``True``, a higher-priority update that's waiting on a local resource
+      cancels that local resource's update. The canceled update runs again afterwards.
+      Manually triggered updates outrank automatic ones. Default is ``False``.
 """
 
+def concurrency_group(name: str, resources: Union[str, List[str]], limit: int=1) -> None:
+  """Limits how many resources in a group can update at the same time.
+
+  Use this for resources that share something scarce, like a single test
+  database or a range of ports. Resources in the group wait while the group
+  has ``limit`` updates in progress, and are shown as waiting on the group.
+  Resources outside the group are not affected, and ``max_parallel_updates``
+  still applies to all updates.
+
+  A resource may belong to more than one group.
+
+  Example ::
+
+    concurrency_group('test-db', ['api-tests', 'worker-tests', 'migrations'])
+
+  Args:
+    name: A unique name for the group.
+    resources: The names of the resources in the group.
+    limit: The maximum number of resources in the group that may update at once. Defaults to 1.
+  """
+
 def ci_settings(
     k8s_grace_period: str='',
     timeout: str='30m',
@@ -1375,10 +1609,11 @@ def probe(initial_delay_secs: int=0,
           failure_threshold: int=3,
           exec: Optional[ExecAction]=None,
           http_get: Optional[HTTPGetAction]=None,
-          tcp_socket: Optional[TCPSocketAction]=None) -> Probe:
+          tcp_socket: Optional[TCPSocketAction]=None,
+          grpc: Optional[GRPCAction]=None) -> Probe:
   """Creates a :class:`Probe` for use with local_resource readiness checks.
 
-  Exactly one of exec, http_get, or tcp_socket must be specified.
+  Exactly one of exec, http_get, tcp_socket, or grpc must be specified.
 
   Args:
     initial_delay_secs: Number of seconds after the resource has started before the probe is
@@ -1393,6 +1628,7 @@ def probe(initial_delay_secs: int=0,
     exec: Process execution handler to determine probe success.
     http_get: HTTP GET handler to determine probe success.
     tcp_socket: TCP socket connection handler to determine probe success.
+    grpc: gRPC health check handler to determine probe success.
   """
 
 def exec_action(command: List[str]) -> ExecAction:
@@ -1436,3 +1672,20 @@ def tcp_socket_action(port: int, host: str='localhost') -> TCPSocketAction:
     port: Port to use for TCP socket connection.
   """
   pass
+
+
+def grpc_action(port: int, host: str='localhost', service: str='', tls: bool=False) -> GRPCAction:
+  """Creates a :class:`GRPCAction` for use with a :class:`Probe` that calls the
+  `gRPC health checking protocol <https://github.com/grpc/grpc/blob/master/doc/health-checking.md>`_
+  to determine service readiness.
+
+  The probe is successful if the server responds within the timeout with a status of ``SERVING``.
+
+  Args:
+    port: Port of the gRPC server.
+    host: Hostname of the gRPC server.
+    service: Name of the service to check. If empty, checks the health of the server as a whole.
+    tls: Whether to connect with TLS. The server certificate is not verified, so self-signed
+      certificates work.
+  """
+  pass
@@ -4,6 +4,16 @@ from typing import Dict, List, Optional
 # DO NOT EDIT MANUALLY
 
 
+class CmdStopSpec:
+  """CmdStopSpec describes how to gracefully shut down a process.
+  
+  Based loosely on the Lifecycle PreStop hook and
+  TerminationGracePeriodSeconds in Kubernetes.
+"""
+  pass
+
+
+
 class ConfigMapDisableSource:
   """Specifies a ConfigMap to control a DisableSource
 """
@@ -32,6 +42,16 @@ class Forward:
 
 
 
+class GRPCAction:
+  """GRPCAction describes an action based on the gRPC health checking protocol
+  (https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+  
+  The server must implement the grpc.health.v1.Health service.
+"""
+  pass
+
+
+
 class HTTPGetAction:
   """HTTPGetAction describes an action based on HTTP Get requests.
 """
@@ -210,6 +230,9 @@ def cmd(
   restart_on: Optional[RestartOnSpec] = None,
   start_on: Optional[StartOnSpec] = None,
   disable_source: Optional[DisableSource] = None,
+  stop: Optional[CmdStopSpec] = None,
+  liveness_probe: Optional[Probe] = None,
+  startup_probe: Optional[Probe] = None,
 ):
   """
   Cmd represents a process on the host machine.
@@ -254,6 +277,25 @@ def cmd(
       StartOn is satisfied.
     disable_source: Specifies how to disable this.
       
+    stop: Specifies how to stop the process.
+      
+      Used whenever Tilt stops the process: on restart, when the Cmd is
+      disabled or deleted, and when Tilt exits.
+      
+      When not specified, Tilt sends SIGTERM to the process group, then
+      kills it abruptly if it hasn't exited after 30 seconds.
+      
+    liveness_probe: Periodic probe of service liveness.
+      
+      When the probe fails, Tilt stops the process and starts it again.
+      Consecutive restarts back off exponentially, up to one minute.
+      
+    startup_probe: Probe that indicates the process has finished starting up.
+      
+      When specified, the readiness and liveness probes don't run until the
+      startup probe succeeds. If the startup probe fails, the process is
+      restarted, the same as for a failed liveness probe.
+      
 """
   pass
 def config_map(
@@ -541,6 +583,37 @@ def ui_button(
 """
   pass
 
+def cmd_stop_spec(
+  signal: str = "",
+  grace_period_seconds: int = 0,
+  pre_stop_args: List[str] = None,
+) -> CmdStopSpec:
+  """
+  CmdStopSpec describes how to gracefully shut down a process.
+  
+  Based loosely on the Lifecycle PreStop hook and
+  TerminationGracePeriodSeconds in Kubernetes.
+
+  Args:
+    signal: The signal to send to the process group to ask it to shut down,
+      e.g., "SIGTERM", "SIGINT", or "SIGQUIT".
+      
+      Defaults to SIGTERM. Ignored on Windows, where Tilt asks the process
+      tree to exit with TASKKILL.
+      
+    grace_period_seconds: Number of seconds to wait for the process to exit before
+      killing it abruptly. Includes the time spent on the pre-stop command.
+      
+      Defaults to 30 seconds. Minimum value is 1.
+      
+    pre_stop_args: Command-line arguments of a command to run before the stop signal is sent.
+      
+      The command runs with the same working directory and environment
+      as the process. Its exit status is logged but otherwise ignored.
+      
+"""
+  pass
+
 def config_map_disable_source(
   name: str = "",
   key: str = "",
@@ -600,6 +673,32 @@ def forward(
 """
   pass
 
+def grpc_action(
+  port: int = 0,
+  host: str = "",
+  service: str = "",
+  tls: bool = False,
+) -> GRPCAction:
+  """
+  GRPCAction describes an action based on the gRPC health checking protocol
+  (https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+  
+  The server must implement the grpc.health.v1.Health service.
+
+  Args:
+    port: Port number of the gRPC service.
+      Number must be in the range 1 to 65535.
+    host: Optional: Host name to connect to, defaults to localhost.
+    service: Service is the name of the service to place in the gRPC HealthCheckRequest.
+      
+      If this is not specified, the server reports the health of the server as a whole.
+    tls: Whether to connect to the server with TLS.
+      
+      The server certificate is not verified, so self-signed certificates
+      for local development work without additional configuration.
+"""
+  pass
+
 def http_get_action(
   path: str = "",
   port: int = 0,
@@ -639,6 +738,7 @@ def handler(
   exec: Optional[ExecAction] = None,
   http_get: Optional[HTTPGetAction] = None,
   tcp_socket: Optional[TCPSocketAction] = None,
+  grpc: Optional[GRPCAction] = None,
 ) -> Handler:
   """
   Handler defines a specific action that should be taken in a probe.
@@ -650,6 +750,7 @@ def handler(
     tcp_socket: TCPSocket specifies an action involving a TCP port.
       TCP hooks not yet supported
       TODO: implement a realistic TCP lifecycle hook
+    grpc: GRPC specifies an action involving a gRPC health check.
 """
   pass
 
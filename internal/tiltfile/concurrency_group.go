@@ -0,0 +1,86 @@
+package tiltfile
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+type concurrencyGroup struct {
+	name      string
+	limit     int
+	resources []string
+
+	// for error reporting
+	position syntax.Position
+}
+
+func (s *tiltfileState) concurrencyGroup(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name string
+	var resources value.StringOrStringList
+	limit := 1
+	err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"resources", &resources,
+		"limit?", &limit)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" {
+		return nil, fmt.Errorf("%s: name cannot be empty", fn.Name())
+	}
+	if limit < 1 {
+		return nil, fmt.Errorf("%s: limit must be >= 1 (got: %d)", fn.Name(), limit)
+	}
+	for _, g := range s.concurrencyGroups {
+		if g.name == name {
+			return nil, fmt.Errorf("%s: group %q was already defined at %s", fn.Name(), name, g.position.String())
+		}
+	}
+
+	// A resource that's listed twice only counts once against the limit.
+	var deduped []string
+	seen := make(map[string]bool, len(resources.Values))
+	for _, r := range resources.Values {
+		if !seen[r] {
+			seen[r] = true
+			deduped = append(deduped, r)
+		}
+	}
+
+	s.concurrencyGroups = append(s.concurrencyGroups, concurrencyGroup{
+		name:      name,
+		limit:     limit,
+		resources: deduped,
+		position:  thread.CallFrame(1).Pos,
+	})
+	return starlark.None, nil
+}
+
+// Attach each concurrency group to the manifests of its resources.
+func (s *tiltfileState) assignConcurrencyGroups(ms []model.Manifest) error {
+	indices := make(map[model.ManifestName]int, len(ms))
+	for i, m := range ms {
+		indices[m.Name] = i
+	}
+
+	for _, g := range s.concurrencyGroups {
+		for _, r := range g.resources {
+			i, ok := indices[model.ManifestName(r)]
+			if !ok {
+				return fmt.Errorf("%s: concurrency group %q includes unknown resource %q", g.position.String(), g.name, r)
+			}
+
+			ms[i] = ms[i].WithConcurrencyGroups(append(ms[i].ConcurrencyGroups, model.ConcurrencyGroup{
+				Name:  g.name,
+				Limit: g.limit,
+			}))
+		}
+	}
+	return nil
+}
@@ -5,15 +5,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
+	"github.com/containerd/platforms"
 	"github.com/distribution/reference"
 	"github.com/moby/patternmatcher/ignorefile"
 	"github.com/pkg/errors"
 	"go.starlark.net/starlark"
 
 	"github.com/tilt-dev/tilt/internal/container"
+	"github.com/tilt-dev/tilt/internal/docker/buildkit"
 	"github.com/tilt-dev/tilt/internal/dockerfile"
 	"github.com/tilt-dev/tilt/internal/ospath"
 	"github.com/tilt-dev/tilt/internal/sliceutils"
@@ -42,6 +45,7 @@ type dockerImage struct {
 	network          string
 	extraTags        []string // Extra tags added at build-time.
 	cacheFrom        []string
+	cacheTo          []string
 	pullParent       bool
 	platform         string
 
@@ -114,8 +118,8 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		onlyVal,
 		entrypoint starlark.Value
 	var buildArgs value.StringStringMap
-	var network, platform value.Stringable
-	var ssh, secret, extraTags, cacheFrom, extraHosts value.StringOrStringList
+	var network value.Stringable
+	var ssh, secret, extraTags, cacheFrom, cacheTo, extraHosts, platformVal value.StringOrStringList
 	var matchInEnvVars, pullParent bool
 	var overrideArgsVal starlark.Sequence
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
@@ -137,8 +141,9 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		"network?", &network,
 		"extra_tag?", &extraTags,
 		"cache_from?", &cacheFrom,
+		"cache_to?", &cacheTo,
 		"pull?", &pullParent,
-		"platform?", &platform,
+		"platform?", &platformVal,
 		"extra_hosts?", &extraHosts,
 	); err != nil {
 		return nil, err
@@ -219,10 +224,20 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		}
 	}
 
-	if platform.Value == "" {
+	cacheFromSpecs := resolveCacheFrom(thread, cacheFrom.Values)
+	cacheToSpecs, err := resolveCacheTo(thread, cacheTo.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	platform, err := parsePlatform(platformVal.Values)
+	if err != nil {
+		return nil, err
+	}
+	if platform == "" {
 		// for compatibility with Docker CLI, support the env var fallback
 		// see https://docs.docker.com/engine/reference/commandline/cli/#environment-variables
-		platform.Value = os.Getenv(dockerPlatformEnv)
+		platform = os.Getenv(dockerPlatformEnv)
 	}
 
 	buildArgsList := []string{}
@@ -253,9 +268,10 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 		targetStage:      targetStage,
 		network:          network.Value,
 		extraTags:        extraTags.Values,
-		cacheFrom:        cacheFrom.Values,
+		cacheFrom:        cacheFromSpecs,
+		cacheTo:          cacheToSpecs,
 		pullParent:       pullParent,
-		platform:         platform.Value,
+		platform:         platform,
 		tiltfilePath:     starkit.CurrentExecPath(thread),
 		extraHosts:       extraHosts.Values,
 	}
@@ -267,6 +283,63 @@ func (s *tiltfileState) dockerBuild(thread *starlark.Thread, fn *starlark.Builti
 	return starlark.None, nil
 }
 
+// Joins a list of platforms into the comma-separated form used by
+// `docker buildx build --platform`.
+func parsePlatform(values []string) (string, error) {
+	var result []string
+	for _, v := range values {
+		for _, p := range strings.Split(v, ",") {
+			p = strings.TrimSpace(p)
+			if p == "" {
+				continue
+			}
+			if p == v1alpha1.PlatformClusterAll {
+				result = append(result, p)
+				continue
+			}
+			if _, err := platforms.Parse(p); err != nil {
+				return "", fmt.Errorf("Argument platform=%q: %v", v, err)
+			}
+			result = append(result, p)
+		}
+	}
+
+	result = sliceutils.Dedupe(result)
+	if len(result) > 1 && slices.Contains(result, v1alpha1.PlatformClusterAll) {
+		return "", fmt.Errorf("Argument platform: %q can't be combined with other platforms", v1alpha1.PlatformClusterAll)
+	}
+	return strings.Join(result, ","), nil
+}
+
+// Local cache directories are relative to the Tiltfile. Anything else is
+// passed through as-is, for the Docker engine to interpret.
+func resolveCacheFrom(thread *starlark.Thread, values []string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		spec, err := buildkit.ParseCacheFrom(v)
+		if err == nil && spec.Type == buildkit.CacheTypeLocal {
+			v = spec.WithDir(starkit.AbsPath(thread, spec.Dir)).String()
+		}
+		result = append(result, v)
+	}
+	return result
+}
+
+func resolveCacheTo(thread *starlark.Thread, values []string) ([]string, error) {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		spec, err := buildkit.ParseCacheTo(v)
+		if err != nil {
+			return nil, fmt.Errorf("Argument cache_to=%q: %v", v, err)
+		}
+		if spec.Type == buildkit.CacheTypeLocal {
+			spec = spec.WithDir(starkit.AbsPath(thread, spec.Dir))
+		}
+		result = append(result, spec.String())
+	}
+	return result, nil
+}
+
 func (s *tiltfileState) parseOnly(val starlark.Value) ([]string, error) {
 	paths, err := parseValuesToStrings(val, "only")
 	if err != nil {
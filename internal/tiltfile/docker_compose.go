@@ -221,6 +221,8 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 	var links links.LinkList
 	var labels value.LabelSet
 	var autoInit = value.Optional[starlark.Bool]{Value: true}
+	var priorityVal value.Optional[starlark.Int]
+	var cancelStaleImageBuilds value.Optional[starlark.Bool]
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"name", &name,
@@ -235,6 +237,8 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		"auto_init?", &autoInit,
 		"project_name?", &projectName,
 		"new_name?", &newName,
+		"priority?", &priorityVal,
+		"cancel_stale_image_builds?", &cancelStaleImageBuilds,
 	); err != nil {
 		return nil, err
 	}
@@ -243,6 +247,11 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		return nil, fmt.Errorf("dc_resource: `name` must not be empty")
 	}
 
+	priority, err := starlarkPriorityToModel(fn.Name(), priorityVal)
+	if err != nil {
+		return nil, err
+	}
+
 	var imageRefAsStr *string
 	switch imageVal := imageVal.(type) {
 	case nil: // optional arg, this is fine
@@ -284,6 +293,10 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		options.Labels[key] = val
 	}
 
+	if priority != nil {
+		options.Priority = *priority
+	}
+
 	if imageRefAsStr != nil {
 		normalized, err := container.ParseNamed(*imageRefAsStr)
 		if err != nil {
@@ -304,6 +317,10 @@ func (s *tiltfileState) dcResource(thread *starlark.Thread, fn *starlark.Builtin
 		options.AutoInit = autoInit
 	}
 
+	if cancelStaleImageBuilds.IsSet {
+		options.CancelStaleImageBuilds = bool(cancelStaleImageBuilds.Value)
+	}
+
 	s.dc[projectName].resOptions[name] = options
 	svc.Options = options
 	return starlark.None, nil
@@ -408,6 +425,10 @@ type dcResourceOptions struct {
 
 	Labels map[string]string
 
+	Priority int
+
+	CancelStaleImageBuilds bool
+
 	resourceDeps []string
 }
 
@@ -550,6 +571,9 @@ func (s *tiltfileState) dcServiceToManifest(service *dcService, dcSet *dcResourc
 		Name:                 model.ManifestName(service.Name),
 		TriggerMode:          um,
 		ResourceDependencies: mds,
+		Priority:             options.Priority,
+
+		CancelStaleImageBuilds: options.CancelStaleImageBuilds,
 	}.WithDeployTarget(dcInfo).
 		WithLabels(options.Labels).
 		WithImageTargets(iTargets)
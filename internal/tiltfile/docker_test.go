@@ -132,6 +132,50 @@ func TestCustomPlatform(t *testing.T) {
 	}
 }
 
+func TestMultiPlatform(t *testing.T) {
+	type tc struct {
+		name     string
+		argValue string
+		expected string
+	}
+	tcs := []tc{
+		{name: "List", argValue: "['linux/amd64', 'linux/arm64']", expected: "linux/amd64,linux/arm64"},
+		{name: "Comma-separated", argValue: "'linux/amd64, linux/arm64'", expected: "linux/amd64,linux/arm64"},
+		{name: "Duplicates", argValue: "['linux/amd64', 'linux/amd64']", expected: "linux/amd64"},
+		{name: "Cluster", argValue: "'cluster-all'", expected: "cluster-all"},
+	}
+
+	for _, tt := range tcs {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFixture(t)
+
+			f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+			f.file("Dockerfile", `FROM alpine`)
+			f.file("Tiltfile", fmt.Sprintf(`
+k8s_yaml('fe.yaml')
+docker_build('gcr.io/fe', '.', platform=%s)
+`, tt.argValue))
+
+			f.load()
+			m := f.assertNextManifest("fe")
+			require.Equal(t, tt.expected, m.ImageTargetAt(0).DockerBuildInfo().Platform)
+		})
+	}
+}
+
+func TestMultiPlatformInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("fe.yaml", deployment("fe", image("gcr.io/fe")))
+	f.file("Dockerfile", `FROM alpine`)
+	f.file("Tiltfile", `
+k8s_yaml('fe.yaml')
+docker_build('gcr.io/fe', '.', platform=['cluster-all', 'linux/amd64'])
+`)
+
+	f.loadErrString(`"cluster-all" can't be combined with other platforms`)
+}
+
 func TestCustomBuildDepsAreLocalRepos(t *testing.T) {
 	f := newFixture(t)
 
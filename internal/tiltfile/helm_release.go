@@ -0,0 +1,167 @@
+package tiltfile
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/internal/tiltfile/starkit"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+// helm_release() deploys a chart with `helm upgrade --install`, so that
+// hooks, lookup functions, and release history work like they do outside Tilt.
+//
+// It's a k8s_custom_deploy() under the hood. The apply and delete commands
+// call back into a hidden `tilt helm-release` subcommand, which injects
+// the images and prints the manifest of the release for Tilt to track.
+func (s *tiltfileState) helmRelease(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var name, chart, namespace, version string
+	var valueFiles, set, flags value.StringOrStringList
+	var imageDeps value.ImageList
+	var imageKeysVal starlark.Value
+	deps := value.NewLocalPathListUnpacker(thread)
+
+	if err := s.unpackArgs(fn.Name(), args, kwargs,
+		"name", &name,
+		"chart", &chart,
+		"namespace?", &namespace,
+		"values?", &valueFiles,
+		"set?", &set,
+		"version?", &version,
+		"flags?", &flags,
+		"image_deps?", &imageDeps,
+		"image_keys?", &imageKeysVal,
+		"deps?", &deps,
+	); err != nil {
+		return nil, err
+	}
+
+	if chart == "" {
+		return nil, fmt.Errorf("%s: chart cannot be empty", fn.Name())
+	}
+
+	imageKeys, err := helmImageKeys(fn.Name(), imageKeysVal, len(imageDeps))
+	if err != nil {
+		return nil, err
+	}
+
+	allDeps := append([]string{}, deps.Value...)
+
+	// A chart is either a local directory, or a reference to a chart in a
+	// repository (e.g., bitnami/redis or oci://...), which we pass through to helm.
+	localPath := starkit.AbsPath(thread, chart)
+	if info, err := os.Stat(localPath); err == nil && info.IsDir() && !strings.HasPrefix(chart, "oci://") {
+		chart = localPath
+		allDeps = append(allDeps, localPath)
+
+		subcharts, err := localSubchartDependenciesFromPath(localPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading subchart deps: %v", err)
+		}
+		for _, d := range subcharts {
+			allDeps = append(allDeps, starkit.AbsPath(thread, d))
+		}
+	}
+
+	var helmFlags []string
+	for _, valueFile := range valueFiles.Values {
+		p := starkit.AbsPath(thread, valueFile)
+		helmFlags = append(helmFlags, "--values", p)
+		allDeps = append(allDeps, p)
+	}
+	for _, setArg := range set.Values {
+		helmFlags = append(helmFlags, "--set", setArg)
+	}
+	if version != "" {
+		helmFlags = append(helmFlags, "--version", version)
+	}
+	helmFlags = append(helmFlags, flags.Values...)
+
+	tilt := tiltExecutable()
+	var nsFlags []string
+	if namespace != "" {
+		nsFlags = []string{"--namespace", namespace}
+	}
+
+	applyArgv := []string{tilt, "helm-release", "apply", name, chart}
+	applyArgv = append(applyArgv, nsFlags...)
+	for _, key := range imageKeys {
+		applyArgv = append(applyArgv, "--image-key", key)
+	}
+	applyArgv = append(applyArgv, "--")
+	applyArgv = append(applyArgv, helmFlags...)
+
+	deleteArgv := []string{tilt, "helm-release", "delete", name}
+	deleteArgv = append(deleteArgv, nsFlags...)
+
+	res, err := s.makeK8sResource(name)
+	if err != nil {
+		return nil, fmt.Errorf("error making resource for %s: %v", name, err)
+	}
+
+	dir := starkit.AbsWorkingDir(thread)
+	res.customDeploy = &k8sCustomDeploy{
+		applyCmd:  model.Cmd{Argv: applyArgv, Dir: dir},
+		deleteCmd: model.Cmd{Argv: deleteArgv, Dir: dir},
+		deps:      allDeps,
+	}
+	for _, imageDep := range imageDeps {
+		res.addImageDep(imageDep, true)
+	}
+
+	return starlark.None, nil
+}
+
+// Each image key is either a single chart value for the whole image ref
+// (e.g., "image"), or a pair of values for the repository and the tag
+// (e.g., ("image.repository", "image.tag")), which we encode as "repo,tag".
+func helmImageKeys(fnName string, v starlark.Value, imageCount int) ([]string, error) {
+	if v == nil || v == starlark.None {
+		if imageCount == 0 {
+			return nil, nil
+		}
+		if imageCount == 1 {
+			return []string{"image"}, nil
+		}
+		return nil, fmt.Errorf("%s: image_keys must be specified when there's more than one image in image_deps", fnName)
+	}
+
+	list, ok := v.(*starlark.List)
+	if !ok {
+		return nil, fmt.Errorf("%s: for parameter \"image_keys\": expected list, got %s", fnName, v.Type())
+	}
+
+	var keys []string
+	for i := 0; i < list.Len(); i++ {
+		switch item := list.Index(i).(type) {
+		case starlark.String:
+			keys = append(keys, item.GoString())
+		case starlark.Tuple:
+			pair, err := value.SequenceToStringSlice(item)
+			if err != nil || len(pair) != 2 {
+				return nil, fmt.Errorf("%s: for parameter \"image_keys\": expected a (repository, tag) pair, got %s", fnName, item)
+			}
+			keys = append(keys, pair[0]+","+pair[1])
+		default:
+			return nil, fmt.Errorf("%s: for parameter \"image_keys\": expected string or tuple, got %s", fnName, item.Type())
+		}
+	}
+
+	if len(keys) != imageCount {
+		return nil, fmt.Errorf("%s: image_keys has %d entries, but image_deps has %d. They must match",
+			fnName, len(keys), imageCount)
+	}
+	return keys, nil
+}
+
+func tiltExecutable() string {
+	e, err := os.Executable()
+	if err != nil {
+		return "tilt"
+	}
+	return e
+}
@@ -0,0 +1,114 @@
+package tiltfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHelmRelease(t *testing.T) {
+	f := newFixture(t)
+	f.setupHelm()
+
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('gcr.io/frontend', '.')
+helm_release('rose-quartz', './helm', namespace='garnet',
+             values=['./dev/helm/values-dev.yaml'], set=['replicas=2'],
+             flags=['--wait'],
+             image_deps=['gcr.io/frontend'])
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("rose-quartz")
+	require.Len(t, m.ImageTargets, 1)
+
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Equal(t, []string{"gcr.io_frontend"}, spec.ImageMaps)
+	require.NotNil(t, spec.ApplyCmd)
+	assert.Equal(t, []string{
+		"helm-release", "apply", "rose-quartz", f.JoinPath("helm"),
+		"--namespace", "garnet",
+		"--image-key", "image",
+		"--",
+		"--values", f.JoinPath("dev/helm/values-dev.yaml"),
+		"--set", "replicas=2",
+		"--wait",
+	}, spec.ApplyCmd.Args[1:])
+
+	require.NotNil(t, spec.DeleteCmd)
+	assert.Equal(t, []string{"helm-release", "delete", "rose-quartz", "--namespace", "garnet"},
+		spec.DeleteCmd.Args[1:])
+
+	assert.ElementsMatch(t, []string{f.JoinPath("helm"), f.JoinPath("dev/helm/values-dev.yaml")},
+		m.K8sTarget().Dependencies())
+}
+
+func TestHelmReleaseRemoteChart(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+helm_release('cache', 'bitnami/redis', version='18.0.0')
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("cache")
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Equal(t, []string{
+		"helm-release", "apply", "cache", "bitnami/redis", "--", "--version", "18.0.0",
+	}, spec.ApplyCmd.Args[1:])
+	assert.Empty(t, m.K8sTarget().Dependencies())
+}
+
+func TestHelmReleaseImageKeyPairs(t *testing.T) {
+	f := newFixture(t)
+	f.setupHelm()
+
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('gcr.io/frontend', '.')
+docker_build('gcr.io/backend', '.')
+helm_release('app', './helm',
+             image_deps=['gcr.io/frontend', 'gcr.io/backend'],
+             image_keys=['frontend.image', ('backend.image.repository', 'backend.image.tag')])
+`)
+
+	f.load()
+
+	m := f.assertNextManifest("app")
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Equal(t, []string{"gcr.io_frontend", "gcr.io_backend"}, spec.ImageMaps)
+	assert.Equal(t, []string{
+		"helm-release", "apply", "app", f.JoinPath("helm"),
+		"--image-key", "frontend.image",
+		"--image-key", "backend.image.repository,backend.image.tag",
+		"--",
+	}, spec.ApplyCmd.Args[1:])
+}
+
+func TestHelmReleaseImageKeysMismatch(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Dockerfile", "FROM golang:1.10")
+	f.file("Tiltfile", `
+docker_build('gcr.io/frontend', '.')
+docker_build('gcr.io/backend', '.')
+helm_release('app', 'bitnami/app', image_deps=['gcr.io/frontend', 'gcr.io/backend'])
+`)
+
+	f.loadErrString("helm_release: image_keys must be specified when there's more than one image in image_deps")
+}
+
+func TestHelmReleaseConflict(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+helm_release('app', 'bitnami/app')
+helm_release('app', 'bitnami/app')
+`)
+
+	f.loadErrString(`k8s_resource named "app" already exists`)
+}
@@ -6,6 +6,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/distribution/reference"
 	"github.com/pkg/errors"
@@ -27,6 +28,10 @@ import (
 
 var emptyYAMLError = fmt.Errorf("Empty YAML passed to k8s_yaml")
 
+// How long we watch the pods of a deploy for failures when
+// auto_rollback is enabled without an explicit window.
+const defaultAutoRollbackWindow = time.Minute
+
 type referenceList []reference.Named
 
 func (l referenceList) Len() int           { return len(l) }
@@ -57,6 +62,15 @@ type k8sResource struct {
 
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
 
+	ssaConflictPolicy v1alpha1.SSAConflictPolicy
+
+	diffPreview *v1alpha1.KubernetesApplyDiffPreview
+
+	logParser v1alpha1.LogParser
+
+	// If non-zero, roll back deploys whose pods fail within this window.
+	autoRollbackWindow time.Duration
+
 	imageMapDeps []string
 
 	triggerMode triggerMode
@@ -70,6 +84,10 @@ type k8sResource struct {
 
 	labels map[string]string
 
+	priority int
+
+	cancelStaleImageBuilds bool
+
 	customDeploy *k8sCustomDeploy
 }
 
@@ -88,8 +106,18 @@ type k8sResourceOptions struct {
 	manuallyGrouped   bool
 	podReadinessMode  model.PodReadinessMode
 	discoveryStrategy v1alpha1.KubernetesDiscoveryStrategy
+	ssaConflictPolicy v1alpha1.SSAConflictPolicy
+	previewDiff       value.Optional[starlark.Bool]
+	confirmDiffKinds  []string
+	logParser         v1alpha1.LogParser
 	links             []model.Link
 	labels            map[string]string
+	priority          *int
+
+	cancelStaleImageBuilds value.Optional[starlark.Bool]
+
+	autoRollback       value.Optional[starlark.Bool]
+	autoRollbackWindow time.Duration
 }
 
 // Count image injection for analytics.
@@ -305,6 +333,14 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 	var autoInit = value.Optional[starlark.Bool]{Value: true}
 	var labels value.LabelSet
 	var discoveryStrategy tiltfile_k8s.DiscoveryStrategy
+	var ssaConflictPolicy tiltfile_k8s.SSAConflictPolicy
+	var previewDiff value.Optional[starlark.Bool]
+	var confirmDiffKinds value.StringOrStringList
+	var logParser value.LogParser
+	var autoRollback value.Optional[starlark.Bool]
+	var autoRollbackWindow value.Duration
+	var priorityVal value.Optional[starlark.Int]
+	var cancelStaleImageBuilds value.Optional[starlark.Bool]
 
 	if err := s.unpackArgs(fn.Name(), args, kwargs,
 		"workload?", &workload,
@@ -319,6 +355,14 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		"links?", &links,
 		"labels?", &labels,
 		"discovery_strategy?", &discoveryStrategy,
+		"priority?", &priorityVal,
+		"cancel_stale_image_builds?", &cancelStaleImageBuilds,
+		"ssa_conflicts?", &ssaConflictPolicy,
+		"preview_diff?", &previewDiff,
+		"confirm_diff_kinds?", &confirmDiffKinds,
+		"log_parser?", &logParser,
+		"auto_rollback?", &autoRollback,
+		"auto_rollback_window?", &autoRollbackWindow,
 	); err != nil {
 		return nil, err
 	}
@@ -364,6 +408,15 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		labelMap[k] = v
 	}
 
+	priority, err := starlarkPriorityToModel(fn.Name(), priorityVal)
+	if err != nil {
+		return nil, err
+	}
+
+	if autoRollbackWindow.AsDuration() < 0 {
+		return nil, fmt.Errorf("%s %q: auto_rollback_window must be positive", fn.Name(), resourceName)
+	}
+
 	s.k8sResourceOptions = append(s.k8sResourceOptions, k8sResourceOptions{
 		workload:          resourceName,
 		newName:           string(newName),
@@ -379,6 +432,16 @@ func (s *tiltfileState) k8sResource(thread *starlark.Thread, fn *starlark.Builti
 		links:             links.Links,
 		labels:            labelMap,
 		discoveryStrategy: v1alpha1.KubernetesDiscoveryStrategy(discoveryStrategy),
+		ssaConflictPolicy: v1alpha1.SSAConflictPolicy(ssaConflictPolicy),
+		previewDiff:       previewDiff,
+		confirmDiffKinds:  confirmDiffKinds.Values,
+		logParser:         v1alpha1.LogParser(logParser),
+		priority:          priority,
+
+		cancelStaleImageBuilds: cancelStaleImageBuilds,
+
+		autoRollback:       autoRollback,
+		autoRollbackWindow: autoRollbackWindow.AsDuration(),
 	})
 
 	return starlark.None, nil
@@ -30,3 +30,27 @@ func (ds *DiscoveryStrategy) Unpack(v starlark.Value) error {
 	*ds = DiscoveryStrategy(kdStrategy)
 	return nil
 }
+
+// Deserializing the server-side apply conflict policy from starlark values.
+type SSAConflictPolicy v1alpha1.SSAConflictPolicy
+
+func (p *SSAConflictPolicy) Unpack(v starlark.Value) error {
+	s, ok := value.AsString(v)
+	if !ok {
+		return fmt.Errorf("Must be a string. Got: %s", v.Type())
+	}
+
+	policy := v1alpha1.SSAConflictPolicy(s)
+	if !(policy == "" ||
+		policy == v1alpha1.SSAConflictPolicyForce ||
+		policy == v1alpha1.SSAConflictPolicyFail ||
+		policy == v1alpha1.SSAConflictPolicyYield) {
+		return fmt.Errorf("Invalid. Must be one of: %q, %q, %q",
+			v1alpha1.SSAConflictPolicyForce,
+			v1alpha1.SSAConflictPolicyFail,
+			v1alpha1.SSAConflictPolicyYield)
+	}
+
+	*p = SSAConflictPolicy(policy)
+	return nil
+}
@@ -146,6 +146,40 @@ func (l liveUpdateInitialSyncStep) Hash() (uint32, error)  { return 0, nil }
 func (l liveUpdateInitialSyncStep) declarationPos() string { return l.position.String() }
 func (l liveUpdateInitialSyncStep) liveUpdateStep()        {}
 
+type liveUpdateVerifySyncStep struct {
+	position syntax.Position
+}
+
+var _ starlark.Value = liveUpdateVerifySyncStep{}
+var _ liveUpdateStep = liveUpdateVerifySyncStep{}
+
+func (l liveUpdateVerifySyncStep) String() string {
+	return "verify_sync step"
+}
+func (l liveUpdateVerifySyncStep) Type() string           { return "live_update_verify_sync_step" }
+func (l liveUpdateVerifySyncStep) Freeze()                {}
+func (l liveUpdateVerifySyncStep) Truth() starlark.Bool   { return true }
+func (l liveUpdateVerifySyncStep) Hash() (uint32, error)  { return 0, nil }
+func (l liveUpdateVerifySyncStep) declarationPos() string { return l.position.String() }
+func (l liveUpdateVerifySyncStep) liveUpdateStep()        {}
+
+type liveUpdateDeltaSyncStep struct {
+	position syntax.Position
+}
+
+var _ starlark.Value = liveUpdateDeltaSyncStep{}
+var _ liveUpdateStep = liveUpdateDeltaSyncStep{}
+
+func (l liveUpdateDeltaSyncStep) String() string {
+	return "delta_sync step"
+}
+func (l liveUpdateDeltaSyncStep) Type() string           { return "live_update_delta_sync_step" }
+func (l liveUpdateDeltaSyncStep) Freeze()                {}
+func (l liveUpdateDeltaSyncStep) Truth() starlark.Bool   { return true }
+func (l liveUpdateDeltaSyncStep) Hash() (uint32, error)  { return 0, nil }
+func (l liveUpdateDeltaSyncStep) declarationPos() string { return l.position.String() }
+func (l liveUpdateDeltaSyncStep) liveUpdateStep()        {}
+
 func (s *tiltfileState) recordLiveUpdateStep(step liveUpdateStep) {
 	s.unconsumedLiveUpdateSteps[step.declarationPos()] = step
 }
@@ -163,6 +197,32 @@ func (s *tiltfileState) liveUpdateInitialSync(thread *starlark.Thread, fn *starl
 	return ret, nil
 }
 
+// verifySync creates a live update step that checks synced files in the container against local files.
+func (s *tiltfileState) liveUpdateVerifySync(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := s.unpackArgs(fn.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	ret := liveUpdateVerifySyncStep{
+		position: thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
+// deltaSync creates a live update step that copies only the changed blocks of large files.
+func (s *tiltfileState) liveUpdateDeltaSync(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	if err := s.unpackArgs(fn.Name(), args, kwargs); err != nil {
+		return nil, err
+	}
+
+	ret := liveUpdateDeltaSyncStep{
+		position: thread.CallFrame(1).Pos,
+	}
+	s.recordLiveUpdateStep(ret)
+	return ret, nil
+}
+
 func (s *tiltfileState) liveUpdateFallBackOn(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
 	files := value.NewLocalPathListUnpacker(thread)
 	if err := s.unpackArgs(fn.Name(), args, kwargs, "paths", &files); err != nil {
@@ -275,6 +335,20 @@ func (s *tiltfileState) liveUpdateFromSteps(t *starlark.Thread, maybeSteps starl
 
 			spec.InitialSync = &v1alpha1.LiveUpdateInitialSync{}
 
+		case liveUpdateVerifySyncStep:
+			if spec.Verify != nil || noMoreFallbacks {
+				return v1alpha1.LiveUpdateSpec{}, fmt.Errorf("verify_sync must appear at most once, before any sync, run, or restart_container steps")
+			}
+
+			spec.Verify = &v1alpha1.LiveUpdateVerify{}
+
+		case liveUpdateDeltaSyncStep:
+			if spec.DeltaSync != nil || noMoreFallbacks {
+				return v1alpha1.LiveUpdateSpec{}, fmt.Errorf("delta_sync must appear at most once, before any sync, run, or restart_container steps")
+			}
+
+			spec.DeltaSync = &v1alpha1.LiveUpdateDeltaSync{}
+
 		case liveUpdateFallBackOnStep:
 			seenInitialSync = true
 			if noMoreFallbacks {
@@ -585,6 +585,90 @@ docker_build('gcr.io/foo', 'foo',
 	f.loadErrString("initial_sync must appear at most once, at the start of the list")
 }
 
+func TestLiveUpdate_VerifySync(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    verify_sync(),
+    sync('foo', '/app'),
+    run('npm install'),
+  ]
+)`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: "foo", ContainerPath: "/app"},
+		},
+		Execs: []v1alpha1.LiveUpdateExec{
+			{Args: []string{"sh", "-c", "npm install"}},
+		},
+		Verify: &v1alpha1.LiveUpdateVerify{},
+	}
+
+	f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+}
+
+func TestLiveUpdate_VerifySync_MustPrecedeSyncs(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/app'),
+    verify_sync(),
+  ]
+)`)
+	f.loadErrString("verify_sync must appear at most once, before any sync, run, or restart_container steps")
+}
+
+func TestLiveUpdate_DeltaSync(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    delta_sync(),
+    sync('foo', '/app'),
+  ]
+)`)
+	f.load()
+
+	lu := v1alpha1.LiveUpdateSpec{
+		BasePath: f.Path(),
+		Syncs: []v1alpha1.LiveUpdateSync{
+			{LocalPath: "foo", ContainerPath: "/app"},
+		},
+		DeltaSync: &v1alpha1.LiveUpdateDeltaSync{},
+	}
+
+	f.assertNextManifest("foo", db(image("gcr.io/foo"), lu))
+}
+
+func TestLiveUpdate_DeltaSync_MustPrecedeSyncs(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build('gcr.io/foo', 'foo',
+  live_update=[
+    sync('foo', '/app'),
+    delta_sync(),
+  ]
+)`)
+	f.loadErrString("delta_sync must appear at most once, before any sync, run, or restart_container steps")
+}
+
 func TestLiveUpdate_InitialSync_K8sCustomDeploy(t *testing.T) {
 	f := newFixture(t)
 
@@ -2,6 +2,7 @@ package tiltfile
 
 import (
 	"fmt"
+	"math"
 	"path/filepath"
 
 	"github.com/pkg/errors"
@@ -14,6 +15,7 @@ import (
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/procutil"
 )
 
 const testDeprecationMsg = "test() is deprecated and will be removed in a future release.\n" +
@@ -26,6 +28,7 @@ type localResource struct {
 	// The working directory of the execution thread where the local resource was created.
 	threadDir     string
 	deps          []string
+	outputs       []string
 	triggerMode   triggerMode
 	autoInit      bool
 	resourceDeps  []string
@@ -33,8 +36,13 @@ type localResource struct {
 	allowParallel bool
 	links         []model.Link
 	labels        map[string]string
+	priority      int
 
 	readinessProbe *v1alpha1.Probe
+	livenessProbe  *v1alpha1.Probe
+	startupProbe   *v1alpha1.Probe
+	serveStop      *v1alpha1.CmdStopSpec
+	logParser      v1alpha1.LogParser
 }
 
 func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
@@ -42,14 +50,20 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 	var updateCmdVal, updateCmdBatVal, serveCmdVal, serveCmdBatVal starlark.Value
 	var updateEnv, serveEnv value.StringStringMap
 	var triggerMode triggerMode
-	var readinessProbe probe.Probe
+	var readinessProbe, livenessProbe, startupProbe probe.Probe
 	var updateCmdDirVal, serveCmdDirVal starlark.Value
+	var serveStopSignal string
+	var serveStopGracePeriod value.Duration
+	var servePreStopCmdVal starlark.Value
+	var logParser value.LogParser
 
 	deps := value.NewLocalPathListUnpacker(thread)
+	outputs := value.NewLocalPathListUnpacker(thread)
 
 	var resourceDepsVal starlark.Sequence
 	var ignoresVal starlark.Value
 	var allowParallel bool
+	var priorityVal value.Optional[starlark.Int]
 	var links links.LinkList
 	var labels value.LabelSet
 	autoInit := true
@@ -78,6 +92,14 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		"readiness_probe?", &readinessProbe,
 		"dir?", &updateCmdDirVal,
 		"serve_dir?", &serveCmdDirVal,
+		"serve_stop_signal?", &serveStopSignal,
+		"serve_stop_grace_period?", &serveStopGracePeriod,
+		"serve_pre_stop_cmd?", &servePreStopCmdVal,
+		"liveness_probe?", &livenessProbe,
+		"startup_probe?", &startupProbe,
+		"priority?", &priorityVal,
+		"outputs?", &outputs,
+		"log_parser?", &logParser,
 	); err != nil {
 		return nil, err
 	}
@@ -87,6 +109,11 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		return nil, errors.Wrapf(err, "%s: resource_deps", fn.Name())
 	}
 
+	priority, err := starlarkPriorityToModel(fn.Name(), priorityVal)
+	if err != nil {
+		return nil, err
+	}
+
 	ignores, err := parseValuesToStrings(ignoresVal, "ignore")
 	if err != nil {
 		return nil, err
@@ -117,18 +144,47 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		return nil, fmt.Errorf("local_resource must have a cmd and/or a serve_cmd, but both were empty")
 	}
 
+	if len(outputs.Value) != 0 && updateCmd.Empty() {
+		return nil, fmt.Errorf("local_resource: 'outputs' specified but 'cmd' is empty")
+	}
+
+	serveStop, err := serveStopSpec(thread, serveStopSignal, serveStopGracePeriod, servePreStopCmdVal, serveCmdDirVal, serveEnv)
+	if err != nil {
+		return nil, errors.Wrapf(err, "%s", fn.Name())
+	}
+	if serveStop != nil && serveCmd.Empty() {
+		return nil, fmt.Errorf("local_resource: 'serve_stop_signal', 'serve_stop_grace_period', and 'serve_pre_stop_cmd' only affect 'serve_cmd', but 'serve_cmd' is empty")
+	}
+
+	if logParser != "" && serveCmd.Empty() {
+		return nil, fmt.Errorf("local_resource: 'log_parser' only affects 'serve_cmd', but 'serve_cmd' is empty")
+	}
+
 	probeSpec := readinessProbe.Spec()
 	if probeSpec != nil && serveCmd.Empty() {
 		s.logger.Warnf("Ignoring readiness probe for local resource %q (no serve_cmd was defined)", name)
 		probeSpec = nil
 	}
 
+	livenessProbeSpec := livenessProbe.Spec()
+	if livenessProbeSpec != nil && serveCmd.Empty() {
+		s.logger.Warnf("Ignoring liveness probe for local resource %q (no serve_cmd was defined)", name)
+		livenessProbeSpec = nil
+	}
+
+	startupProbeSpec := startupProbe.Spec()
+	if startupProbeSpec != nil && serveCmd.Empty() {
+		s.logger.Warnf("Ignoring startup probe for local resource %q (no serve_cmd was defined)", name)
+		startupProbeSpec = nil
+	}
+
 	res := &localResource{
 		name:           string(name),
 		updateCmd:      updateCmd,
 		serveCmd:       serveCmd,
 		threadDir:      filepath.Dir(starkit.CurrentExecPath(thread)),
 		deps:           deps.Value,
+		outputs:        outputs.Value,
 		triggerMode:    triggerMode,
 		autoInit:       autoInit,
 		resourceDeps:   resourceDeps,
@@ -137,6 +193,13 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 		links:          links.Links,
 		labels:         labels.Values,
 		readinessProbe: probeSpec,
+		livenessProbe:  livenessProbeSpec,
+		startupProbe:   startupProbeSpec,
+		serveStop:      serveStop,
+		logParser:      v1alpha1.LogParser(logParser),
+	}
+	if priority != nil {
+		res.priority = *priority
 	}
 
 	// check for duplicate resources by name and throw error if found
@@ -149,3 +212,35 @@ func (s *tiltfileState) localResource(thread *starlark.Thread, fn *starlark.Buil
 
 	return starlark.None, nil
 }
+
+// Converts the serve_stop_* arguments of local_resource to a CmdStopSpec.
+//
+// Returns nil if none of them were specified.
+func serveStopSpec(thread *starlark.Thread, signal string, gracePeriod value.Duration, preStopCmdVal, dirVal starlark.Value, env map[string]string) (*v1alpha1.CmdStopSpec, error) {
+	if signal == "" && gracePeriod.IsZero() && preStopCmdVal == nil {
+		return nil, nil
+	}
+
+	if signal != "" {
+		_, err := procutil.ParseSignal(signal)
+		if err != nil {
+			return nil, errors.Wrap(err, "serve_stop_signal")
+		}
+	}
+
+	if gracePeriod.AsDuration() < 0 {
+		return nil, fmt.Errorf("serve_stop_grace_period: must be positive, got %s", gracePeriod.AsDuration())
+	}
+	gracePeriodSecs := int32(math.Ceil(gracePeriod.AsDuration().Seconds()))
+
+	preStopCmd, err := value.ValueToHostCmd(thread, preStopCmdVal, dirVal, env)
+	if err != nil {
+		return nil, errors.Wrap(err, "serve_pre_stop_cmd")
+	}
+
+	return &v1alpha1.CmdStopSpec{
+		Signal:             signal,
+		GracePeriodSeconds: gracePeriodSecs,
+		PreStopArgs:        preStopCmd.Argv,
+	}, nil
+}
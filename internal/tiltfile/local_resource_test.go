@@ -1,6 +1,12 @@
 package tiltfile
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
 
 func TestTestFnDeprecated(t *testing.T) {
 	f := newFixture(t)
@@ -55,3 +61,158 @@ local_resource("test", serve_cmd="python server.py", serve_dir="./foo")
 `)
 	f.load()
 }
+
+func TestLocalResourceServeStop(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py",
+               serve_stop_signal="SIGINT",
+               serve_stop_grace_period="90s",
+               serve_pre_stop_cmd=["./flush.sh", "--now"])
+`)
+	f.load()
+
+	m := f.assertNextManifest("test")
+	assert.Equal(t, &v1alpha1.CmdStopSpec{
+		Signal:             "SIGINT",
+		GracePeriodSeconds: 90,
+		PreStopArgs:        []string{"./flush.sh", "--now"},
+	}, m.LocalTarget().ServeCmdStop)
+}
+
+func TestLocalResourceServeStopDefault(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py")
+`)
+	f.load()
+
+	m := f.assertNextManifest("test")
+	assert.Nil(t, m.LocalTarget().ServeCmdStop)
+}
+
+func TestLocalResourceServeStopInvalidSignal(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py", serve_stop_signal="SIGWINCH")
+`)
+	f.loadErrString(`serve_stop_signal: unsupported signal "SIGWINCH"`)
+}
+
+func TestLocalResourceServeStopWithoutServeCmd(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", cmd="echo hi", serve_stop_signal="SIGINT")
+`)
+	f.loadErrString("only affect 'serve_cmd', but 'serve_cmd' is empty")
+}
+
+func TestLocalResourceLogParser(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py", log_parser="json")
+`)
+	f.load()
+
+	m := f.assertNextManifest("test")
+	assert.Equal(t, v1alpha1.LogParserJSON, m.LocalTarget().ServeCmdLogParser)
+}
+
+func TestLocalResourceLogParserInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py", log_parser="xml")
+`)
+	f.loadErrString(`for parameter "log_parser": Invalid. Must be one of: "json", "logfmt"`)
+}
+
+func TestLocalResourceLogParserWithoutServeCmd(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", cmd="echo hi", log_parser="logfmt")
+`)
+	f.loadErrString("'log_parser' only affects 'serve_cmd', but 'serve_cmd' is empty")
+}
+
+func TestLocalResourceLivenessAndStartupProbes(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py",
+               liveness_probe=probe(period_secs=5, http_get=http_get_action(port=8080, path="/healthz")),
+               startup_probe=probe(failure_threshold=30, tcp_socket=tcp_socket_action(port=8080)))
+`)
+	f.load()
+
+	lt := f.assertNextManifest("test").LocalTarget()
+	assert.Equal(t, &v1alpha1.Probe{
+		PeriodSeconds: 5,
+		Handler: v1alpha1.Handler{
+			HTTPGet: &v1alpha1.HTTPGetAction{Port: 8080, Path: "/healthz"},
+		},
+	}, lt.LivenessProbe)
+	assert.Equal(t, &v1alpha1.Probe{
+		FailureThreshold: 30,
+		Handler: v1alpha1.Handler{
+			TCPSocket: &v1alpha1.TCPSocketAction{Port: 8080},
+		},
+	}, lt.StartupProbe)
+}
+
+func TestLocalResourceLivenessProbeWithoutServeCmd(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", cmd="echo hi",
+               liveness_probe=probe(exec=exec_action(["true"])))
+`)
+	f.loadAllowWarnings()
+
+	assert.Nil(t, f.assertNextManifest("test").LocalTarget().LivenessProbe)
+	assert.Equal(t, []string{`Ignoring liveness probe for local resource "test" (no serve_cmd was defined)` + "\n"}, f.warnings)
+}
+
+func TestLocalResourceGRPCReadinessProbe(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="./server",
+               readiness_probe=probe(grpc=grpc_action(port=50051, service="frontend")))
+`)
+	f.load()
+
+	lt := f.assertNextManifest("test").LocalTarget()
+	assert.Equal(t, &v1alpha1.GRPCAction{Port: 50051, Service: "frontend"}, lt.ReadinessProbe.GRPC)
+}
+
+func TestLocalResourceOutputs(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("gen", cmd="make gen", deps=["proto"], outputs=["proto/gen", "out.txt"])
+`)
+	f.load()
+
+	lt := f.assertNextManifest("gen").LocalTarget()
+	assert.Equal(t, []string{f.JoinPath("proto", "gen"), f.JoinPath("out.txt")}, lt.Outputs)
+
+	// Outputs are ignored, so that the cmd doesn't trigger itself.
+	assert.Contains(t, lt.FileWatchIgnores, v1alpha1.IgnoreDef{BasePath: f.JoinPath("proto", "gen")})
+	assert.Contains(t, lt.FileWatchIgnores, v1alpha1.IgnoreDef{BasePath: f.JoinPath("out.txt")})
+}
+
+func TestLocalResourceOutputsWithoutCmd(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource("test", serve_cmd="python server.py", outputs=["out.txt"])
+`)
+	f.loadErrString("'outputs' specified but 'cmd' is empty")
+}
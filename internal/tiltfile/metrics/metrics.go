@@ -23,7 +23,7 @@ func setMetricsSettings(thread *starlark.Thread, fn *starlark.Builtin, args star
 		return nil, err
 	}
 
-	logger.Get(ctx).Warnf("experimental_metrics_settings() is deprecated")
+	logger.Get(ctx).Warnf("experimental_metrics_settings() is deprecated. Tilt serves Prometheus metrics at /metrics on its web port.")
 	return starlark.None, nil
 }
 
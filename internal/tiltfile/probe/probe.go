@@ -18,9 +18,10 @@ const (
 	typeExecAction      = "ExecAction"
 	typeHTTPGetAction   = "HTTPGetAction"
 	typeTCPSocketAction = "TCPSocketAction"
+	typeGRPCAction      = "GRPCAction"
 )
 
-var errInvalidProbeAction = errors.New("exactly one of exec, http_get, or tcp_socket must be specified")
+var errInvalidProbeAction = errors.New("exactly one of exec, http_get, tcp_socket, or grpc must be specified")
 
 func NewPlugin() Plugin {
 	return Plugin{}
@@ -40,6 +41,9 @@ func (e Plugin) OnStart(env *starkit.Environment) error {
 	if err := env.AddBuiltin("tcp_socket_action", e.tcpSocketAction); err != nil {
 		return fmt.Errorf("could not add tcp_socket_action builtin: %v", err)
 	}
+	if err := env.AddBuiltin("grpc_action", e.grpcAction); err != nil {
+		return fmt.Errorf("could not add grpc_action builtin: %v", err)
+	}
 	if err := env.AddBuiltin("probe", e.probe); err != nil {
 		return fmt.Errorf("could not add Probe builtin: %v", err)
 	}
@@ -82,6 +86,7 @@ func (e Plugin) probe(thread *starlark.Thread, fn *starlark.Builtin, args starla
 	var exec ExecAction
 	var httpGet HTTPGetAction
 	var tcpSocket TCPSocketAction
+	var grpc GRPCAction
 	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 		"initial_delay_secs?", &initialDelayVal,
 		"timeout_secs?", &timeoutVal,
@@ -91,6 +96,7 @@ func (e Plugin) probe(thread *starlark.Thread, fn *starlark.Builtin, args starla
 		"exec?", &exec,
 		"http_get?", &httpGet,
 		"tcp_socket?", &tcpSocket,
+		"grpc?", &grpc,
 	)
 	if err != nil {
 		return nil, err
@@ -106,6 +112,7 @@ func (e Plugin) probe(thread *starlark.Thread, fn *starlark.Builtin, args starla
 			HTTPGet:   httpGet.action,
 			Exec:      exec.action,
 			TCPSocket: tcpSocket.action,
+			GRPC:      grpc.action,
 		},
 	}
 
@@ -123,6 +130,7 @@ func (e Plugin) probe(thread *starlark.Thread, fn *starlark.Builtin, args starla
 			{starlark.String("exec"), exec.ValueOrNone()},
 			{starlark.String("http_get"), httpGet.ValueOrNone()},
 			{starlark.String("tcp_socket"), tcpSocket.ValueOrNone()},
+			{starlark.String("grpc"), grpc.ValueOrNone()},
 		}),
 		spec: spec,
 	}, nil
@@ -139,6 +147,9 @@ func validateProbeSpec(spec *v1alpha1.Probe) error {
 	if spec.TCPSocket != nil {
 		actionCount++
 	}
+	if spec.GRPC != nil {
+		actionCount++
+	}
 	if actionCount != 1 {
 		return errInvalidProbeAction
 	}
@@ -316,3 +327,68 @@ func (e Plugin) tcpSocketAction(thread *starlark.Thread, fn *starlark.Builtin, a
 		action: spec,
 	}, nil
 }
+
+type GRPCAction struct {
+	*starlarkstruct.Struct
+	action *v1alpha1.GRPCAction
+}
+
+var _ starlark.Value = GRPCAction{}
+
+// Unpack handles the possibility of receiving starlark.None but otherwise just casts to GRPCAction
+func (g *GRPCAction) Unpack(v starlark.Value) error {
+	if v == nil || v == starlark.None {
+		return nil
+	}
+
+	if grpc, ok := v.(GRPCAction); ok {
+		*g = grpc
+	} else {
+		return fmt.Errorf("got %T, want %s", v, g.Type())
+	}
+
+	return nil
+}
+
+func (g GRPCAction) ValueOrNone() starlark.Value {
+	// starlarkstruct does not handle being nil well, so need to explicitly return a NoneType
+	// instead of it when embedding in another value (i.e. within the probe)
+	if g.Struct != nil {
+		return g
+	}
+	return starlark.None
+}
+
+func (g GRPCAction) Type() string {
+	return typeGRPCAction
+}
+
+func (e Plugin) grpcAction(thread *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var host, service starlark.String
+	var port int
+	var tls bool
+	err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
+		"port", &port,
+		"host?", &host,
+		"service?", &service,
+		"tls?", &tls,
+	)
+	if err != nil {
+		return nil, err
+	}
+	spec := &v1alpha1.GRPCAction{
+		Host:    host.GoString(),
+		Port:    int32(port),
+		Service: service.GoString(),
+		TLS:     tls,
+	}
+	return GRPCAction{
+		Struct: starlarkstruct.FromKeywords(starlark.String(typeGRPCAction), []starlark.Tuple{
+			{starlark.String("host"), host},
+			{starlark.String("port"), starlark.MakeInt(port)},
+			{starlark.String("service"), service},
+			{starlark.String("tls"), starlark.Bool(tls)},
+		}),
+		action: spec,
+	}, nil
+}
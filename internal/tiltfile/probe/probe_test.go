@@ -29,6 +29,7 @@ print(p.failure_threshold)
 print("exec:", p.exec)
 print("http_get:", p.http_get)
 print("tcp_socket:", p.tcp_socket)
+print("grpc:", p.grpc)
 `)
 
 	_, err := f.ExecFile("Tiltfile")
@@ -43,6 +44,7 @@ print("tcp_socket:", p.tcp_socket)
 exec: ExecAction(command = [])
 http_get: None
 tcp_socket: None
+grpc: None
 `)
 
 	require.Contains(t, f.PrintOutput(), expectedOutput)
@@ -54,7 +56,7 @@ func TestProbeActions_None(t *testing.T) {
 	f.File("Tiltfile", `p = probe()`)
 
 	_, err := f.ExecFile("Tiltfile")
-	require.EqualError(t, err, `exactly one of exec, http_get, or tcp_socket must be specified`)
+	require.EqualError(t, err, `exactly one of exec, http_get, tcp_socket, or grpc must be specified`)
 }
 
 func TestProbeActions_Multiple(t *testing.T) {
@@ -65,7 +67,7 @@ p = probe(exec=exec_action([]), http_get=http_get_action(8000), tcp_socket=None)
 `)
 
 	_, err := f.ExecFile("Tiltfile")
-	require.EqualError(t, err, `exactly one of exec, http_get, or tcp_socket must be specified`)
+	require.EqualError(t, err, `exactly one of exec, http_get, tcp_socket, or grpc must be specified`)
 }
 
 func TestProbeActions_Exec(t *testing.T) {
@@ -152,3 +154,26 @@ localhost
 
 	require.Contains(t, f.PrintOutput(), expectedOutput)
 }
+
+func TestProbeActions_GRPC(t *testing.T) {
+	f := starkit.NewFixture(t, NewPlugin())
+
+	f.File("Tiltfile", `
+p = probe(grpc=grpc_action(50051, service="frontend", tls=True))
+
+print(p.grpc.port)
+print(p.grpc.service)
+print(p.grpc.tls)
+`)
+
+	_, err := f.ExecFile("Tiltfile")
+	require.NoError(t, err)
+
+	expectedOutput := strings.TrimSpace(`
+50051
+frontend
+True
+`)
+
+	require.Contains(t, f.PrintOutput(), expectedOutput)
+}
@@ -57,6 +57,48 @@ experimental_telemetry_cmd('bar.sh')
 	assert.EqualError(t, err, "experimental_telemetry_cmd called multiple times; already set to foo.sh")
 }
 
+func TestOTLPExporter(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+otlp_exporter('localhost:4317', protocol='grpc', headers={'api-key': 'secret'})
+`)
+	result, err := f.ExecFile("Tiltfile")
+
+	assert.NoError(t, err)
+	assert.Equal(t, model.OTLPSettings{
+		Endpoint: "localhost:4317",
+		Protocol: "grpc",
+		Headers:  map[string]string{"api-key": "secret"},
+	}, MustState(result).OTLP)
+}
+
+func TestOTLPExporterDefaultProtocol(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "otlp_exporter('http://localhost:4318')")
+	result, err := f.ExecFile("Tiltfile")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "http/protobuf", MustState(result).OTLP.Protocol)
+}
+
+func TestOTLPExporterBadProtocol(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", "otlp_exporter('http://localhost:4318', protocol='http/json')")
+	_, err := f.ExecFile("Tiltfile")
+
+	assert.EqualError(t, err, `otlp_exporter: protocol must be one of "grpc", "http/protobuf". Actual: "http/json"`)
+}
+
+func TestOTLPExporterMultiple(t *testing.T) {
+	f := newFixture(t)
+	f.File("Tiltfile", `
+otlp_exporter('localhost:4317')
+otlp_exporter('localhost:4318')
+`)
+	_, err := f.ExecFile("Tiltfile")
+	assert.EqualError(t, err, "otlp_exporter called multiple times; already set to localhost:4317")
+}
+
 func newFixture(tb testing.TB) *starkit.Fixture {
 	return starkit.NewFixture(tb, NewPlugin())
 }
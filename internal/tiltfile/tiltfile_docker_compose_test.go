@@ -447,6 +447,34 @@ docker_compose('docker-compose1.yml')`
 	assert.Equal(t, 2, len(f.loadResult.Manifests))
 }
 
+func TestDockerComposeResourcePriority(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', priority=3)
+`)
+
+	f.load()
+	f.assertNextManifest("foo", priority(3))
+}
+
+func TestDockerComposeCancelStaleImageBuilds(t *testing.T) {
+	f := newFixture(t)
+
+	f.dockerfile(filepath.Join("foo", "Dockerfile"))
+	f.file("docker-compose.yml", simpleConfig)
+	f.file("Tiltfile", `
+docker_compose('docker-compose.yml')
+dc_resource('foo', cancel_stale_image_builds=True)
+`)
+
+	f.load()
+	f.assertNextManifest("foo", cancelStaleImageBuilds(true))
+}
+
 func TestMultipleDockerComposeNameConflict(t *testing.T) {
 	f := newFixture(t)
 
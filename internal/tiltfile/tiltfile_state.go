@@ -53,6 +53,7 @@ import (
 	"github.com/tilt-dev/tilt/internal/tiltfile/telemetry"
 	"github.com/tilt-dev/tilt/internal/tiltfile/updatesettings"
 	tfv1alpha1 "github.com/tilt-dev/tilt/internal/tiltfile/v1alpha1"
+	"github.com/tilt-dev/tilt/internal/tiltfile/value"
 	"github.com/tilt-dev/tilt/internal/tiltfile/version"
 	"github.com/tilt-dev/tilt/internal/tiltfile/watch"
 	fwatch "github.com/tilt-dev/tilt/internal/watch"
@@ -92,6 +93,9 @@ type tiltfileState struct {
 	buildIndex     *buildIndex
 	k8sObjectIndex *tiltfile_k8s.State
 
+	// the path of the main Tiltfile, which scopes the applysets of its resources
+	tiltfilePath string
+
 	// The mutation semantics of these 3 things are a bit fuzzy
 	// Objects are moved back and forth between them in different
 	// phases of tiltfile execution and post-execution assembly.
@@ -116,6 +120,8 @@ type tiltfileState struct {
 
 	workloadToResourceFunction workloadToResourceFunction
 
+	concurrencyGroups []concurrencyGroup
+
 	// for assembly
 	usedImages map[string]bool
 
@@ -209,6 +215,7 @@ func (s *tiltfileState) print(_ *starlark.Thread, msg string) {
 // all the mutable state collected by execution.
 func (s *tiltfileState) loadManifests(tf *v1alpha1.Tiltfile) ([]model.Manifest, starkit.Model, error) {
 	s.logger.Infof("Loading Tiltfile at: %s", tf.Spec.Path)
+	s.tiltfilePath = tf.Spec.Path
 
 	result, err := starkit.ExecFile(tf,
 		s,
@@ -332,6 +339,11 @@ to your Tiltfile. Otherwise, switch k8s contexts and restart Tilt.`, kubeContext
 		return nil, starkit.Model{}, err
 	}
 
+	err = s.assignConcurrencyGroups(manifests)
+	if err != nil {
+		return nil, starkit.Model{}, err
+	}
+
 	for i := range manifests {
 		// ensure all manifests have a label indicating they're owned
 		// by the Tiltfile - some reconcilers have special handling
@@ -373,6 +385,7 @@ const (
 	k8sImageJSONPathN           = "k8s_image_json_path"
 	workloadToResourceFunctionN = "workload_to_resource_function"
 	k8sCustomDeployN            = "k8s_custom_deploy"
+	helmReleaseN                = "helm_release"
 
 	// local resource functions
 	localResourceN = "local_resource"
@@ -385,6 +398,8 @@ const (
 
 	// live update functions
 	initialSyncN      = "initial_sync"
+	verifySyncN       = "verify_sync"
+	deltaSyncN        = "delta_sync"
 	fallBackOnN       = "fall_back_on"
 	syncN             = "sync"
 	runN              = "run"
@@ -402,7 +417,8 @@ const (
 	disableSnapshotsN = "disable_snapshots"
 
 	// other functions
-	setTeamN = "set_team"
+	setTeamN          = "set_team"
+	concurrencyGroupN = "concurrency_group"
 )
 
 type triggerMode int
@@ -468,6 +484,19 @@ func starlarkTriggerModeToModel(triggerMode triggerMode, autoInit bool) (model.T
 	}
 }
 
+// Converts the `priority` argument of a resource function.
+// Returns nil if the argument wasn't set.
+func starlarkPriorityToModel(fnName string, v value.Optional[starlark.Int]) (*int, error) {
+	if !v.IsSet {
+		return nil, nil
+	}
+	p, err := starlark.AsInt32(v.Value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: priority: %v", fnName, err)
+	}
+	return &p, nil
+}
+
 // count how many times each Builtin is called, for analytics
 func (s *tiltfileState) OnBuiltinCall(name string, fn *starlark.Builtin) {
 	s.builtinCallCounts[name]++
@@ -556,6 +585,7 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{filterYamlN, s.filterYaml},
 		{k8sResourceN, s.k8sResource},
 		{k8sCustomDeployN, s.k8sCustomDeploy},
+		{helmReleaseN, s.helmRelease},
 		{localResourceN, s.localResource},
 		{testN, s.localResource},
 		{portForwardN, s.portForward},
@@ -566,6 +596,8 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{helmN, s.helm},
 		{triggerModeN, s.triggerModeFn},
 		{initialSyncN, s.liveUpdateInitialSync},
+		{verifySyncN, s.liveUpdateVerifySync},
+		{deltaSyncN, s.liveUpdateDeltaSync},
 		{fallBackOnN, s.liveUpdateFallBackOn},
 		{syncN, s.liveUpdateSync},
 		{runN, s.liveUpdateRun},
@@ -574,6 +606,7 @@ func (s *tiltfileState) OnStart(e *starkit.Environment) error {
 		{disableFeatureN, s.disableFeature},
 		{disableSnapshotsN, s.disableSnapshots},
 		{setTeamN, s.setTeam},
+		{concurrencyGroupN, s.concurrencyGroup},
 	} {
 		err := e.AddBuiltin(b.name, b.builtin)
 		if err != nil {
@@ -807,6 +840,34 @@ func (s *tiltfileState) assembleK8s() error {
 			if opts.discoveryStrategy != "" {
 				r.discoveryStrategy = opts.discoveryStrategy
 			}
+			if opts.ssaConflictPolicy != "" {
+				r.ssaConflictPolicy = opts.ssaConflictPolicy
+			}
+			if opts.logParser != "" {
+				r.logParser = opts.logParser
+			}
+			if opts.previewDiff.IsSet {
+				r.diffPreview = nil
+				if opts.previewDiff.Value {
+					r.diffPreview = &v1alpha1.KubernetesApplyDiffPreview{}
+				}
+			}
+			if len(opts.confirmDiffKinds) > 0 {
+				// Confirming a diff implies previewing it.
+				if r.diffPreview == nil {
+					r.diffPreview = &v1alpha1.KubernetesApplyDiffPreview{}
+				}
+				r.diffPreview.ConfirmKinds = opts.confirmDiffKinds
+			}
+			if opts.autoRollbackWindow != 0 {
+				// Setting a window implies rolling back.
+				r.autoRollbackWindow = opts.autoRollbackWindow
+			} else if bool(opts.autoRollback.Value) && r.autoRollbackWindow == 0 {
+				r.autoRollbackWindow = defaultAutoRollbackWindow
+			}
+			if opts.autoRollback.IsSet && !bool(opts.autoRollback.Value) {
+				r.autoRollbackWindow = 0
+			}
 			r.portForwards = append(r.portForwards, opts.portForwards...)
 			if opts.triggerMode != TriggerModeUnset {
 				r.triggerMode = opts.triggerMode
@@ -819,6 +880,12 @@ func (s *tiltfileState) assembleK8s() error {
 			for k, v := range opts.labels {
 				r.labels[k] = v
 			}
+			if opts.priority != nil {
+				r.priority = *opts.priority
+			}
+			if opts.cancelStaleImageBuilds.IsSet {
+				r.cancelStaleImageBuilds = bool(opts.cancelStaleImageBuilds.Value)
+			}
 			if opts.newName != "" && opts.newName != r.name {
 				err := s.checkResourceConflict(opts.newName)
 				if err != nil {
@@ -1134,6 +1201,9 @@ func (s *tiltfileState) translateK8s(resources []*k8sResource, updateSettings mo
 			Name:                 mn,
 			TriggerMode:          tm,
 			ResourceDependencies: mds,
+			Priority:             r.priority,
+
+			CancelStaleImageBuilds: r.cancelStaleImageBuilds,
 		}
 
 		m = m.WithLabels(r.labels)
@@ -1184,6 +1254,8 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		Timeout:                         metav1.Duration{Duration: updateSettings.K8sUpsertTimeout()},
 		PortForwardTemplateSpec:         k8s.PortForwardTemplateSpec(s.defaultedPortForwards(r.portForwards)),
 		DiscoveryStrategy:               r.discoveryStrategy,
+		SSAConflictPolicy:               r.ssaConflictPolicy,
+		DiffPreview:                     r.diffPreview,
 		KubernetesDiscoveryTemplateSpec: kdTemplateSpec,
 		PodLogStreamTemplateSpec: &v1alpha1.PodLogStreamTemplateSpec{
 			SinceTime: &sinceTime,
@@ -1193,6 +1265,7 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 				string(container.LinkerdSidecarContainerName),
 				string(container.LinkerdInitContainerName),
 			},
+			LogParser: r.logParser,
 		},
 	}
 
@@ -1200,6 +1273,13 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		applySpec.ServerSideApply = true
 	}
 
+	// Conflicts only happen with server-side apply, so don't let
+	// the policy be silently ignored.
+	if r.ssaConflictPolicy != "" && r.ssaConflictPolicy != v1alpha1.SSAConflictPolicyForce && !applySpec.ServerSideApply {
+		return model.K8sTarget{}, fmt.Errorf("resource %q: ssa_conflicts=%q requires server-side apply. "+
+			"Enable it with update_settings(k8s_server_side_apply='true')", r.name, r.ssaConflictPolicy)
+	}
+
 	var deps []string
 	var ignores []v1alpha1.IgnoreDef
 	if r.customDeploy != nil {
@@ -1217,6 +1297,7 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		if err != nil {
 			return model.K8sTarget{}, err
 		}
+		applySpec.ApplySet = k8s.ApplySetID(s.tiltfilePath, r.name)
 
 		for _, locator := range s.k8sImageLocatorsList() {
 			if k8s.LocatorMatchesOne(locator, entities) {
@@ -1232,6 +1313,7 @@ func (s *tiltfileState) k8sDeployTarget(targetName model.TargetName, r *k8sResou
 		return model.K8sTarget{}, err
 	}
 
+	t.AutoRollbackWindow = r.autoRollbackWindow
 	t = t.WithImageDependencies(model.FilterLiveUpdateOnly(r.imageMapDeps, imageTargets)).
 		WithRefInjectCounts(r.imageRefInjectCounts()).
 		WithPathDependencies(deps).
@@ -1458,6 +1540,7 @@ func (s *tiltfileState) imgTargetsForDepsHelper(mn model.ManifestName, imageMapD
 				Secrets:            image.secretSpecs,
 				Network:            image.network,
 				CacheFrom:          image.cacheFrom,
+				CacheTo:            image.cacheTo,
 				Pull:               image.pullParent,
 				Platform:           image.platform,
 				ExtraTags:          image.extraTags,
@@ -1608,10 +1691,20 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 			})
 		}
 
+		// Don't re-run the cmd when it writes its own outputs.
+		for _, output := range r.outputs {
+			ignores = append(ignores, v1alpha1.IgnoreDef{BasePath: output})
+		}
+
 		lt := model.NewLocalTarget(model.TargetName(r.name), r.updateCmd, r.serveCmd, r.deps).
 			WithAllowParallel(r.allowParallel || r.updateCmd.Empty()).
 			WithLinks(r.links).
-			WithReadinessProbe(r.readinessProbe)
+			WithReadinessProbe(r.readinessProbe).
+			WithLivenessProbe(r.livenessProbe).
+			WithStartupProbe(r.startupProbe).
+			WithServeCmdStop(r.serveStop).
+			WithServeCmdLogParser(r.logParser).
+			WithOutputs(r.outputs)
 		lt.FileWatchIgnores = ignores
 
 		var mds []model.ManifestName
@@ -1622,6 +1715,7 @@ func (s *tiltfileState) translateLocal() ([]model.Manifest, error) {
 			Name:                 mn,
 			TriggerMode:          tm,
 			ResourceDependencies: mds,
+			Priority:             r.priority,
 		}.WithDeployTarget(lt)
 
 		m = m.WithLabels(r.labels)
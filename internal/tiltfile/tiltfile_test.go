@@ -693,6 +693,48 @@ docker_build("gcr.io/foo", "foo", cache_from='gcr.io/foo')
 	assert.Equal(t, []string{"gcr.io/foo"}, m.ImageTargets[0].BuildDetails.(model.DockerBuild).CacheFrom)
 }
 
+func TestDockerBuildCacheFromLocal(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", cache_from=['gcr.io/foo', 'type=local,src=.cache/foo'])
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, []string{"gcr.io/foo", "type=local,src=" + f.JoinPath(".cache", "foo")},
+		m.ImageTargets[0].BuildDetails.(model.DockerBuild).CacheFrom)
+}
+
+func TestDockerBuildCacheTo(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", cache_to=['type=inline', 'gcr.io/foo:cache', 'type=local,dest=.cache/foo,mode=min'])
+`)
+	f.load()
+	m := f.assertNextManifest("foo")
+	assert.Equal(t, []string{
+		"type=inline",
+		"type=registry,ref=gcr.io/foo:cache",
+		"type=local,dest=" + f.JoinPath(".cache", "foo"),
+	}, m.ImageTargets[0].BuildDetails.(model.DockerBuild).CacheTo)
+}
+
+func TestDockerBuildCacheToInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.setupFoo()
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+docker_build("gcr.io/foo", "foo", cache_to='type=registry,ref=gcr.io/foo:cache,mode=max')
+`)
+	f.loadErrString(`cache_to="type=registry,ref=gcr.io/foo:cache,mode=max"`, "mode=min")
+}
+
 func TestDockerBuildExtraTagString(t *testing.T) {
 	f := newFixture(t)
 
@@ -1902,6 +1944,161 @@ k8s_resource('foo', discovery_strategy='typo')
 	f.loadErrString("Invalid. Must be one of: \"default\", \"selectors-only\"")
 }
 
+func TestK8sSSAConflicts(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+update_settings(k8s_server_side_apply='true')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', ssa_conflicts='yield')
+`)
+
+	f.load("foo")
+	m := f.assertNextManifest("foo", deployment("foo"))
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.True(t, spec.ServerSideApply)
+	assert.Equal(t, v1alpha1.SSAConflictPolicyYield, spec.SSAConflictPolicy)
+}
+
+func TestK8sApplySet(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+`)
+
+	f.load("foo")
+	m := f.assertNextManifest("foo", deployment("foo"))
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Equal(t, k8s.ApplySetID(f.JoinPath("Tiltfile"), "foo"), spec.ApplySet)
+}
+
+func TestK8sSSAConflictsWithoutServerSideApply(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', ssa_conflicts='fail')
+`)
+
+	f.loadErrString(`resource "foo": ssa_conflicts="fail" requires server-side apply`)
+}
+
+func TestK8sSSAConflictsInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', ssa_conflicts='steal')
+`)
+
+	f.loadErrString("Invalid. Must be one of: \"force\", \"fail\", \"yield\"")
+}
+
+func TestK8sLogParser(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', log_parser='logfmt')
+`)
+
+	f.load("foo")
+	m := f.assertNextManifest("foo", deployment("foo"))
+	spec := m.K8sTarget().KubernetesApplySpec
+	assert.Equal(t, v1alpha1.LogParserLogfmt, spec.PodLogStreamTemplateSpec.LogParser)
+}
+
+func TestK8sLogParserInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', log_parser='xml')
+`)
+
+	f.loadErrString("Invalid. Must be one of: \"json\", \"logfmt\"")
+}
+
+func TestK8sPreviewDiff(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/bar:stable")))
+	f.file("Tiltfile", `
+k8s_yaml(['foo.yaml', 'bar.yaml'])
+k8s_resource('foo', preview_diff=True)
+k8s_resource('bar', confirm_diff_kinds=['Secret', 'Deployment'])
+`)
+
+	f.load("foo", "bar")
+	m := f.assertNextManifest("foo", deployment("foo"))
+	assert.Equal(t, &v1alpha1.KubernetesApplyDiffPreview{},
+		m.K8sTarget().KubernetesApplySpec.DiffPreview)
+
+	m = f.assertNextManifest("bar", deployment("bar"))
+	assert.Equal(t, &v1alpha1.KubernetesApplyDiffPreview{ConfirmKinds: []string{"Secret", "Deployment"}},
+		m.K8sTarget().KubernetesApplySpec.DiffPreview)
+}
+
+func TestK8sPreviewDiffDefault(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', preview_diff=True)
+k8s_resource('foo', preview_diff=False)
+`)
+
+	f.load("foo")
+	m := f.assertNextManifest("foo", deployment("foo"))
+	assert.Nil(t, m.K8sTarget().KubernetesApplySpec.DiffPreview)
+}
+
+func TestK8sAutoRollback(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.yaml("bar.yaml", deployment("bar", image("gcr.io/bar:stable")))
+	f.yaml("baz.yaml", deployment("baz", image("gcr.io/baz:stable")))
+	f.file("Tiltfile", `
+k8s_yaml(['foo.yaml', 'bar.yaml', 'baz.yaml'])
+k8s_resource('foo', auto_rollback=True)
+k8s_resource('bar', auto_rollback_window='90s')
+k8s_resource('baz', auto_rollback_window='90s')
+k8s_resource('baz', auto_rollback=False)
+`)
+
+	f.load("foo", "bar", "baz")
+	m := f.assertNextManifest("foo", deployment("foo"))
+	assert.Equal(t, time.Minute, m.K8sTarget().AutoRollbackWindow)
+
+	m = f.assertNextManifest("bar", deployment("bar"))
+	assert.Equal(t, 90*time.Second, m.K8sTarget().AutoRollbackWindow)
+
+	m = f.assertNextManifest("baz", deployment("baz"))
+	assert.Equal(t, time.Duration(0), m.K8sTarget().AutoRollbackWindow)
+}
+
+func TestK8sAutoRollbackWindowInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.yaml("foo.yaml", deployment("foo", image("gcr.io/foo:stable")))
+	f.file("Tiltfile", `
+k8s_yaml('foo.yaml')
+k8s_resource('foo', auto_rollback_window='-1m')
+`)
+
+	f.loadErrString("auto_rollback_window must be positive")
+}
+
 func TestPodReadinessOverrideDeployment(t *testing.T) {
 	f := newFixture(t)
 
@@ -4796,6 +4993,117 @@ local_resource('baz', 'echo baz', resource_deps=['bar'])
 	f.loadErrString("cycle detected in resource dependency graph", "bar -> foo", "foo -> baz", "baz -> bar")
 }
 
+func TestConcurrencyGroup(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+local_resource('bar', 'echo bar')
+local_resource('baz', 'echo baz')
+concurrency_group('db', ['foo', 'bar'])
+concurrency_group('ports', 'bar', limit=2)
+`)
+
+	f.load()
+	f.assertNextManifest("foo", concurrencyGroups(model.ConcurrencyGroup{Name: "db", Limit: 1}))
+	f.assertNextManifest("bar", concurrencyGroups(
+		model.ConcurrencyGroup{Name: "db", Limit: 1},
+		model.ConcurrencyGroup{Name: "ports", Limit: 2}))
+	f.assertNextManifest("baz", concurrencyGroups())
+}
+
+func TestConcurrencyGroupDuplicateResource(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+local_resource('bar', 'echo bar')
+concurrency_group('db', ['foo', 'bar', 'foo'], limit=2)
+`)
+
+	f.load()
+	f.assertNextManifest("foo", concurrencyGroups(model.ConcurrencyGroup{Name: "db", Limit: 2}))
+	f.assertNextManifest("bar", concurrencyGroups(model.ConcurrencyGroup{Name: "db", Limit: 2}))
+}
+
+func TestConcurrencyGroupUnknownResource(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+concurrency_group('db', ['foo', 'bar'])
+`)
+
+	f.loadErrString(`concurrency group "db" includes unknown resource "bar"`)
+}
+
+func TestConcurrencyGroupDuplicate(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+concurrency_group('db', ['foo'])
+concurrency_group('db', ['foo'])
+`)
+
+	f.loadErrString(`concurrency_group: group "db" was already defined at`)
+}
+
+func TestConcurrencyGroupInvalidLimit(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('foo', 'echo foo')
+concurrency_group('db', ['foo'], limit=0)
+`)
+
+	f.loadErrString("concurrency_group: limit must be >= 1 (got: 0)")
+}
+
+func TestResourcePriority(t *testing.T) {
+	f := newFixture(t)
+	f.setupFoo()
+
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+k8s_yaml('foo.yaml')
+k8s_resource('foo', priority=10)
+local_resource('bar', 'echo bar', priority=-5)
+local_resource('baz', 'echo baz')
+`)
+
+	f.load()
+	f.assertNextManifest("foo", priority(10))
+	f.assertNextManifest("bar", priority(-5))
+	f.assertNextManifest("baz", priority(0))
+}
+
+func TestResourcePriorityInvalid(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `
+local_resource('bar', 'echo bar', priority=1 << 40)
+`)
+
+	f.loadErrString("local_resource: priority:")
+}
+
+func TestCancelStaleImageBuilds(t *testing.T) {
+	f := newFixture(t)
+	f.setupFooAndBar()
+
+	f.file("Tiltfile", `
+docker_build('gcr.io/foo', 'foo')
+docker_build('gcr.io/bar', 'bar')
+k8s_yaml(['foo.yaml', 'bar.yaml'])
+k8s_resource('foo', cancel_stale_image_builds=True)
+`)
+
+	f.load()
+	f.assertNextManifest("foo", cancelStaleImageBuilds(true))
+	f.assertNextManifest("bar", cancelStaleImageBuilds(false))
+}
+
 func TestDependsOnPulledInOnPartialLoad(t *testing.T) {
 	for _, tc := range []struct {
 		name            string
@@ -4981,6 +5289,17 @@ func TestK8sUpsertTimeout(t *testing.T) {
 	}
 }
 
+func TestPreemptLocalUpdates(t *testing.T) {
+	f := newFixture(t)
+
+	f.file("Tiltfile", `update_settings(max_parallel_updates=2)
+update_settings(preempt_local_updates=True)`)
+
+	f.load()
+	assert.True(t, f.loadResult.UpdateSettings.PreemptLocalUpdates())
+	assert.Equal(t, 2, f.loadResult.UpdateSettings.MaxParallelUpdates())
+}
+
 func TestUpdateSettingsCalledTwice(t *testing.T) {
 	f := newFixture(t)
 
@@ -6631,6 +6950,24 @@ func resourceDeps(deps ...string) resourceDependenciesHelper {
 	return resourceDependenciesHelper{deps: mns}
 }
 
+func concurrencyGroups(groups ...model.ConcurrencyGroup) funcOpt {
+	return func(t *testing.T, m model.Manifest) bool {
+		return assert.Equal(t, groups, m.ConcurrencyGroups)
+	}
+}
+
+func priority(p int) funcOpt {
+	return func(t *testing.T, m model.Manifest) bool {
+		return assert.Equal(t, p, m.Priority)
+	}
+}
+
+func cancelStaleImageBuilds(cancel bool) funcOpt {
+	return func(t *testing.T, m model.Manifest) bool {
+		return assert.Equal(t, cancel, m.CancelStaleImageBuilds)
+	}
+}
+
 type resourceLabelsHelper struct {
 	labels map[string]string
 }
@@ -32,11 +32,13 @@ func (e *Plugin) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, a
 	var maxParallelUpdates, k8sUpsertTimeoutSecs starlark.Value
 	var unusedImageWarnings value.StringOrStringList
 	var k8sServerSideApply string
+	var preemptLocalUpdates value.Optional[starlark.Bool]
 	if err := starkit.UnpackArgs(thread, fn.Name(), args, kwargs,
 		"max_parallel_updates?", &maxParallelUpdates,
 		"k8s_upsert_timeout_secs?", &k8sUpsertTimeoutSecs,
 		"suppress_unused_image_warnings?", &unusedImageWarnings,
-		"k8s_server_side_apply?", &k8sServerSideApply); err != nil {
+		"k8s_server_side_apply?", &k8sServerSideApply,
+		"preempt_local_updates?", &preemptLocalUpdates); err != nil {
 		return nil, err
 	}
 
@@ -73,6 +75,9 @@ func (e *Plugin) updateSettings(thread *starlark.Thread, fn *starlark.Builtin, a
 		if k8sServerSideApply != "" {
 			settings = settings.WithK8sServerSideApply(k8sServerSideApply)
 		}
+		if preemptLocalUpdates.IsSet {
+			settings = settings.WithPreemptLocalUpdates(bool(preemptLocalUpdates.Value))
+		}
 		return settings
 	})
 
@@ -50,6 +50,10 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.cmd_stop_spec", p.cmdStopSpec)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.config_map_disable_source", p.configMapDisableSource)
 	if err != nil {
 		return err
@@ -66,6 +70,11 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.grpc_action", p.gRPCAction)
+	if err != nil {
+		return err
+	}
+
 	err = env.AddBuiltin("v1alpha1.http_get_action", p.hTTPGetAction)
 	if err != nil {
 		return err
@@ -86,6 +95,10 @@ func (p Plugin) registerSymbols(env *starkit.Environment) error {
 	if err != nil {
 		return err
 	}
+	err = env.AddBuiltin("v1alpha1.kubernetes_apply_diff_preview", p.kubernetesApplyDiffPreview)
+	if err != nil {
+		return err
+	}
 	err = env.AddBuiltin("v1alpha1.kubernetes_discovery_template_spec", p.kubernetesDiscoveryTemplateSpec)
 	if err != nil {
 		return err
@@ -182,6 +195,9 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 	var restartOn RestartOnSpec = RestartOnSpec{t: t}
 	var startOn StartOnSpec = StartOnSpec{t: t}
 	var disableSource DisableSource = DisableSource{t: t}
+	var stop CmdStopSpec = CmdStopSpec{t: t}
+	var livenessProbe Probe = Probe{t: t}
+	var startupProbe Probe = Probe{t: t}
 	var labels value.StringStringMap
 	var annotations value.StringStringMap
 	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
@@ -195,6 +211,9 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 		"restart_on?", &restartOn,
 		"start_on?", &startOn,
 		"disable_source?", &disableSource,
+		"stop?", &stop,
+		"liveness_probe?", &livenessProbe,
+		"startup_probe?", &startupProbe,
 	)
 	if err != nil {
 		return nil, err
@@ -215,6 +234,15 @@ func (p Plugin) cmd(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tupl
 	if disableSource.isUnpacked {
 		obj.Spec.DisableSource = (*v1alpha1.DisableSource)(&disableSource.Value)
 	}
+	if stop.isUnpacked {
+		obj.Spec.Stop = (*v1alpha1.CmdStopSpec)(&stop.Value)
+	}
+	if livenessProbe.isUnpacked {
+		obj.Spec.LivenessProbe = (*v1alpha1.Probe)(&livenessProbe.Value)
+	}
+	if startupProbe.isUnpacked {
+		obj.Spec.StartupProbe = (*v1alpha1.Probe)(&startupProbe.Value)
+	}
 	obj.ObjectMeta.Labels = labels
 	obj.ObjectMeta.Annotations = annotations
 	return p.register(t, obj)
@@ -347,6 +375,8 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 	var applyCmd KubernetesApplyCmd = KubernetesApplyCmd{t: t}
 	var restartOn RestartOnSpec = RestartOnSpec{t: t}
 	var deleteCmd KubernetesApplyCmd = KubernetesApplyCmd{t: t}
+	var ssaConflictPolicy string
+	var diffPreview KubernetesApplyDiffPreview = KubernetesApplyDiffPreview{t: t}
 	var labels value.StringStringMap
 	var annotations value.StringStringMap
 	err = starkit.UnpackArgs(t, fn.Name(), args, kwargs,
@@ -367,6 +397,8 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 		"delete_cmd?", &deleteCmd,
 		"cluster?", &obj.Spec.Cluster,
 		"server_side_apply?", &obj.Spec.ServerSideApply,
+		"ssa_conflict_policy?", &ssaConflictPolicy,
+		"diff_preview?", &diffPreview,
 	)
 	if err != nil {
 		return nil, err
@@ -397,6 +429,10 @@ func (p Plugin) kubernetesApply(t *starlark.Thread, fn *starlark.Builtin, args s
 	if deleteCmd.isUnpacked {
 		obj.Spec.DeleteCmd = (*v1alpha1.KubernetesApplyCmd)(&deleteCmd.Value)
 	}
+	obj.Spec.SSAConflictPolicy = v1alpha1.SSAConflictPolicy(ssaConflictPolicy)
+	if diffPreview.isUnpacked {
+		obj.Spec.DiffPreview = (*v1alpha1.KubernetesApplyDiffPreview)(&diffPreview.Value)
+	}
 	obj.ObjectMeta.Labels = labels
 	obj.ObjectMeta.Annotations = annotations
 	return p.register(t, obj)
@@ -474,6 +510,143 @@ func (p Plugin) uiButton(t *starlark.Thread, fn *starlark.Builtin, args starlark
 	return p.register(t, obj)
 }
 
+type CmdStopSpec struct {
+	*starlark.Dict
+	Value      v1alpha1.CmdStopSpec
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) cmdStopSpec(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var signal starlark.Value
+	var gracePeriodSeconds starlark.Value
+	var preStopArgs starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"signal?", &signal,
+		"grace_period_seconds?", &gracePeriodSeconds,
+		"pre_stop_args?", &preStopArgs,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(3)
+
+	if signal != nil {
+		err := dict.SetKey(starlark.String("signal"), signal)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if gracePeriodSeconds != nil {
+		err := dict.SetKey(starlark.String("grace_period_seconds"), gracePeriodSeconds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if preStopArgs != nil {
+		err := dict.SetKey(starlark.String("pre_stop_args"), preStopArgs)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *CmdStopSpec = &CmdStopSpec{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *CmdStopSpec) Unpack(v starlark.Value) error {
+	obj := v1alpha1.CmdStopSpec{}
+
+	starlarkObj, ok := v.(*CmdStopSpec)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "signal" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Signal = string(v)
+			continue
+		}
+		if key == "grace_period_seconds" {
+			v, err := starlark.AsInt32(val)
+			if err != nil {
+				return fmt.Errorf("Expected int, got: %v", err)
+			}
+			obj.GracePeriodSeconds = int32(v)
+			continue
+		}
+		if key == "pre_stop_args" {
+			var v value.StringList
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.PreStopArgs = v
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type CmdStopSpecList struct {
+	*starlark.List
+	Value []v1alpha1.CmdStopSpec
+	t     *starlark.Thread
+}
+
+func (o *CmdStopSpecList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.CmdStopSpec{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := CmdStopSpec{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.CmdStopSpec(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
 type ConfigMapDisableSource struct {
 	*starlark.Dict
 	Value      v1alpha1.ConfigMapDisableSource
@@ -989,6 +1162,158 @@ func (o *ForwardList) Unpack(v starlark.Value) error {
 	return nil
 }
 
+type GRPCAction struct {
+	*starlark.Dict
+	Value      v1alpha1.GRPCAction
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) gRPCAction(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var port starlark.Value
+	var host starlark.Value
+	var service starlark.Value
+	var tLS starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"port?", &port,
+		"host?", &host,
+		"service?", &service,
+		"tls?", &tLS,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(4)
+
+	if port != nil {
+		err := dict.SetKey(starlark.String("port"), port)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if host != nil {
+		err := dict.SetKey(starlark.String("host"), host)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if service != nil {
+		err := dict.SetKey(starlark.String("service"), service)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if tLS != nil {
+		err := dict.SetKey(starlark.String("tls"), tLS)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *GRPCAction = &GRPCAction{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *GRPCAction) Unpack(v starlark.Value) error {
+	obj := v1alpha1.GRPCAction{}
+
+	starlarkObj, ok := v.(*GRPCAction)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "port" {
+			v, err := starlark.AsInt32(val)
+			if err != nil {
+				return fmt.Errorf("Expected int, got: %v", err)
+			}
+			obj.Port = int32(v)
+			continue
+		}
+		if key == "host" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Host = string(v)
+			continue
+		}
+		if key == "service" {
+			v, ok := starlark.AsString(val)
+			if !ok {
+				return fmt.Errorf("Expected string, actual: %s", val.Type())
+			}
+			obj.Service = string(v)
+			continue
+		}
+		if key == "tls" {
+			v, ok := val.(starlark.Bool)
+			if !ok {
+				return fmt.Errorf("Expected bool, got: %v", val.Type())
+			}
+			obj.TLS = bool(v)
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type GRPCActionList struct {
+	*starlark.List
+	Value []v1alpha1.GRPCAction
+	t     *starlark.Thread
+}
+
+func (o *GRPCActionList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.GRPCAction{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := GRPCAction{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.GRPCAction(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
 type HTTPGetAction struct {
 	*starlark.Dict
 	Value      v1alpha1.HTTPGetAction
@@ -1289,16 +1614,18 @@ func (p Plugin) handler(t *starlark.Thread, fn *starlark.Builtin, args starlark.
 	var exec starlark.Value
 	var hTTPGet starlark.Value
 	var tCPSocket starlark.Value
+	var gRPC starlark.Value
 	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
 		"exec?", &exec,
 		"http_get?", &hTTPGet,
 		"tcp_socket?", &tCPSocket,
+		"grpc?", &gRPC,
 	)
 	if err != nil {
 		return nil, err
 	}
 
-	dict := starlark.NewDict(3)
+	dict := starlark.NewDict(4)
 
 	if exec != nil {
 		err := dict.SetKey(starlark.String("exec"), exec)
@@ -1318,6 +1645,12 @@ func (p Plugin) handler(t *starlark.Thread, fn *starlark.Builtin, args starlark.
 			return nil, err
 		}
 	}
+	if gRPC != nil {
+		err := dict.SetKey(starlark.String("grpc"), gRPC)
+		if err != nil {
+			return nil, err
+		}
+	}
 	var obj *Handler = &Handler{t: t}
 	err = obj.Unpack(dict)
 	if err != nil {
@@ -1374,6 +1707,15 @@ func (o *Handler) Unpack(v starlark.Value) error {
 			obj.TCPSocket = (*v1alpha1.TCPSocketAction)(&v.Value)
 			continue
 		}
+		if key == "grpc" {
+			v := GRPCAction{t: o.t}
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.GRPC = (*v1alpha1.GRPCAction)(&v.Value)
+			continue
+		}
 		return fmt.Errorf("Unexpected attribute name: %s", key)
 	}
 
@@ -1678,6 +2020,111 @@ func (o *KubernetesApplyCmdList) Unpack(v starlark.Value) error {
 	return nil
 }
 
+type KubernetesApplyDiffPreview struct {
+	*starlark.Dict
+	Value      v1alpha1.KubernetesApplyDiffPreview
+	isUnpacked bool
+	t          *starlark.Thread // instantiation thread for computing abspath
+}
+
+func (p Plugin) kubernetesApplyDiffPreview(t *starlark.Thread, fn *starlark.Builtin, args starlark.Tuple, kwargs []starlark.Tuple) (starlark.Value, error) {
+	var confirmKinds starlark.Value
+	err := starkit.UnpackArgs(t, fn.Name(), args, kwargs,
+		"confirm_kinds?", &confirmKinds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	dict := starlark.NewDict(1)
+
+	if confirmKinds != nil {
+		err := dict.SetKey(starlark.String("confirm_kinds"), confirmKinds)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var obj *KubernetesApplyDiffPreview = &KubernetesApplyDiffPreview{t: t}
+	err = obj.Unpack(dict)
+	if err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+func (o *KubernetesApplyDiffPreview) Unpack(v starlark.Value) error {
+	obj := v1alpha1.KubernetesApplyDiffPreview{}
+
+	starlarkObj, ok := v.(*KubernetesApplyDiffPreview)
+	if ok {
+		*o = *starlarkObj
+		return nil
+	}
+
+	mapObj, ok := v.(*starlark.Dict)
+	if !ok {
+		return fmt.Errorf("expected dict, actual: %v", v.Type())
+	}
+
+	for _, item := range mapObj.Items() {
+		keyV, val := item[0], item[1]
+		key, ok := starlark.AsString(keyV)
+		if !ok {
+			return fmt.Errorf("key must be string. Got: %s", keyV.Type())
+		}
+
+		if key == "confirm_kinds" {
+			var v value.StringList
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.ConfirmKinds = v
+			continue
+		}
+		return fmt.Errorf("Unexpected attribute name: %s", key)
+	}
+
+	mapObj.Freeze()
+	o.Dict = mapObj
+	o.Value = obj
+	o.isUnpacked = true
+
+	return nil
+}
+
+type KubernetesApplyDiffPreviewList struct {
+	*starlark.List
+	Value []v1alpha1.KubernetesApplyDiffPreview
+	t     *starlark.Thread
+}
+
+func (o *KubernetesApplyDiffPreviewList) Unpack(v starlark.Value) error {
+	items := []v1alpha1.KubernetesApplyDiffPreview{}
+
+	listObj, ok := v.(*starlark.List)
+	if !ok {
+		return fmt.Errorf("expected list, actual: %v", v.Type())
+	}
+
+	for i := 0; i < listObj.Len(); i++ {
+		v := listObj.Index(i)
+
+		item := KubernetesApplyDiffPreview{t: o.t}
+		err := item.Unpack(v)
+		if err != nil {
+			return fmt.Errorf("at index %d: %v", i, err)
+		}
+		items = append(items, v1alpha1.KubernetesApplyDiffPreview(item.Value))
+	}
+
+	listObj.Freeze()
+	o.List = listObj
+	o.Value = items
+
+	return nil
+}
+
 type KubernetesDiscoveryTemplateSpec struct {
 	*starlark.Dict
 	Value      v1alpha1.KubernetesDiscoveryTemplateSpec
@@ -2943,6 +3390,15 @@ func (o *Probe) Unpack(v starlark.Value) error {
 			obj.TCPSocket = (*v1alpha1.TCPSocketAction)(&v.Value)
 			continue
 		}
+		if key == "grpc" {
+			v := GRPCAction{t: o.t}
+			err := v.Unpack(val)
+			if err != nil {
+				return fmt.Errorf("unpacking %s: %v", key, err)
+			}
+			obj.GRPC = (*v1alpha1.GRPCAction)(&v.Value)
+			continue
+		}
 		if key == "initial_delay_seconds" {
 			v, err := starlark.AsInt32(val)
 			if err != nil {
@@ -0,0 +1,31 @@
+package value
+
+import (
+	"fmt"
+
+	"go.starlark.net/starlark"
+
+	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
+)
+
+// Parse a log parser name from starlark.
+type LogParser v1alpha1.LogParser
+
+func (p *LogParser) Unpack(v starlark.Value) error {
+	s, ok := AsString(v)
+	if !ok {
+		return fmt.Errorf("Must be a string. Got: %s", v.Type())
+	}
+
+	parser := v1alpha1.LogParser(s)
+	if !(parser == v1alpha1.LogParserNone ||
+		parser == v1alpha1.LogParserJSON ||
+		parser == v1alpha1.LogParserLogfmt) {
+		return fmt.Errorf("Invalid. Must be one of: %q, %q",
+			v1alpha1.LogParserJSON,
+			v1alpha1.LogParserLogfmt)
+	}
+
+	*p = LogParser(parser)
+	return nil
+}
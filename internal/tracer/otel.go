@@ -1,16 +1,93 @@
 package tracer
 
 import (
+	"context"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
 	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.40.0"
 	"go.opentelemetry.io/otel/trace"
 )
 
 const tracerName = "tilt.dev/usage"
 
+// Standard attributes on Tilt's build spans.
+const (
+	AttrManifestName = attribute.Key("tilt.manifest.name")
+	AttrTargetIDs    = attribute.Key("tilt.target.ids")
+	AttrBuildReason  = attribute.Key("tilt.build.reason")
+)
+
 func InitOpenTelemetry(exporter sdktrace.SpanExporter) trace.Tracer {
-	tp := sdktrace.NewTracerProvider(sdktrace.WithSampler(sdktrace.AlwaysSample()))
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSampler(sdktrace.AlwaysSample()),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName("tilt"))))
 	sp := sdktrace.NewBatchSpanProcessor(exporter)
 	tp.RegisterSpanProcessor(sp)
 	tracer := tp.Tracer(tracerName)
 	return tracer
 }
+
+// Sends spans both to the SpanCollector (for experimental_telemetry_cmd)
+// and to the OTLP collector, if any.
+func ProvideSpanExporter(collector *SpanCollector, otlp *OTLPExporter) sdktrace.SpanExporter {
+	return multiExporter{collector, otlp}
+}
+
+type multiExporter []sdktrace.SpanExporter
+
+func (m multiExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.ExportSpans(ctx, spans))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiExporter) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, e := range m {
+		errs = append(errs, e.Shutdown(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+type attributesKey struct{}
+
+// Attaches attributes to the context, so that spans started further down
+// the stack can pick them up with AttributesFromContext.
+func WithAttributes(ctx context.Context, attrs ...attribute.KeyValue) context.Context {
+	existing := AttributesFromContext(ctx)
+	combined := make([]attribute.KeyValue, 0, len(existing)+len(attrs))
+	combined = append(combined, existing...)
+	combined = append(combined, attrs...)
+	return context.WithValue(ctx, attributesKey{}, combined)
+}
+
+func AttributesFromContext(ctx context.Context) []attribute.KeyValue {
+	attrs, _ := ctx.Value(attributesKey{}).([]attribute.KeyValue)
+	return attrs
+}
+
+// Returns TRACEPARENT (and TRACESTATE, if set) environment variables
+// for the span in the context, so that commands we run can add
+// their own spans to the same trace.
+//
+// https://www.w3.org/TR/trace-context/
+func TraceparentEnv(ctx context.Context) []string {
+	carrier := propagation.MapCarrier{}
+	propagation.TraceContext{}.Inject(ctx, carrier)
+
+	var env []string
+	for _, k := range []string{"traceparent", "tracestate"} {
+		v := carrier.Get(k)
+		if v != "" {
+			env = append(env, strings.ToUpper(k)+"="+v)
+		}
+	}
+	return env
+}
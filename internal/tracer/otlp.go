@@ -0,0 +1,295 @@
+package tracer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+const (
+	OTLPProtocolGRPC = "grpc"
+	OTLPProtocolHTTP = "http/protobuf"
+)
+
+// The standard OpenTelemetry environment variables for configuring an exporter.
+//
+// https://opentelemetry.io/docs/specs/otel/protocol/exporter/
+const (
+	envOTLPEndpoint       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	envOTLPTracesEndpoint = "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"
+	envOTLPProtocol       = "OTEL_EXPORTER_OTLP_PROTOCOL"
+	envOTLPTracesProtocol = "OTEL_EXPORTER_OTLP_TRACES_PROTOCOL"
+	envOTLPHeaders        = "OTEL_EXPORTER_OTLP_HEADERS"
+	envOTLPTracesHeaders  = "OTEL_EXPORTER_OTLP_TRACES_HEADERS"
+)
+
+// Reads OTLP exporter settings from the standard OpenTelemetry
+// environment variables.
+func OTLPSettingsFromEnv() model.OTLPSettings {
+	return otlpSettingsFromEnv(os.Getenv)
+}
+
+func otlpSettingsFromEnv(getenv func(string) string) model.OTLPSettings {
+	firstNonEmpty := func(keys ...string) string {
+		for _, k := range keys {
+			v := getenv(k)
+			if v != "" {
+				return v
+			}
+		}
+		return ""
+	}
+
+	headers := map[string]string{}
+	for _, kv := range strings.Split(firstNonEmpty(envOTLPTracesHeaders, envOTLPHeaders), ",") {
+		k, v, ok := strings.Cut(kv, "=")
+		if !ok {
+			continue
+		}
+		v, err := url.QueryUnescape(strings.TrimSpace(v))
+		if err != nil {
+			continue
+		}
+		headers[strings.TrimSpace(k)] = v
+	}
+	if len(headers) == 0 {
+		headers = nil
+	}
+
+	protocol := firstNonEmpty(envOTLPTracesProtocol, envOTLPProtocol)
+
+	// The signal-specific endpoint is used as-is. The base endpoint is
+	// the root of the collector, so over HTTP we always append the traces
+	// path, even if the base endpoint has a path of its own.
+	endpoint := getenv(envOTLPTracesEndpoint)
+	if endpoint == "" {
+		endpoint = getenv(envOTLPEndpoint)
+		if endpoint != "" && protocol != OTLPProtocolGRPC {
+			endpoint = strings.TrimSuffix(endpoint, "/") + "/v1/traces"
+		}
+	}
+
+	return model.OTLPSettings{
+		Endpoint: endpoint,
+		Protocol: protocol,
+		Headers:  headers,
+	}
+}
+
+// OTLPExporter sends spans to an OpenTelemetry collector (like Jaeger or Tempo).
+//
+// The collector is configured from the environment when Tilt starts,
+// and can be overridden from the Tiltfile with SetSettings.
+//
+// If no collector is configured, spans are dropped.
+type OTLPExporter struct {
+	envSettings model.OTLPSettings
+
+	mu       sync.Mutex
+	settings model.OTLPSettings
+	exporter sdktrace.SpanExporter
+
+	// We only report the first error after a success,
+	// so that a collector that's down doesn't spam the logs.
+	failing bool
+	err     error
+}
+
+var _ sdktrace.SpanExporter = &OTLPExporter{}
+
+func NewOTLPExporter(ctx context.Context) *OTLPExporter {
+	e := &OTLPExporter{envSettings: OTLPSettingsFromEnv()}
+	err := e.SetSettings(ctx, model.OTLPSettings{})
+	if err != nil {
+		e.err = err
+	}
+	return e
+}
+
+// Points the exporter at a new collector.
+//
+// Empty settings fall back to the settings from the environment.
+func (e *OTLPExporter) SetSettings(ctx context.Context, s model.OTLPSettings) error {
+	if s.Empty() {
+		s = e.envSettings
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if reflect.DeepEqual(s, e.settings) {
+		return nil
+	}
+
+	old := e.exporter
+	e.settings = s
+	e.exporter = nil
+	e.failing = false
+	if old != nil {
+		_ = old.Shutdown(ctx)
+	}
+
+	if s.Empty() {
+		return nil
+	}
+
+	exporter, err := newOTLPSpanExporter(ctx, s)
+	if err != nil {
+		return fmt.Errorf("Configuring OTLP exporter for %s: %v", s.Endpoint, err)
+	}
+	e.exporter = exporter
+	return nil
+}
+
+// Returns (and clears) the most recent error exporting spans, if any.
+func (e *OTLPExporter) TakeError() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	err := e.err
+	e.err = nil
+	return err
+}
+
+func (e *OTLPExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	exporter := e.exporter
+	endpoint := e.settings.Endpoint
+	e.mu.Unlock()
+
+	if exporter == nil {
+		return nil
+	}
+
+	err := exporter.ExportSpans(ctx, spans)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if exporter != e.exporter {
+		// The exporter was re-configured while we were exporting.
+		return nil
+	}
+	if err != nil {
+		if !e.failing {
+			e.err = fmt.Errorf("Exporting spans to %s: %v", endpoint, err)
+		}
+		e.failing = true
+	} else {
+		e.failing = false
+	}
+
+	// Errors are reported with TakeError rather than returned, because
+	// OpenTelemetry prints returned errors to stderr.
+	return nil
+}
+
+func (e *OTLPExporter) Shutdown(ctx context.Context) error {
+	e.mu.Lock()
+	exporter := e.exporter
+	e.exporter = nil
+	e.settings = model.OTLPSettings{}
+	e.mu.Unlock()
+
+	if exporter == nil {
+		return nil
+	}
+	return exporter.Shutdown(ctx)
+}
+
+func newOTLPSpanExporter(ctx context.Context, s model.OTLPSettings) (sdktrace.SpanExporter, error) {
+	endpoint := s.Endpoint
+	if !strings.Contains(endpoint, "://") {
+		// Most collectors that people run locally don't use TLS.
+		endpoint = "http://" + endpoint
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %v", err)
+	}
+
+	switch s.Protocol {
+	case OTLPProtocolGRPC:
+		return otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpointURL(u.String()),
+			otlptracegrpc.WithHeaders(s.Headers))
+	case OTLPProtocolHTTP, "":
+		// Endpoints from the environment already have the full path.
+		// Endpoints from the Tiltfile may be just a host.
+		if u.Path == "" || u.Path == "/" {
+			u.Path = "/v1/traces"
+		}
+		return otlptrace.New(ctx, &otlpHTTPClient{
+			url:     u.String(),
+			headers: s.Headers,
+			client:  &http.Client{},
+		})
+	default:
+		return nil, fmt.Errorf("unsupported protocol %q. Must be one of: %s, %s",
+			s.Protocol, OTLPProtocolGRPC, OTLPProtocolHTTP)
+	}
+}
+
+// Sends spans to a collector as protobufs over HTTP.
+type otlpHTTPClient struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+}
+
+var _ otlptrace.Client = &otlpHTTPClient{}
+
+func (c *otlpHTTPClient) Start(ctx context.Context) error {
+	return nil
+}
+
+func (c *otlpHTTPClient) Stop(ctx context.Context) error {
+	c.client.CloseIdleConnections()
+	return nil
+}
+
+func (c *otlpHTTPClient) UploadTraces(ctx context.Context, protoSpans []*tracepb.ResourceSpans) error {
+	body, err := proto.Marshal(&coltracepb.ExportTraceServiceRequest{ResourceSpans: protoSpans})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	for k, v := range c.headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(msg)))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,215 @@
+package tracer
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/tilt-dev/tilt/pkg/model"
+)
+
+func TestOTLPSettingsFromEnv(t *testing.T) {
+	env := map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT":        "http://localhost:4318",
+		"OTEL_EXPORTER_OTLP_TRACES_ENDPOINT": "http://localhost:4318/custom/traces",
+		"OTEL_EXPORTER_OTLP_PROTOCOL":        "grpc",
+		"OTEL_EXPORTER_OTLP_HEADERS":         "api-key=secret,x-scope-orgid=a%20b",
+	}
+	s := otlpSettingsFromEnv(func(k string) string { return env[k] })
+	assert.Equal(t, model.OTLPSettings{
+		Endpoint: "http://localhost:4318/custom/traces",
+		Protocol: "grpc",
+		Headers:  map[string]string{"api-key": "secret", "x-scope-orgid": "a b"},
+	}, s)
+}
+
+func TestOTLPSettingsFromEnvBaseEndpoint(t *testing.T) {
+	for _, tc := range []struct {
+		endpoint string
+		expected string
+	}{
+		{"http://localhost:4318", "http://localhost:4318/v1/traces"},
+		{"http://localhost:4318/", "http://localhost:4318/v1/traces"},
+		{"https://otlp.example.com/otlp", "https://otlp.example.com/otlp/v1/traces"},
+		{"https://otlp.example.com/otlp/", "https://otlp.example.com/otlp/v1/traces"},
+	} {
+		t.Run(tc.endpoint, func(t *testing.T) {
+			env := map[string]string{"OTEL_EXPORTER_OTLP_ENDPOINT": tc.endpoint}
+			s := otlpSettingsFromEnv(func(k string) string { return env[k] })
+			assert.Equal(t, tc.expected, s.Endpoint)
+		})
+	}
+}
+
+func TestOTLPSettingsFromEnvBaseEndpointGRPC(t *testing.T) {
+	env := map[string]string{
+		"OTEL_EXPORTER_OTLP_ENDPOINT": "http://localhost:4317",
+		"OTEL_EXPORTER_OTLP_PROTOCOL": "grpc",
+	}
+	s := otlpSettingsFromEnv(func(k string) string { return env[k] })
+	assert.Equal(t, "http://localhost:4317", s.Endpoint)
+}
+
+func TestOTLPSettingsFromEnvEmpty(t *testing.T) {
+	s := otlpSettingsFromEnv(func(k string) string { return "" })
+	assert.True(t, s.Empty())
+	assert.Nil(t, s.Headers)
+}
+
+func TestOTLPExporterNoEndpoint(t *testing.T) {
+	e := &OTLPExporter{}
+	require.NoError(t, e.SetSettings(context.Background(), model.OTLPSettings{}))
+	require.NoError(t, e.ExportSpans(context.Background(), spans("foo")))
+	assert.NoError(t, e.TakeError())
+}
+
+func TestOTLPExporterHTTP(t *testing.T) {
+	f := newCollectorFixture(t)
+	e := &OTLPExporter{}
+	require.NoError(t, e.SetSettings(context.Background(), model.OTLPSettings{
+		Endpoint: f.server.URL,
+		Headers:  map[string]string{"api-key": "secret"},
+	}))
+
+	require.NoError(t, e.ExportSpans(context.Background(), spans("update")))
+	require.NoError(t, e.TakeError())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Equal(t, "/v1/traces", f.path)
+	assert.Equal(t, "secret", f.header.Get("api-key"))
+	assert.Equal(t, "application/x-protobuf", f.header.Get("Content-Type"))
+	assert.Equal(t, []string{"update"}, f.spanNames)
+}
+
+func TestOTLPExporterHTTPError(t *testing.T) {
+	f := newCollectorFixture(t)
+	f.status = http.StatusServiceUnavailable
+
+	e := &OTLPExporter{}
+	require.NoError(t, e.SetSettings(context.Background(), model.OTLPSettings{
+		Endpoint: f.server.URL,
+	}))
+
+	require.NoError(t, e.ExportSpans(context.Background(), spans("update")))
+	err := e.TakeError()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "503 Service Unavailable")
+	}
+
+	// Don't report the same failure over and over.
+	require.NoError(t, e.ExportSpans(context.Background(), spans("update")))
+	assert.NoError(t, e.TakeError())
+}
+
+func TestOTLPExporterInvalidProtocol(t *testing.T) {
+	e := &OTLPExporter{}
+	err := e.SetSettings(context.Background(), model.OTLPSettings{
+		Endpoint: "localhost:4318",
+		Protocol: "http/json",
+	})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unsupported protocol "http/json"`)
+	}
+}
+
+func TestOTLPExporterFallsBackToEnv(t *testing.T) {
+	f := newCollectorFixture(t)
+	e := &OTLPExporter{envSettings: model.OTLPSettings{Endpoint: f.server.URL}}
+	require.NoError(t, e.SetSettings(context.Background(), model.OTLPSettings{}))
+
+	require.NoError(t, e.ExportSpans(context.Background(), spans("update")))
+	require.NoError(t, e.TakeError())
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	assert.Equal(t, []string{"update"}, f.spanNames)
+}
+
+func TestTraceparentEnv(t *testing.T) {
+	assert.Empty(t, TraceparentEnv(context.Background()))
+
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    traceID,
+		SpanID:     spanID,
+		TraceFlags: trace.FlagsSampled,
+	}))
+	assert.Equal(t, []string{"TRACEPARENT=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+		TraceparentEnv(ctx))
+}
+
+func TestAttributesFromContext(t *testing.T) {
+	ctx := WithAttributes(context.Background(), AttrManifestName.String("fe"))
+	ctx = WithAttributes(ctx, AttrBuildReason.String("Initial Build"))
+	assert.Equal(t, []attribute.KeyValue{
+		AttrManifestName.String("fe"),
+		AttrBuildReason.String("Initial Build"),
+	}, AttributesFromContext(ctx))
+}
+
+func spans(names ...string) []sdktrace.ReadOnlySpan {
+	var result []sdktrace.ReadOnlySpan
+	for i, name := range names {
+		result = append(result, tracetest.SpanStub{
+			SpanContext: trace.NewSpanContext(trace.SpanContextConfig{
+				TraceID: trace.TraceID{1},
+				SpanID:  trace.SpanID{byte(i + 1)},
+			}),
+			Name: name,
+		}.Snapshot())
+	}
+	return result
+}
+
+type collectorFixture struct {
+	server *httptest.Server
+	status int
+
+	mu        sync.Mutex
+	path      string
+	header    http.Header
+	spanNames []string
+}
+
+func newCollectorFixture(t *testing.T) *collectorFixture {
+	f := &collectorFixture{status: http.StatusOK}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var req coltracepb.ExportTraceServiceRequest
+		require.NoError(t, proto.Unmarshal(body, &req))
+
+		f.mu.Lock()
+		f.path = r.URL.Path
+		f.header = r.Header
+		for _, rs := range req.ResourceSpans {
+			for _, ss := range rs.ScopeSpans {
+				for _, s := range ss.Spans {
+					f.spanNames = append(f.spanNames, s.Name)
+				}
+			}
+		}
+		f.mu.Unlock()
+
+		w.WriteHeader(f.status)
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
@@ -180,6 +180,16 @@ type ClusterStatus struct {
 	// of the architectures.
 	Arch string `json:"arch,omitempty" protobuf:"bytes,1,opt,name=arch"`
 
+	// All the chip architectures of the cluster, sorted.
+	//
+	// On Kubernetes, this will be the set of kubernetes.io/arch labels
+	// across all nodes.
+	//
+	// On Docker, this will only contain the Architecture of the Docker daemon.
+	//
+	// +optional
+	Arches []string `json:"arches,omitempty" protobuf:"bytes,7,rep,name=arches"`
+
 	// An unrecoverable error connecting to the cluster.
 	//
 	// +optional
@@ -109,6 +109,72 @@ type CmdSpec struct {
 	//
 	// +optional
 	DisableSource *DisableSource `json:"disableSource,omitempty" protobuf:"bytes,7,opt,name=disableSource"`
+
+	// Specifies how to stop the process.
+	//
+	// Used whenever Tilt stops the process: on restart, when the Cmd is
+	// disabled or deleted, and when Tilt exits.
+	//
+	// When not specified, Tilt sends SIGTERM to the process group, then
+	// kills it abruptly if it hasn't exited after 30 seconds.
+	//
+	// +optional
+	Stop *CmdStopSpec `json:"stop,omitempty" protobuf:"bytes,8,opt,name=stop"`
+
+	// Periodic probe of service liveness.
+	//
+	// When the probe fails, Tilt stops the process and starts it again.
+	// Consecutive restarts back off exponentially, up to one minute.
+	//
+	// +optional
+	LivenessProbe *Probe `json:"livenessProbe,omitempty" protobuf:"bytes,9,opt,name=livenessProbe"`
+
+	// Probe that indicates the process has finished starting up.
+	//
+	// When specified, the readiness and liveness probes don't run until the
+	// startup probe succeeds. If the startup probe fails, the process is
+	// restarted, the same as for a failed liveness probe.
+	//
+	// +optional
+	StartupProbe *Probe `json:"startupProbe,omitempty" protobuf:"bytes,10,opt,name=startupProbe"`
+
+	// How to parse the output of the process into structured logs.
+	//
+	// One of "json" or "logfmt". When not specified, output is logged as plain text.
+	//
+	// +optional
+	LogParser LogParser `json:"logParser,omitempty" protobuf:"bytes,11,opt,name=logParser,casttype=LogParser"`
+}
+
+// CmdStopSpec describes how to gracefully shut down a process.
+//
+// Based loosely on the Lifecycle PreStop hook and
+// TerminationGracePeriodSeconds in Kubernetes.
+type CmdStopSpec struct {
+	// The signal to send to the process group to ask it to shut down,
+	// e.g., "SIGTERM", "SIGINT", or "SIGQUIT".
+	//
+	// Defaults to SIGTERM. Ignored on Windows, where Tilt asks the process
+	// tree to exit with TASKKILL.
+	//
+	// +optional
+	Signal string `json:"signal,omitempty" protobuf:"bytes,1,opt,name=signal"`
+
+	// Number of seconds to wait for the process to exit before
+	// killing it abruptly. Includes the time spent on the pre-stop command.
+	//
+	// Defaults to 30 seconds. Minimum value is 1.
+	//
+	// +optional
+	GracePeriodSeconds int32 `json:"gracePeriodSeconds,omitempty" protobuf:"varint,2,opt,name=gracePeriodSeconds"`
+
+	// Command-line arguments of a command to run before the stop signal is sent.
+	//
+	// The command runs with the same working directory and environment
+	// as the process. Its exit status is logged but otherwise ignored.
+	//
+	// +optional
+	PreStopArgs []string `json:"preStopArgs,omitempty" protobuf:"bytes,3,rep,name=preStopArgs"`
 }
 
 var _ resource.Object = &Cmd{}
@@ -152,8 +218,7 @@ func (in *Cmd) IsStorageVersion() bool {
 }
 
 func (in *Cmd) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	return validateLogParser(field.NewPath("spec", "logParser"), in.Spec.LogParser)
 }
 
 var _ resource.ObjectList = &CmdList{}
@@ -190,6 +255,17 @@ type CmdStatus struct {
 	// Details about whether/why this is disabled.
 	// +optional
 	DisableStatus *DisableStatus `json:"disableStatus,omitempty" protobuf:"bytes,5,opt,name=disableStatus"`
+
+	// The number of times the process has been restarted
+	// because its liveness or startup probe failed.
+	//
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty" protobuf:"varint,6,opt,name=restartCount"`
+
+	// (brief) reason the process was last restarted by a failing probe.
+	//
+	// +optional
+	LastRestartReason string `json:"lastRestartReason,omitempty" protobuf:"bytes,7,opt,name=lastRestartReason"`
 }
 
 // CmdStateWaiting is a waiting state of a local command.
@@ -29,6 +29,9 @@ import (
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource/resourcestrategy"
 )
 
+// Build the image for every architecture in the cluster.
+const PlatformClusterAll = "cluster-all"
+
 // +genclient
 // +genclient:nonNamespaced
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
@@ -134,11 +137,25 @@ type DockerImageSpec struct {
 	// Equivalent to `--cache-from` in the Docker CLI.
 	CacheFrom []string `json:"cacheFrom,omitempty" protobuf:"bytes,9,rep,name=cacheFrom"`
 
+	// Where to export the build cache after a successful build, so that later
+	// builds can import it with CacheFrom.
+	//
+	// Uses the syntax of `--cache-to` in the Docker buildx CLI. Supports the
+	// inline, registry, and local exporters, in mode=min.
+	//
+	// +optional
+	CacheTo []string `json:"cacheTo,omitempty" protobuf:"bytes,18,rep,name=cacheTo"`
+
 	// Platform specifies architecture information for target image.
 	//
 	// https://docs.docker.com/desktop/multi-arch/
 	//
 	// Equivalent to `--platform` in the Docker CLI.
+	//
+	// May be a comma-separated list of platforms, or "cluster-all" to build for
+	// every architecture in the cluster. When there's more than one platform,
+	// Tilt builds each platform separately, then pushes a multi-arch manifest
+	// list that refers to all of them.
 	Platform string `json:"platform,omitempty" protobuf:"bytes,10,opt,name=platform"`
 
 	// By default, Tilt creates a new temporary image reference for each build.
@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 
 	"github.com/tilt-dev/tilt-apiserver/pkg/server/builder/resource"
@@ -191,6 +192,36 @@ type KubernetesApplySpec struct {
 	//
 	// +optional
 	ServerSideApply bool `json:"serverSideApply,omitempty" protobuf:"bytes,14,opt,name=serverSideApply"`
+
+	// SSAConflictPolicy determines what happens when a server-side apply
+	// tries to change a field that another field manager owns
+	// (e.g., an HPA that manages replicas, or a kubectl edit).
+	//
+	// Only used when ServerSideApply is true.
+	//
+	// If not provided, defaults to "force".
+	//
+	// +optional
+	SSAConflictPolicy SSAConflictPolicy `json:"ssaConflictPolicy,omitempty" protobuf:"bytes,15,opt,name=ssaConflictPolicy,casttype=SSAConflictPolicy"`
+
+	// DiffPreview shows a server-side dry-run diff between the objects
+	// to apply and the live objects in the cluster before each apply.
+	//
+	// Only supported for YAML applies.
+	//
+	// +optional
+	DiffPreview *KubernetesApplyDiffPreview `json:"diffPreview,omitempty" protobuf:"bytes,16,opt,name=diffPreview"`
+
+	// ApplySet is the ID that Tilt labels the applied objects with,
+	// so that it can prune them when they're removed from the apply.
+	//
+	// Must be unique across every Tilt session that shares the cluster.
+	// The Tiltfile derives it from the Tiltfile path and the resource name.
+	//
+	// Only supported for YAML applies. If not provided, Tilt doesn't prune.
+	//
+	// +optional
+	ApplySet string `json:"applySet,omitempty" protobuf:"bytes,17,opt,name=applySet"`
 }
 
 var _ resource.Object = &KubernetesApply{}
@@ -261,6 +292,30 @@ func (in *KubernetesApply) Validate(ctx context.Context) field.ErrorList {
 			}))
 	}
 
+	conflictPolicy := in.Spec.SSAConflictPolicy
+	if !(conflictPolicy == "" ||
+		conflictPolicy == SSAConflictPolicyForce ||
+		conflictPolicy == SSAConflictPolicyFail ||
+		conflictPolicy == SSAConflictPolicyYield) {
+		fieldErrors = append(fieldErrors, field.NotSupported(
+			field.NewPath("spec.ssaConflictPolicy"),
+			conflictPolicy,
+			[]string{
+				string(SSAConflictPolicyForce),
+				string(SSAConflictPolicyFail),
+				string(SSAConflictPolicyYield),
+			}))
+	}
+
+	if in.Spec.ApplySet != "" {
+		for _, msg := range validation.IsValidLabelValue(in.Spec.ApplySet) {
+			fieldErrors = append(fieldErrors, field.Invalid(
+				field.NewPath("spec.applySet"),
+				in.Spec.ApplySet,
+				msg))
+		}
+	}
+
 	if in.Spec.YAML != "" {
 		if in.Spec.ApplyCmd != nil {
 			fieldErrors = append(fieldErrors, field.Invalid(
@@ -335,6 +390,27 @@ type KubernetesApplyStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" protobuf:"bytes,7,rep,name=conditions"`
 
+	// Fields that other field managers own, which conflicted with
+	// the last server-side apply.
+	//
+	// +optional
+	Conflicts []KubernetesApplyConflict `json:"conflicts,omitempty" protobuf:"bytes,8,rep,name=conflicts"`
+
+	// The diff between the objects to apply and the live objects
+	// in the cluster, from the last diff preview.
+	//
+	// +optional
+	Diff string `json:"diff,omitempty" protobuf:"bytes,9,opt,name=diff"`
+
+	// A hash of the diff that's waiting for the user to confirm it,
+	// because it changes objects of one of the DiffPreview.ConfirmKinds.
+	//
+	// To confirm, set the tilt.dev/confirmed-diff annotation to this hash
+	// (e.g., with `tilt diff --confirm`).
+	//
+	// +optional
+	DiffAwaitingConfirmation string `json:"diffAwaitingConfirmation,omitempty" protobuf:"bytes,10,opt,name=diffAwaitingConfirmation"`
+
 	// TODO(nick): We should also add some sort of status field to this
 	// status (like waiting, active, done).
 }
@@ -420,6 +496,51 @@ var (
 	KubernetesDiscoveryStrategySelectorsOnly KubernetesDiscoveryStrategy = "selectors-only"
 )
 
+type KubernetesApplyDiffPreview struct {
+	// Kinds of objects (e.g., Secret or CustomResourceDefinition) whose
+	// changes must be confirmed before Tilt applies them.
+	//
+	// When the diff changes an object of one of these kinds, the apply
+	// waits until the diff is confirmed.
+	//
+	// +optional
+	ConfirmKinds []string `json:"confirmKinds,omitempty" protobuf:"bytes,1,rep,name=confirmKinds"`
+}
+
+type SSAConflictPolicy string
+
+var (
+	// Take ownership of the conflicting fields, overwriting changes made by
+	// other field managers. Equivalent to `kubectl apply --server-side --force-conflicts`.
+	SSAConflictPolicyForce SSAConflictPolicy = "force"
+
+	// Fail the apply, and report the conflicting fields.
+	SSAConflictPolicyFail SSAConflictPolicy = "fail"
+
+	// Leave the conflicting fields to their current managers, and apply
+	// everything else.
+	SSAConflictPolicyYield SSAConflictPolicy = "yield"
+)
+
+// A field owned by another field manager, which conflicted with a server-side apply.
+type KubernetesApplyConflict struct {
+	// The object with the conflicting field, in the form kind/namespace/name
+	// (or kind/name for cluster-scoped objects).
+	Object string `json:"object" protobuf:"bytes,1,opt,name=object"`
+
+	// The field manager that owns the field (e.g., "kubectl-edit").
+	Manager string `json:"manager" protobuf:"bytes,2,opt,name=manager"`
+
+	// The path of the field, in the server-side apply field path format
+	// (e.g., .spec.replicas or .spec.containers[name="app"].image).
+	Field string `json:"field" protobuf:"bytes,3,opt,name=field"`
+
+	// True if Tilt left the field to the other manager and applied everything else.
+	//
+	// +optional
+	Yielded bool `json:"yielded,omitempty" protobuf:"varint,4,opt,name=yielded"`
+}
+
 type KubernetesApplyCmd struct {
 	// Args are the command-line arguments for the apply command. Must have length >= 1.
 	Args []string `json:"args" protobuf:"bytes,1,rep,name=args"`
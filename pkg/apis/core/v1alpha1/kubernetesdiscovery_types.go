@@ -154,6 +154,13 @@ type PodLogStreamTemplateSpec struct {
 	//
 	// +optional
 	IgnoreContainers []string `json:"ignoreContainers,omitempty" protobuf:"bytes,3,rep,name=ignoreContainers"`
+
+	// How to parse the container logs into structured logs.
+	//
+	// One of "json" or "logfmt". When not specified, logs are stored as plain text.
+	//
+	// +optional
+	LogParser LogParser `json:"logParser,omitempty" protobuf:"bytes,4,opt,name=logParser,casttype=LogParser"`
 }
 
 func (in *KubernetesDiscovery) Default() {
@@ -117,6 +117,24 @@ type LiveUpdateSpec struct {
 	//
 	// +optional
 	InitialSync *LiveUpdateInitialSync `json:"initialSync,omitempty" protobuf:"bytes,8,opt,name=initialSync"`
+
+	// Verify configures a check that the container's copy of the synced
+	// files matches the local copy after each live update.
+	//
+	// If any file has drifted, the live update fails with reason FileDrift,
+	// and Tilt falls back to a full rebuild.
+	//
+	// +optional
+	Verify *LiveUpdateVerify `json:"verify,omitempty" protobuf:"bytes,10,opt,name=verify"`
+
+	// DeltaSync configures block-level transfer of large files.
+	//
+	// When set, large files that already exist in the container are copied
+	// by sending only the blocks that changed. Only supported when Tilt
+	// copies files with `kubectl exec`.
+	//
+	// +optional
+	DeltaSync *LiveUpdateDeltaSync `json:"deltaSync,omitempty" protobuf:"bytes,11,opt,name=deltaSync"`
 }
 
 var _ resource.Object = &LiveUpdate{}
@@ -388,6 +406,24 @@ var (
 // LiveUpdateInitialSync enables full file sync on container start/restart.
 type LiveUpdateInitialSync struct{}
 
+// LiveUpdateVerify enables verification of synced files.
+//
+// After the files are synced and the Execs have run, Tilt runs `sha256sum`
+// in the container on every file in the update and compares the checksums
+// against the local files. Files deleted locally must also be gone from
+// the container.
+//
+// Execs that rewrite synced files will be reported as drift.
+type LiveUpdateVerify struct{}
+
+// LiveUpdateDeltaSync enables block-level transfer of large files.
+//
+// Before copying a large file, Tilt asks the container for checksums of each
+// block of its current copy with `dd` and `sha256sum`, then sends only the
+// blocks that differ. If the container doesn't have these tools, Tilt copies
+// whole files.
+type LiveUpdateDeltaSync struct{}
+
 // LiveUpdateContainerStatus defines the observed state of
 // the live-update syncer for a particular container.
 type LiveUpdateContainerStatus struct {
@@ -0,0 +1,37 @@
+package v1alpha1
+
+import (
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// LogParser describes how to parse lines of log output into structured logs.
+//
+// When a parser is set, each line that it can parse is logged with the
+// level, message, and fields it contains. Lines that it can't parse
+// are logged as-is.
+type LogParser string
+
+var (
+	// Don't parse logs. Each line is logged as plain text.
+	LogParserNone LogParser = ""
+
+	// Parse each line as a JSON object, like
+	// {"level": "info", "msg": "hello", "request_id": "abc"}
+	LogParserJSON LogParser = "json"
+
+	// Parse each line as logfmt key/value pairs, like
+	// level=info msg=hello request_id=abc
+	LogParserLogfmt LogParser = "logfmt"
+)
+
+func validateLogParser(path *field.Path, parser LogParser) field.ErrorList {
+	if parser == LogParserNone || parser == LogParserJSON || parser == LogParserLogfmt {
+		return nil
+	}
+	return field.ErrorList{
+		field.NotSupported(path, parser, []string{
+			string(LogParserJSON),
+			string(LogParserLogfmt),
+		}),
+	}
+}
@@ -102,6 +102,13 @@ type PodLogStreamSpec struct {
 	//
 	// +optional
 	Cluster string `json:"cluster" protobuf:"bytes,6,opt,name=cluster"`
+
+	// How to parse the container logs into structured logs.
+	//
+	// One of "json" or "logfmt". When not specified, logs are stored as plain text.
+	//
+	// +optional
+	LogParser LogParser `json:"logParser,omitempty" protobuf:"bytes,7,opt,name=logParser,casttype=LogParser"`
 }
 
 var _ resource.Object = &PodLogStream{}
@@ -152,8 +159,7 @@ func (in *PodLogStream) Default() {
 }
 
 func (in *PodLogStream) Validate(ctx context.Context) field.ErrorList {
-	// TODO(user): Modify it, adding your API validation here.
-	return nil
+	return validateLogParser(field.NewPath("spec", "logParser"), in.Spec.LogParser)
 }
 
 var _ resource.ObjectList = &PodLogStreamList{}
@@ -68,6 +68,30 @@ type TCPSocketAction struct {
 	Host string `json:"host,omitempty" protobuf:"bytes,2,opt,name=host"`
 }
 
+// GRPCAction describes an action based on the gRPC health checking protocol
+// (https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+//
+// The server must implement the grpc.health.v1.Health service.
+type GRPCAction struct {
+	// Port number of the gRPC service.
+	// Number must be in the range 1 to 65535.
+	Port int32 `json:"port" protobuf:"varint,1,opt,name=port"`
+	// Optional: Host name to connect to, defaults to localhost.
+	// +optional
+	Host string `json:"host,omitempty" protobuf:"bytes,2,opt,name=host"`
+	// Service is the name of the service to place in the gRPC HealthCheckRequest.
+	//
+	// If this is not specified, the server reports the health of the server as a whole.
+	// +optional
+	Service string `json:"service,omitempty" protobuf:"bytes,3,opt,name=service"`
+	// Whether to connect to the server with TLS.
+	//
+	// The server certificate is not verified, so self-signed certificates
+	// for local development work without additional configuration.
+	// +optional
+	TLS bool `json:"tls,omitempty" protobuf:"varint,4,opt,name=tls"`
+}
+
 // ExecAction describes a "run in container" action.
 type ExecAction struct {
 	// Command is the command line to execute inside the container, the working directory for the
@@ -121,4 +145,7 @@ type Handler struct {
 	// TODO: implement a realistic TCP lifecycle hook
 	// +optional
 	TCPSocket *TCPSocketAction `json:"tcpSocket,omitempty" protobuf:"bytes,3,opt,name=tcpSocket"`
+	// GRPC specifies an action involving a gRPC health check.
+	// +optional
+	GRPC *GRPCAction `json:"grpc,omitempty" protobuf:"bytes,4,opt,name=grpc"`
 }
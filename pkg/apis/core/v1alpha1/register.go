@@ -37,6 +37,10 @@ const AnnotationTargetID = "tilt.dev/target-id"
 // that's not entirely expressed in apiserver objects.
 const AnnotationManagedBy = "tilt.dev/managed-by"
 
+// AnnotationConfirmedDiff confirms a KubernetesApply diff that's waiting for
+// confirmation. The value must be the hash in Status.DiffAwaitingConfirmation.
+const AnnotationConfirmedDiff = "tilt.dev/confirmed-diff"
+
 // AnnotationManifest identifies which manifest an object's logs should appear under.
 const AnnotationManifest = "tilt.dev/resource"
 
@@ -365,6 +365,46 @@ type UIResourceKubernetes struct {
 	// for this resource.
 	// +optional
 	DisplayNames []string `json:"displayNames,omitempty" protobuf:"bytes,9,rep,name=displayNames"`
+
+	// Set when Tilt rolled back the most recent deploy
+	// because its pods failed.
+	// +optional
+	Rollback *UIResourceKubernetesRollback `json:"rollback,omitempty" protobuf:"bytes,10,opt,name=rollback"`
+
+	// Fields that other field managers own, which conflicted with
+	// the last server-side apply.
+	// +optional
+	Conflicts []KubernetesApplyConflict `json:"conflicts,omitempty" protobuf:"bytes,11,rep,name=conflicts"`
+
+	// The diff between the objects to apply and the live objects
+	// in the cluster, from the last diff preview.
+	// +optional
+	Diff string `json:"diff,omitempty" protobuf:"bytes,12,opt,name=diff"`
+
+	// A hash of the diff that's waiting for the user to confirm it.
+	// Empty if the apply isn't waiting.
+	// +optional
+	DiffAwaitingConfirmation string `json:"diffAwaitingConfirmation,omitempty" protobuf:"bytes,13,opt,name=diffAwaitingConfirmation"`
+}
+
+// UIResourceKubernetesRollback describes an automatic rollback
+// to the last healthy deploy of a resource.
+type UIResourceKubernetesRollback struct {
+	// Time when Tilt rolled back the deploy.
+	// +optional
+	Time metav1.MicroTime `json:"time,omitempty" protobuf:"bytes,1,opt,name=time"`
+
+	// (brief) reason the deploy was rolled back.
+	// +optional
+	Reason string `json:"reason,omitempty" protobuf:"bytes,2,opt,name=reason"`
+
+	// The name of the failed pod.
+	// +optional
+	PodName string `json:"podName,omitempty" protobuf:"bytes,3,opt,name=podName"`
+
+	// The span where the failed pod stored its logs in the Tilt logstore.
+	// +optional
+	SpanID string `json:"spanID,omitempty" protobuf:"bytes,4,opt,name=spanID"`
 }
 
 // UIResourceCompose contains status information specific to Docker Compose.
@@ -385,6 +425,15 @@ type UIResourceLocal struct {
 	//
 	// +optional
 	IsTest bool `json:"isTest,omitempty" protobuf:"varint,2,opt,name=isTest"`
+
+	// The number of times the local command has been restarted
+	// because its liveness or startup probe failed.
+	// +optional
+	RestartCount int32 `json:"restartCount,omitempty" protobuf:"varint,3,opt,name=restartCount"`
+
+	// (brief) reason the local command was last restarted by a failing probe.
+	// +optional
+	LastRestartReason string `json:"lastRestartReason,omitempty" protobuf:"bytes,4,opt,name=lastRestartReason"`
 }
 
 type UIResourceStateWaiting struct {
@@ -90,6 +90,16 @@ var (
 	ErrorLvl   = Level{id: 5, name: "ERROR", severity: 500}
 )
 
+// Finds the level with the given name (as returned by Name()).
+func LevelByName(name string) (Level, bool) {
+	for _, l := range []Level{NoneLvl, DebugLvl, VerboseLvl, InfoLvl, WarnLvl, ErrorLvl} {
+		if l.name == name {
+			return l, true
+		}
+	}
+	return Level{}, false
+}
+
 type contextKey struct{}
 
 var LoggerContextKey = contextKey{}
@@ -44,3 +44,12 @@ func TestWriteWithFormatPlaceholder(t *testing.T) {
 	l.Write(InfoLvl, []byte("%s"))
 	assert.Equal(t, "%s", out.String())
 }
+
+func TestLevelByName(t *testing.T) {
+	level, ok := LevelByName(WarnLvl.Name())
+	assert.True(t, ok)
+	assert.Equal(t, WarnLvl, level)
+
+	_, ok = LevelByName("LOUD")
+	assert.False(t, ok)
+}
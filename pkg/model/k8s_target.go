@@ -3,6 +3,7 @@ package model
 import (
 	"fmt"
 	"reflect"
+	"time"
 
 	"github.com/tilt-dev/tilt/internal/sliceutils"
 	"github.com/tilt-dev/tilt/pkg/apis/core/v1alpha1"
@@ -34,6 +35,11 @@ type K8sTarget struct {
 
 	PodReadinessMode PodReadinessMode
 
+	// If non-zero, Tilt watches the pods of each deploy for this long.
+	// If they crash or don't become ready, Tilt re-applies the last
+	// deploy whose pods stayed healthy.
+	AutoRollbackWindow time.Duration
+
 	// Map configRef -> number of times we (expect to) inject it.
 	// NOTE(maia): currently this map is only for use in metrics, though someday
 	// we want a better way of mapping configRefs -> their injection point(s)
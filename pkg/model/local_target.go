@@ -16,6 +16,10 @@ type LocalTarget struct {
 	Links    []Link   // zero+ links assoc'd with this resource (to be displayed in UIs)
 	Deps     []string // a list of ABSOLUTE file paths that are dependencies of this target
 
+	// ABSOLUTE paths of the files that the update command generates.
+	// If set, we skip the command when its deps and outputs are unchanged.
+	Outputs []string
+
 	FileWatchIgnores []v1alpha1.IgnoreDef
 
 	// Indicates that we should allow this to run in parallel with other
@@ -23,9 +27,17 @@ type LocalTarget struct {
 	AllowParallel bool
 
 	ReadinessProbe *v1alpha1.Probe
+	LivenessProbe  *v1alpha1.Probe
+	StartupProbe   *v1alpha1.Probe
 
 	// Move this to CmdServerSpec when we move CmdServer to API
 	ServeCmdDisableSource *v1alpha1.DisableSource
+
+	// How to gracefully stop the ServeCmd.
+	ServeCmdStop *v1alpha1.CmdStopSpec
+
+	// How to parse the output of the ServeCmd into structured logs.
+	ServeCmdLogParser v1alpha1.LogParser
 }
 
 var _ TargetSpec = LocalTarget{}
@@ -68,6 +80,11 @@ func (lt LocalTarget) Empty() bool {
 	return lt.UpdateCmdSpec == nil && lt.ServeCmd.Empty()
 }
 
+func (lt LocalTarget) WithOutputs(outputs []string) LocalTarget {
+	lt.Outputs = outputs
+	return lt
+}
+
 func (lt LocalTarget) WithAllowParallel(val bool) LocalTarget {
 	lt.AllowParallel = val
 	return lt
@@ -83,6 +100,26 @@ func (lt LocalTarget) WithReadinessProbe(probeSpec *v1alpha1.Probe) LocalTarget
 	return lt
 }
 
+func (lt LocalTarget) WithLivenessProbe(probeSpec *v1alpha1.Probe) LocalTarget {
+	lt.LivenessProbe = probeSpec
+	return lt
+}
+
+func (lt LocalTarget) WithStartupProbe(probeSpec *v1alpha1.Probe) LocalTarget {
+	lt.StartupProbe = probeSpec
+	return lt
+}
+
+func (lt LocalTarget) WithServeCmdStop(stop *v1alpha1.CmdStopSpec) LocalTarget {
+	lt.ServeCmdStop = stop
+	return lt
+}
+
+func (lt LocalTarget) WithServeCmdLogParser(parser v1alpha1.LogParser) LocalTarget {
+	lt.ServeCmdLogParser = parser
+	return lt
+}
+
 func (lt LocalTarget) ID() TargetID {
 	return TargetID{
 		Name: lt.Name,
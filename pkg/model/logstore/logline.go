@@ -23,9 +23,33 @@ type LogLine struct {
 	// output - e.g., a line that communicates that the upload finished.
 	ProgressMustPrint bool
 
+	// Any other fields of the log, like those parsed from structured application logs.
+	Fields logger.Fields
+
 	Time time.Time
 }
 
+// Fields that have their own LogLine field.
+var lineFieldNames = map[string]bool{
+	logger.FieldNameProgressID:        true,
+	logger.FieldNameProgressMustPrint: true,
+	logger.FieldNameBuildEvent:        true,
+}
+
+func otherFields(fields logger.Fields) logger.Fields {
+	var result logger.Fields
+	for k, v := range fields {
+		if lineFieldNames[k] {
+			continue
+		}
+		if result == nil {
+			result = logger.Fields{}
+		}
+		result[k] = v
+	}
+	return result
+}
+
 type logLineBuilder struct {
 	span        *Span
 	segments    []LogSegment
@@ -87,6 +111,7 @@ func (b *logLineBuilder) buildSpaceLine(options logOptions) LogLine {
 		Level:        segment.Level,
 		BuildEvent:   segment.Fields[logger.FieldNameBuildEvent],
 		ManifestName: span.ManifestName,
+		Fields:       otherFields(segment.Fields),
 		Time:         time,
 	}
 }
@@ -132,6 +157,7 @@ func (b *logLineBuilder) buildMainLine(options logOptions) LogLine {
 		ManifestName:      span.ManifestName,
 		ProgressID:        progressID,
 		ProgressMustPrint: progressMustPrint,
+		Fields:            otherFields(segment.Fields),
 		Time:              time,
 	}
 }
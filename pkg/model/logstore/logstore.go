@@ -2,12 +2,11 @@ package logstore
 
 import (
 	"fmt"
+	"math"
 	"sort"
 	"strings"
 	"time"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-
 	"github.com/tilt-dev/tilt/pkg/logger"
 	"github.com/tilt-dev/tilt/pkg/model"
 	"github.com/tilt-dev/tilt/pkg/webview"
@@ -155,6 +154,19 @@ type LogStore struct {
 
 	// If the log is truncated, we need to adjust all checkpoints
 	checkpointOffset Checkpoint
+
+	// Receives segments as they're truncated, if set.
+	spillover Spillover
+}
+
+// Spillover receives segments as they're truncated from the LogStore,
+// so that they can be kept somewhere with more room (like on disk).
+//
+// Spill is called while the LogStore is being modified, so
+// implementations should be quick and must not call back into the LogStore.
+type Spillover interface {
+	// The spans include (at least) the spans of all the segments.
+	Spill(spans map[SpanID]*Span, segments []LogSegment)
 }
 
 func NewLogStoreForTesting(msg string) *LogStore {
@@ -172,6 +184,20 @@ func NewLogStore() *LogStore {
 	}
 }
 
+// Creates a LogStore that never truncates.
+//
+// Only use this for short-lived stores that hold a bounded set of logs,
+// like the results of a Query.
+func NewUnboundedLogStore() *LogStore {
+	s := NewLogStore()
+	s.maxLogLengthInBytes = math.MaxInt
+	return s
+}
+
+func (s *LogStore) SetSpillover(spillover Spillover) {
+	s.spillover = spillover
+}
+
 func (s *LogStore) Checkpoint() Checkpoint {
 	return s.checkpointFromIndex(len(s.segments))
 }
@@ -508,6 +534,17 @@ func (s *LogStore) ContinuingLinesWithOptions(checkpoint Checkpoint, opts LineOp
 }
 
 func (s *LogStore) ToLogList(fromCheckpoint Checkpoint) (*webview.LogList, error) {
+	return s.ToLogListMatching(fromCheckpoint, Query{})
+}
+
+// Like ToLogList, but only includes the segments that match the query.
+//
+// The checkpoints still cover all the segments, so that the client can
+// pick up from ToCheckpoint. The query's Limit is ignored.
+//
+// If a line didn't match until its last segments were written, the result
+// also includes its first segments, even if they're before fromCheckpoint.
+func (s *LogStore) ToLogListMatching(fromCheckpoint Checkpoint, q Query) (*webview.LogList, error) {
 	spans := make(map[string]*webview.LogSpan, len(s.spans))
 	for spanID, span := range s.spans {
 		spans[string(spanID)] = &webview.LogSpan{
@@ -524,17 +561,16 @@ func (s *LogStore) ToLogList(fromCheckpoint Checkpoint) (*webview.LogList, error
 		}, nil
 	}
 
-	segments := make([]*webview.LogSegment, 0, len(s.segments)-startIndex)
-	for i := startIndex; i < len(s.segments); i++ {
-		segment := s.segments[i]
-		segments = append(segments, &webview.LogSegment{
-			SpanId: string(segment.SpanID),
-			Level:  webview.LogLevel(segment.Level.Name()),
-			Time:   metav1.NewMicroTime(segment.Time),
-			Text:   string(segment.Text),
-			Anchor: segment.Anchor,
-			Fields: segment.Fields,
-		})
+	var segments []*webview.LogSegment
+	if q.IsZero() {
+		segments = make([]*webview.LogSegment, 0, len(s.segments)-startIndex)
+		for i := startIndex; i < len(s.segments); i++ {
+			segments = append(segments, SegmentToWebview(s.segments[i]))
+		}
+	} else {
+		for _, segment := range s.matchingSegments(startIndex, q) {
+			segments = append(segments, SegmentToWebview(segment))
+		}
 	}
 
 	return &webview.LogList{
@@ -754,6 +790,17 @@ func (s *LogStore) computeLen() int {
 	return result
 }
 
+// The number of bytes stored for each manifest.
+//
+// Global logs are under the empty manifest name.
+func (s *LogStore) BytesByManifest() map[model.ManifestName]int {
+	result := make(map[model.ManifestName]int)
+	for mn, weight := range s.createManifestWeightMap() {
+		result[mn] = weight.byteCount
+	}
+	return result
+}
+
 // After a log hits its limit, we need to truncate it to keep it small
 // we do this by cutting a big chunk at a time, so that we have rarer, larger changes, instead of
 // a small change every time new data is written to the log
@@ -789,6 +836,7 @@ func (s *LogStore) ensureMaxLength() {
 	// Lastly, go through all the segments, and truncate the manifests
 	// where we said we would.
 	newSegments := make([]LogSegment, 0, len(s.segments)/2)
+	var trimmedSegments []LogSegment
 	trimmedSegmentCount := 0
 	for i := len(s.segments) - 1; i >= 0; i-- {
 		segment := s.segments[i]
@@ -796,6 +844,9 @@ func (s *LogStore) ensureMaxLength() {
 		manifestWeightMap[mn].byteCount -= segment.Len()
 		if manifestWeightMap[mn].byteCount < 0 {
 			trimmedSegmentCount++
+			if s.spillover != nil {
+				trimmedSegments = append(trimmedSegments, segment)
+			}
 			continue
 		}
 
@@ -803,6 +854,10 @@ func (s *LogStore) ensureMaxLength() {
 	}
 
 	reverseLogSegments(newSegments)
+	if s.spillover != nil {
+		reverseLogSegments(trimmedSegments)
+		s.spillover.Spill(s.spans, trimmedSegments)
+	}
 	s.checkpointOffset += Checkpoint(trimmedSegmentCount)
 	s.segments = newSegments
 	s.recomputeDerivedValues()
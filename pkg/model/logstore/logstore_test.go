@@ -74,6 +74,36 @@ func TestLog_TruncateChattySpansFirst(t *testing.T) {
 	assert.Contains(t, l.String(), "Tiltfile Success")
 }
 
+func TestLog_BytesByManifest(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newGlobalTestLogEvent("hello\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe1\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe2\n"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "be\n"), nil)
+
+	assert.Equal(t, map[model.ManifestName]int{"": 6, "fe": 8, "be": 3}, l.BytesByManifest())
+}
+
+func TestLog_SpillTruncatedSegments(t *testing.T) {
+	l := NewLogStore()
+	l.maxLogLengthInBytes = 20
+	spillover := &fakeSpillover{}
+	l.SetSpillover(spillover)
+
+	l.Append(newTestLogEvent("fe", time.Now(), "fe1\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe2\n"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "be1\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe3\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe4\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe5\n"), nil)
+
+	// Every segment is either in memory or spilled, in order.
+	assert.Equal(t, "fe1\nfe2\nfe3\nfe4\n", spillover.String())
+	assert.Equal(t, "fe\nfe\nfe\nfe\n", spillover.manifests.String())
+	assert.Equal(t, "fe5\n", l.ManifestLog("fe"))
+	assert.Equal(t, "be1\n", l.ManifestLog("be"))
+}
+
 func TestLog_ChattyServicesDoNotTruncateTests(t *testing.T) {
 	if runtime.GOOS == "windows" {
 		t.Skip() // Windows has slightly different line-endings which effect this test
@@ -584,3 +614,15 @@ func lineOptionsWithManifests(mns ...model.ManifestName) LineOptions {
 	}
 	return LineOptions{ManifestNames: mnSet}
 }
+
+type fakeSpillover struct {
+	strings.Builder
+	manifests strings.Builder
+}
+
+func (s *fakeSpillover) Spill(spans map[SpanID]*Span, segments []LogSegment) {
+	for _, segment := range segments {
+		s.Write(segment.Text)
+		s.manifests.WriteString(spans[segment.SpanID].ManifestName.String() + "\n")
+	}
+}
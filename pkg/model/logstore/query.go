@@ -0,0 +1,396 @@
+package logstore
+
+import (
+	"bytes"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+// Query parameters, as they appear in a URL.
+const (
+	QueryParamSince    = "since"
+	QueryParamUntil    = "until"
+	QueryParamManifest = "manifest"
+	QueryParamLevel    = "level"
+	QueryParamGrep     = "grep"
+	QueryParamField    = "field"
+	QueryParamSource   = "source"
+	QueryParamLimit    = "limit"
+)
+
+// Where a log came from.
+type Source string
+
+const (
+	// Logs from both builds and runtime.
+	SourceAll Source = ""
+
+	// Logs from image builds and update commands.
+	SourceBuild Source = "build"
+
+	// Logs from running resources, like pods and serve_cmds.
+	SourceRuntime Source = "runtime"
+)
+
+// The implementation is identical to isBuildSpanId in web/src/logs.ts.
+func IsBuildSpanID(spanID SpanID) bool {
+	return strings.HasPrefix(string(spanID), "build:") || strings.HasPrefix(string(spanID), "cmdimage:")
+}
+
+// A Query selects log lines by time, manifest, level, and text.
+//
+// The zero Query matches all lines.
+//
+// Build events (the headers at the start of each build) match any Level and
+// Source, so that filtered logs still show which build each line belongs to.
+// The web UI filters logs the same way.
+type Query struct {
+	// Only match segments at or after Since, and before Until.
+	// Zero times mean no bound.
+	Since time.Time
+	Until time.Time
+
+	// Only match segments from these manifests. Empty matches all manifests.
+	ManifestNames model.ManifestNameSet
+
+	// Only match segments at least as severe as Level.
+	Level logger.Level
+
+	// Only match lines whose text matches, like grep. A line that was
+	// written in several segments is matched as a whole (see LineMatcher).
+	Regexp *regexp.Regexp
+
+	// Only match segments that have all of these fields, with these values.
+	Fields logger.Fields
+
+	// Only match build logs or runtime logs. Empty matches both.
+	Source Source
+
+	// Return at most this many segments, keeping the most recent.
+	// Zero means no limit.
+	Limit int
+}
+
+// Reports whether a single segment matches.
+//
+// Most callers want a LineMatcher instead, so that a line split
+// across segments is matched as a whole.
+func (q Query) Matches(mn model.ManifestName, segment LogSegment) bool {
+	if !q.Since.IsZero() && segment.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !segment.Time.Before(q.Until) {
+		return false
+	}
+	if len(q.ManifestNames) != 0 && !q.ManifestNames[mn] {
+		return false
+	}
+	if segment.Fields[logger.FieldNameBuildEvent] == "" {
+		if !segment.Level.AsSevereAs(q.Level) {
+			return false
+		}
+		if q.Source != SourceAll && IsBuildSpanID(segment.SpanID) != (q.Source == SourceBuild) {
+			return false
+		}
+	}
+	if q.Regexp != nil && !q.Regexp.Match(bytes.TrimRight(segment.Text, "\r\n")) {
+		return false
+	}
+	for k, v := range q.Fields {
+		actual, ok := segment.Fields[k]
+		if !ok || actual != v {
+			return false
+		}
+	}
+	return true
+}
+
+// LineMatcher applies a Query to whole lines.
+//
+// A line may be written in several segments, e.g., if a process prints part
+// of a line and then the rest of it later. If the line matches so far, all of
+// its segments match, including the ones held back before it matched, so that
+// clients never get part of a line.
+//
+// Segments must be added in the order they were written.
+type LineMatcher struct {
+	q     Query
+	lines map[SpanID]*partialLine
+}
+
+// The segments of a span's current line.
+type partialLine struct {
+	// The text of the line so far, with the metadata of its first segment.
+	joined  LogSegment
+	mn      model.ManifestName
+	matched bool
+
+	// Segments that we're holding back until the line matches.
+	held []LogSegment
+}
+
+func NewLineMatcher(q Query) *LineMatcher {
+	return &LineMatcher{q: q, lines: map[SpanID]*partialLine{}}
+}
+
+// Adds the next segment, and returns the segments that match, in order.
+//
+// Returns nothing if the line doesn't match yet. Returns the held back
+// segments too, once a later segment makes the line match.
+func (m *LineMatcher) Add(mn model.ManifestName, segment LogSegment) []LogSegment {
+	line, ok := m.lines[segment.SpanID]
+	if !ok || !segment.ContinuesLine {
+		joined := segment
+		joined.Text = append([]byte{}, segment.Text...)
+		line = &partialLine{joined: joined, mn: mn}
+		m.lines[segment.SpanID] = line
+	} else {
+		line.joined.Text = append(line.joined.Text, segment.Text...)
+	}
+
+	if segment.IsComplete() {
+		delete(m.lines, segment.SpanID)
+	}
+
+	if !line.matched && m.q.Matches(line.mn, line.joined) {
+		line.matched = true
+	}
+	if !line.matched {
+		line.held = append(line.held, segment)
+		return nil
+	}
+
+	result := append(line.held, segment)
+	line.held = nil
+	return result
+}
+
+// Encodes the query as URL parameters, for ParseQuery.
+func (q Query) Values() url.Values {
+	v := url.Values{}
+	if !q.Since.IsZero() {
+		v.Set(QueryParamSince, q.Since.Format(time.RFC3339Nano))
+	}
+	if !q.Until.IsZero() {
+		v.Set(QueryParamUntil, q.Until.Format(time.RFC3339Nano))
+	}
+	if len(q.ManifestNames) != 0 {
+		mns := make([]string, 0, len(q.ManifestNames))
+		for mn := range q.ManifestNames {
+			mns = append(mns, mn.String())
+		}
+		sort.Strings(mns)
+		v[QueryParamManifest] = mns
+	}
+	if q.Level.AsSevereAs(logger.DebugLvl) {
+		v.Set(QueryParamLevel, strings.ToLower(q.Level.Name()))
+	}
+	if q.Regexp != nil {
+		v.Set(QueryParamGrep, q.Regexp.String())
+	}
+	if len(q.Fields) != 0 {
+		fields := make([]string, 0, len(q.Fields))
+		for k, value := range q.Fields {
+			fields = append(fields, k+"="+value)
+		}
+		sort.Strings(fields)
+		v[QueryParamField] = fields
+	}
+	if q.Source != SourceAll {
+		v.Set(QueryParamSource, string(q.Source))
+	}
+	if q.Limit > 0 {
+		v.Set(QueryParamLimit, strconv.Itoa(q.Limit))
+	}
+	return v
+}
+
+// Parses a query from URL parameters.
+//
+// Times are in RFC3339 format. Levels are case-insensitive level names
+// (like "warn"). The grep parameter is a Go regular expression.
+// Fields are key=value pairs, and may be repeated. The source is one of
+// "all", "build", or "runtime".
+func ParseQuery(v url.Values) (Query, error) {
+	q := Query{}
+	var err error
+	if s := v.Get(QueryParamSince); s != "" {
+		q.Since, err = time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid %s: %v", QueryParamSince, err)
+		}
+	}
+	if s := v.Get(QueryParamUntil); s != "" {
+		q.Until, err = time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid %s: %v", QueryParamUntil, err)
+		}
+	}
+	if mns := v[QueryParamManifest]; len(mns) != 0 {
+		q.ManifestNames = model.ManifestNameSet{}
+		for _, mn := range mns {
+			q.ManifestNames[model.ManifestName(mn)] = true
+		}
+	}
+	if s := v.Get(QueryParamLevel); s != "" {
+		name := strings.ToUpper(s)
+		if name == "WARNING" {
+			name = logger.WarnLvl.Name()
+		}
+		level, ok := logger.LevelByName(name)
+		if !ok {
+			return Query{}, fmt.Errorf("invalid %s: %q", QueryParamLevel, s)
+		}
+		q.Level = level
+	}
+	if s := v.Get(QueryParamGrep); s != "" {
+		q.Regexp, err = regexp.Compile(s)
+		if err != nil {
+			return Query{}, fmt.Errorf("invalid %s: %v", QueryParamGrep, err)
+		}
+	}
+	if fields := v[QueryParamField]; len(fields) != 0 {
+		q.Fields = logger.Fields{}
+		for _, field := range fields {
+			k, value, ok := strings.Cut(field, "=")
+			if !ok || k == "" {
+				return Query{}, fmt.Errorf("invalid %s: %q (expected key=value)", QueryParamField, field)
+			}
+			q.Fields[k] = value
+		}
+	}
+	switch s := v.Get(QueryParamSource); s {
+	case "", "all":
+	case string(SourceBuild), string(SourceRuntime):
+		q.Source = Source(s)
+	default:
+		return Query{}, fmt.Errorf("invalid %s: %q", QueryParamSource, s)
+	}
+	if s := v.Get(QueryParamLimit); s != "" {
+		q.Limit, err = strconv.Atoi(s)
+		if err != nil || q.Limit < 0 {
+			return Query{}, fmt.Errorf("invalid %s: %q", QueryParamLimit, s)
+		}
+	}
+	return q, nil
+}
+
+// Reports whether the query matches all segments.
+func (q Query) IsZero() bool {
+	return q.Since.IsZero() &&
+		q.Until.IsZero() &&
+		len(q.ManifestNames) == 0 &&
+		!q.Level.AsSevereAs(logger.DebugLvl) &&
+		q.Regexp == nil &&
+		len(q.Fields) == 0 &&
+		q.Source == SourceAll &&
+		q.Limit == 0
+}
+
+// Returns the segments in the store that match the query.
+//
+// The result doesn't use the incremental load protocol,
+// so its checkpoints are both 0.
+func (s *LogStore) Query(q Query) *webview.LogList {
+	result := &webview.LogList{Spans: map[string]*webview.LogSpan{}}
+	for _, segment := range s.matchingSegments(0, q) {
+		span := s.spans[segment.SpanID]
+		result.Segments = append(result.Segments, SegmentToWebview(segment))
+		result.Spans[string(segment.SpanID)] = &webview.LogSpan{ManifestName: span.ManifestName.String()}
+	}
+
+	if q.Limit > 0 && len(result.Segments) > q.Limit {
+		result.Segments = result.Segments[len(result.Segments)-q.Limit:]
+		spans := map[string]*webview.LogSpan{}
+		for _, segment := range result.Segments {
+			spans[segment.SpanId] = result.Spans[segment.SpanId]
+		}
+		result.Spans = spans
+	}
+	return result
+}
+
+// Returns the segments from startIndex on that match the query.
+//
+// A line that started before startIndex was matched by an earlier call, when
+// only its first segments had been written. If it didn't match then but does
+// now, we return its first segments too, so the client gets the whole line.
+func (s *LogStore) matchingSegments(startIndex int, q Query) []LogSegment {
+	m := NewLineMatcher(q)
+	for _, i := range s.unfinishedLineStarts(startIndex) {
+		segment := s.segments[i]
+		span, ok := s.spans[segment.SpanID]
+		if !ok {
+			continue
+		}
+		// If the line matched, these segments were returned last time.
+		_ = m.Add(span.ManifestName, segment)
+	}
+
+	var result []LogSegment
+	for i := startIndex; i < len(s.segments); i++ {
+		segment := s.segments[i]
+		span, ok := s.spans[segment.SpanID]
+		if !ok {
+			continue
+		}
+		result = append(result, m.Add(span.ManifestName, segment)...)
+	}
+	return result
+}
+
+// Returns the indices of the segments before startIndex that are
+// part of a line that continues at or after startIndex.
+func (s *LogStore) unfinishedLineStarts(startIndex int) []int {
+	continued := map[SpanID]bool{}
+	seen := map[SpanID]bool{}
+	for i := startIndex; i < len(s.segments); i++ {
+		segment := s.segments[i]
+		if seen[segment.SpanID] {
+			continue
+		}
+		seen[segment.SpanID] = true
+		if segment.ContinuesLine {
+			continued[segment.SpanID] = true
+		}
+	}
+
+	var result []int
+	for i := startIndex - 1; i >= 0 && len(continued) > 0; i-- {
+		segment := s.segments[i]
+		if !continued[segment.SpanID] {
+			continue
+		}
+		result = append(result, i)
+		if segment.StartsLine() {
+			delete(continued, segment.SpanID)
+		}
+	}
+
+	for i, j := 0, len(result)-1; i < j; i, j = i+1, j-1 {
+		result[i], result[j] = result[j], result[i]
+	}
+	return result
+}
+
+func SegmentToWebview(segment LogSegment) *webview.LogSegment {
+	return &webview.LogSegment{
+		SpanId: string(segment.SpanID),
+		Level:  webview.LogLevel(segment.Level.Name()),
+		Time:   metav1.NewMicroTime(segment.Time),
+		Text:   string(segment.Text),
+		Anchor: segment.Anchor,
+		Fields: segment.Fields,
+	}
+}
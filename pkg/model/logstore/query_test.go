@@ -0,0 +1,217 @@
+package logstore
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/tilt-dev/tilt/pkg/logger"
+	"github.com/tilt-dev/tilt/pkg/model"
+	"github.com/tilt-dev/tilt/pkg/webview"
+)
+
+func TestQuery(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", start, "fe starting\n"), nil)
+	l.Append(newTestLogEvent("be", start.Add(time.Minute), "be starting\n"), nil)
+	warning := newTestLogEvent("fe", start.Add(2*time.Minute), "fe is slow\n")
+	warning.level = logger.WarnLvl
+	l.Append(warning, nil)
+	done := newTestLogEvent("fe", start.Add(3*time.Minute), "fe done\n")
+	done.fields = logger.Fields{"request_id": "abc"}
+	l.Append(done, nil)
+
+	for _, tc := range []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{"all", Query{}, []string{"fe starting\n", "be starting\n", "fe is slow\n", "fe done\n"}},
+		{"since", Query{Since: start.Add(2 * time.Minute)}, []string{"fe is slow\n", "fe done\n"}},
+		{"until", Query{Until: start.Add(time.Minute)}, []string{"fe starting\n"}},
+		{"manifests", Query{ManifestNames: model.ManifestNameSet{"be": true}}, []string{"be starting\n"}},
+		{"level", Query{Level: logger.WarnLvl}, []string{"fe is slow\n"}},
+		{"regexp", Query{Regexp: regexp.MustCompile("starting$")}, []string{"fe starting\n", "be starting\n"}},
+		{"fields", Query{Fields: logger.Fields{"request_id": "abc"}}, []string{"fe done\n"}},
+		{"missing field", Query{Fields: logger.Fields{"request_id": "xyz"}}, []string{}},
+		{"limit", Query{Limit: 1}, []string{"fe done\n"}},
+		{"build", Query{Source: SourceBuild}, []string{}},
+		{"runtime", Query{Source: SourceRuntime}, []string{"fe starting\n", "be starting\n", "fe is slow\n", "fe done\n"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			result := l.Query(tc.query)
+			texts := []string{}
+			for _, segment := range result.Segments {
+				texts = append(texts, segment.Text)
+				assert.Contains(t, result.Spans, segment.SpanId)
+			}
+			assert.Equal(t, tc.expected, texts)
+			assert.Len(t, result.Spans, len(spanIDs(result.Segments)))
+		})
+	}
+}
+
+func TestQuerySourceAndBuildEvents(t *testing.T) {
+	start := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLogStore()
+	header := newTestLogEvent("fe", start, "Initial Build\n")
+	header.spanID = "build:1"
+	header.fields = logger.Fields{logger.FieldNameBuildEvent: "init"}
+	l.Append(header, nil)
+	build := newTestLogEvent("fe", start.Add(time.Minute), "Step 1\n")
+	build.spanID = "build:1"
+	l.Append(build, nil)
+	l.Append(newTestLogEvent("fe", start.Add(2*time.Minute), "Listening\n"), nil)
+
+	for _, tc := range []struct {
+		name     string
+		query    Query
+		expected []string
+	}{
+		{"build", Query{Source: SourceBuild}, []string{"Initial Build\n", "Step 1\n"}},
+		{"runtime", Query{Source: SourceRuntime}, []string{"Initial Build\n", "Listening\n"}},
+		{"level", Query{Level: logger.ErrorLvl}, []string{"Initial Build\n"}},
+		{"regexp", Query{Regexp: regexp.MustCompile("Listening")}, []string{"Listening\n"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			texts := []string{}
+			for _, segment := range l.Query(tc.query).Segments {
+				texts = append(texts, segment.Text)
+			}
+			assert.Equal(t, tc.expected, texts)
+		})
+	}
+}
+
+func TestToLogListMatching(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "fe 1\n"), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "be 1\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "fe 2\n"), nil)
+
+	q := Query{ManifestNames: model.ManifestNameSet{"fe": true}}
+	list, err := l.ToLogListMatching(1, q)
+	require.NoError(t, err)
+	require.Len(t, list.Segments, 1)
+	assert.Equal(t, "fe 2\n", list.Segments[0].Text)
+	assert.Equal(t, int32(1), list.FromCheckpoint)
+	assert.Equal(t, int32(3), list.ToCheckpoint)
+
+	list, err = l.ToLogListMatching(0, Query{ManifestNames: model.ManifestNameSet{"db": true}})
+	require.NoError(t, err)
+	assert.Empty(t, list.Segments)
+	assert.Equal(t, int32(0), list.FromCheckpoint)
+	assert.Equal(t, int32(3), list.ToCheckpoint)
+}
+
+func TestQueryLineSplitAcrossWrites(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "connection "), nil)
+	l.Append(newTestLogEvent("be", time.Now(), "be 1\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "refused\n"), nil)
+
+	for _, re := range []string{"refused", "^connection refused$", "connection"} {
+		t.Run(re, func(t *testing.T) {
+			texts := []string{}
+			for _, segment := range l.Query(Query{Regexp: regexp.MustCompile(re)}).Segments {
+				texts = append(texts, segment.Text)
+			}
+			assert.Equal(t, []string{"connection ", "refused\n"}, texts)
+		})
+	}
+}
+
+func TestToLogListMatchingLineSplitAcrossWrites(t *testing.T) {
+	l := NewLogStore()
+	l.Append(newTestLogEvent("fe", time.Now(), "connection "), nil)
+
+	// The line doesn't match yet, so it's held back.
+	q := Query{Regexp: regexp.MustCompile("refused")}
+	list, err := l.ToLogListMatching(0, q)
+	require.NoError(t, err)
+	assert.Empty(t, list.Segments)
+	assert.Equal(t, int32(1), list.ToCheckpoint)
+
+	// Once it matches, the client gets the whole line.
+	l.Append(newTestLogEvent("be", time.Now(), "be 1\n"), nil)
+	l.Append(newTestLogEvent("fe", time.Now(), "refused\n"), nil)
+	list, err = l.ToLogListMatching(1, q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"connection ", "refused\n"}, segmentTexts(list.Segments))
+
+	// If the start of the line already matched, the client
+	// already has it, so only the rest is sent.
+	q = Query{Regexp: regexp.MustCompile("connection")}
+	list, err = l.ToLogListMatching(0, q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"connection ", "refused\n"}, segmentTexts(list.Segments))
+	list, err = l.ToLogListMatching(1, q)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"refused\n"}, segmentTexts(list.Segments))
+}
+
+func segmentTexts(segments []*webview.LogSegment) []string {
+	texts := []string{}
+	for _, segment := range segments {
+		texts = append(texts, segment.Text)
+	}
+	return texts
+}
+
+func TestQueryValuesRoundTrip(t *testing.T) {
+	q := Query{
+		Since:         time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC),
+		Until:         time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC),
+		ManifestNames: model.ManifestNameSet{"fe": true, "be": true},
+		Level:         logger.WarnLvl,
+		Regexp:        regexp.MustCompile("err(or)?"),
+		Fields:        logger.Fields{"request_id": "abc", "user": "a=b"},
+		Source:        SourceRuntime,
+		Limit:         10,
+	}
+	assert.Equal(t,
+		"field=request_id%3Dabc&field=user%3Da%3Db&grep=err%28or%29%3F&level=warn&limit=10&manifest=be&manifest=fe&since=2026-01-01T00%3A00%3A00Z&source=runtime&until=2026-01-02T00%3A00%3A00Z",
+		q.Values().Encode())
+
+	parsed, err := ParseQuery(q.Values())
+	require.NoError(t, err)
+	assert.Equal(t, q, parsed)
+
+	empty, err := ParseQuery(Query{}.Values())
+	require.NoError(t, err)
+	assert.Equal(t, Query{}, empty)
+	assert.True(t, empty.IsZero())
+	assert.False(t, parsed.IsZero())
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	for _, tc := range []struct {
+		param, value, expected string
+	}{
+		{QueryParamSince, "yesterday", "invalid since"},
+		{QueryParamLevel, "loud", `invalid level: "loud"`},
+		{QueryParamGrep, "(", "invalid grep"},
+		{QueryParamField, "request_id", `invalid field: "request_id"`},
+		{QueryParamSource, "cluster", `invalid source: "cluster"`},
+		{QueryParamLimit, "-1", `invalid limit: "-1"`},
+	} {
+		t.Run(tc.param, func(t *testing.T) {
+			_, err := ParseQuery(map[string][]string{tc.param: {tc.value}})
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tc.expected)
+			}
+		})
+	}
+}
+
+func spanIDs(segments []*webview.LogSegment) map[string]bool {
+	result := map[string]bool{}
+	for _, segment := range segments {
+		result[segment.SpanId] = true
+	}
+	return result
+}
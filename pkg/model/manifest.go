@@ -63,6 +63,27 @@ type Manifest struct {
 	SourceTiltfile ManifestName
 
 	Labels map[string]string
+
+	// When several resources are waiting to build, Tilt builds the one
+	// with the highest priority first. The default is 0.
+	Priority int
+
+	// Concurrency groups this resource belongs to. The resource will not be
+	// built while its group already has the maximum number of builds in progress.
+	ConcurrencyGroups []ConcurrencyGroup
+
+	// When true, file changes that arrive while an image for this resource is
+	// building cancel the build. Tilt starts a new build with all the changes.
+	CancelStaleImageBuilds bool
+}
+
+// A set of resources that share something scarce (like a test database),
+// and must limit how many of them update at once.
+type ConcurrencyGroup struct {
+	Name string
+
+	// The maximum number of resources in this group that may build at the same time.
+	Limit int
 }
 
 func (m Manifest) ID() TargetID {
@@ -232,6 +253,21 @@ func (m Manifest) WithLabels(labels map[string]string) Manifest {
 	return m
 }
 
+func (m Manifest) WithPriority(priority int) Manifest {
+	m.Priority = priority
+	return m
+}
+
+func (m Manifest) WithCancelStaleImageBuilds(cancel bool) Manifest {
+	m.CancelStaleImageBuilds = cancel
+	return m
+}
+
+func (m Manifest) WithConcurrencyGroups(groups []ConcurrencyGroup) Manifest {
+	m.ConcurrencyGroups = append([]ConcurrencyGroup(nil), groups...)
+	return m
+}
+
 func (m Manifest) Validate() error {
 	if m.Name == "" {
 		return fmt.Errorf("[validate] manifest missing name: %+v", m)
@@ -593,7 +629,12 @@ var portForwardPathAllowUnexported = cmp.AllowUnexported(PortForward{})
 var ignoreCustomBuildDepsField = cmpopts.IgnoreFields(CustomBuild{}, "Deps")
 var ignoreLocalTargetDepsField = cmpopts.IgnoreFields(LocalTarget{}, "Deps")
 var ignoreDockerBuildCacheFrom = cmpopts.IgnoreFields(DockerBuild{}, "CacheFrom")
+var ignoreDockerBuildCacheTo = cmpopts.IgnoreFields(DockerBuild{}, "CacheTo")
 var ignoreLabels = cmpopts.IgnoreFields(Manifest{}, "Labels")
+var ignoreConcurrencyGroups = cmpopts.IgnoreFields(Manifest{}, "ConcurrencyGroups")
+var ignorePriority = cmpopts.IgnoreFields(Manifest{}, "Priority")
+var ignoreCancelStaleImageBuilds = cmpopts.IgnoreFields(Manifest{}, "CancelStaleImageBuilds")
+var ignoreAutoRollbackWindow = cmpopts.IgnoreFields(K8sTarget{}, "AutoRollbackWindow")
 var ignoreDockerComposeProject = cmpopts.IgnoreFields(v1alpha1.DockerComposeServiceSpec{}, "Project")
 var ignoreRegistryFields = cmpopts.IgnoreFields(v1alpha1.RegistryHosting{}, "HostFromClusterNetwork", "Help")
 
@@ -635,13 +676,22 @@ func equalForBuildInvalidation(x, y interface{}) bool {
 		ignoreCustomBuildDepsField,
 		ignoreLocalTargetDepsField,
 
-		// DockerBuild.CacheFrom doesn't invalidate a build (b/c it affects HOW we build but
+		// DockerBuild.CacheFrom and CacheTo don't invalidate a build (b/c they affect HOW we build but
 		// shouldn't affect the result of the build), so don't compare these fields
 		ignoreDockerBuildCacheFrom,
+		ignoreDockerBuildCacheTo,
 
 		// user-added labels don't invalidate a build
 		ignoreLabels,
 
+		// concurrency groups and priority only affect when we build, not what we build
+		ignoreConcurrencyGroups,
+		ignorePriority,
+		ignoreCancelStaleImageBuilds,
+
+		// auto-rollback only affects what we do after a deploy fails
+		ignoreAutoRollbackWindow,
+
 		// user-added links don't invalidate a build
 		ignoreLinks,
 
@@ -10,4 +10,23 @@ type TelemetrySettings struct {
 
 	// How often to send the trace data.
 	Period time.Duration
+
+	// Where to export trace data with the OpenTelemetry protocol.
+	// If empty, we fall back to the OTEL_EXPORTER_OTLP_* environment variables.
+	OTLP OTLPSettings
+}
+
+type OTLPSettings struct {
+	// The URL of the collector, e.g., http://localhost:4317
+	Endpoint string
+
+	// Either "grpc" or "http/protobuf".
+	Protocol string
+
+	// Extra headers to send with each export request.
+	Headers map[string]string
+}
+
+func (s OTLPSettings) Empty() bool {
+	return s.Endpoint == ""
 }
@@ -19,6 +19,10 @@ type UpdateSettings struct {
 
 	// A list of images to suppress the warning for.
 	SuppressUnusedImageWarnings []string
+
+	// Whether to cancel in-progress local builds to make room for
+	// higher-priority builds.
+	preemptLocalUpdates bool
 }
 
 func (us UpdateSettings) MaxParallelUpdates() int {
@@ -47,6 +51,15 @@ func (us UpdateSettings) WithK8sServerSideApply(v string) UpdateSettings {
 	return us
 }
 
+func (us UpdateSettings) PreemptLocalUpdates() bool {
+	return us.preemptLocalUpdates
+}
+
+func (us UpdateSettings) WithPreemptLocalUpdates(v bool) UpdateSettings {
+	us.preemptLocalUpdates = v
+	return us
+}
+
 func (us UpdateSettings) K8sUpsertTimeout() time.Duration {
 	// Min. value is 1s
 	if us.k8sUpsertTimeout < time.Second {
@@ -54,6 +54,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.CmdStateTerminated{}.OpenAPIModelName():                schema_pkg_apis_core_v1alpha1_CmdStateTerminated(ref),
 		v1alpha1.CmdStateWaiting{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_CmdStateWaiting(ref),
 		v1alpha1.CmdStatus{}.OpenAPIModelName():                         schema_pkg_apis_core_v1alpha1_CmdStatus(ref),
+		v1alpha1.CmdStopSpec{}.OpenAPIModelName():                       schema_pkg_apis_core_v1alpha1_CmdStopSpec(ref),
 		v1alpha1.ConfigMap{}.OpenAPIModelName():                         schema_pkg_apis_core_v1alpha1_ConfigMap(ref),
 		v1alpha1.ConfigMapDisableSource{}.OpenAPIModelName():            schema_pkg_apis_core_v1alpha1_ConfigMapDisableSource(ref),
 		v1alpha1.ConfigMapList{}.OpenAPIModelName():                     schema_pkg_apis_core_v1alpha1_ConfigMapList(ref),
@@ -103,6 +104,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.FileWatchStatus{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_FileWatchStatus(ref),
 		v1alpha1.Forward{}.OpenAPIModelName():                           schema_pkg_apis_core_v1alpha1_Forward(ref),
 		v1alpha1.ForwardStatus{}.OpenAPIModelName():                     schema_pkg_apis_core_v1alpha1_ForwardStatus(ref),
+		v1alpha1.GRPCAction{}.OpenAPIModelName():                        schema_pkg_apis_core_v1alpha1_GRPCAction(ref),
 		v1alpha1.HTTPGetAction{}.OpenAPIModelName():                     schema_pkg_apis_core_v1alpha1_HTTPGetAction(ref),
 		v1alpha1.HTTPHeader{}.OpenAPIModelName():                        schema_pkg_apis_core_v1alpha1_HTTPHeader(ref),
 		v1alpha1.Handler{}.OpenAPIModelName():                           schema_pkg_apis_core_v1alpha1_Handler(ref),
@@ -115,6 +117,8 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.ImageMapStatus{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_ImageMapStatus(ref),
 		v1alpha1.KubernetesApply{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_KubernetesApply(ref),
 		v1alpha1.KubernetesApplyCmd{}.OpenAPIModelName():                schema_pkg_apis_core_v1alpha1_KubernetesApplyCmd(ref),
+		v1alpha1.KubernetesApplyConflict{}.OpenAPIModelName():           schema_pkg_apis_core_v1alpha1_KubernetesApplyConflict(ref),
+		v1alpha1.KubernetesApplyDiffPreview{}.OpenAPIModelName():        schema_pkg_apis_core_v1alpha1_KubernetesApplyDiffPreview(ref),
 		v1alpha1.KubernetesApplyList{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref),
 		v1alpha1.KubernetesApplySpec{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref),
 		v1alpha1.KubernetesApplyStatus{}.OpenAPIModelName():             schema_pkg_apis_core_v1alpha1_KubernetesApplyStatus(ref),
@@ -133,6 +137,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.LiveUpdate{}.OpenAPIModelName():                        schema_pkg_apis_core_v1alpha1_LiveUpdate(ref),
 		v1alpha1.LiveUpdateContainerStateWaiting{}.OpenAPIModelName():   schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStateWaiting(ref),
 		v1alpha1.LiveUpdateContainerStatus{}.OpenAPIModelName():         schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStatus(ref),
+		v1alpha1.LiveUpdateDeltaSync{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_LiveUpdateDeltaSync(ref),
 		v1alpha1.LiveUpdateDockerComposeSelector{}.OpenAPIModelName():   schema_pkg_apis_core_v1alpha1_LiveUpdateDockerComposeSelector(ref),
 		v1alpha1.LiveUpdateExec{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_LiveUpdateExec(ref),
 		v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName():             schema_pkg_apis_core_v1alpha1_LiveUpdateInitialSync(ref),
@@ -144,6 +149,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.LiveUpdateStateFailed{}.OpenAPIModelName():             schema_pkg_apis_core_v1alpha1_LiveUpdateStateFailed(ref),
 		v1alpha1.LiveUpdateStatus{}.OpenAPIModelName():                  schema_pkg_apis_core_v1alpha1_LiveUpdateStatus(ref),
 		v1alpha1.LiveUpdateSync{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref),
+		v1alpha1.LiveUpdateVerify{}.OpenAPIModelName():                  schema_pkg_apis_core_v1alpha1_LiveUpdateVerify(ref),
 		v1alpha1.ObjectSelector{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_ObjectSelector(ref),
 		v1alpha1.Pod{}.OpenAPIModelName():                               schema_pkg_apis_core_v1alpha1_Pod(ref),
 		v1alpha1.PodCondition{}.OpenAPIModelName():                      schema_pkg_apis_core_v1alpha1_PodCondition(ref),
@@ -210,6 +216,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		v1alpha1.UIResourceCompose{}.OpenAPIModelName():                 schema_pkg_apis_core_v1alpha1_UIResourceCompose(ref),
 		v1alpha1.UIResourceCondition{}.OpenAPIModelName():               schema_pkg_apis_core_v1alpha1_UIResourceCondition(ref),
 		v1alpha1.UIResourceKubernetes{}.OpenAPIModelName():              schema_pkg_apis_core_v1alpha1_UIResourceKubernetes(ref),
+		v1alpha1.UIResourceKubernetesRollback{}.OpenAPIModelName():      schema_pkg_apis_core_v1alpha1_UIResourceKubernetesRollback(ref),
 		v1alpha1.UIResourceLink{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_UIResourceLink(ref),
 		v1alpha1.UIResourceList{}.OpenAPIModelName():                    schema_pkg_apis_core_v1alpha1_UIResourceList(ref),
 		v1alpha1.UIResourceLocal{}.OpenAPIModelName():                   schema_pkg_apis_core_v1alpha1_UIResourceLocal(ref),
@@ -468,6 +475,21 @@ func schema_pkg_apis_core_v1alpha1_ClusterStatus(ref common.ReferenceCallback) c
 							Format:      "",
 						},
 					},
+					"arches": {
+						SchemaProps: spec.SchemaProps{
+							Description: "All the chip architectures of the cluster, sorted.\n\nOn Kubernetes, this will be the set of kubernetes.io/arch labels across all nodes.\n\nOn Docker, this will only contain the Architecture of the Docker daemon.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 					"error": {
 						SchemaProps: spec.SchemaProps{
 							Description: "An unrecoverable error connecting to the cluster.",
@@ -1006,11 +1028,36 @@ func schema_pkg_apis_core_v1alpha1_CmdSpec(ref common.ReferenceCallback) common.
 							Ref:         ref(v1alpha1.DisableSource{}.OpenAPIModelName()),
 						},
 					},
+					"stop": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Specifies how to stop the process.\n\nUsed whenever Tilt stops the process: on restart, when the Cmd is disabled or deleted, and when Tilt exits.\n\nWhen not specified, Tilt sends SIGTERM to the process group, then kills it abruptly if it hasn't exited after 30 seconds.",
+							Ref:         ref(v1alpha1.CmdStopSpec{}.OpenAPIModelName()),
+						},
+					},
+					"livenessProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Periodic probe of service liveness.\n\nWhen the probe fails, Tilt stops the process and starts it again. Consecutive restarts back off exponentially, up to one minute.",
+							Ref:         ref(v1alpha1.Probe{}.OpenAPIModelName()),
+						},
+					},
+					"startupProbe": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Probe that indicates the process has finished starting up.\n\nWhen specified, the readiness and liveness probes don't run until the startup probe succeeds. If the startup probe fails, the process is restarted, the same as for a failed liveness probe.",
+							Ref:         ref(v1alpha1.Probe{}.OpenAPIModelName()),
+						},
+					},
+					"logParser": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to parse the output of the process into structured logs.\n\nOne of \"json\" or \"logfmt\". When not specified, output is logged as plain text.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.DisableSource{}.OpenAPIModelName(), v1alpha1.Probe{}.OpenAPIModelName(), v1alpha1.RestartOnSpec{}.OpenAPIModelName(), v1alpha1.StartOnSpec{}.OpenAPIModelName()},
+			v1alpha1.CmdStopSpec{}.OpenAPIModelName(), v1alpha1.DisableSource{}.OpenAPIModelName(), v1alpha1.Probe{}.OpenAPIModelName(), v1alpha1.RestartOnSpec{}.OpenAPIModelName(), v1alpha1.StartOnSpec{}.OpenAPIModelName()},
 	}
 }
 
@@ -1153,6 +1200,20 @@ func schema_pkg_apis_core_v1alpha1_CmdStatus(ref common.ReferenceCallback) commo
 							Ref:         ref(v1alpha1.DisableStatus{}.OpenAPIModelName()),
 						},
 					},
+					"restartCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of times the process has been restarted because its liveness or startup probe failed.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastRestartReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "(brief) reason the process was last restarted by a failing probe.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -1161,6 +1222,48 @@ func schema_pkg_apis_core_v1alpha1_CmdStatus(ref common.ReferenceCallback) commo
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_CmdStopSpec(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CmdStopSpec describes how to gracefully shut down a process.\n\nBased loosely on the Lifecycle PreStop hook and TerminationGracePeriodSeconds in Kubernetes.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"signal": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The signal to send to the process group to ask it to shut down, e.g., \"SIGTERM\", \"SIGINT\", or \"SIGQUIT\".\n\nDefaults to SIGTERM. Ignored on Windows, where Tilt asks the process tree to exit with TASKKILL.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"gracePeriodSeconds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Number of seconds to wait for the process to exit before killing it abruptly. Includes the time spent on the pre-stop command.\n\nDefaults to 30 seconds. Minimum value is 1.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"preStopArgs": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Command-line arguments of a command to run before the stop signal is sent.\n\nThe command runs with the same working directory and environment as the process. Its exit status is logged but otherwise ignored.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_ConfigMap(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -2464,9 +2567,24 @@ func schema_pkg_apis_core_v1alpha1_DockerImageSpec(ref common.ReferenceCallback)
 							},
 						},
 					},
+					"cacheTo": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Where to export the build cache after a successful build, so that later builds can import it with CacheFrom.\n\nUses the syntax of `--cache-to` in the Docker buildx CLI. Supports the inline, registry, and local exporters, in mode=min.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
 					"platform": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Platform specifies architecture information for target image.\n\nhttps://docs.docker.com/desktop/multi-arch/\n\nEquivalent to `--platform` in the Docker CLI.",
+							Description: "Platform specifies architecture information for target image.\n\nhttps://docs.docker.com/desktop/multi-arch/\n\nEquivalent to `--platform` in the Docker CLI.\n\nMay be a comma-separated list of platforms, or \"cluster-all\" to build for every architecture in the cluster. When there's more than one platform, Tilt builds each platform separately, then pushes a multi-arch manifest list that refers to all of them.",
 							Type:        []string{"string"},
 							Format:      "",
 						},
@@ -3497,6 +3615,49 @@ func schema_pkg_apis_core_v1alpha1_ForwardStatus(ref common.ReferenceCallback) c
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_GRPCAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "GRPCAction describes an action based on the gRPC health checking protocol (https://github.com/grpc/grpc/blob/master/doc/health-checking.md).\n\nThe server must implement the grpc.health.v1.Health service.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"port": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Port number of the gRPC service. Number must be in the range 1 to 65535.",
+							Default:     0,
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"host": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Optional: Host name to connect to, defaults to localhost.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"service": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Service is the name of the service to place in the gRPC HealthCheckRequest.\n\nIf this is not specified, the server reports the health of the server as a whole.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"tls": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Whether to connect to the server with TLS.\n\nThe server certificate is not verified, so self-signed certificates for local development work without additional configuration.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"port"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_HTTPGetAction(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3611,11 +3772,17 @@ func schema_pkg_apis_core_v1alpha1_Handler(ref common.ReferenceCallback) common.
 							Ref:         ref(v1alpha1.TCPSocketAction{}.OpenAPIModelName()),
 						},
 					},
+					"grpc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GRPC specifies an action involving a gRPC health check.",
+							Ref:         ref(v1alpha1.GRPCAction{}.OpenAPIModelName()),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.ExecAction{}.OpenAPIModelName(), v1alpha1.HTTPGetAction{}.OpenAPIModelName(), v1alpha1.TCPSocketAction{}.OpenAPIModelName()},
+			v1alpha1.ExecAction{}.OpenAPIModelName(), v1alpha1.GRPCAction{}.OpenAPIModelName(), v1alpha1.HTTPGetAction{}.OpenAPIModelName(), v1alpha1.TCPSocketAction{}.OpenAPIModelName()},
 	}
 }
 
@@ -4000,6 +4167,78 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplyCmd(ref common.ReferenceCallba
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyConflict(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "A field owned by another field manager, which conflicted with a server-side apply.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"object": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The object with the conflicting field, in the form kind/namespace/name (or kind/name for cluster-scoped objects).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"manager": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The field manager that owns the field (e.g., \"kubectl-edit\").",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"field": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The path of the field, in the server-side apply field path format (e.g., .spec.replicas or .spec.containers[name=\"app\"].image).",
+							Default:     "",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"yielded": {
+						SchemaProps: spec.SchemaProps{
+							Description: "True if Tilt left the field to the other manager and applied everything else.",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
+				},
+				Required: []string{"object", "manager", "field"},
+			},
+		},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_KubernetesApplyDiffPreview(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Type: []string{"object"},
+				Properties: map[string]spec.Schema{
+					"confirmKinds": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kinds of objects (e.g., Secret or CustomResourceDefinition) whose changes must be confirmed before Tilt applies them.\n\nWhen the diff changes an object of one of these kinds, the apply waits until the diff is confirmed.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: "",
+										Type:    []string{"string"},
+										Format:  "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_KubernetesApplyList(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -4162,11 +4401,31 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplySpec(ref common.ReferenceCallb
 							Format:      "",
 						},
 					},
+					"ssaConflictPolicy": {
+						SchemaProps: spec.SchemaProps{
+							Description: "SSAConflictPolicy determines what happens when a server-side apply tries to change a field that another field manager owns (e.g., an HPA that manages replicas, or a kubectl edit).\n\nOnly used when ServerSideApply is true.\n\nIf not provided, defaults to \"force\".",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"diffPreview": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DiffPreview shows a server-side dry-run diff between the objects to apply and the live objects in the cluster before each apply.\n\nOnly supported for YAML applies.",
+							Ref:         ref(v1alpha1.KubernetesApplyDiffPreview{}.OpenAPIModelName()),
+						},
+					},
+					"applySet": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ApplySet is the ID that Tilt labels the applied objects with, so that it can prune them when they're removed from the apply.\n\nMust be unique across every Tilt session that shares the cluster. The Tiltfile derives it from the Tiltfile path and the resource name.\n\nOnly supported for YAML applies. If not provided, Tilt doesn't prune.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.DisableSource{}.OpenAPIModelName(), v1alpha1.KubernetesApplyCmd{}.OpenAPIModelName(), v1alpha1.KubernetesDiscoveryTemplateSpec{}.OpenAPIModelName(), v1alpha1.KubernetesImageLocator{}.OpenAPIModelName(), v1alpha1.PodLogStreamTemplateSpec{}.OpenAPIModelName(), v1alpha1.PortForwardTemplateSpec{}.OpenAPIModelName(), v1alpha1.RestartOnSpec{}.OpenAPIModelName(), v1.Duration{}.OpenAPIModelName()},
+			v1alpha1.DisableSource{}.OpenAPIModelName(), v1alpha1.KubernetesApplyCmd{}.OpenAPIModelName(), v1alpha1.KubernetesApplyDiffPreview{}.OpenAPIModelName(), v1alpha1.KubernetesDiscoveryTemplateSpec{}.OpenAPIModelName(), v1alpha1.KubernetesImageLocator{}.OpenAPIModelName(), v1alpha1.PodLogStreamTemplateSpec{}.OpenAPIModelName(), v1alpha1.PortForwardTemplateSpec{}.OpenAPIModelName(), v1alpha1.RestartOnSpec{}.OpenAPIModelName(), v1.Duration{}.OpenAPIModelName()},
 	}
 }
 
@@ -4230,11 +4489,39 @@ func schema_pkg_apis_core_v1alpha1_KubernetesApplyStatus(ref common.ReferenceCal
 							},
 						},
 					},
+					"conflicts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fields that other field managers own, which conflicted with the last server-side apply.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.KubernetesApplyConflict{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"diff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The diff between the objects to apply and the live objects in the cluster, from the last diff preview.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"diffAwaitingConfirmation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A hash of the diff that's waiting for the user to confirm it, because it changes objects of one of the DiffPreview.ConfirmKinds.\n\nTo confirm, set the tilt.dev/confirmed-diff annotation to this hash (e.g., with `tilt diff --confirm`).",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.DisableStatus{}.OpenAPIModelName(), v1.Condition{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
+			v1alpha1.DisableStatus{}.OpenAPIModelName(), v1alpha1.KubernetesApplyConflict{}.OpenAPIModelName(), v1.Condition{}.OpenAPIModelName(), v1.MicroTime{}.OpenAPIModelName()},
 	}
 }
 
@@ -4835,6 +5122,17 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateContainerStatus(ref common.Referenc
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_LiveUpdateDeltaSync(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LiveUpdateDeltaSync enables block-level transfer of large files.\n\nBefore copying a large file, Tilt asks the container for checksums of each block of its current copy with `dd` and `sha256sum`, then sends only the blocks that differ. If the container doesn't have these tools, Tilt copies whole files.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_LiveUpdateDockerComposeSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5164,12 +5462,24 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSpec(ref common.ReferenceCallback)
 							Ref:         ref(v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName()),
 						},
 					},
+					"verify": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Verify configures a check that the container's copy of the synced files matches the local copy after each live update.\n\nIf any file has drifted, the live update fails with reason FileDrift, and Tilt falls back to a full rebuild.",
+							Ref:         ref(v1alpha1.LiveUpdateVerify{}.OpenAPIModelName()),
+						},
+					},
+					"deltaSync": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeltaSync configures block-level transfer of large files.\n\nWhen set, large files that already exist in the container are copied by sending only the blocks that changed. Only supported when Tilt copies files with `kubectl exec`.",
+							Ref:         ref(v1alpha1.LiveUpdateDeltaSync{}.OpenAPIModelName()),
+						},
+					},
 				},
 				Required: []string{"basePath", "selector"},
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.LiveUpdateExec{}.OpenAPIModelName(), v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateSelector{}.OpenAPIModelName(), v1alpha1.LiveUpdateSource{}.OpenAPIModelName(), v1alpha1.LiveUpdateSync{}.OpenAPIModelName()},
+			v1alpha1.LiveUpdateDeltaSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateExec{}.OpenAPIModelName(), v1alpha1.LiveUpdateInitialSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateSelector{}.OpenAPIModelName(), v1alpha1.LiveUpdateSource{}.OpenAPIModelName(), v1alpha1.LiveUpdateSync{}.OpenAPIModelName(), v1alpha1.LiveUpdateVerify{}.OpenAPIModelName()},
 	}
 }
 
@@ -5273,6 +5583,17 @@ func schema_pkg_apis_core_v1alpha1_LiveUpdateSync(ref common.ReferenceCallback)
 	}
 }
 
+func schema_pkg_apis_core_v1alpha1_LiveUpdateVerify(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LiveUpdateVerify enables verification of synced files.\n\nAfter the files are synced and the Execs have run, Tilt runs `sha256sum` in the container on every file in the update and compares the checksums against the local files. Files deleted locally must also be gone from the container.\n\nExecs that rewrite synced files will be reported as drift.",
+				Type:        []string{"object"},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_core_v1alpha1_ObjectSelector(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -5674,6 +5995,13 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamSpec(ref common.ReferenceCallback
 							Format:      "",
 						},
 					},
+					"logParser": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to parse the container logs into structured logs.\n\nOne of \"json\" or \"logfmt\". When not specified, logs are stored as plain text.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -5761,6 +6089,13 @@ func schema_pkg_apis_core_v1alpha1_PodLogStreamTemplateSpec(ref common.Reference
 							},
 						},
 					},
+					"logParser": {
+						SchemaProps: spec.SchemaProps{
+							Description: "How to parse the container logs into structured logs.\n\nOne of \"json\" or \"logfmt\". When not specified, logs are stored as plain text.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -6047,6 +6382,12 @@ func schema_pkg_apis_core_v1alpha1_Probe(ref common.ReferenceCallback) common.Op
 							Ref:         ref(v1alpha1.TCPSocketAction{}.OpenAPIModelName()),
 						},
 					},
+					"grpc": {
+						SchemaProps: spec.SchemaProps{
+							Description: "GRPC specifies an action involving a gRPC health check.",
+							Ref:         ref(v1alpha1.GRPCAction{}.OpenAPIModelName()),
+						},
+					},
 					"initialDelaySeconds": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Number of seconds after the container has started before liveness probes are initiated. More info: https://kubernetes.io/docs/concepts/workloads/pods/pod-lifecycle#container-probes",
@@ -6086,7 +6427,7 @@ func schema_pkg_apis_core_v1alpha1_Probe(ref common.ReferenceCallback) common.Op
 			},
 		},
 		Dependencies: []string{
-			v1alpha1.ExecAction{}.OpenAPIModelName(), v1alpha1.HTTPGetAction{}.OpenAPIModelName(), v1alpha1.TCPSocketAction{}.OpenAPIModelName()},
+			v1alpha1.ExecAction{}.OpenAPIModelName(), v1alpha1.GRPCAction{}.OpenAPIModelName(), v1alpha1.HTTPGetAction{}.OpenAPIModelName(), v1alpha1.TCPSocketAction{}.OpenAPIModelName()},
 	}
 }
 
@@ -8100,11 +8441,87 @@ func schema_pkg_apis_core_v1alpha1_UIResourceKubernetes(ref common.ReferenceCall
 							},
 						},
 					},
+					"rollback": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Set when Tilt rolled back the most recent deploy because its pods failed.",
+							Ref:         ref(v1alpha1.UIResourceKubernetesRollback{}.OpenAPIModelName()),
+						},
+					},
+					"conflicts": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Fields that other field managers own, which conflicted with the last server-side apply.",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Default: map[string]interface{}{},
+										Ref:     ref(v1alpha1.KubernetesApplyConflict{}.OpenAPIModelName()),
+									},
+								},
+							},
+						},
+					},
+					"diff": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The diff between the objects to apply and the live objects in the cluster, from the last diff preview.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"diffAwaitingConfirmation": {
+						SchemaProps: spec.SchemaProps{
+							Description: "A hash of the diff that's waiting for the user to confirm it. Empty if the apply isn't waiting.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			v1.Time{}.OpenAPIModelName()},
+			v1alpha1.KubernetesApplyConflict{}.OpenAPIModelName(), v1alpha1.UIResourceKubernetesRollback{}.OpenAPIModelName(), v1.Time{}.OpenAPIModelName()},
+	}
+}
+
+func schema_pkg_apis_core_v1alpha1_UIResourceKubernetesRollback(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "UIResourceKubernetesRollback describes an automatic rollback to the last healthy deploy of a resource.",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"time": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Time when Tilt rolled back the deploy.",
+							Ref:         ref(v1.MicroTime{}.OpenAPIModelName()),
+						},
+					},
+					"reason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "(brief) reason the deploy was rolled back.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"podName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The name of the failed pod.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"spanID": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The span where the failed pod stored its logs in the Tilt logstore.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{
+			v1.MicroTime{}.OpenAPIModelName()},
 	}
 }
 
@@ -8205,6 +8622,20 @@ func schema_pkg_apis_core_v1alpha1_UIResourceLocal(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"restartCount": {
+						SchemaProps: spec.SchemaProps{
+							Description: "The number of times the local command has been restarted because its liveness or startup probe failed.",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"lastRestartReason": {
+						SchemaProps: spec.SchemaProps{
+							Description: "(brief) reason the local command was last restarted by a failing probe.",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
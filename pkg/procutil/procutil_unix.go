@@ -23,9 +23,14 @@ func KillProcessGroup(cmd *exec.Cmd) {
 }
 
 func GracefullyShutdownProcess(p *os.Process) error {
+	return GracefullyShutdownProcessWithSignal(p, syscall.SIGTERM)
+}
+
+// Sends the given signal to the process group.
+func GracefullyShutdownProcessWithSignal(p *os.Process, sig syscall.Signal) error {
 	if p == nil {
 		return nil
 	}
 
-	return syscall.Kill(-p.Pid, syscall.SIGTERM)
+	return syscall.Kill(-p.Pid, sig)
 }
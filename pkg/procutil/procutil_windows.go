@@ -22,3 +22,8 @@ func KillProcessGroup(cmd *exec.Cmd) {
 func GracefullyShutdownProcess(p *os.Process) error {
 	return exec.Command("TASKKILL", "/T", "/PID", fmt.Sprintf("%d", p.Pid)).Run()
 }
+
+// Windows doesn't have process group signals, so the signal is ignored.
+func GracefullyShutdownProcessWithSignal(p *os.Process, sig syscall.Signal) error {
+	return GracefullyShutdownProcess(p)
+}
@@ -0,0 +1,29 @@
+package procutil
+
+import (
+	"fmt"
+	"strings"
+	"syscall"
+)
+
+var signalsByName = map[string]syscall.Signal{
+	"SIGHUP":  syscall.SIGHUP,
+	"SIGINT":  syscall.SIGINT,
+	"SIGQUIT": syscall.SIGQUIT,
+	"SIGKILL": syscall.SIGKILL,
+	"SIGTERM": syscall.SIGTERM,
+}
+
+// ParseSignal parses a signal name, like "SIGINT" or "INT".
+func ParseSignal(name string) (syscall.Signal, error) {
+	key := strings.ToUpper(strings.TrimSpace(name))
+	if !strings.HasPrefix(key, "SIG") {
+		key = "SIG" + key
+	}
+
+	sig, ok := signalsByName[key]
+	if !ok {
+		return 0, fmt.Errorf("unsupported signal %q (must be one of SIGHUP, SIGINT, SIGQUIT, SIGKILL, SIGTERM)", name)
+	}
+	return sig, nil
+}
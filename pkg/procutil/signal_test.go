@@ -0,0 +1,20 @@
+package procutil
+
+import (
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignal(t *testing.T) {
+	for _, name := range []string{"SIGINT", "INT", "sigint", " int "} {
+		sig, err := ParseSignal(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, syscall.SIGINT, sig, name)
+	}
+
+	_, err := ParseSignal("SIGWINCH")
+	assert.EqualError(t, err, `unsupported signal "SIGWINCH" (must be one of SIGHUP, SIGINT, SIGQUIT, SIGKILL, SIGTERM)`)
+}
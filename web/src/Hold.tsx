@@ -6,6 +6,7 @@ export class Hold {
   resources: string[] = []
   images: string[] = []
   clusters: string[] = []
+  concurrencyGroups: string[] = []
 
   constructor(waiting: UIResourceStateWaiting) {
     this.reason = waiting.reason ?? ""
@@ -20,6 +21,9 @@ export class Hold {
       if (ref.kind === "Cluster" && ref.name) {
         this.clusters.push(ref.name)
       }
+      if (ref.kind === "ConcurrencyGroup" && ref.name) {
+        this.concurrencyGroups.push(ref.name)
+      }
     }
   }
 }
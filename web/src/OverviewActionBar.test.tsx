@@ -6,6 +6,7 @@ import {
   within,
 } from "@testing-library/react"
 import userEvent from "@testing-library/user-event"
+import fetchMock from "fetch-mock"
 import { MemoryRouter } from "react-router"
 import { useLocation } from "react-router-dom"
 import { SnackbarProvider } from "notistack"
@@ -81,6 +82,108 @@ describe("OverviewActionBar", () => {
     expect(screen.queryByLabelText(/links and custom buttons/i)).toBeNull()
   })
 
+  it("renders a rolled back notice that links to the runtime logs", () => {
+    const resource = oneResource({ name: "vigoda" })
+    resource.status!.k8sResourceInfo!.rollback = {
+      reason: "pod vigoda-pod crashed (restarts: 2)",
+      podName: "vigoda-pod",
+    }
+    customRender(
+      <OverviewActionBar resource={resource} filterSet={DEFAULT_FILTER_SET} />,
+      { initialEntries: ["/r/vigoda/overview"] }
+    )
+
+    expect(
+      screen.getByText(/Rolled back: pod vigoda-pod crashed/i)
+    ).toBeInTheDocument()
+
+    userEvent.click(screen.getByRole("link", { name: /view failure logs/i }))
+
+    expect(getSearch()).toEqual("?source=runtime")
+  })
+
+  it("renders server-side apply conflicts", async () => {
+    const resource = oneResource({ name: "vigoda" })
+    resource.status!.k8sResourceInfo!.conflicts = [
+      {
+        object: "Deployment/default/vigoda",
+        manager: "kube-controller-manager",
+        field: ".spec.replicas",
+        yielded: true,
+      },
+      {
+        object: "Deployment/default/vigoda",
+        manager: "kubectl-edit",
+        field: '.spec.template.spec.containers[name="app"].image',
+      },
+    ]
+    customRender(
+      <OverviewActionBar resource={resource} filterSet={DEFAULT_FILTER_SET} />
+    )
+
+    expect(
+      screen.getByText("2 field conflicts (1 yielded)")
+    ).toBeInTheDocument()
+
+    userEvent.hover(screen.getByLabelText("Server-side apply conflicts"))
+
+    expect(
+      await screen.findByText(/replicas: owned by kube-controller-manager/)
+    ).toBeInTheDocument()
+  })
+
+  describe("diff preview", () => {
+    beforeEach(() => {
+      fetchMock.mock("/api/confirm_diff", JSON.stringify({}))
+    })
+
+    afterEach(() => {
+      fetchMock.reset()
+    })
+
+    it("renders the diff without a confirm button", async () => {
+      const resource = oneResource({ name: "vigoda" })
+      resource.status!.k8sResourceInfo!.diff = "~ Secret/default/vigoda\n"
+      customRender(
+        <OverviewActionBar
+          resource={resource}
+          filterSet={DEFAULT_FILTER_SET}
+        />
+      )
+
+      expect(screen.getByText("Diff preview")).toBeInTheDocument()
+      expect(screen.queryByLabelText("Confirm diff")).toBeNull()
+
+      userEvent.hover(screen.getByText("Diff preview"))
+
+      expect(
+        await screen.findByText(/Secret\/default\/vigoda/)
+      ).toBeInTheDocument()
+    })
+
+    it("confirms a diff awaiting confirmation", () => {
+      const resource = oneResource({ name: "vigoda" })
+      resource.status!.k8sResourceInfo!.diff = "~ Secret/default/vigoda\n"
+      resource.status!.k8sResourceInfo!.diffAwaitingConfirmation = "abc123"
+      customRender(
+        <OverviewActionBar
+          resource={resource}
+          filterSet={DEFAULT_FILTER_SET}
+        />
+      )
+
+      expect(screen.getByText("Diff awaiting confirmation")).toBeInTheDocument()
+
+      userEvent.click(screen.getByLabelText("Confirm diff"))
+
+      expect(fetchMock.calls().length).toEqual(1)
+      expect(fetchMock.calls()[0][0]).toEqual("/api/confirm_diff")
+      expect(fetchMock.calls()[0][1]?.body).toEqual(
+        JSON.stringify({ manifest_name: "vigoda", hash: "abc123" })
+      )
+    })
+  })
+
   describe("log filters", () => {
     beforeEach(() => customRender(<FullBar />))
 
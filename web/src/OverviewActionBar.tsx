@@ -6,7 +6,7 @@ import { makeStyles } from "@material-ui/core/styles"
 import ExpandMoreIcon from "@material-ui/icons/ExpandMore"
 import { History } from "history"
 import React, { ChangeEvent, useEffect, useState } from "react"
-import { useNavigate, useLocation } from "react-router-dom"
+import { Link, useNavigate, useLocation } from "react-router-dom"
 import styled from "styled-components"
 import { Alert } from "./alerts"
 import { ApiButton, ButtonSet } from "./ApiButton"
@@ -16,6 +16,10 @@ import { ReactComponent as CloseSvg } from "./assets/svg/close.svg"
 import { ReactComponent as CopySvg } from "./assets/svg/copy.svg"
 import { ReactComponent as FilterSvg } from "./assets/svg/filter.svg"
 import { ReactComponent as LinkSvg } from "./assets/svg/link.svg"
+import type {
+  KubernetesApplyConflict,
+  UIResourceKubernetesRollback,
+} from "./core"
 import {
   InstrumentedButton,
   InstrumentedTextField,
@@ -47,7 +51,8 @@ import {
   mixinResetButtonStyle,
   SizeUnit,
 } from "./style-helpers"
-import { TiltInfoTooltip } from "./Tooltip"
+import TiltTooltip, { TiltInfoTooltip } from "./Tooltip"
+import { confirmDiff } from "./trigger"
 import { ResourceName, UIButton, UIResource } from "./types"
 
 type OverviewActionBarProps = {
@@ -676,6 +681,181 @@ let EndpointIcon = styled(LinkSvg)`
   margin-right: ${SizeUnit(0.25)};
 `
 
+let RollbackNoticeRoot = styled.div`
+  display: flex;
+  align-items: center;
+  color: ${Color.red};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+
+  & + * {
+    margin-left: ${SizeUnit(0.5)};
+  }
+`
+
+let RollbackLogsLink = styled(Link)`
+  color: ${Color.gray70};
+  margin-left: ${SizeUnit(0.25)};
+  transition: color ${AnimDuration.default} ease;
+
+  &:hover {
+    color: ${Color.blue};
+  }
+`
+
+// Marks a resource whose last deploy Tilt rolled back,
+// with a link to the runtime logs that show why.
+export function RollbackNotice(props: {
+  rollback: UIResourceKubernetesRollback
+}) {
+  const l = useLocation()
+  const search = createLogSearch(l.search, {
+    source: FilterSource.runtime,
+    level: FilterLevel.all,
+  })
+  const reason = props.rollback.reason || ""
+  return (
+    <RollbackNoticeRoot aria-label="Rolled back">
+      <AlertIcon width="16" height="16" />
+      <TruncateText title={reason}>
+        Rolled back{reason ? `: ${reason}` : ""}
+      </TruncateText>
+      <RollbackLogsLink
+        to={{ pathname: l.pathname, search: search.toString() }}
+      >
+        View failure logs
+      </RollbackLogsLink>
+    </RollbackNoticeRoot>
+  )
+}
+
+let ConflictsNoticeRoot = styled.div`
+  display: flex;
+  align-items: center;
+  color: ${Color.yellow};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+
+  & + * {
+    margin-left: ${SizeUnit(0.5)};
+  }
+`
+
+let ConflictList = styled.ul`
+  margin: 0;
+  padding-left: ${SizeUnit(0.5)};
+  font-family: ${Font.monospace};
+`
+
+// Marks a resource whose last server-side apply conflicted with fields
+// that other field managers own. Hover to see the fields.
+export function ConflictsNotice(props: {
+  conflicts: KubernetesApplyConflict[]
+}) {
+  const conflicts = props.conflicts
+  const yielded = conflicts.filter((c) => c.yielded).length
+  const noun = conflicts.length === 1 ? "field conflict" : "field conflicts"
+  const summary = yielded
+    ? `${conflicts.length} ${noun} (${yielded} yielded)`
+    : `${conflicts.length} ${noun}`
+  const details = (
+    <ConflictList>
+      {conflicts.map((c) => (
+        <li key={`${c.object} ${c.field} ${c.manager}`}>
+          {c.object} {c.field}: owned by {c.manager}
+          {c.yielded ? " (yielded)" : ""}
+        </li>
+      ))}
+    </ConflictList>
+  )
+  return (
+    <TiltTooltip title={details}>
+      <ConflictsNoticeRoot aria-label="Server-side apply conflicts">
+        <AlertIcon width="16" height="16" />
+        <TruncateText>{summary}</TruncateText>
+      </ConflictsNoticeRoot>
+    </TiltTooltip>
+  )
+}
+
+let DiffNoticeRoot = styled.div`
+  display: flex;
+  align-items: center;
+  color: ${Color.gray70};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+
+  &.isAwaitingConfirmation {
+    color: ${Color.yellow};
+  }
+
+  & + * {
+    margin-left: ${SizeUnit(0.5)};
+  }
+`
+
+let DiffText = styled.pre`
+  margin: 0;
+  max-height: 50vh;
+  overflow: auto;
+  font-family: ${Font.monospace};
+  white-space: pre;
+`
+
+let ConfirmDiffButton = styled(InstrumentedButton)`
+  ${mixinResetButtonStyle}
+  color: ${Color.gray70};
+  font-family: ${Font.monospace};
+  font-size: ${FontSize.small};
+  margin-left: ${SizeUnit(0.25)};
+  transition: color ${AnimDuration.default} ease;
+
+  &:hover {
+    color: ${Color.blue};
+  }
+`
+
+// Shows the diff from the last diff preview of a resource. Hover to see
+// the diff. If the apply is waiting for the diff to be confirmed,
+// the user can confirm it here.
+export function DiffNotice(props: {
+  resourceName: string
+  diff: string
+  diffAwaitingConfirmation?: string
+}) {
+  const hash = props.diffAwaitingConfirmation
+  const [confirming, setConfirming] = useState(false)
+  const onConfirm = () => {
+    if (!hash) {
+      return
+    }
+    setConfirming(true)
+    confirmDiff(props.resourceName, hash).finally(() => setConfirming(false))
+  }
+
+  return (
+    <DiffNoticeRoot
+      className={hash ? "isAwaitingConfirmation" : ""}
+      aria-label="Diff preview"
+    >
+      <TiltTooltip title={<DiffText>{props.diff}</DiffText>}>
+        <TruncateText>
+          {hash ? "Diff awaiting confirmation" : "Diff preview"}
+        </TruncateText>
+      </TiltTooltip>
+      {hash ? (
+        <ConfirmDiffButton
+          aria-label="Confirm diff"
+          disabled={confirming}
+          onClick={onConfirm}
+        >
+          Confirm and apply
+        </ConfirmDiffButton>
+      ) : null}
+    </DiffNoticeRoot>
+  )
+}
+
 // TODO(nick): Put this in a global React Context object with
 // other page-level stuffs
 function openEndpointUrl(url: string) {
@@ -740,6 +920,25 @@ export default function OverviewActionBar(props: OverviewActionBarProps) {
   }
 
   let topRowEls = new Array<JSX.Element>()
+  const rollback = resource?.status?.k8sResourceInfo?.rollback
+  if (rollback && !isDisabled) {
+    topRowEls.push(<RollbackNotice rollback={rollback} key="rollback" />)
+  }
+  const conflicts = resource?.status?.k8sResourceInfo?.conflicts || []
+  if (conflicts.length && !isDisabled) {
+    topRowEls.push(<ConflictsNotice conflicts={conflicts} key="conflicts" />)
+  }
+  const k8sInfo = resource?.status?.k8sResourceInfo
+  if (k8sInfo?.diff && !isDisabled) {
+    topRowEls.push(
+      <DiffNotice
+        resourceName={resource?.metadata?.name || ""}
+        diff={k8sInfo.diff}
+        diffAwaitingConfirmation={k8sInfo.diffAwaitingConfirmation}
+        key="diff"
+      />
+    )
+  }
   if (endpointEls.length) {
     topRowEls.push(
       <EndpointSet key="endpointSet">
@@ -242,6 +242,10 @@ function holdStatusText(hold?: Hold | null): string {
     return "Waiting for shared image build"
   }
 
+  if (hold.concurrencyGroups.length) {
+    return `Waiting on concurrency group ${hold.concurrencyGroups.join(", ")}`
+  }
+
   if (hold.resources.length === 1) {
     // show the actual name
     return `Waiting on ${hold.resources[0]}`
@@ -104,6 +104,14 @@ export interface ClusterStatus {
    * of the architectures.
    */
   arch?: string
+  /**
+   * All the chip architectures of the cluster, sorted.
+   * On Kubernetes, this will be the set of kubernetes.io/arch labels
+   * across all nodes.
+   * On Docker, this will only contain the Architecture of the Docker daemon.
+   * +optional
+   */
+  arches?: string[]
   /**
    * An unrecoverable error connecting to the cluster.
    * +optional
@@ -264,6 +272,65 @@ export interface CmdSpec {
    * +optional
    */
   disableSource?: DisableSource
+  /**
+   * Specifies how to stop the process.
+   * Used whenever Tilt stops the process: on restart, when the Cmd is
+   * disabled or deleted, and when Tilt exits.
+   * When not specified, Tilt sends SIGTERM to the process group, then
+   * kills it abruptly if it hasn't exited after 30 seconds.
+   * +optional
+   */
+  stop?: CmdStopSpec
+  /**
+   * Periodic probe of service liveness.
+   * When the probe fails, Tilt stops the process and starts it again.
+   * Consecutive restarts back off exponentially, up to one minute.
+   * +optional
+   */
+  livenessProbe?: Probe
+  /**
+   * Probe that indicates the process has finished starting up.
+   * When specified, the readiness and liveness probes don't run until the
+   * startup probe succeeds. If the startup probe fails, the process is
+   * restarted, the same as for a failed liveness probe.
+   * +optional
+   */
+  startupProbe?: Probe
+  /**
+   * How to parse the output of the process into structured logs.
+   * One of "json" or "logfmt". When not specified, output is logged as plain text.
+   * +optional
+   */
+  logParser?: string
+}
+/**
+ * CmdStopSpec describes how to gracefully shut down a process.
+ * Based loosely on the Lifecycle PreStop hook and
+ * TerminationGracePeriodSeconds in Kubernetes.
+ */
+export interface CmdStopSpec {
+  /**
+   * The signal to send to the process group to ask it to shut down,
+   * e.g., "SIGTERM", "SIGINT", or "SIGQUIT".
+   * Defaults to SIGTERM. Ignored on Windows, where Tilt asks the process
+   * tree to exit with TASKKILL.
+   * +optional
+   */
+  signal?: string
+  /**
+   * Number of seconds to wait for the process to exit before
+   * killing it abruptly. Includes the time spent on the pre-stop command.
+   * Defaults to 30 seconds. Minimum value is 1.
+   * +optional
+   */
+  gracePeriodSeconds?: number /* int32 */
+  /**
+   * Command-line arguments of a command to run before the stop signal is sent.
+   * The command runs with the same working directory and environment
+   * as the process. Its exit status is logged but otherwise ignored.
+   * +optional
+   */
+  preStopArgs?: string[]
 }
 /**
  * CmdStatus defines the observed state of Cmd
@@ -297,6 +364,17 @@ export interface CmdStatus {
    * +optional
    */
   disableStatus?: DisableStatus
+  /**
+   * The number of times the process has been restarted
+   * because its liveness or startup probe failed.
+   * +optional
+   */
+  restartCount?: number /* int32 */
+  /**
+   * (brief) reason the process was last restarted by a failing probe.
+   * +optional
+   */
+  lastRestartReason?: string
 }
 /**
  * CmdStateWaiting is a waiting state of a local command.
@@ -958,10 +1036,22 @@ export interface DockerImageSpec {
    * Equivalent to `--cache-from` in the Docker CLI.
    */
   cacheFrom?: string[]
+  /**
+   * Where to export the build cache after a successful build, so that later
+   * builds can import it with CacheFrom.
+   * Uses the syntax of `--cache-to` in the Docker buildx CLI. Supports the
+   * inline, registry, and local exporters, in mode=min.
+   * +optional
+   */
+  cacheTo?: string[]
   /**
    * Platform specifies architecture information for target image.
    * https://docs.docker.com/desktop/multi-arch/
    * Equivalent to `--platform` in the Docker CLI.
+   * May be a comma-separated list of platforms, or "cluster-all" to build for
+   * every architecture in the cluster. When there's more than one platform,
+   * Tilt builds each platform separately, then pushes a multi-arch manifest
+   * list that refers to all of them.
    */
   platform?: string
   /**
@@ -1639,6 +1729,31 @@ export interface KubernetesApplySpec {
    * +optional
    */
   serverSideApply?: boolean
+  /**
+   * SSAConflictPolicy determines what happens when a server-side apply
+   * tries to change a field that another field manager owns
+   * (e.g., an HPA that manages replicas, or a kubectl edit).
+   * Only used when ServerSideApply is true.
+   * If not provided, defaults to "force".
+   * +optional
+   */
+  ssaConflictPolicy?: SSAConflictPolicy
+  /**
+   * DiffPreview shows a server-side dry-run diff between the objects
+   * to apply and the live objects in the cluster before each apply.
+   * Only supported for YAML applies.
+   * +optional
+   */
+  diffPreview?: KubernetesApplyDiffPreview
+  /**
+   * ApplySet is the ID that Tilt labels the applied objects with,
+   * so that it can prune them when they're removed from the apply.
+   * Must be unique across every Tilt session that shares the cluster.
+   * The Tiltfile derives it from the Tiltfile path and the resource name.
+   * Only supported for YAML applies. If not provided, Tilt doesn't prune.
+   * +optional
+   */
+  applySet?: string
 }
 /**
  * KubernetesApplyStatus defines the observed state of KubernetesApply
@@ -1689,6 +1804,26 @@ export interface KubernetesApplyStatus {
    * +optional
    */
   conditions?: any /* metav1.Condition */[]
+  /**
+   * Fields that other field managers own, which conflicted with
+   * the last server-side apply.
+   * +optional
+   */
+  conflicts?: KubernetesApplyConflict[]
+  /**
+   * The diff between the objects to apply and the live objects
+   * in the cluster, from the last diff preview.
+   * +optional
+   */
+  diff?: string
+  /**
+   * A hash of the diff that's waiting for the user to confirm it,
+   * because it changes objects of one of the DiffPreview.ConfirmKinds.
+   * To confirm, set the tilt.dev/confirmed-diff annotation to this hash
+   * (e.g., with `tilt diff --confirm`).
+   * +optional
+   */
+  diffAwaitingConfirmation?: string
 }
 /**
  * ApplyConditionJobComplete means the apply was for a batch/v1.Job that has already
@@ -1742,6 +1877,41 @@ export interface KubernetesDiscoveryTemplateSpec {
   extraSelectors?: any /* metav1.LabelSelector */[]
 }
 export type KubernetesDiscoveryStrategy = string
+export interface KubernetesApplyDiffPreview {
+  /**
+   * Kinds of objects (e.g., Secret or CustomResourceDefinition) whose
+   * changes must be confirmed before Tilt applies them.
+   * When the diff changes an object of one of these kinds, the apply
+   * waits until the diff is confirmed.
+   * +optional
+   */
+  confirmKinds?: string[]
+}
+export type SSAConflictPolicy = string
+/**
+ * A field owned by another field manager, which conflicted with a server-side apply.
+ */
+export interface KubernetesApplyConflict {
+  /**
+   * The object with the conflicting field, in the form kind/namespace/name
+   * (or kind/name for cluster-scoped objects).
+   */
+  object: string
+  /**
+   * The field manager that owns the field (e.g., "kubectl-edit").
+   */
+  manager: string
+  /**
+   * The path of the field, in the server-side apply field path format
+   * (e.g., .spec.replicas or .spec.containers[name="app"].image).
+   */
+  field: string
+  /**
+   * True if Tilt left the field to the other manager and applied everything else.
+   * +optional
+   */
+  yielded?: boolean
+}
 export interface KubernetesApplyCmd {
   /**
    * Args are the command-line arguments for the apply command. Must have length >= 1.
@@ -1886,6 +2056,12 @@ export interface PodLogStreamTemplateSpec {
    * +optional
    */
   ignoreContainers?: string[]
+  /**
+   * How to parse the container logs into structured logs.
+   * One of "json" or "logfmt". When not specified, logs are stored as plain text.
+   * +optional
+   */
+  logParser?: string
 }
 /**
  * KubernetesDiscoveryStatus defines the observed state of KubernetesDiscovery
@@ -2227,6 +2403,22 @@ export interface LiveUpdateSpec {
    * +optional
    */
   initialSync?: LiveUpdateInitialSync
+  /**
+   * Verify configures a check that the container's copy of the synced
+   * files matches the local copy after each live update.
+   * If any file has drifted, the live update fails with reason FileDrift,
+   * and Tilt falls back to a full rebuild.
+   * +optional
+   */
+  verify?: LiveUpdateVerify
+  /**
+   * DeltaSync configures block-level transfer of large files.
+   * When set, large files that already exist in the container are copied
+   * by sending only the blocks that changed. Only supported when Tilt
+   * copies files with `kubectl exec`.
+   * +optional
+   */
+  deltaSync?: LiveUpdateDeltaSync
 }
 /**
  * LiveUpdateStatus defines the observed state of LiveUpdate
@@ -2376,6 +2568,23 @@ export type LiveUpdateRestartStrategy = string
  * LiveUpdateInitialSync enables full file sync on container start/restart.
  */
 export interface LiveUpdateInitialSync {}
+/**
+ * LiveUpdateVerify enables verification of synced files.
+ * After the files are synced and the Execs have run, Tilt runs `sha256sum`
+ * in the container on every file in the update and compares the checksums
+ * against the local files. Files deleted locally must also be gone from
+ * the container.
+ * Execs that rewrite synced files will be reported as drift.
+ */
+export interface LiveUpdateVerify {}
+/**
+ * LiveUpdateDeltaSync enables block-level transfer of large files.
+ * Before copying a large file, Tilt asks the container for checksums of each
+ * block of its current copy with `dd` and `sha256sum`, then sends only the
+ * blocks that differ. If the container doesn't have these tools, Tilt copies
+ * whole files.
+ */
+export interface LiveUpdateDeltaSync {}
 /**
  * LiveUpdateContainerStatus defines the observed state of
  * the live-update syncer for a particular container.
@@ -2551,6 +2760,12 @@ export interface PodLogStreamSpec {
    * +optional
    */
   cluster: string
+  /**
+   * How to parse the container logs into structured logs.
+   * One of "json" or "logfmt". When not specified, logs are stored as plain text.
+   * +optional
+   */
+  logParser?: string
 }
 /**
  * PodLogStreamStatus defines the observed state of PodLogStream
@@ -2777,6 +2992,36 @@ export interface TCPSocketAction {
    */
   host?: string
 }
+/**
+ * GRPCAction describes an action based on the gRPC health checking protocol
+ * (https://github.com/grpc/grpc/blob/master/doc/health-checking.md).
+ * The server must implement the grpc.health.v1.Health service.
+ */
+export interface GRPCAction {
+  /**
+   * Port number of the gRPC service.
+   * Number must be in the range 1 to 65535.
+   */
+  port: number /* int32 */
+  /**
+   * Optional: Host name to connect to, defaults to localhost.
+   * +optional
+   */
+  host?: string
+  /**
+   * Service is the name of the service to place in the gRPC HealthCheckRequest.
+   * If this is not specified, the server reports the health of the server as a whole.
+   * +optional
+   */
+  service?: string
+  /**
+   * Whether to connect to the server with TLS.
+   * The server certificate is not verified, so self-signed certificates
+   * for local development work without additional configuration.
+   * +optional
+   */
+  tls?: boolean
+}
 /**
  * ExecAction describes a "run in container" action.
  */
@@ -2854,6 +3099,11 @@ export interface Handler {
    * +optional
    */
   tcpSocket?: TCPSocketAction
+  /**
+   * GRPC specifies an action involving a gRPC health check.
+   * +optional
+   */
+  grpc?: GRPCAction
 }
 
 //////////
@@ -4081,6 +4331,56 @@ export interface UIResourceKubernetes {
    * +optional
    */
   displayNames?: string[]
+  /**
+   * Set when Tilt rolled back the most recent deploy
+   * because its pods failed.
+   * +optional
+   */
+  rollback?: UIResourceKubernetesRollback
+  /**
+   * Fields that other field managers own, which conflicted with
+   * the last server-side apply.
+   * +optional
+   */
+  conflicts?: KubernetesApplyConflict[]
+  /**
+   * The diff between the objects to apply and the live objects
+   * in the cluster, from the last diff preview.
+   * +optional
+   */
+  diff?: string
+  /**
+   * A hash of the diff that's waiting for the user to confirm it.
+   * Empty if the apply isn't waiting.
+   * +optional
+   */
+  diffAwaitingConfirmation?: string
+}
+/**
+ * UIResourceKubernetesRollback describes an automatic rollback
+ * to the last healthy deploy of a resource.
+ */
+export interface UIResourceKubernetesRollback {
+  /**
+   * Time when Tilt rolled back the deploy.
+   * +optional
+   */
+  time?: string
+  /**
+   * (brief) reason the deploy was rolled back.
+   * +optional
+   */
+  reason?: string
+  /**
+   * The name of the failed pod.
+   * +optional
+   */
+  podName?: string
+  /**
+   * The span where the failed pod stored its logs in the Tilt logstore.
+   * +optional
+   */
+  spanID?: string
 }
 /**
  * UIResourceCompose contains status information specific to Docker Compose.
@@ -4106,6 +4406,17 @@ export interface UIResourceLocal {
    * +optional
    */
   isTest?: boolean
+  /**
+   * The number of times the local command has been restarted
+   * because its liveness or startup probe failed.
+   * +optional
+   */
+  restartCount?: number /* int32 */
+  /**
+   * (brief) reason the local command was last restarted by a failing probe.
+   * +optional
+   */
+  lastRestartReason?: string
 }
 export interface UIResourceStateWaiting {
   /**
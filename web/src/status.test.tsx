@@ -217,6 +217,18 @@ describe("PendingBuildDescription", () => {
     )
   })
 
+  it("shows concurrency group name", () => {
+    let hold = new Hold({
+      reason: "waiting-for-concurrency-group",
+      on: [
+        { group: "", apiVersion: "", kind: "ConcurrencyGroup", name: "db" },
+      ],
+    })
+    expect(PendingBuildDescription(hold)).toBe(
+      "Update: waiting on concurrency group: db"
+    )
+  })
+
   it("prefers image over resource", () => {
     let hold = new Hold({
       reason: "waiting-for-deploy",
@@ -120,6 +120,12 @@ export function PendingBuildDescription(hold?: Hold | null): string {
   } else if (hold?.clusters.length) {
     text += hold.clusters.length > 1 ? "clusters: " : "cluster: "
     toShow = hold.clusters
+  } else if (hold?.concurrencyGroups.length) {
+    text +=
+      hold.concurrencyGroups.length > 1
+        ? "concurrency groups: "
+        : "concurrency group: "
+    toShow = hold.concurrencyGroups
   } else {
     text += `${hold.count} object${hold.count > 1 ? "s" : ""}`
     return text
@@ -57,3 +57,21 @@ export function toggleTriggerMode(name: string, mode: TriggerMode) {
     }
   })
 }
+
+// Confirms the diff that a resource's apply is waiting on. The hash
+// must match, so that we never confirm a diff the user hasn't seen.
+export function confirmDiff(name: string, hash: string) {
+  let url = "/api/confirm_diff"
+
+  return fetch(url, {
+    method: "post",
+    body: JSON.stringify({
+      manifest_name: name,
+      hash: hash,
+    }),
+  }).then((response) => {
+    if (!response.ok) {
+      console.log(response)
+    }
+  })
+}